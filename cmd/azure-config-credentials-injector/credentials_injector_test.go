@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -49,6 +50,7 @@ func Test_mergeCloudConfig(t *testing.T) {
 		injectorOpts.disableIdentityExtensionAuth = false
 		injectorOpts.cloudConfigFilePath = ""
 		injectorOpts.outputFilePath = ""
+		injectorOpts.credsFilePath = ""
 	}
 
 	cleanupInputFile := func(path string) {
@@ -209,3 +211,118 @@ func Test_mergeCloudConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_mergeCloudConfigFromCredsFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "cccmo-azure-creds-injector")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	inputFile := filepath.Join(tmpDir, "dummy-config")
+	require.NoError(t, os.WriteFile(inputFile, []byte("{\"bar\": \"baz\"}"), 0644))
+	outputFile := filepath.Join(tmpDir, "dummy-config-merged")
+	credsFile := filepath.Join(tmpDir, "credentials")
+
+	cleanupOpts := func() {
+		injectorOpts.disableIdentityExtensionAuth = false
+		injectorOpts.cloudConfigFilePath = ""
+		injectorOpts.outputFilePath = ""
+		injectorOpts.credsFilePath = ""
+	}
+
+	testCases := []struct {
+		name            string
+		args            []string
+		envVars         map[string]string
+		credsContent    string
+		expectedContent string
+		expectedErrMsg  string
+	}{
+		{
+			name:            "all ok, client secret from json file",
+			credsContent:    "{\"azure_client_id\": \"foo\", \"azure_client_secret\": \"bar\"}",
+			expectedContent: "{\"aadClientId\":\"foo\",\"aadClientSecret\":\"bar\",\"bar\":\"baz\"}",
+		},
+		{
+			name:            "all ok, client secret from ini file",
+			credsContent:    "[default]\n# comment\nazure_client_id = foo\nazure_client_secret = \"bar\"\n",
+			expectedContent: "{\"aadClientId\":\"foo\",\"aadClientSecret\":\"bar\",\"bar\":\"baz\"}",
+		},
+		{
+			name:            "all ok, workload identity from json file",
+			args:            []string{"--enable-azure-workload-identity=true"},
+			credsContent:    "{\"azure_client_id\": \"buzz\", \"azure_tenant_id\": \"bar\", \"azure_federated_token_file\": \"baz\"}",
+			expectedContent: "{\"aadClientId\":\"buzz\",\"aadFederatedTokenFile\":\"baz\",\"bar\":\"baz\",\"tenantId\":\"bar\",\"useFederatedWorkloadIdentityExtension\":true}",
+		},
+		{
+			name:            "all ok, workload identity from ini file",
+			args:            []string{"--enable-azure-workload-identity=true"},
+			credsContent:    "azure_client_id=buzz\nazure_tenant_id=bar\nazure_federated_token_file=baz\n",
+			expectedContent: "{\"aadClientId\":\"buzz\",\"aadFederatedTokenFile\":\"baz\",\"bar\":\"baz\",\"tenantId\":\"bar\",\"useFederatedWorkloadIdentityExtension\":true}",
+		},
+		{
+			name:            "all ok, env variables are ignored",
+			envVars:         map[string]string{"AZURE_CLIENT_ID": "fizz", "AZURE_CLIENT_SECRET": "buzz"},
+			credsContent:    "{\"azure_client_id\": \"foo\", \"azure_client_secret\": \"bar\"}",
+			expectedContent: "{\"aadClientId\":\"foo\",\"aadClientSecret\":\"bar\",\"bar\":\"baz\"}",
+		},
+		{
+			name:           "should fail, client id missing",
+			envVars:        map[string]string{"AZURE_CLIENT_ID": "fizz"},
+			credsContent:   "{\"azure_client_secret\": \"bar\"}",
+			expectedErrMsg: "azure_client_id key in " + credsFile + " should be set up",
+		},
+		{
+			name:           "should fail, client secret missing",
+			credsContent:   "{\"azure_client_id\": \"foo\"}",
+			expectedErrMsg: "azure_client_secret key in " + credsFile + " should be set up",
+		},
+		{
+			name:           "should fail, client secret is present while federated token file is present",
+			credsContent:   "{\"azure_client_id\": \"foo\", \"azure_client_secret\": \"bar\", \"azure_tenant_id\": \"baz\", \"azure_federated_token_file\": \"baz\"}",
+			expectedErrMsg: "azure_client_secret key in " + credsFile + " is set while workload identity is enabled using azure_federated_token_file key in " + credsFile + ", this should never happen.\nPlease consider reporting a bug: https://issues.redhat.com",
+		},
+		{
+			name:           "should fail, tenant id missing while federated token file is present",
+			credsContent:   "{\"azure_client_id\": \"buzz\", \"azure_federated_token_file\": \"baz\"}",
+			expectedErrMsg: "azure_tenant_id key in " + credsFile + " should be set up while workload identity is enabled using azure_federated_token_file key in " + credsFile + ", this should never happen.\nPlease consider reporting a bug: https://issues.redhat.com",
+		},
+		{
+			name:           "should fail, credentials file is malformed",
+			credsContent:   "azure_client_id",
+			expectedErrMsg: "couldn't read credentials from file: line 1: expected key = value format",
+		},
+		{
+			name:           "should fail, credentials file does not exist",
+			expectedErrMsg: "couldn't read credentials from file: open " + credsFile + ": no such file or directory",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for envVarName, envVarValue := range tc.envVars {
+				t.Setenv(envVarName, envVarValue)
+			}
+
+			if tc.credsContent != "" {
+				require.NoError(t, os.WriteFile(credsFile, []byte(tc.credsContent), 0644))
+				defer os.Remove(credsFile)
+			}
+			defer os.Remove(outputFile)
+			defer cleanupOpts()
+
+			args := append([]string{"--cloud-config-file-path", inputFile, "--output-file-path", outputFile, "--creds-file", credsFile}, tc.args...)
+			_, mergeCloudConfError := executeCommand(injectorCmd, args...)
+
+			if tc.expectedErrMsg != "" {
+				require.NotNil(t, mergeCloudConfError, "Error was expected but not returned by `mergeCloudConfig` function")
+				assert.Equal(t, tc.expectedErrMsg, mergeCloudConfError.Error())
+				return
+			}
+
+			require.NoError(t, mergeCloudConfError)
+			fileContent, err := os.ReadFile(outputFile)
+			require.NoError(t, err, "Cannot read output file")
+			assert.Equal(t, tc.expectedContent, string(fileContent))
+		})
+	}
+}
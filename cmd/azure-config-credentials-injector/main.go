@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
@@ -16,6 +17,12 @@ const (
 	tenantIDEnvKey       = "AZURE_TENANT_ID"
 	federatedTokenEnvKey = "AZURE_FEDERATED_TOKEN_FILE"
 
+	// Keys of the combined credentials file, matching keys of the credentials secret.
+	clientIDFileKey       = "azure_client_id"
+	clientSecretFileKey   = "azure_client_secret"
+	tenantIDFileKey       = "azure_tenant_id"
+	federatedTokenFileKey = "azure_federated_token_file"
+
 	clientIDCloudConfigKey               = "aadClientId"
 	clientSecretCloudConfigKey           = "aadClientSecret"
 	useManagedIdentityExtensionConfigKey = "useManagedIdentityExtension"
@@ -37,6 +44,15 @@ var (
 		outputFilePath               string
 		enableWorkloadIdentity       string
 		disableIdentityExtensionAuth bool
+		credsFilePath                string
+	}
+
+	// credentialsFileKeys maps env variables credentials are read from by default to keys of the combined credentials file.
+	credentialsFileKeys = map[string]string{
+		clientIDEnvKey:       clientIDFileKey,
+		clientSecretEnvKey:   clientSecretFileKey,
+		tenantIDEnvKey:       tenantIDFileKey,
+		federatedTokenEnvKey: federatedTokenFileKey,
 	}
 )
 
@@ -47,6 +63,8 @@ func init() {
 	injectorCmd.PersistentFlags().StringVar(&injectorOpts.outputFilePath, "output-file-path", "/tmp/merged-cloud-config/cloud.conf", "Location of the generated cloud config file with injected credentials.")
 	injectorCmd.PersistentFlags().BoolVar(&injectorOpts.disableIdentityExtensionAuth, "disable-identity-extension-auth", false, "Disable managed identity authentication, if it's set in cloudConfig.")
 	injectorCmd.PersistentFlags().StringVar(&injectorOpts.enableWorkloadIdentity, "enable-azure-workload-identity", "false", "Enable workload identity authentication.")
+	injectorCmd.PersistentFlags().StringVar(&injectorOpts.credsFilePath, "creds-file", "", "Location of a single JSON or INI file to read credentials from instead of env variables. "+
+		"Credentials are looked up by azure_client_id, azure_client_secret, azure_tenant_id and azure_federated_token_file keys.")
 }
 
 func main() {
@@ -71,30 +89,45 @@ func mergeCloudConfig(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	azureClientId, found := mustLookupEnvValue(clientIDEnvKey)
+	lookupValue, describeKey := mustLookupEnvValue, describeEnvKey
+	if injectorOpts.credsFilePath != "" {
+		credentials, err := readCredentialsFile(injectorOpts.credsFilePath)
+		if err != nil {
+			return fmt.Errorf("couldn't read credentials from file: %w", err)
+		}
+		lookupValue = func(key string) (string, bool) {
+			value := credentials[credentialsFileKeys[key]]
+			return value, len(value) != 0
+		}
+		describeKey = func(key string) string {
+			return fmt.Sprintf("%s key in %s", credentialsFileKeys[key], injectorOpts.credsFilePath)
+		}
+	}
+
+	azureClientId, found := lookupValue(clientIDEnvKey)
 	if !found {
-		return fmt.Errorf("%s env variable should be set up", clientIDEnvKey)
+		return fmt.Errorf("%s should be set up", describeKey(clientIDEnvKey))
 	}
 
-	// Check for environment variables
-	azureClientSecret, secretFound = mustLookupEnvValue(clientSecretEnvKey)
-	federatedTokenFile, federatedTokenFileFound = mustLookupEnvValue(federatedTokenEnvKey)
-	tenantId, tenantIdFound = mustLookupEnvValue(tenantIDEnvKey)
+	// Check for credentials
+	azureClientSecret, secretFound = lookupValue(clientSecretEnvKey)
+	federatedTokenFile, federatedTokenFileFound = lookupValue(federatedTokenEnvKey)
+	tenantId, tenantIdFound = lookupValue(tenantIDEnvKey)
 
 	// If federatedTokenFile found, workload identity should be used
 	if federatedTokenFileFound {
 		// azureClientSecret should not be set for workload identity auth, report error when secretFound
 		if secretFound {
-			return fmt.Errorf("%s env variable is set while workload identity is enabled using %s env variable, this should never happen.\nPlease consider reporting a bug: https://issues.redhat.com", clientSecretEnvKey, federatedTokenEnvKey)
+			return fmt.Errorf("%s is set while workload identity is enabled using %s, this should never happen.\nPlease consider reporting a bug: https://issues.redhat.com", describeKey(clientSecretEnvKey), describeKey(federatedTokenEnvKey))
 		}
 		// tenantId is required for workload identity auth, report error when !tenantIdFound
 		if !tenantIdFound {
-			return fmt.Errorf("%s env variable should be set up while workload identity is enabled using %s env variable, this should never happen.\nPlease consider reporting a bug: https://issues.redhat.com", tenantIDEnvKey, federatedTokenEnvKey)
+			return fmt.Errorf("%s should be set up while workload identity is enabled using %s, this should never happen.\nPlease consider reporting a bug: https://issues.redhat.com", describeKey(tenantIDEnvKey), describeKey(federatedTokenEnvKey))
 		}
 	} else {
 		// federatedTokenFile not found, secret will be required
 		if !secretFound {
-			return fmt.Errorf("%s env variable should be set up", clientSecretEnvKey)
+			return fmt.Errorf("%s should be set up", describeKey(clientSecretEnvKey))
 		}
 	}
 
@@ -159,6 +192,36 @@ func prepareCloudConfig(cloudConfig map[string]interface{}, clientId, clientSecr
 	return marshalled, nil
 }
 
+// readCredentialsFile reads credentials from a single file, either a JSON object with string values
+// or INI-like "key = value" lines. Sections and comments of INI files are ignored.
+func readCredentialsFile(path string) (map[string]string, error) {
+	rawData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := map[string]string{}
+	if strings.HasPrefix(strings.TrimSpace(string(rawData)), "{") {
+		if err := json.Unmarshal(rawData, &credentials); err != nil {
+			return nil, err
+		}
+		return credentials, nil
+	}
+
+	for i, line := range strings.Split(string(rawData), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "[") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected key = value format", i+1)
+		}
+		credentials[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return credentials, nil
+}
+
 func writeCloudConfig(path string, preparedConfig []byte) error {
 	if err := os.WriteFile(path, preparedConfig, 0644); err != nil {
 		return err
@@ -166,6 +229,10 @@ func writeCloudConfig(path string, preparedConfig []byte) error {
 	return nil
 }
 
+func describeEnvKey(key string) string {
+	return fmt.Sprintf("%s env variable", key)
+}
+
 func mustLookupEnvValue(key string) (string, bool) {
 	value, found := os.LookupEnv(key)
 	if !found || len(value) == 0 {
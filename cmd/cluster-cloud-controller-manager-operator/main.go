@@ -17,9 +17,13 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -30,6 +34,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/component-base/config"
@@ -37,6 +42,7 @@ import (
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -48,7 +54,10 @@ import (
 	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
 	"github.com/openshift/library-go/pkg/operator/events"
 
+	operatorconfig "github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/controllers"
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/controllers/resourceapply"
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/restmapper"
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/util"
 	// +kubebuilder:scaffold:imports
 )
@@ -84,6 +93,12 @@ func main() {
 		"Address for hosting metrics",
 	)
 
+	profilingAddr := flag.String(
+		"profiling-bind-address",
+		"",
+		"Address for serving pprof profiles, separate from metrics, e.g. \"127.0.0.1:6060\". Profiling is disabled if empty.",
+	)
+
 	healthAddr := flag.String(
 		"health-addr",
 		":9440",
@@ -98,16 +113,125 @@ func main() {
 	imagesFile := flag.String(
 		"images-json",
 		defaultImagesLocation,
-		"The location of images file to use by operator for managed CCM binaries.",
+		"The location of images file to use by operator for managed CCM binaries. "+
+			"A comma-separated list of files may be passed, images set in later files override earlier ones.",
+	)
+
+	validateImages := flag.Bool(
+		"validate-images",
+		false,
+		"Validate the images file passed with --images-json, report missing images per platform and exit.",
+	)
+
+	featureGatesOverride := flag.String(
+		"feature-gates",
+		"",
+		"Static comma-separated list of feature gates, e.g. \"Foo=true,Bar=false\". When set, the FeatureGate object is not observed.",
+	)
+
+	ignoreUnsupportedFeatureGates := flag.Bool(
+		"ignore-unsupported-feature-gates",
+		false,
+		"Only log enabled feature gates which are not supported on the cluster platform, instead of reporting the operator as degraded.",
+	)
+
+	dryRun := flag.Bool(
+		"dry-run",
+		false,
+		"Report operand changes with events and logs instead of applying them, e.g. to validate a new payload.",
+	)
+
+	strippedAnnotations := flag.String(
+		"stripped-annotations",
+		strings.Join(resourceapply.DefaultStrippedAnnotations, ","),
+		"Comma-separated list of annotations removed from operands before they are applied, so they never trigger operand updates.",
+	)
+
+	nodeManagerConfigHashSources := flag.String(
+		"node-manager-config-hash-sources",
+		"",
+		"Comma-separated list of config maps in the managed namespace whose changes restart node-manager pods, although the pods do not reference them.",
+	)
+
+	startupScriptsFromConfigMap := flag.Bool(
+		"startup-scripts-from-configmap",
+		false,
+		"Render operand command scripts into config maps which operand containers run them from, instead of inlining them.",
+	)
+
+	logOperandFlags := flag.Bool(
+		"log-operand-flags",
+		false,
+		"Make cloud-controller-manager containers echo their effective command with all flags to stdout before it is executed.",
+	)
+
+	tolerateRoleBindingFailures := flag.Bool(
+		"tolerate-role-binding-failures",
+		false,
+		"Apply the remaining operands when some role bindings fail to apply, reporting the failed bindings as degraded afterwards.",
+	)
+
+	disablePodDisruptionBudget := flag.Bool(
+		"disable-pod-disruption-budget",
+		false,
+		"Do not create PodDisruptionBudgets for operands, and delete the ones created before, e.g. when they are managed centrally.",
+	)
+
+	dropOperandPrivilegesToUser := flag.Int64(
+		"drop-operand-privileges-to-uid",
+		0,
+		"ID of the non-root user cloud-controller-manager and cloud-node-manager binaries are executed as after their startup scripts. Zero keeps them running as root.",
+	)
+
+	restartOnDNSChange := flag.Bool(
+		"restart-operands-on-dns-change",
+		false,
+		"Restart operand pods when the cluster DNS config changes, since host network operands resolve names with the node resolver.",
+	)
+
+	shutdownGracePeriod := flag.Duration(
+		"shutdown-grace-period",
+		30*time.Second,
+		"How long in-flight reconciles may take to complete once the manager is stopped, so operands are not left half applied.",
+	)
+
+	logFormat := flag.String(
+		"log-format",
+		util.LogFormatText,
+		fmt.Sprintf("The log format, one of: %s, %s.", util.LogFormatText, util.LogFormatJSON),
 	)
 
+	operandOverrides := controllers.OperandOverrides{}
+	addOperandOverrideFlags(flag.CommandLine, &operandOverrides)
+
 	// Once all the flags are regitered, switch to pflag
 	// to allow leader lection flags to be bound
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	options.BindLeaderElectionFlags(&leaderElectionConfig, pflag.CommandLine)
 	pflag.Parse()
 
-	ctrl.SetLogger(klog.NewKlogr().WithName("CCMOperator"))
+	if *validateImages {
+		if err := operatorconfig.ValidateImagesFile(*imagesFile); err != nil {
+			fmt.Fprintf(os.Stderr, "images file %s is invalid:\n", *imagesFile)
+			errs := []error{err}
+			if agg, ok := err.(utilerrors.Aggregate); ok {
+				errs = agg.Errors()
+			}
+			for _, err := range errs {
+				fmt.Fprintf(os.Stderr, "  %v\n", err)
+			}
+			os.Exit(1)
+		}
+		fmt.Printf("images file %s is valid\n", *imagesFile)
+		os.Exit(0)
+	}
+
+	logger, err := util.NewLogger(*logFormat, klog.NewKlogr)
+	if err != nil {
+		klog.Errorf("unable to set up logging: %v", err)
+		os.Exit(1)
+	}
+	ctrl.SetLogger(logger.WithName("CCMOperator"))
 
 	restConfig := ctrl.GetConfigOrDie()
 	le := util.GetLeaderElectionDefaults(restConfig, configv1.LeaderElection{
@@ -119,31 +243,7 @@ func main() {
 
 	ctx := ctrl.SetupSignalHandler()
 
-	syncPeriod := 10 * time.Minute
-	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
-		Scheme: scheme,
-		Metrics: metricsserver.Options{
-			BindAddress: *metricsAddr,
-		},
-		Cache: cache.Options{
-			SyncPeriod: &syncPeriod,
-			DefaultNamespaces: map[string]cache.Config{
-				*managedNamespace: {},
-			},
-		},
-		WebhookServer: &webhook.DefaultServer{
-			Options: webhook.Options{
-				Port: 9443,
-			},
-		},
-		HealthProbeBindAddress:  *healthAddr,
-		LeaderElectionNamespace: leaderElectionConfig.ResourceNamespace,
-		LeaderElection:          leaderElectionConfig.LeaderElect,
-		LeaderElectionID:        leaderElectionConfig.ResourceName,
-		LeaseDuration:           &le.LeaseDuration.Duration,
-		RetryPeriod:             &le.RetryPeriod.Duration,
-		RenewDeadline:           &le.RenewDeadline.Duration,
-	})
+	mgr, err := ctrl.NewManager(restConfig, newManagerOptions(*metricsAddr, *profilingAddr, *healthAddr, *managedNamespace, le, *shutdownGracePeriod))
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -173,11 +273,21 @@ func main() {
 	}
 
 	recorder := events.NewKubeRecorder(kubeClient.CoreV1().Events(*managedNamespace), "cloud-controller-manager-operator", controllerRef)
-	featureGateAccessor := featuregates.NewFeatureGateAccess(
-		desiredVersion, missingVersion,
-		configInformers.Config().V1().ClusterVersions(), configInformers.Config().V1().FeatureGates(),
-		recorder,
-	)
+	var featureGateAccessor featuregates.FeatureGateAccess
+	if *featureGatesOverride != "" {
+		featureGateAccessor, err = util.NewStaticFeatureGateAccess(*featureGatesOverride)
+		if err != nil {
+			setupLog.Error(err, "unable to parse feature gates")
+			os.Exit(1)
+		}
+		setupLog.Info("Using static feature gates", "featureGates", *featureGatesOverride)
+	} else {
+		featureGateAccessor = featuregates.NewFeatureGateAccess(
+			desiredVersion, missingVersion,
+			configInformers.Config().V1().ClusterVersions(), configInformers.Config().V1().FeatureGates(),
+			recorder,
+		)
+	}
 
 	featureGateAccessor.SetChangeHandler(func(featureChange featuregates.FeatureChange) {
 		// Do nothing here. The controller watches feature gate changes and will react to them.
@@ -187,26 +297,52 @@ func main() {
 	go featureGateAccessor.Run(ctx)
 	go configInformers.Start(ctx.Done())
 
-	select {
-	case <-featureGateAccessor.InitialFeatureGatesObserved():
+	featureGateAccessor, observed := util.WaitForFeatureGates(ctx, featureGateAccessor, 1*time.Minute)
+	if observed {
 		features, _ := featureGateAccessor.CurrentFeatureGates()
 
 		enabled, disabled := util.GetEnabledDisabledFeatures(features, nil)
 		setupLog.Info("FeatureGates initialized", "enabled", enabled, "disabled", disabled)
-	case <-time.After(1 * time.Minute):
-		setupLog.Error(errors.New("timed out waiting for FeatureGate detection"), "unable to start manager")
+	} else {
+		setupLog.Error(errors.New("timed out waiting for FeatureGate detection"), "continuing with an empty set of feature gates until they are observed")
+	}
+
+	var stripped []string
+	for _, annotation := range strings.Split(*strippedAnnotations, ",") {
+		if annotation = strings.TrimSpace(annotation); annotation != "" {
+			stripped = append(stripped, annotation)
+		}
+	}
+
+	var nodeManagerSources []string
+	for _, name := range strings.Split(*nodeManagerConfigHashSources, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			nodeManagerSources = append(nodeManagerSources, name)
+		}
 	}
 
 	if err = (&controllers.CloudOperatorReconciler{
 		ClusterOperatorStatusClient: controllers.ClusterOperatorStatusClient{
-			Client:           mgr.GetClient(),
-			Recorder:         mgr.GetEventRecorderFor("cloud-controller-manager-operator"),
-			ReleaseVersion:   controllers.GetReleaseVersion(),
-			ManagedNamespace: *managedNamespace,
+			Client:              mgr.GetClient(),
+			Recorder:            mgr.GetEventRecorderFor("cloud-controller-manager-operator"),
+			ReleaseVersion:      controllers.GetReleaseVersion(),
+			ManagedNamespace:    *managedNamespace,
+			ShutdownGracePeriod: *shutdownGracePeriod,
 		},
-		Scheme:            mgr.GetScheme(),
-		ImagesFile:        *imagesFile,
-		FeatureGateAccess: featureGateAccessor,
+		OperandOverrides:              operandOverrides,
+		Scheme:                        mgr.GetScheme(),
+		ImagesFile:                    *imagesFile,
+		FeatureGateAccess:             featureGateAccessor,
+		DryRun:                        *dryRun,
+		StrippedAnnotations:           stripped,
+		NodeManagerConfigHashSources:  nodeManagerSources,
+		StartupScriptsFromConfigMap:   *startupScriptsFromConfigMap,
+		LogOperandFlags:               *logOperandFlags,
+		DropOperandPrivilegesToUser:   *dropOperandPrivilegesToUser,
+		DisablePodDisruptionBudget:    *disablePodDisruptionBudget,
+		TolerateRoleBindingFailures:   *tolerateRoleBindingFailures,
+		RestartOnDNSChange:            *restartOnDNSChange,
+		IgnoreUnsupportedFeatureGates: *ignoreUnsupportedFeatureGates,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterOperator")
 		os.Exit(1)
@@ -221,6 +357,10 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("images", controllers.NewImagesFileChecker(*imagesFile)); err != nil {
+		setupLog.Error(err, "unable to set up images file ready check")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctx); err != nil {
@@ -228,3 +368,188 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// newManagerOptions returns options of the controller manager for the given operator flags.
+func newManagerOptions(metricsAddr, profilingAddr, healthAddr, managedNamespace string, le configv1.LeaderElection, shutdownGracePeriod time.Duration) ctrl.Options {
+	syncPeriod := 10 * time.Minute
+	return ctrl.Options{
+		Scheme: scheme,
+		// Lookups of kinds which are not served, e.g. of optional APIs, are remembered for a short time
+		// instead of querying discovery on every reconcile.
+		MapperProvider: restmapper.NewNegativeCachingRestMapperProvider(apiutil.NewDynamicRESTMapper, restmapper.DefaultNegativeCacheTTL),
+		Metrics: metricsserver.Options{
+			BindAddress: metricsAddr,
+		},
+		Cache: cache.Options{
+			SyncPeriod: &syncPeriod,
+			DefaultNamespaces: map[string]cache.Config{
+				managedNamespace: {},
+			},
+		},
+		WebhookServer: &webhook.DefaultServer{
+			Options: webhook.Options{
+				Port: 9443,
+			},
+		},
+		HealthProbeBindAddress:  healthAddr,
+		PprofBindAddress:        profilingAddr,
+		LeaderElectionNamespace: leaderElectionConfig.ResourceNamespace,
+		LeaderElection:          leaderElectionConfig.LeaderElect,
+		LeaderElectionID:        leaderElectionConfig.ResourceName,
+		LeaseDuration:           &le.LeaseDuration.Duration,
+		RetryPeriod:             &le.RetryPeriod.Duration,
+		RenewDeadline:           &le.RenewDeadline.Duration,
+		GracefulShutdownTimeout: &shutdownGracePeriod,
+	}
+}
+
+// addOperandOverrideFlags registers flags overriding operand settings of the provider manifests.
+// Settings are only overridden when their flags are passed, except for startup timeouts, which
+// are set to the platform defaults.
+func addOperandOverrideFlags(fs *flag.FlagSet, overrides *controllers.OperandOverrides) {
+	overrides.StartupTimeouts = operatorconfig.DefaultStartupTimeouts()
+	optionalFlag(fs, &overrides.AllocateNodeCIDRs, "allocate-node-cidrs", strconv.ParseBool,
+		"Override whether cloud-controller-manager allocates node CIDRs, for providers which do the allocation themselves.")
+	optionalFlag(fs, &overrides.CCMVerbosity, "ccm-verbosity", parseNonNegativeInt,
+		"Override the log verbosity of the cloud-controller-manager container, e.g. to debug a provider issue.")
+	optionalFlag(fs, &overrides.ConfigureCloudRoutes, "configure-cloud-routes", strconv.ParseBool,
+		"Enable the cloud route controller of cloud-controller-manager, for platforms which set up pod networking with cloud routes.")
+	jsonFlag(fs, &overrides.ExtraHostPathMounts, "extra-host-path-mounts",
+		`JSON list of additional host paths mounted into operand containers, e.g. [{"name":"creds","hostPath":"/var/lib/creds","mountPath":"/creds","type":"Directory","readOnly":true}].`)
+	jsonFlag(fs, &overrides.PDBPolicy, "pdb-policy",
+		`JSON disruption policy of operand PodDisruptionBudgets, e.g. {"type":"MaxUnavailable","value":1}. At least one pod has to be available when not set.`)
+	fs.Func("extra-node-manager-arg",
+		"Flag appended to the cloud-node-manager container command, e.g. \"--node-status-update-frequency=1m\". May be repeated.",
+		func(value string) error {
+			overrides.ExtraNodeManagerArgs = append(overrides.ExtraNodeManagerArgs, value)
+			return nil
+		})
+	jsonFlag(fs, &overrides.ServiceAccountAnnotations, "service-account-annotations",
+		`JSON object of annotations set on operand service accounts, e.g. {"eks.amazonaws.com/role-arn":"arn:aws:iam::123456789012:role/ccm"}.`)
+	jsonFlag(fs, &overrides.InitContainerResources, "init-container-resources",
+		`JSON resource requirements of operand init containers, such as the Azure credentials injector, e.g. {"requests":{"cpu":"20m","memory":"20Mi"}}.`)
+	fs.StringVar(&overrides.NodeManagerPriorityClassName, "node-manager-priority-class", "",
+		"Priority class of node-manager pods, system-node-critical is used when empty.")
+	optionalFlag(fs, &overrides.NodeManagerMinReadySeconds, "node-manager-min-ready-seconds", parseNonNegativeInt32,
+		"How long new node-manager pods have to be ready before they are considered available, so rollouts wait for nodes to be initialized.")
+	fs.BoolVar(&overrides.CloudAPIReadinessProbe, "cloud-api-readiness-probe", false,
+		"Report cloud-controller-manager pods as not ready while the cloud metadata endpoint is unreachable, on platforms which have one.")
+	fs.Func("operand-log-format",
+		fmt.Sprintf("The log format of the cloud-controller-manager container, one of: %s, %s. Provider defaults are used when not set.",
+			operatorconfig.LoggingFormatText, operatorconfig.LoggingFormatJSON),
+		func(value string) error {
+			format := operatorconfig.LoggingFormat(value)
+			if err := format.Validate(); err != nil {
+				return err
+			}
+			overrides.LoggingFormat = format
+			return nil
+		})
+	jsonFlag(fs, &overrides.ResourceAutosizing, "ccm-resource-autosizing",
+		`JSON description of how resource requests of the cloud-controller-manager container grow with the cluster node count, `+
+			`e.g. {"min":{"cpu":"200m"},"perNode":{"cpu":"2m"},"max":{"cpu":"1"}}. Requests of the provider manifests are used when not set.`)
+	fs.Func("cloud-endpoint-override",
+		"Cloud API endpoint override passed to operands as an environment variable, as ENV=URL, e.g. \"AWS_ENDPOINT_URL_EC2=https://ec2.example.com\". May be repeated.",
+		func(value string) error {
+			envName, url, ok := strings.Cut(value, "=")
+			if !ok {
+				return fmt.Errorf("expected ENV=URL, got %q", value)
+			}
+			overrides.CloudEndpointOverrides = append(overrides.CloudEndpointOverrides, operatorconfig.CloudEndpointOverride{EnvName: envName, URL: url})
+			return nil
+		})
+	jsonFlag(fs, &overrides.ExtraVolumes, "extra-volumes",
+		`JSON list of volumes added to every operand pod, e.g. [{"name":"custom-ca","configMap":{"name":"custom-ca"}}].`)
+	jsonFlag(fs, &overrides.ExtraVolumeMounts, "extra-volume-mounts",
+		`JSON list of mounts of extra volumes added to every operand container, e.g. [{"name":"custom-ca","mountPath":"/etc/custom-ca","readOnly":true}].`)
+	fs.Func("startup-timeouts",
+		"Comma-separated list of per platform limits of how long operand container scripts may take to start the operand binary, "+
+			"e.g. \"AWS=2m,VSphere=10m\". Listed platforms override the defaults, a zero timeout disables the limit.",
+		func(value string) error {
+			for _, entry := range strings.Split(value, ",") {
+				platform, timeout, ok := strings.Cut(strings.TrimSpace(entry), "=")
+				if !ok {
+					return fmt.Errorf("expected PLATFORM=DURATION, got %q", entry)
+				}
+				duration, err := time.ParseDuration(timeout)
+				if err != nil {
+					return err
+				}
+				overrides.StartupTimeouts[configv1.PlatformType(platform)] = duration
+			}
+			return nil
+		})
+	azureCredentials := func() *operatorconfig.AzureCredentials {
+		if overrides.AzureCredentials == nil {
+			overrides.AzureCredentials = &operatorconfig.AzureCredentials{}
+		}
+		return overrides.AzureCredentials
+	}
+	fs.Func("azure-credentials-secret",
+		"Name of the secret in the managed namespace Azure operands read cloud credentials from, azure-cloud-credentials is used when not set.",
+		func(value string) error {
+			azureCredentials().SecretName = value
+			return nil
+		})
+	fs.Func("azure-credentials-file",
+		"Absolute path of a JSON or INI file the Azure credentials injector reads credentials from, instead of env variables. "+
+			"The file is the key of the credentials secret named as the base name of the path.",
+		func(value string) error {
+			azureCredentials().FilePath = value
+			return nil
+		})
+	jsonFlag(fs, &overrides.PodSysctls, "operand-sysctls",
+		`JSON object of sysctls set on operand pods per platform, e.g. {"AWS":[{"name":"kernel.shm_rmid_forced","value":"1"}]}.`)
+	jsonFlag(fs, &overrides.HostAliases, "operand-host-aliases",
+		`JSON list of host aliases added to /etc/hosts of operand pods, e.g. [{"ip":"10.0.0.10","hostnames":["ec2.us-east-1.amazonaws.com"]}].`)
+	jsonFlag(fs, &overrides.Affinity, "operand-affinity",
+		"JSON affinity merged into the affinity of controller pods. Node affinity is added to the provider one, "+
+			"pod affinity and pod anti-affinity replace the provider ones if set.")
+}
+
+// optionalFlag registers a flag which sets the target to its parsed value only when the flag is passed,
+// so the target stays nil otherwise.
+func optionalFlag[T any](fs *flag.FlagSet, target **T, name string, parse func(string) (T, error), usage string) {
+	fs.Func(name, usage, func(value string) error {
+		parsed, err := parse(value)
+		if err != nil {
+			return err
+		}
+		*target = &parsed
+		return nil
+	})
+}
+
+// jsonFlag registers a flag whose value is decoded as JSON into the target. Unknown fields are rejected,
+// so misspelled settings are not silently ignored.
+func jsonFlag(fs *flag.FlagSet, target any, name, usage string) {
+	fs.Func(name, usage, func(value string) error {
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.DisallowUnknownFields()
+		return decoder.Decode(target)
+	})
+}
+
+// parseNonNegativeInt32 parses a decimal 32-bit integer which must not be negative.
+func parseNonNegativeInt32(value string) (int32, error) {
+	parsed, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("%d should not be negative", parsed)
+	}
+	return int32(parsed), nil
+}
+
+// parseNonNegativeInt parses a decimal integer which must not be negative.
+func parseNonNegativeInt(value string) (int, error) {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("%d should not be negative", parsed)
+	}
+	return parsed, nil
+}
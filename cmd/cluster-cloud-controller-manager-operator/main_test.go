@@ -0,0 +1,314 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	operatorconfig "github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/controllers"
+)
+
+func TestNewManagerOptions(t *testing.T) {
+	le := configv1.LeaderElection{
+		LeaseDuration: metav1.Duration{Duration: 137 * time.Second},
+		RenewDeadline: metav1.Duration{Duration: 107 * time.Second},
+		RetryPeriod:   metav1.Duration{Duration: 26 * time.Second},
+	}
+
+	tc := []struct {
+		name                 string
+		profilingAddr        string
+		expectedPprofAddress string
+	}{{
+		name:                 "Profiling enabled",
+		profilingAddr:        "127.0.0.1:6060",
+		expectedPprofAddress: "127.0.0.1:6060",
+	}, {
+		name:                 "Profiling disabled",
+		profilingAddr:        "",
+		expectedPprofAddress: "",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			options := newManagerOptions("127.0.0.1:9257", tc.profilingAddr, "127.0.0.1:9259", "openshift-cloud-controller-manager", le, 30*time.Second)
+
+			assert.Equal(t, tc.expectedPprofAddress, options.PprofBindAddress)
+			// Profiles are served separately from metrics
+			assert.Equal(t, "127.0.0.1:9257", options.Metrics.BindAddress)
+			assert.Equal(t, "127.0.0.1:9259", options.HealthProbeBindAddress)
+			assert.Len(t, options.Cache.DefaultNamespaces, 1)
+			assert.Contains(t, options.Cache.DefaultNamespaces, "openshift-cloud-controller-manager")
+			assert.Equal(t, 137*time.Second, *options.LeaseDuration)
+			assert.Equal(t, 30*time.Second, *options.GracefulShutdownTimeout)
+		})
+	}
+}
+
+func TestOperandOverrideFlags(t *testing.T) {
+	tc := []struct {
+		name          string
+		args          []string
+		expected      controllers.OperandOverrides
+		expectedError string
+	}{{
+		name: "No overrides",
+	}, {
+		name: "Allocate node CIDRs",
+		args: []string{"--allocate-node-cidrs=true"},
+		expected: controllers.OperandOverrides{
+			AllocateNodeCIDRs: ptr.To(true),
+		},
+	}, {
+		name: "Do not allocate node CIDRs",
+		args: []string{"--allocate-node-cidrs=false"},
+		expected: controllers.OperandOverrides{
+			AllocateNodeCIDRs: ptr.To(false),
+		},
+	}, {
+		name:          "Invalid allocate node CIDRs",
+		args:          []string{"--allocate-node-cidrs=maybe"},
+		expectedError: `invalid value "maybe" for flag -allocate-node-cidrs`,
+	}, {
+		name: "CCM verbosity",
+		args: []string{"--ccm-verbosity=4"},
+		expected: controllers.OperandOverrides{
+			CCMVerbosity: ptr.To(4),
+		},
+	}, {
+		name:          "Negative CCM verbosity",
+		args:          []string{"--ccm-verbosity=-1"},
+		expectedError: "-1 should not be negative",
+	}, {
+		name: "Configure cloud routes",
+		args: []string{"--configure-cloud-routes=true"},
+		expected: controllers.OperandOverrides{
+			ConfigureCloudRoutes: ptr.To(true),
+		},
+	}, {
+		name: "Extra host path mounts",
+		args: []string{`--extra-host-path-mounts=[{"name":"creds","hostPath":"/var/lib/creds","mountPath":"/creds","type":"Directory","readOnly":true}]`},
+		expected: controllers.OperandOverrides{
+			ExtraHostPathMounts: []operatorconfig.HostPathMount{{
+				Name:      "creds",
+				HostPath:  "/var/lib/creds",
+				MountPath: "/creds",
+				Type:      corev1.HostPathDirectory,
+				ReadOnly:  true,
+			}},
+		},
+	}, {
+		name:          "Unknown extra host path mount field",
+		args:          []string{`--extra-host-path-mounts=[{"name":"creds","hostpath":"/var/lib/creds","mountDir":"/creds"}]`},
+		expectedError: `unknown field "mountDir"`,
+	}, {
+		name: "PDB policy",
+		args: []string{`--pdb-policy={"type":"MaxUnavailable","value":1}`},
+		expected: controllers.OperandOverrides{
+			PDBPolicy: &operatorconfig.PDBPolicy{
+				Type:  operatorconfig.PDBPolicyMaxUnavailable,
+				Value: intstr.FromInt32(1),
+			},
+		},
+	}, {
+		name: "Extra node manager args",
+		args: []string{"--extra-node-manager-arg=--node-status-update-frequency=1m", "--extra-node-manager-arg=--wait-routes"},
+		expected: controllers.OperandOverrides{
+			ExtraNodeManagerArgs: []string{"--node-status-update-frequency=1m", "--wait-routes"},
+		},
+	}, {
+		name: "Service account annotations",
+		args: []string{`--service-account-annotations={"eks.amazonaws.com/role-arn":"arn:aws:iam::123456789012:role/ccm"}`},
+		expected: controllers.OperandOverrides{
+			ServiceAccountAnnotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/ccm"},
+		},
+	}, {
+		name:          "Malformed service account annotations",
+		args:          []string{`--service-account-annotations={"eks.amazonaws.com/role-arn":1}`},
+		expectedError: "cannot unmarshal number",
+	}, {
+		name: "Init container resources",
+		args: []string{`--init-container-resources={"requests":{"cpu":"20m","memory":"20Mi"}}`},
+		expected: controllers.OperandOverrides{
+			InitContainerResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("20m"),
+					corev1.ResourceMemory: resource.MustParse("20Mi"),
+				},
+			},
+		},
+	}, {
+		name: "Node manager priority class",
+		args: []string{"--node-manager-priority-class=node-manager-critical"},
+		expected: controllers.OperandOverrides{
+			NodeManagerPriorityClassName: "node-manager-critical",
+		},
+	}, {
+		name: "Node manager min ready seconds",
+		args: []string{"--node-manager-min-ready-seconds=30"},
+		expected: controllers.OperandOverrides{
+			NodeManagerMinReadySeconds: ptr.To[int32](30),
+		},
+	}, {
+		name:          "Node manager min ready seconds out of range",
+		args:          []string{"--node-manager-min-ready-seconds=4294967296"},
+		expectedError: "value out of range",
+	}, {
+		name: "Cloud API readiness probe",
+		args: []string{"--cloud-api-readiness-probe"},
+		expected: controllers.OperandOverrides{
+			CloudAPIReadinessProbe: true,
+		},
+	}, {
+		name: "Operand log format",
+		args: []string{"--operand-log-format=json"},
+		expected: controllers.OperandOverrides{
+			LoggingFormat: operatorconfig.LoggingFormatJSON,
+		},
+	}, {
+		name:          "Unsupported operand log format",
+		args:          []string{"--operand-log-format=logfmt"},
+		expectedError: `unsupported logging format "logfmt"`,
+	}, {
+		name: "CCM resource autosizing",
+		args: []string{`--ccm-resource-autosizing={"min":{"cpu":"200m"},"perNode":{"cpu":"2m"},"max":{"cpu":"1"},"nodeBucketSize":5}`},
+		expected: controllers.OperandOverrides{
+			ResourceAutosizing: &operatorconfig.ResourceAutosizing{
+				Min:            corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+				PerNode:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2m")},
+				Max:            corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				NodeBucketSize: 5,
+			},
+		},
+	}, {
+		name: "Cloud endpoint overrides",
+		args: []string{
+			"--cloud-endpoint-override=AWS_ENDPOINT_URL_EC2=https://ec2.example.com",
+			"--cloud-endpoint-override=AWS_ENDPOINT_URL_ELB=https://elb.example.com/?region=a",
+		},
+		expected: controllers.OperandOverrides{
+			CloudEndpointOverrides: []operatorconfig.CloudEndpointOverride{
+				{EnvName: "AWS_ENDPOINT_URL_EC2", URL: "https://ec2.example.com"},
+				{EnvName: "AWS_ENDPOINT_URL_ELB", URL: "https://elb.example.com/?region=a"},
+			},
+		},
+	}, {
+		name:          "Cloud endpoint override without URL",
+		args:          []string{"--cloud-endpoint-override=AWS_ENDPOINT_URL_EC2"},
+		expectedError: `expected ENV=URL, got "AWS_ENDPOINT_URL_EC2"`,
+	}, {
+		name: "Extra volumes and mounts",
+		args: []string{
+			`--extra-volumes=[{"name":"custom-ca","configMap":{"name":"custom-ca"}}]`,
+			`--extra-volume-mounts=[{"name":"custom-ca","mountPath":"/etc/custom-ca","readOnly":true}]`,
+		},
+		expected: controllers.OperandOverrides{
+			ExtraVolumes: []corev1.Volume{{
+				Name: "custom-ca",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "custom-ca"},
+					},
+				},
+			}},
+			ExtraVolumeMounts: []corev1.VolumeMount{{
+				Name:      "custom-ca",
+				MountPath: "/etc/custom-ca",
+				ReadOnly:  true,
+			}},
+		},
+	}, {
+		name: "Startup timeouts",
+		args: []string{"--startup-timeouts=AWS=2m, VSphere=0"},
+		expected: controllers.OperandOverrides{
+			StartupTimeouts: func() map[configv1.PlatformType]time.Duration {
+				timeouts := operatorconfig.DefaultStartupTimeouts()
+				timeouts[configv1.AWSPlatformType] = 2 * time.Minute
+				timeouts[configv1.VSpherePlatformType] = 0
+				return timeouts
+			}(),
+		},
+	}, {
+		name:          "Malformed startup timeouts",
+		args:          []string{"--startup-timeouts=AWS"},
+		expectedError: `expected PLATFORM=DURATION, got "AWS"`,
+	}, {
+		name: "Azure credentials secret",
+		args: []string{"--azure-credentials-secret=azure-workload-identity"},
+		expected: controllers.OperandOverrides{
+			AzureCredentials: &operatorconfig.AzureCredentials{SecretName: "azure-workload-identity"},
+		},
+	}, {
+		name: "Azure credentials secret and file",
+		args: []string{"--azure-credentials-file=/etc/azure/credentials.json", "--azure-credentials-secret=azure-workload-identity"},
+		expected: controllers.OperandOverrides{
+			AzureCredentials: &operatorconfig.AzureCredentials{
+				SecretName: "azure-workload-identity",
+				FilePath:   "/etc/azure/credentials.json",
+			},
+		},
+	}, {
+		name: "Operand sysctls",
+		args: []string{`--operand-sysctls={"AWS":[{"name":"kernel.shm_rmid_forced","value":"1"}]}`},
+		expected: controllers.OperandOverrides{
+			PodSysctls: map[configv1.PlatformType]operatorconfig.Sysctls{
+				configv1.AWSPlatformType: {{Name: "kernel.shm_rmid_forced", Value: "1"}},
+			},
+		},
+	}, {
+		name: "Operand host aliases",
+		args: []string{`--operand-host-aliases=[{"ip":"10.0.0.10","hostnames":["ec2.us-east-1.amazonaws.com"]}]`},
+		expected: controllers.OperandOverrides{
+			HostAliases: []corev1.HostAlias{{IP: "10.0.0.10", Hostnames: []string{"ec2.us-east-1.amazonaws.com"}}},
+		},
+	}, {
+		name: "Operand affinity",
+		args: []string{`--operand-affinity={"nodeAffinity":{"requiredDuringSchedulingIgnoredDuringExecution":{"nodeSelectorTerms":[{"matchExpressions":[{"key":"topology.kubernetes.io/zone","operator":"In","values":["a"]}]}]}}}`},
+		expected: controllers.OperandOverrides{
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key:      "topology.kubernetes.io/zone",
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{"a"},
+							}},
+						}},
+					},
+				},
+			},
+		},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			overrides := controllers.OperandOverrides{}
+			addOperandOverrideFlags(fs, &overrides)
+
+			err := fs.Parse(tc.args)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expected.StartupTimeouts == nil {
+				tc.expected.StartupTimeouts = operatorconfig.DefaultStartupTimeouts()
+			}
+			assert.Equal(t, tc.expected, overrides)
+		})
+	}
+}
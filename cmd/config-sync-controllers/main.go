@@ -18,23 +18,31 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/spf13/pflag"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/component-base/config"
 	"k8s.io/component-base/config/options"
+	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/textlogger"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
@@ -46,7 +54,16 @@ import (
 	// +kubebuilder:scaffold:imports
 )
 
+const (
+	// Names of controllers which can be selected with the controllers flag.
+	cloudConfigSyncControllerName = "cloud-config-sync"
+	trustedCABundleControllerName = "trusted-ca-bundle"
+)
+
 var (
+	// knownControllers lists controllers the binary runs, all of them are run by default.
+	knownControllers = []string{cloudConfigSyncControllerName, trustedCABundleControllerName}
+
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
 
@@ -63,10 +80,36 @@ func init() {
 	// +kubebuilder:scaffold:scheme
 }
 
+// parseControllers returns names of the controllers selected by the given comma-separated list.
+// Unknown names are rejected, as well as a list which does not select any controller.
+func parseControllers(names string) (sets.Set[string], error) {
+	selected := sets.New[string]()
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(knownControllers, name) {
+			return nil, fmt.Errorf("unknown controller %q, expected one of %s", name, strings.Join(knownControllers, ", "))
+		}
+		selected.Insert(name)
+	}
+	if selected.Len() == 0 {
+		return nil, fmt.Errorf("no controllers selected, expected any of %s", strings.Join(knownControllers, ", "))
+	}
+	return selected, nil
+}
+
 func main() {
 	textLoggerCfg := textlogger.NewConfig()
 	textLoggerCfg.AddFlags(flag.CommandLine)
 
+	metricsAddr := flag.String(
+		"metrics-bind-address",
+		"0",
+		"Address for hosting metrics, such as cloud config transform failures. Disabled by default.",
+	)
+
 	healthAddr := flag.String(
 		"health-addr",
 		":9440",
@@ -79,13 +122,48 @@ func main() {
 		"The namespace for managed objects, target cloud-conf in particular.",
 	)
 
+	cloudConfigSecretName := flag.String(
+		"cloud-config-secret",
+		"",
+		"The name of a Secret in the openshift-config namespace to source the cloud config from instead of the ConfigMap referenced by the infrastructure resource. The Secret must not carry credentials, since the config is synced into a ConfigMap.",
+	)
+
+	controllerNames := flag.String(
+		"controllers",
+		strings.Join(knownControllers, ","),
+		fmt.Sprintf("Comma-separated list of controllers to run, any of: %s.", strings.Join(knownControllers, ", ")),
+	)
+
+	shutdownGracePeriod := flag.Duration(
+		"shutdown-grace-period",
+		30*time.Second,
+		"How long in-flight reconciles may take to complete once the manager is stopped, so operands are not left half applied.",
+	)
+
+	logFormat := flag.String(
+		"log-format",
+		util.LogFormatText,
+		fmt.Sprintf("The log format, one of: %s, %s.", util.LogFormatText, util.LogFormatJSON),
+	)
+
 	// Once all the flags are regitered, switch to pflag
 	// to allow leader lection flags to be bound
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	options.BindLeaderElectionFlags(&leaderElectionConfig, pflag.CommandLine)
 	pflag.Parse()
 
-	ctrl.SetLogger(textlogger.NewLogger(textLoggerCfg).WithName("CCCMOConfigSyncControllers"))
+	logger, err := util.NewLogger(*logFormat, func() logr.Logger { return textlogger.NewLogger(textLoggerCfg) })
+	if err != nil {
+		klog.Errorf("unable to set up logging: %v", err)
+		os.Exit(1)
+	}
+	ctrl.SetLogger(logger.WithName("CCCMOConfigSyncControllers"))
+
+	enabledControllers, err := parseControllers(*controllerNames)
+	if err != nil {
+		setupLog.Error(err, "invalid controllers selection")
+		os.Exit(1)
+	}
 
 	restConfig := ctrl.GetConfigOrDie()
 	le := util.GetLeaderElectionDefaults(restConfig, configv1.LeaderElection{
@@ -104,11 +182,19 @@ func main() {
 			controllers.OpenshiftConfigNamespace:        {},
 			controllers.OpenshiftManagedConfigNamespace: {}},
 	}
+	if *cloudConfigSecretName != "" {
+		// Secrets are only read from the openshift-config namespace
+		cacheOptions.ByObject = map[client.Object]cache.ByObject{
+			&corev1.Secret{}: {
+				Namespaces: map[string]cache.Config{controllers.OpenshiftConfigNamespace: {}},
+			},
+		}
+	}
 
 	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme: scheme,
-		Metrics: metricsserver.Options{ // we do not expose any metric at this point
-			BindAddress: "0",
+		Metrics: metricsserver.Options{
+			BindAddress: *metricsAddr,
 		},
 		HealthProbeBindAddress: *healthAddr,
 		MapperProvider: restmapper.NewPartialRestMapperProvider(
@@ -124,6 +210,7 @@ func main() {
 		LeaseDuration:           &le.LeaseDuration.Duration,
 		RetryPeriod:             &le.RetryPeriod.Duration,
 		RenewDeadline:           &le.RenewDeadline.Duration,
+		GracefulShutdownTimeout: shutdownGracePeriod,
 		Cache:                   cacheOptions,
 	})
 	if err != nil {
@@ -131,30 +218,37 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controllers.CloudConfigReconciler{
-		ClusterOperatorStatusClient: controllers.ClusterOperatorStatusClient{
-			Client:           mgr.GetClient(),
-			Recorder:         mgr.GetEventRecorderFor("cloud-controller-manager-operator-cloud-config-sync-controller"),
-			ReleaseVersion:   controllers.GetReleaseVersion(),
-			ManagedNamespace: *managedNamespace,
-		},
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create cloud-config sync controller", "controller", "ClusterOperator")
-		os.Exit(1)
+	if enabledControllers.Has(cloudConfigSyncControllerName) {
+		if err = (&controllers.CloudConfigReconciler{
+			ClusterOperatorStatusClient: controllers.ClusterOperatorStatusClient{
+				Client:              mgr.GetClient(),
+				Recorder:            mgr.GetEventRecorderFor("cloud-controller-manager-operator-cloud-config-sync-controller"),
+				ReleaseVersion:      controllers.GetReleaseVersion(),
+				ManagedNamespace:    *managedNamespace,
+				ShutdownGracePeriod: *shutdownGracePeriod,
+			},
+			Scheme:                mgr.GetScheme(),
+			CloudConfigSecretName: *cloudConfigSecretName,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create cloud-config sync controller", "controller", "ClusterOperator")
+			os.Exit(1)
+		}
 	}
 
-	if err = (&controllers.TrustedCABundleReconciler{
-		ClusterOperatorStatusClient: controllers.ClusterOperatorStatusClient{
-			Client:           mgr.GetClient(),
-			Recorder:         mgr.GetEventRecorderFor("cloud-controller-manager-operator-ca-sync-controller"),
-			ReleaseVersion:   controllers.GetReleaseVersion(),
-			ManagedNamespace: *managedNamespace,
-		},
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create Trusted CA sync controller", "controller", "ClusterOperator")
-		os.Exit(1)
+	if enabledControllers.Has(trustedCABundleControllerName) {
+		if err = (&controllers.TrustedCABundleReconciler{
+			ClusterOperatorStatusClient: controllers.ClusterOperatorStatusClient{
+				Client:              mgr.GetClient(),
+				Recorder:            mgr.GetEventRecorderFor("cloud-controller-manager-operator-ca-sync-controller"),
+				ReleaseVersion:      controllers.GetReleaseVersion(),
+				ManagedNamespace:    *managedNamespace,
+				ShutdownGracePeriod: *shutdownGracePeriod,
+			},
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create Trusted CA sync controller", "controller", "ClusterOperator")
+			os.Exit(1)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
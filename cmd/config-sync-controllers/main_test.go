@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestParseControllers(t *testing.T) {
+	tc := []struct {
+		name                string
+		controllers         string
+		expectedControllers sets.Set[string]
+		expectedError       string
+	}{{
+		name:                "Both controllers",
+		controllers:         "cloud-config-sync,trusted-ca-bundle",
+		expectedControllers: sets.New("cloud-config-sync", "trusted-ca-bundle"),
+	}, {
+		name:                "Single controller",
+		controllers:         "trusted-ca-bundle",
+		expectedControllers: sets.New("trusted-ca-bundle"),
+	}, {
+		name:                "Spaces and empty entries are ignored",
+		controllers:         " cloud-config-sync, ,",
+		expectedControllers: sets.New("cloud-config-sync"),
+	}, {
+		name:          "Unknown controller",
+		controllers:   "cloud-config-sync,kube-controller-manager",
+		expectedError: `unknown controller "kube-controller-manager", expected one of cloud-config-sync, trusted-ca-bundle`,
+	}, {
+		name:          "No controllers",
+		controllers:   "",
+		expectedError: "no controllers selected, expected any of cloud-config-sync, trusted-ca-bundle",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			controllers, err := parseControllers(tc.controllers)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedControllers, controllers)
+		})
+	}
+}
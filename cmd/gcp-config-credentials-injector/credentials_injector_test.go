@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeCommand(root *cobra.Command, args ...string) (output string, err error) {
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(buf)
+	root.SetArgs(args)
+
+	_, err = root.ExecuteC()
+
+	return buf.String(), err
+}
+
+func Test_mergeCredentialsConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	templateFile := filepath.Join(tmpDir, "service_account.json")
+	tokenFile := filepath.Join(tmpDir, "token")
+	outputFile := filepath.Join(tmpDir, "application_default_credentials.json")
+
+	cleanupOpts := func() {
+		injectorOpts.credentialsTemplateFilePath = ""
+		injectorOpts.tokenFilePath = ""
+		injectorOpts.outputFilePath = ""
+	}
+
+	testCases := []struct {
+		name            string
+		templateContent string
+		tokenContent    string
+		expectedContent string
+		expectedErrMsg  string
+	}{
+		{
+			name:            "all ok, token file is injected",
+			templateContent: `{"type": "external_account", "audience": "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/provider", "subject_token_type": "urn:ietf:params:oauth:token-type:jwt", "token_url": "https://sts.googleapis.com/v1/token"}`,
+			tokenContent:    "token",
+			expectedContent: `{"audience":"//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/provider","credential_source":{"file":"` + tokenFile + `","format":{"type":"text"}},"subject_token_type":"urn:ietf:params:oauth:token-type:jwt","token_url":"https://sts.googleapis.com/v1/token","type":"external_account"}`,
+		},
+		{
+			name:            "all ok, token file of the template is replaced and other fields are kept",
+			templateContent: `{"type": "external_account", "audience": "aud", "subject_token_type": "jwt", "token_url": "https://sts.googleapis.com/v1/token", "service_account_impersonation_url": "https://iamcredentials.googleapis.com/v1/sa", "credential_source": {"file": "/var/run/token", "format": {"type": "json", "subject_token_field_name": "token"}}}`,
+			tokenContent:    "token",
+			expectedContent: `{"audience":"aud","credential_source":{"file":"` + tokenFile + `","format":{"subject_token_field_name":"token","type":"json"}},"service_account_impersonation_url":"https://iamcredentials.googleapis.com/v1/sa","subject_token_type":"jwt","token_url":"https://sts.googleapis.com/v1/token","type":"external_account"}`,
+		},
+		{
+			name:           "should fail, template file does not exist",
+			tokenContent:   "token",
+			expectedErrMsg: "stat " + templateFile + ": no such file or directory",
+		},
+		{
+			name:            "should fail, token file does not exist",
+			templateContent: `{"type": "external_account", "audience": "aud", "subject_token_type": "jwt", "token_url": "https://sts.googleapis.com/v1/token"}`,
+			expectedErrMsg:  "couldn't find projected service account token: stat " + tokenFile + ": no such file or directory",
+		},
+		{
+			name:            "should fail, template is not an external account config",
+			templateContent: `{"type": "service_account", "private_key": "key"}`,
+			tokenContent:    "token",
+			expectedErrMsg:  `couldn't prepare credentials config: type should be "external_account", got "service_account"`,
+		},
+		{
+			name:            "should fail, audience missing",
+			templateContent: `{"type": "external_account", "subject_token_type": "jwt", "token_url": "https://sts.googleapis.com/v1/token"}`,
+			tokenContent:    "token",
+			expectedErrMsg:  "couldn't prepare credentials config: audience should be set up",
+		},
+		{
+			name:            "should fail, credential source is malformed",
+			templateContent: `{"type": "external_account", "audience": "aud", "subject_token_type": "jwt", "token_url": "https://sts.googleapis.com/v1/token", "credential_source": "file"}`,
+			tokenContent:    "token",
+			expectedErrMsg:  "couldn't prepare credentials config: credential_source should be an object",
+		},
+		{
+			name:            "should fail, template is malformed",
+			templateContent: `{"type": `,
+			tokenContent:    "token",
+			expectedErrMsg:  "couldn't read credentials template from file: unexpected end of JSON input",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.templateContent != "" {
+				require.NoError(t, os.WriteFile(templateFile, []byte(tc.templateContent), 0644))
+				defer os.Remove(templateFile)
+			}
+			if tc.tokenContent != "" {
+				require.NoError(t, os.WriteFile(tokenFile, []byte(tc.tokenContent), 0600))
+				defer os.Remove(tokenFile)
+			}
+			defer os.Remove(outputFile)
+			defer cleanupOpts()
+
+			_, err := executeCommand(injectorCmd, "--credentials-template-file-path", templateFile,
+				"--token-file-path", tokenFile, "--output-file-path", outputFile)
+
+			if tc.expectedErrMsg != "" {
+				require.NotNil(t, err, "Error was expected but not returned by `mergeCredentialsConfig` function")
+				assert.Equal(t, tc.expectedErrMsg, err.Error())
+				_, statErr := os.Stat(outputFile)
+				assert.True(t, os.IsNotExist(statErr), "Output file should not be written on failure")
+				return
+			}
+
+			require.NoError(t, err)
+			fileContent, err := os.ReadFile(outputFile)
+			require.NoError(t, err, "Cannot read output file")
+			assert.Equal(t, tc.expectedContent, string(fileContent))
+
+			entries, err := os.ReadDir(tmpDir)
+			require.NoError(t, err)
+			for _, entry := range entries {
+				assert.NotContains(t, entry.Name(), ".tmp-", "Temporary files should be cleaned up")
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+)
+
+const (
+	externalAccountCredentialsType = "external_account"
+
+	// Keys of the external account credential config read by Google client libraries.
+	typeConfigKey             = "type"
+	audienceConfigKey         = "audience"
+	subjectTokenTypeConfigKey = "subject_token_type"
+	tokenURLConfigKey         = "token_url"
+	credentialSourceConfigKey = "credential_source"
+
+	credentialSourceFileKey   = "file"
+	credentialSourceFormatKey = "format"
+)
+
+var (
+	injectorCmd = &cobra.Command{
+		Use:   "gcp-config-credentials-injector [OPTIONS]",
+		Short: "Workload identity federation credentials injection tool for gcp cloud platform",
+		RunE:  mergeCredentialsConfig,
+	}
+
+	injectorOpts struct {
+		credentialsTemplateFilePath string
+		tokenFilePath               string
+		outputFilePath              string
+	}
+
+	// requiredConfigKeys must be set in the credentials template for Google client libraries
+	// to exchange the projected token for cloud credentials.
+	requiredConfigKeys = []string{audienceConfigKey, subjectTokenTypeConfigKey, tokenURLConfigKey}
+)
+
+func init() {
+	klog.InitFlags(flag.CommandLine)
+	injectorCmd.PersistentFlags().AddGoFlagSet(flag.CommandLine)
+	injectorCmd.PersistentFlags().StringVar(&injectorOpts.credentialsTemplateFilePath, "credentials-template-file-path", "/tmp/credentials-template/service_account.json", "Location of the external account credential config template.")
+	injectorCmd.PersistentFlags().StringVar(&injectorOpts.tokenFilePath, "token-file-path", "/var/run/secrets/openshift/serviceaccount/token", "Location of the projected service account token exchanged for cloud credentials.")
+	injectorCmd.PersistentFlags().StringVar(&injectorOpts.outputFilePath, "output-file-path", "/tmp/merged-credentials/application_default_credentials.json", "Location of the generated credential config file.")
+}
+
+func main() {
+	if err := injectorCmd.Execute(); err != nil {
+		klog.Fatal(err)
+	}
+}
+
+func mergeCredentialsConfig(_ *cobra.Command, args []string) error {
+	if _, err := os.Stat(injectorOpts.credentialsTemplateFilePath); os.IsNotExist(err) {
+		return err
+	}
+
+	if _, err := os.Stat(injectorOpts.tokenFilePath); err != nil {
+		return fmt.Errorf("couldn't find projected service account token: %w", err)
+	}
+
+	credentialsConfig, err := readCredentialsConfig(injectorOpts.credentialsTemplateFilePath)
+	if err != nil {
+		return fmt.Errorf("couldn't read credentials template from file: %w", err)
+	}
+
+	preparedCredentialsConfig, err := prepareCredentialsConfig(credentialsConfig, injectorOpts.tokenFilePath)
+	if err != nil {
+		return fmt.Errorf("couldn't prepare credentials config: %w", err)
+	}
+
+	if err := writeCredentialsConfig(injectorOpts.outputFilePath, preparedCredentialsConfig); err != nil {
+		return fmt.Errorf("couldn't write prepared credentials config to file: %w", err)
+	}
+
+	return nil
+}
+
+func readCredentialsConfig(path string) (map[string]interface{}, error) {
+	var data map[string]interface{}
+
+	rawData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(rawData, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// prepareCredentialsConfig validates the external account credential config template and points
+// its credential source to the projected token file. Other fields of the credential source, such as
+// the token format, are kept.
+func prepareCredentialsConfig(credentialsConfig map[string]interface{}, tokenFilePath string) ([]byte, error) {
+	if credentialsType, _ := credentialsConfig[typeConfigKey].(string); credentialsType != externalAccountCredentialsType {
+		return nil, fmt.Errorf("%s should be %q, got %q", typeConfigKey, externalAccountCredentialsType, credentialsConfig[typeConfigKey])
+	}
+	for _, key := range requiredConfigKeys {
+		if value, _ := credentialsConfig[key].(string); len(value) == 0 {
+			return nil, fmt.Errorf("%s should be set up", key)
+		}
+	}
+
+	credentialSource := map[string]interface{}{}
+	if value, found := credentialsConfig[credentialSourceConfigKey]; found {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s should be an object", credentialSourceConfigKey)
+		}
+		credentialSource = source
+	}
+	if previous, found := credentialSource[credentialSourceFileKey]; found && previous != tokenFilePath {
+		klog.V(4).Infof("%s.%s %v replaced with %s", credentialSourceConfigKey, credentialSourceFileKey, previous, tokenFilePath)
+	}
+	credentialSource[credentialSourceFileKey] = tokenFilePath
+	if _, found := credentialSource[credentialSourceFormatKey]; !found {
+		credentialSource[credentialSourceFormatKey] = map[string]interface{}{"type": "text"}
+	}
+	credentialsConfig[credentialSourceConfigKey] = credentialSource
+
+	marshalled, err := json.Marshal(credentialsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalled, nil
+}
+
+// writeCredentialsConfig writes the config to a temporary file next to the given path and renames it,
+// so consumers never read a partially written config.
+func writeCredentialsConfig(path string, preparedConfig []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(preparedConfig); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Chmod(0644); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
@@ -0,0 +1,139 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	ini "gopkg.in/ini.v1"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+const (
+	globalSectionName          = "Global"
+	serviceOverrideSectionName = "ServiceOverride"
+	nodeIPFamiliesKeyName      = "NodeIPFamilies"
+)
+
+// CloudConfigTransformer implements the cloudConfigTransformer. It takes the user-provided
+// aws-cloud-controller-manager configuration and sets service endpoint overrides from the
+// Infrastructure resource, which clusters in isolated regions (e.g. GovCloud or C2S) rely on.
+// Service overrides already present in the source config are replaced.
+// On IPv6 and dual-stack clusters it also sets the IP families of node addresses, primary family first,
+// from the Network resource.
+// The source config is returned as is when nothing has to be set, since writing the config back
+// reformats it.
+// It returns an error if the platform is not AWSPlatformType, if the source config can not be parsed
+// or if a service endpoint is listed more than once.
+func CloudConfigTransformer(source string, infra *configv1.Infrastructure, network *configv1.Network) (string, error) {
+	if infra.Status.PlatformStatus == nil ||
+		infra.Status.PlatformStatus.Type != configv1.AWSPlatformType {
+		return "", fmt.Errorf("invalid platform, expected to be %s", configv1.AWSPlatformType)
+	}
+
+	// Keys like NodeIPFamilies may be repeated to list multiple values, keep all of them
+	cfg, err := ini.LoadSources(ini.LoadOptions{AllowShadows: true}, []byte(source))
+	if err != nil {
+		return "", fmt.Errorf("failed to read the cloud.conf: %w", err)
+	}
+
+	global, err := cfg.GetSection(globalSectionName)
+	if err != nil {
+		if global, err = cfg.NewSection(globalSectionName); err != nil {
+			return "", fmt.Errorf("failed to modify the provided configuration: %w", err)
+		}
+	}
+
+	changed, err := setNodeIPFamilies(global, config.GetIPFamilies(network))
+	if err != nil {
+		return "", err
+	}
+
+	awsStatus := infra.Status.PlatformStatus.AWS
+	if awsStatus != nil && len(awsStatus.ServiceEndpoints) > 0 {
+		if err := setServiceOverrides(cfg, awsStatus); err != nil {
+			return "", err
+		}
+		changed = true
+	}
+
+	if !changed {
+		return source, nil
+	}
+
+	var buf bytes.Buffer
+	if _, err := cfg.WriteTo(&buf); err != nil {
+		return "", fmt.Errorf("failed to modify the provided configuration: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// setNodeIPFamilies replaces NodeIPFamilies keys of the global section with a key per IP family,
+// primary family first. IPv4 single-stack clusters are left untouched, as IPv4 is what
+// aws-cloud-controller-manager defaults to. It returns whether the section changed.
+func setNodeIPFamilies(global *ini.Section, families []string) (bool, error) {
+	if len(families) == 0 || slices.Equal(families, []string{"ipv4"}) {
+		return false, nil
+	}
+
+	if global.HasKey(nodeIPFamiliesKeyName) {
+		if slices.Equal(global.Key(nodeIPFamiliesKeyName).ValueWithShadows(), families) {
+			return false, nil
+		}
+		klog.Infof("%s key found; replacing it with IP families of the network resource", nodeIPFamiliesKeyName)
+		global.DeleteKey(nodeIPFamiliesKeyName)
+	}
+	key, err := global.NewKey(nodeIPFamiliesKeyName, families[0])
+	if err != nil {
+		return false, fmt.Errorf("failed to modify the provided configuration: %w", err)
+	}
+	for _, family := range families[1:] {
+		if err := key.AddShadow(family); err != nil {
+			return false, fmt.Errorf("failed to modify the provided configuration: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// setServiceOverrides replaces ServiceOverride sections of the config with ones for the service endpoints
+// of the platform status, signed with the cluster region.
+func setServiceOverrides(cfg *ini.File, awsStatus *configv1.AWSPlatformStatus) error {
+	seen := make(map[string]bool, len(awsStatus.ServiceEndpoints))
+	for _, endpoint := range awsStatus.ServiceEndpoints {
+		if seen[endpoint.Name] {
+			return fmt.Errorf("duplicate service endpoint name %q", endpoint.Name)
+		}
+		seen[endpoint.Name] = true
+	}
+
+	for _, name := range cfg.SectionStrings() {
+		if strings.HasPrefix(name, serviceOverrideSectionName) {
+			klog.Infof("[%s] section found; replacing it with service endpoints from the infrastructure resource", name)
+			cfg.DeleteSection(name)
+		}
+	}
+
+	for i, endpoint := range awsStatus.ServiceEndpoints {
+		section, err := cfg.NewSection(fmt.Sprintf("%s %s", serviceOverrideSectionName, strconv.Quote(strconv.Itoa(i))))
+		if err != nil {
+			return fmt.Errorf("failed to modify the provided configuration: %w", err)
+		}
+		// Use a slice to preserve keys order
+		for _, o := range []struct{ k, v string }{
+			{"Service", endpoint.Name},
+			{"Region", awsStatus.Region},
+			{"URL", endpoint.URL},
+			{"SigningRegion", awsStatus.Region},
+		} {
+			if _, err := section.NewKey(o.k, o.v); err != nil {
+				return fmt.Errorf("failed to modify the provided configuration: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package aws
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makeInfrastructureResource(platform configv1.PlatformType, endpoints []configv1.AWSServiceEndpoint) *configv1.Infrastructure {
+	infra := &configv1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Status: configv1.InfrastructureStatus{
+			PlatformStatus: &configv1.PlatformStatus{
+				Type: platform,
+			},
+		},
+	}
+	if platform == configv1.AWSPlatformType {
+		infra.Status.PlatformStatus.AWS = &configv1.AWSPlatformStatus{
+			Region:           "us-gov-west-1",
+			ServiceEndpoints: endpoints,
+		}
+	}
+	return infra
+}
+
+func makeNetworkResource(serviceNetwork ...string) *configv1.Network {
+	return &configv1.Network{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Spec: configv1.NetworkSpec{
+			ServiceNetwork: serviceNetwork,
+		},
+	}
+}
+
+func TestCloudConfigTransformer(t *testing.T) {
+	tc := []struct {
+		name     string
+		source   string
+		infra    *configv1.Infrastructure
+		network  *configv1.Network
+		expected string
+		errMsg   string
+	}{
+		{
+			name:   "No service endpoints keeps config untouched",
+			source: "[Global]\nZone = us-gov-west-1a\n",
+			infra:  makeInfrastructureResource(configv1.AWSPlatformType, nil),
+			expected: `[Global]
+Zone = us-gov-west-1a
+`,
+		},
+		{
+			name:   "Repeated keys are kept",
+			source: "[Global]\nNodeIPFamilies = ipv4\nNodeIPFamilies = ipv6\n",
+			infra: makeInfrastructureResource(configv1.AWSPlatformType, []configv1.AWSServiceEndpoint{
+				{Name: "ec2", URL: "https://ec2.us-gov-west-1.amazonaws.com"},
+			}),
+			expected: `[Global]
+NodeIPFamilies = ipv4
+NodeIPFamilies = ipv6
+
+[ServiceOverride "0"]
+Service       = ec2
+Region        = us-gov-west-1
+URL           = https://ec2.us-gov-west-1.amazonaws.com
+SigningRegion = us-gov-west-1
+`,
+		},
+		{
+			name:     "Config without changes is not reformatted",
+			source:   "; managed by the installer\n[Global]\nZone=us-gov-west-1a\nNodeIPFamilies   =   ipv4\n\n\n[Custom]\nKey=value",
+			infra:    makeInfrastructureResource(configv1.AWSPlatformType, nil),
+			expected: "; managed by the installer\n[Global]\nZone=us-gov-west-1a\nNodeIPFamilies   =   ipv4\n\n\n[Custom]\nKey=value",
+		},
+		{
+			name:     "Matching node IP families are not reformatted",
+			source:   "[Global]\nZone=us-gov-west-1a\nNodeIPFamilies=ipv6\nNodeIPFamilies=ipv4\n",
+			infra:    makeInfrastructureResource(configv1.AWSPlatformType, nil),
+			network:  makeNetworkResource("fd02::/112", "172.30.0.0/16"),
+			expected: "[Global]\nZone=us-gov-west-1a\nNodeIPFamilies=ipv6\nNodeIPFamilies=ipv4\n",
+		},
+		{
+			name:     "Empty source is passed through",
+			source:   "",
+			infra:    makeInfrastructureResource(configv1.AWSPlatformType, nil),
+			expected: "",
+		},
+		{
+			name:   "Global section is created when missing",
+			source: "",
+			infra: makeInfrastructureResource(configv1.AWSPlatformType, []configv1.AWSServiceEndpoint{
+				{Name: "ec2", URL: "https://ec2.us-gov-west-1.amazonaws.com"},
+			}),
+			expected: `[Global]
+
+[ServiceOverride "0"]
+Service       = ec2
+Region        = us-gov-west-1
+URL           = https://ec2.us-gov-west-1.amazonaws.com
+SigningRegion = us-gov-west-1
+`,
+		},
+		{
+			name:   "Single service endpoint override",
+			source: "[Global]\n",
+			infra: makeInfrastructureResource(configv1.AWSPlatformType, []configv1.AWSServiceEndpoint{
+				{Name: "ec2", URL: "https://ec2.us-gov-west-1.amazonaws.com"},
+			}),
+			expected: `[Global]
+
+[ServiceOverride "0"]
+Service       = ec2
+Region        = us-gov-west-1
+URL           = https://ec2.us-gov-west-1.amazonaws.com
+SigningRegion = us-gov-west-1
+`,
+		},
+		{
+			name:   "Multiple service endpoint overrides replace existing ones",
+			source: "[Global]\nZone = us-gov-west-1a\n\n[ServiceOverride \"0\"]\nService = s3\nRegion = us-gov-west-1\nURL = https://s3.example.com\nSigningRegion = us-gov-west-1\n",
+			infra: makeInfrastructureResource(configv1.AWSPlatformType, []configv1.AWSServiceEndpoint{
+				{Name: "ec2", URL: "https://ec2.us-gov-west-1.amazonaws.com"},
+				{Name: "elasticloadbalancing", URL: "https://elasticloadbalancing.us-gov-west-1.amazonaws.com"},
+			}),
+			expected: `[Global]
+Zone = us-gov-west-1a
+
+[ServiceOverride "0"]
+Service       = ec2
+Region        = us-gov-west-1
+URL           = https://ec2.us-gov-west-1.amazonaws.com
+SigningRegion = us-gov-west-1
+
+[ServiceOverride "1"]
+Service       = elasticloadbalancing
+Region        = us-gov-west-1
+URL           = https://elasticloadbalancing.us-gov-west-1.amazonaws.com
+SigningRegion = us-gov-west-1
+`,
+		},
+		{
+			name:     "IPv4 single-stack network keeps node IP families untouched",
+			source:   "[Global]\nNodeIPFamilies = ipv4\n",
+			infra:    makeInfrastructureResource(configv1.AWSPlatformType, nil),
+			network:  makeNetworkResource("172.30.0.0/16"),
+			expected: "[Global]\nNodeIPFamilies = ipv4\n",
+		},
+		{
+			name:    "IPv6-primary dual-stack network sets node IP families",
+			source:  "[Global]\nZone = us-gov-west-1a\n",
+			infra:   makeInfrastructureResource(configv1.AWSPlatformType, nil),
+			network: makeNetworkResource("fd02::/112", "172.30.0.0/16"),
+			expected: `[Global]
+Zone           = us-gov-west-1a
+NodeIPFamilies = ipv6
+NodeIPFamilies = ipv4
+`,
+		},
+		{
+			name:    "Node IP families of the source config are replaced",
+			source:  "[Global]\nNodeIPFamilies = ipv4\nNodeIPFamilies = ipv6\n",
+			infra:   makeInfrastructureResource(configv1.AWSPlatformType, nil),
+			network: makeNetworkResource("fd02::/112"),
+			expected: `[Global]
+NodeIPFamilies = ipv6
+`,
+		},
+		{
+			name:   "Duplicate service endpoint names return an error",
+			source: "[Global]\n",
+			infra: makeInfrastructureResource(configv1.AWSPlatformType, []configv1.AWSServiceEndpoint{
+				{Name: "ec2", URL: "https://ec2.us-gov-west-1.amazonaws.com"},
+				{Name: "ec2", URL: "https://ec2.example.com"},
+			}),
+			errMsg: "duplicate service endpoint name \"ec2\"",
+		},
+		{
+			name:   "Non AWS platform returns an error",
+			source: "[Global]\n",
+			infra:  makeInfrastructureResource(configv1.GCPPlatformType, nil),
+			errMsg: "invalid platform, expected to be AWS",
+		},
+	}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := CloudConfigTransformer(tc.source, tc.infra, tc.network)
+			if tc.errMsg != "" {
+				g.Expect(err).Should(MatchError(tc.errMsg))
+				return
+			}
+
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(actual).Should(Equal(tc.expected))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package azure
 
 import (
+	"bytes"
 	"embed"
 	"encoding/json"
 	"fmt"
@@ -12,7 +13,9 @@ import (
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	azureconsts "sigs.k8s.io/cloud-provider-azure/pkg/consts"
@@ -22,7 +25,13 @@ import (
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
 )
 
-const providerName = "azure"
+const (
+	providerName = "azure"
+
+	// Names of the containers running controller and node-manager images, see assets.
+	controllerContainerName  = "cloud-controller-manager"
+	nodeManagerContainerName = "cloud-node-manager"
+)
 
 var (
 	//go:embed assets/*
@@ -56,10 +65,20 @@ var (
 	}()
 )
 
+// admissionPolicyParams keeps the cloud node manager, which only sets provider and topology labels,
+// from changing node roles.
+var admissionPolicyParams = common.AdmissionPolicyParams{
+	ProtectedNodeLabels: []string{
+		"node-role.kubernetes.io/control-plane",
+		"node-role.kubernetes.io/master",
+		"node-role.kubernetes.io/worker",
+	},
+}
+
 type imagesReference struct {
-	CloudControllerManager         string `valid:"required"`
-	CloudControllerManagerOperator string `valid:"required"`
-	CloudNodeManager               string `valid:"required"`
+	CloudControllerManager string `valid:"required"`
+	CredentialsInjector    string `valid:"required"`
+	CloudNodeManager       string `valid:"required"`
 }
 
 var templateValuesValidationMap = map[string]interface{}{
@@ -90,11 +109,21 @@ func getTemplateValues(images *imagesReference, operatorConfig config.OperatorCo
 	return values, nil
 }
 
+// getOperandImages returns images of the controller and node-manager containers, which are distinct on Azure.
+func getOperandImages(images *imagesReference) common.OperandImages {
+	return common.OperandImages{
+		ControllerContainerName:  controllerContainerName,
+		ControllerImage:          images.CloudControllerManager,
+		NodeManagerContainerName: nodeManagerContainerName,
+		NodeManagerImage:         images.CloudNodeManager,
+	}
+}
+
 func NewProviderAssets(config config.OperatorConfig) (common.CloudProviderAssets, error) {
 	images := &imagesReference{
-		CloudControllerManager:         config.ImagesReference.CloudControllerManagerAzure,
-		CloudControllerManagerOperator: config.ImagesReference.CloudControllerManagerOperator,
-		CloudNodeManager:               config.ImagesReference.CloudNodeManagerAzure,
+		CloudControllerManager: config.ImagesReference.CloudControllerManagerAzure,
+		CredentialsInjector:    config.ImagesReference.GetCredentialsInjector(),
+		CloudNodeManager:       config.ImagesReference.CloudNodeManagerAzure,
 	}
 	_, err := govalidator.ValidateStruct(images)
 	if err != nil {
@@ -112,10 +141,12 @@ func NewProviderAssets(config config.OperatorConfig) (common.CloudProviderAssets
 		return nil, fmt.Errorf("can not construct template values for %s assets: %v", providerName, err)
 	}
 
-	assets.renderedResources, err = common.RenderTemplates(objTemplates, templateValues)
+	renderedResources, err := common.RenderTemplates(objTemplates, templateValues)
 	if err != nil {
 		return nil, err
 	}
+	renderedResources = common.SubstituteOperandImages(getOperandImages(images), renderedResources)
+	assets.renderedResources = common.SetAdmissionPolicyParams(admissionPolicyParams, renderedResources)
 	return assets, nil
 }
 
@@ -179,12 +210,91 @@ func CloudConfigTransformer(source string, infra *configv1.Infrastructure, netwo
 		cfg.VMType = azureconsts.VMTypeStandard
 	}
 
-	// Ensure we are using the shared health probe
-	cfg.ClusterServiceLoadBalancerHealthProbeMode = azureconsts.ClusterServiceLoadBalancerHealthProbeModeShared
+	applyTopologyTuning(&cfg, infra, network)
 
 	cfgbytes, err := json.Marshal(cfg)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal the cloud.conf: %w", err)
 	}
+	cfgbytes, err = appendUnknownKeys(cfgbytes, []byte(source))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the cloud.conf: %w", err)
+	}
 	return string(cfgbytes), nil
 }
+
+// sharedHealthProbeNetworkTypes lists network plugins serving the cluster-wide kube-proxy health endpoint,
+// which load balancer health probes check in the shared health probe mode.
+var sharedHealthProbeNetworkTypes = sets.New[string]("OVNKubernetes", "OpenShiftSDN")
+
+// applyTopologyTuning overrides a small allowlist of tuning keys depending on the cluster topology:
+//   - clusterServiceLoadBalancerHealthProbeMode is set to shared unless a third party network plugin is used,
+//     which might not serve the health endpoint.
+//   - excludeMasterFromStandardLB is disabled on single replica clusters, where the only node
+//     is a control plane node running workloads, so it has to stay in load balancer backend pools.
+//
+// Other keys are left as provided in the source config.
+func applyTopologyTuning(cfg *azure.Config, infra *configv1.Infrastructure, network *configv1.Network) {
+	if networkType := getNetworkType(network); networkType == "" || sharedHealthProbeNetworkTypes.Has(networkType) {
+		cfg.ClusterServiceLoadBalancerHealthProbeMode = azureconsts.ClusterServiceLoadBalancerHealthProbeModeShared
+	}
+
+	if infra.Status.InfrastructureTopology == configv1.SingleReplicaTopologyMode {
+		cfg.ExcludeMasterFromStandardLB = ptr.To(false)
+	}
+}
+
+// getNetworkType returns the network plugin in use, or an empty string if it is not known yet.
+func getNetworkType(network *configv1.Network) string {
+	if network == nil {
+		return ""
+	}
+	if network.Status.NetworkType != "" {
+		return network.Status.NetworkType
+	}
+	return network.Spec.NetworkType
+}
+
+// appendUnknownKeys appends keys of the source config which were dropped from the marshalled config,
+// since azure.Config does not know them, e.g. keys supported by newer cloud provider versions.
+// Appended keys are sorted, so the output is stable.
+func appendUnknownKeys(cfg []byte, source []byte) ([]byte, error) {
+	var sourceKeys, cfgKeys map[string]json.RawMessage
+	if err := json.Unmarshal(source, &sourceKeys); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(cfg, &cfgKeys); err != nil {
+		return nil, err
+	}
+
+	// Keys are matched case-insensitively, the same way json.Unmarshal matches struct fields.
+	known := sets.New[string]()
+	for key := range cfgKeys {
+		known.Insert(strings.ToLower(key))
+	}
+	var unknown []string
+	for key := range sourceKeys {
+		if !known.Has(strings.ToLower(key)) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return cfg, nil
+	}
+	slices.Sort(unknown)
+
+	out := bytes.TrimSuffix(bytes.TrimSpace(cfg), []byte("}"))
+	for _, key := range unknown {
+		if !bytes.HasSuffix(out, []byte("{")) {
+			out = append(out, ',')
+		}
+		keyBytes, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, keyBytes...)
+		out = append(out, ':')
+		out = append(out, sourceKeys[key]...)
+	}
+	return append(out, '}'), nil
+}
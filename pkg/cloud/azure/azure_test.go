@@ -9,6 +9,9 @@ import (
 	"github.com/onsi/gomega/format"
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	azureconsts "sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	azure "sigs.k8s.io/cloud-provider-azure/pkg/provider"
@@ -34,7 +37,7 @@ func TestResourcesRenderingSmoke(t *testing.T) {
 		{
 			name:       "Empty config",
 			config:     config.OperatorConfig{},
-			initErrMsg: "azure: missed images in config: CloudControllerManager: non zero value required;CloudControllerManagerOperator: non zero value required;CloudNodeManager: non zero value required",
+			initErrMsg: "azure: missed images in config: CloudControllerManager: non zero value required;CloudNodeManager: non zero value required;CredentialsInjector: non zero value required",
 		}, {
 			name: "No infra config",
 			config: config.OperatorConfig{
@@ -95,6 +98,92 @@ func TestResourcesRenderingSmoke(t *testing.T) {
 	}
 }
 
+func TestCredentialsInjectorImage(t *testing.T) {
+	tc := []struct {
+		name          string
+		images        config.ImagesReference
+		expectedImage string
+	}{{
+		name: "Operator image is used by default",
+		images: config.ImagesReference{
+			CloudControllerManagerAzure:    "CloudControllerManagerAzure",
+			CloudNodeManagerAzure:          "CloudNodeManagerAzure",
+			CloudControllerManagerOperator: "CloudControllerManagerOperator",
+		},
+		expectedImage: "CloudControllerManagerOperator",
+	}, {
+		name: "Injector image is overridden",
+		images: config.ImagesReference{
+			CloudControllerManagerAzure:    "CloudControllerManagerAzure",
+			CloudNodeManagerAzure:          "CloudNodeManagerAzure",
+			CloudControllerManagerOperator: "CloudControllerManagerOperator",
+			CredentialsInjector:            "CredentialsInjector",
+		},
+		expectedImage: "CredentialsInjector",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			assets, err := NewProviderAssets(config.OperatorConfig{
+				ManagedNamespace:   "my-cool-namespace",
+				ImagesReference:    tc.images,
+				PlatformStatus:     &configv1.PlatformStatus{Type: configv1.AzurePlatformType},
+				InfrastructureName: "infra",
+			})
+			assert.NoError(t, err)
+
+			var initContainers []corev1.Container
+			for _, resource := range assets.GetRenderedResources() {
+				switch obj := resource.(type) {
+				case *appsv1.Deployment:
+					initContainers = append(initContainers, obj.Spec.Template.Spec.InitContainers...)
+				case *appsv1.DaemonSet:
+					initContainers = append(initContainers, obj.Spec.Template.Spec.InitContainers...)
+				}
+			}
+
+			assert.Len(t, initContainers, 2)
+			for _, container := range initContainers {
+				assert.Equal(t, "azure-inject-credentials", container.Name)
+				assert.Equal(t, tc.expectedImage, container.Image)
+			}
+		})
+	}
+}
+
+func TestOperandImages(t *testing.T) {
+	assets, err := NewProviderAssets(config.OperatorConfig{
+		ManagedNamespace: "my-cool-namespace",
+		ImagesReference: config.ImagesReference{
+			CloudControllerManagerAzure:    "CloudControllerManagerAzure",
+			CloudNodeManagerAzure:          "CloudNodeManagerAzure",
+			CloudControllerManagerOperator: "CloudControllerManagerOperator",
+		},
+		PlatformStatus:     &configv1.PlatformStatus{Type: configv1.AzurePlatformType},
+		InfrastructureName: "infra",
+	})
+	assert.NoError(t, err)
+
+	containerImages := map[string]string{}
+	for _, resource := range assets.GetRenderedResources() {
+		switch obj := resource.(type) {
+		case *appsv1.Deployment:
+			for _, container := range obj.Spec.Template.Spec.Containers {
+				containerImages[container.Name] = container.Image
+			}
+		case *appsv1.DaemonSet:
+			for _, container := range obj.Spec.Template.Spec.Containers {
+				containerImages[container.Name] = container.Image
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"cloud-controller-manager": "CloudControllerManagerAzure",
+		"cloud-node-manager":       "CloudNodeManagerAzure",
+	}, containerImages)
+}
+
 func makeInfrastructureResource(platform configv1.PlatformType, cloudName configv1.AzureCloudEnvironment) *configv1.Infrastructure {
 	cfg := configv1.Infrastructure{
 		ObjectMeta: metav1.ObjectMeta{
@@ -269,3 +358,134 @@ func TestCloudConfigTransformer(t *testing.T) {
 		})
 	}
 }
+
+func TestCloudConfigTransformerTopologyTuning(t *testing.T) {
+	makeNetwork := func(networkType string) *configv1.Network {
+		return &configv1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status:     configv1.NetworkStatus{NetworkType: networkType},
+		}
+	}
+	singleReplicaInfra := makeInfrastructureResource(configv1.AzurePlatformType, configv1.AzurePublicCloud)
+	singleReplicaInfra.Status.InfrastructureTopology = configv1.SingleReplicaTopologyMode
+
+	tc := []struct {
+		name     string
+		source   string
+		infra    *configv1.Infrastructure
+		network  *configv1.Network
+		expected map[string]interface{}
+		absent   map[string]struct{}
+	}{
+		{
+			name:    "shared health probe mode is set with OVNKubernetes",
+			source:  `{"clusterServiceLoadBalancerHealthProbeMode":"servicenodeport"}`,
+			infra:   makeInfrastructureResource(configv1.AzurePlatformType, configv1.AzurePublicCloud),
+			network: makeNetwork("OVNKubernetes"),
+			expected: map[string]interface{}{
+				"clusterServiceLoadBalancerHealthProbeMode": azureconsts.ClusterServiceLoadBalancerHealthProbeModeShared,
+			},
+		},
+		{
+			name:    "health probe mode is kept with a third party network plugin",
+			source:  `{"clusterServiceLoadBalancerHealthProbeMode":"servicenodeport"}`,
+			infra:   makeInfrastructureResource(configv1.AzurePlatformType, configv1.AzurePublicCloud),
+			network: makeNetwork("Calico"),
+			expected: map[string]interface{}{
+				"clusterServiceLoadBalancerHealthProbeMode": "servicenodeport",
+			},
+		},
+		{
+			name:    "control plane nodes are kept in load balancers on single replica clusters",
+			source:  `{"excludeMasterFromStandardLB":true}`,
+			infra:   singleReplicaInfra,
+			network: makeNetwork("OVNKubernetes"),
+			expected: map[string]interface{}{
+				"excludeMasterFromStandardLB": false,
+			},
+		},
+		{
+			name:    "excludeMasterFromStandardLB is kept on highly available clusters",
+			source:  `{"excludeMasterFromStandardLB":true}`,
+			infra:   makeInfrastructureResource(configv1.AzurePlatformType, configv1.AzurePublicCloud),
+			network: makeNetwork("OVNKubernetes"),
+			expected: map[string]interface{}{
+				"excludeMasterFromStandardLB": true,
+			},
+		},
+		{
+			name:    "keys not in the allowlist are passed through",
+			source:  `{"putVMSSVMBatchSize":5,"loadBalancerSku":"standard","someFutureKey":{"nested":["a","b"]},"anotherFutureKey":"value"}`,
+			infra:   singleReplicaInfra,
+			network: makeNetwork("OVNKubernetes"),
+			expected: map[string]interface{}{
+				"putVMSSVMBatchSize": float64(5),
+				"loadBalancerSku":    "standard",
+				"someFutureKey":      map[string]interface{}{"nested": []interface{}{"a", "b"}},
+				"anotherFutureKey":   "value",
+			},
+		},
+		{
+			name:    "keys known with a different case are not duplicated",
+			source:  `{"VMType":"vmss"}`,
+			infra:   makeInfrastructureResource(configv1.AzurePlatformType, configv1.AzurePublicCloud),
+			network: makeNetwork("OVNKubernetes"),
+			expected: map[string]interface{}{
+				"vmType": "vmss",
+			},
+			absent: map[string]struct{}{"VMType": {}},
+		},
+	}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := CloudConfigTransformer(tc.source, tc.infra, tc.network)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			var observed map[string]interface{}
+			g.Expect(json.Unmarshal([]byte(actual), &observed)).To(Succeed(), "Unmarshal of observed data should succeed")
+			for key, value := range tc.expected {
+				g.Expect(observed).To(HaveKeyWithValue(key, value))
+			}
+			for key := range tc.absent {
+				g.Expect(observed).NotTo(HaveKey(key))
+			}
+		})
+	}
+}
+
+func TestAdmissionPolicyParams(t *testing.T) {
+	assets, err := NewProviderAssets(config.OperatorConfig{
+		ManagedNamespace: "openshift-cloud-controller-manager",
+		ImagesReference: config.ImagesReference{
+			CloudControllerManagerAzure:    "CloudControllerManagerAzure",
+			CloudNodeManagerAzure:          "CloudNodeManagerAzure",
+			CloudControllerManagerOperator: "CloudControllerManagerOperator",
+		},
+		PlatformStatus:     &configv1.PlatformStatus{Type: configv1.AzurePlatformType},
+		InfrastructureName: "infra",
+	})
+	assert.NoError(t, err)
+
+	var policy *admissionregistrationv1.ValidatingAdmissionPolicy
+	for _, resource := range assets.GetRenderedResources() {
+		if p, ok := resource.(*admissionregistrationv1.ValidatingAdmissionPolicy); ok {
+			policy = p
+		}
+	}
+	if !assert.NotNil(t, policy, "validating admission policy not found") {
+		return
+	}
+
+	var messages []string
+	for _, validation := range policy.Spec.Validations {
+		messages = append(messages, validation.Message)
+	}
+	for _, label := range admissionPolicyParams.ProtectedNodeLabels {
+		assert.Contains(t, messages, fmt.Sprintf("updates to Node may not add, change or remove the %q label", label))
+	}
+	// Validations of the policy template are kept
+	assert.Contains(t, messages, `this user must have a "authentication.kubernetes.io/node-name" claim`)
+}
@@ -16,7 +16,13 @@ import (
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
 )
 
-const providerName = "azurestack"
+const (
+	providerName = "azurestack"
+
+	// Names of the containers running controller and node-manager images, see assets.
+	controllerContainerName  = "cloud-controller-manager"
+	nodeManagerContainerName = "cloud-node-manager"
+)
 
 var (
 	//go:embed assets/*
@@ -28,7 +34,7 @@ var (
 )
 
 type imagesReference struct {
-	Operator               string `valid:"required"`
+	CredentialsInjector    string `valid:"required"`
 	CloudControllerManager string `valid:"required"`
 	CloudNodeManager       string `valid:"required"`
 }
@@ -61,9 +67,19 @@ func getTemplateValues(images imagesReference, operatorConfig config.OperatorCon
 	return values, nil
 }
 
+// getOperandImages returns images of the controller and node-manager containers, which are distinct on Azure.
+func getOperandImages(images imagesReference) common.OperandImages {
+	return common.OperandImages{
+		ControllerContainerName:  controllerContainerName,
+		ControllerImage:          images.CloudControllerManager,
+		NodeManagerContainerName: nodeManagerContainerName,
+		NodeManagerImage:         images.CloudNodeManager,
+	}
+}
+
 func NewProviderAssets(config config.OperatorConfig) (common.CloudProviderAssets, error) {
 	images := imagesReference{
-		Operator:               config.ImagesReference.CloudControllerManagerOperator,
+		CredentialsInjector:    config.ImagesReference.GetCredentialsInjector(),
 		CloudControllerManager: config.ImagesReference.CloudControllerManagerAzure,
 		CloudNodeManager:       config.ImagesReference.CloudNodeManagerAzure,
 	}
@@ -84,10 +100,11 @@ func NewProviderAssets(config config.OperatorConfig) (common.CloudProviderAssets
 		return nil, fmt.Errorf("can not construct template values for %s assets: %v", providerName, err)
 	}
 
-	assets.renderedResources, err = common.RenderTemplates(objTemplates, templateValues)
+	renderedResources, err := common.RenderTemplates(objTemplates, templateValues)
 	if err != nil {
 		return nil, err
 	}
+	assets.renderedResources = common.SubstituteOperandImages(getOperandImages(images), renderedResources)
 	return assets, nil
 }
 
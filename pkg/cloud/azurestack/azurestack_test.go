@@ -30,7 +30,7 @@ func TestResourcesRenderingSmoke(t *testing.T) {
 		{
 			name:       "Empty config",
 			config:     config.OperatorConfig{},
-			initErrMsg: "azurestack: missed images in config: CloudControllerManager: non zero value required;CloudNodeManager: non zero value required;Operator: non zero value required",
+			initErrMsg: "azurestack: missed images in config: CloudControllerManager: non zero value required;CloudNodeManager: non zero value required;CredentialsInjector: non zero value required",
 		}, {
 			name: "No infra config",
 			config: config.OperatorConfig{
@@ -33,13 +33,25 @@ type cloudConfigTransformer func(source string, infra *configv1.Infrastructure,
 // See the FIXME comments below, and the TODO comment in the Reconcile function
 // inside cloud_config_sync_controller.go.
 func GetCloudConfigTransformer(platformStatus *configv1.PlatformStatus) (cloudConfigTransformer, bool, error) {
+	transformer, _, needsManagedConfigLookup, err := getCloudConfigTransformer(platformStatus)
+	return transformer, needsManagedConfigLookup, err
+}
+
+// GetCloudConfigTransformerName returns the name of the dedicated cloud config transformer of the platform,
+// for status reporting. An empty name is returned for platforms whose cloud config is passed through as is.
+func GetCloudConfigTransformerName(platformStatus *configv1.PlatformStatus) string {
+	_, name, _, _ := getCloudConfigTransformer(platformStatus)
+	return name
+}
+
+// getCloudConfigTransformer returns the cloud config transformer of the platform along with its name, empty for
+// platforms whose cloud config is passed through as is, and whether the config should be synced from the CCO namespace.
+func getCloudConfigTransformer(platformStatus *configv1.PlatformStatus) (cloudConfigTransformer, string, bool, error) {
 	switch platformStatus.Type {
 	case configv1.AWSPlatformType:
-		// We intentionally return nil rather than NoOpTransformer since we
-		// want to handle this differently in the caller.
-		// FIXME: We need to implement a transformer for this. Currently we're
-		// relying on CCO to do the heavy lifting for us.
-		return nil, true, nil
+		// The transformer only sets service endpoint overrides, the rest of
+		// the config is still synced from the CCO managed namespace.
+		return aws.CloudConfigTransformer, string(configv1.AWSPlatformType), true, nil
 	case configv1.AzurePlatformType:
 		// We intentionally return nil rather than NoOpTransformer since we
 		// want to handle this differently in the caller.
@@ -49,24 +61,41 @@ func GetCloudConfigTransformer(platformStatus *configv1.PlatformStatus) (cloudCo
 		// relying on CCO to do the heavy lifting for us. The Azure Stack Hub
 		// transformer is only to fix OCPBUGS-20213.
 		if azurestack.IsAzureStackHub(platformStatus) {
-			return azurestack.CloudConfigTransformer, true, nil
+			return azurestack.CloudConfigTransformer, "AzureStackHub", true, nil
 		}
-		return azure.CloudConfigTransformer, true, nil
+		return azure.CloudConfigTransformer, string(configv1.AzurePlatformType), true, nil
 	case configv1.GCPPlatformType:
-		return common.NoOpTransformer, false, nil
+		return common.NoOpTransformer, "", false, nil
 	case configv1.IBMCloudPlatformType:
-		return common.NoOpTransformer, false, nil
+		return common.NoOpTransformer, "", false, nil
 	case configv1.OpenStackPlatformType:
-		return openstack.CloudConfigTransformer, false, nil
+		return openstack.CloudConfigTransformer, string(configv1.OpenStackPlatformType), false, nil
 	case configv1.PowerVSPlatformType:
 		//Power VS platform uses ibm cloud provider
-		return common.NoOpTransformer, false, nil
+		return powervs.CloudConfigTransformer, string(configv1.PowerVSPlatformType), false, nil
 	case configv1.VSpherePlatformType:
-		return vsphere.CloudConfigTransformer, false, nil
+		return vsphere.CloudConfigTransformer, string(configv1.VSpherePlatformType), false, nil
 	case configv1.NutanixPlatformType:
-		return common.NoOpTransformer, false, nil
+		return nutanix.CloudConfigTransformer, string(configv1.NutanixPlatformType), false, nil
+	default:
+		return nil, "", false, newPlatformNotFoundError(platformStatus.Type)
+	}
+}
+
+// cloudConfigValidator function checks that a transformed cloud config can be read by the cloud provider.
+type cloudConfigValidator func(config string) error
+
+// GetCloudConfigValidator returns the function that should be used to check the transformed cloud configuration
+// before it is synced, so a malformed transformer output is not passed to the operands. Nil is returned
+// for platforms which do not have a validator.
+func GetCloudConfigValidator(platformStatus *configv1.PlatformStatus) cloudConfigValidator {
+	switch platformStatus.Type {
+	case configv1.IBMCloudPlatformType, configv1.PowerVSPlatformType:
+		return ibm.CloudConfigValidator
+	case configv1.VSpherePlatformType:
+		return vsphere.CloudConfigValidator
 	default:
-		return nil, false, newPlatformNotFoundError(platformStatus.Type)
+		return nil
 	}
 }
 
@@ -76,6 +105,51 @@ func GetCloudConfigTransformer(platformStatus *configv1.PlatformStatus) (cloudCo
 // These resources will be actively maintained by the operator, preventing
 // changes in their spec.
 func GetResources(operatorConfig config.OperatorConfig) ([]client.Object, error) {
+	for _, mount := range operatorConfig.ExtraHostPathMounts {
+		if err := mount.Validate(); err != nil {
+			klog.Errorf("invalid extra host path mount: %v", err)
+			return nil, err
+		}
+	}
+
+	if err := operatorConfig.LoggingFormat.Validate(); err != nil {
+		klog.Errorf("invalid logging format: %v", err)
+		return nil, err
+	}
+
+	if operatorConfig.ResourceAutosizing != nil {
+		if err := operatorConfig.ResourceAutosizing.Validate(); err != nil {
+			klog.Errorf("invalid resource autosizing: %v", err)
+			return nil, err
+		}
+	}
+
+	for _, override := range operatorConfig.CloudEndpointOverrides {
+		if err := override.Validate(); err != nil {
+			klog.Errorf("invalid cloud endpoint override: %v", err)
+			return nil, err
+		}
+	}
+
+	for platform, sysctls := range operatorConfig.PodSysctls {
+		if err := sysctls.Validate(); err != nil {
+			klog.Errorf("invalid pod sysctls for platform %s: %v", platform, err)
+			return nil, err
+		}
+	}
+
+	if err := config.ValidateHostAliases(operatorConfig.HostAliases); err != nil {
+		klog.Errorf("invalid host aliases: %v", err)
+		return nil, err
+	}
+
+	if operatorConfig.AzureCredentials != nil {
+		if err := operatorConfig.AzureCredentials.Validate(); err != nil {
+			klog.Errorf("invalid azure credentials: %v", err)
+			return nil, err
+		}
+	}
+
 	assets, err := getAssets(operatorConfig)
 	if err != nil {
 		if _, isPlatformNotFoundError := err.(*platformNotFoundError); isPlatformNotFoundError {
@@ -85,14 +159,58 @@ func GetResources(operatorConfig config.OperatorConfig) ([]client.Object, error)
 		klog.Errorf("can not get assets: %v", err)
 		return nil, err
 	}
+	return getResourcesFromAssets(operatorConfig, assets)
+}
+
+// getResourcesFromAssets validates resources rendered by the provider assets and returns them with
+// substituted common parts, along with the resources common to all platforms.
+func getResourcesFromAssets(operatorConfig config.OperatorConfig, assets common.CloudProviderAssets) ([]client.Object, error) {
 	renderedObjects := assets.GetRenderedResources()
+	if err := common.ValidateSelectorLabels(operatorConfig, renderedObjects); err != nil {
+		klog.Errorf("unexpected operand selector: %v", err)
+		return nil, err
+	}
+	if err := common.ValidateOperandImages(operatorConfig, renderedObjects); err != nil {
+		klog.Errorf("unexpected operand image: %v", err)
+		return nil, err
+	}
+	if err := common.ValidateDefaultNodeSelector(operatorConfig, renderedObjects); err != nil {
+		klog.Errorf("operand node selection is not kept: %v", err)
+		return nil, err
+	}
+	if err := common.ValidateExtraNodeManagerArgs(operatorConfig, renderedObjects); err != nil {
+		klog.Errorf("invalid extra node-manager args: %v", err)
+		return nil, err
+	}
+	if err := common.ValidateExtraHostPathMounts(operatorConfig, renderedObjects); err != nil {
+		klog.Errorf("invalid extra host path mounts: %v", err)
+		return nil, err
+	}
+	if err := common.ValidateExtraVolumes(operatorConfig, renderedObjects); err != nil {
+		klog.Errorf("invalid extra volumes: %v", err)
+		return nil, err
+	}
+	if err := common.ValidateDropPrivileges(operatorConfig, renderedObjects); err != nil {
+		klog.Errorf("operand privileges can not be dropped: %v", err)
+		return nil, err
+	}
 	substitutedObjects := common.SubstituteCommonPartsFromConfig(operatorConfig, renderedObjects)
+	substitutedObjects = common.ExternalizeStartupScripts(operatorConfig, substitutedObjects)
+	if err := common.ValidateHostPorts(substitutedObjects); err != nil {
+		klog.Errorf("unregistered operand host port: %v", err)
+		return nil, err
+	}
+	substitutedObjects = append(substitutedObjects, common.GetServiceAccounts(operatorConfig, substitutedObjects)...)
+	if webhookProvider, ok := assets.(common.WebhookProvider); ok {
+		substitutedObjects = append(substitutedObjects, common.GetWebhookResources(operatorConfig, webhookProvider.GetWebhookConfig())...)
+	}
 	commonResources, err := common.GetCommonResources(operatorConfig)
 	if err != nil {
 		klog.Errorf("can not create common resources %v", err)
 		return nil, err
 	}
 	substitutedObjects = append(substitutedObjects, commonResources...)
+	common.SetOwnershipLabels(substitutedObjects)
 	return substitutedObjects, nil
 }
 
@@ -4,25 +4,29 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
 	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/cloud/common"
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/util/testingutils"
 )
 
-const (
-	cloudControllerManagerDefaultPort = 10258
-	cloudNodeManagerDefaultPort       = 10263
-)
-
 func getDummyPlatformStatus(platformType configv1.PlatformType, isAzureStack bool) *configv1.PlatformStatus {
 	platformStatus := configv1.PlatformStatus{
 		Type: platformType,
@@ -80,6 +84,30 @@ func getPlatforms() testPlatformsMap {
 	}
 }
 
+func TestGetCloudConfigTransformerName(t *testing.T) {
+	tc := []struct {
+		platform     string
+		expectedName string
+	}{
+		{platform: string(configv1.AWSPlatformType), expectedName: "AWS"},
+		{platform: string(configv1.AzurePlatformType), expectedName: "Azure"},
+		{platform: "AzureStackHub", expectedName: "AzureStackHub"},
+		{platform: string(configv1.GCPPlatformType)},
+		{platform: string(configv1.IBMCloudPlatformType)},
+		{platform: string(configv1.NutanixPlatformType), expectedName: "Nutanix"},
+		{platform: string(configv1.OpenStackPlatformType), expectedName: "OpenStack"},
+		{platform: string(configv1.PowerVSPlatformType), expectedName: "PowerVS"},
+		{platform: string(configv1.VSpherePlatformType), expectedName: "VSphere"},
+	}
+
+	for _, tc := range tc {
+		t.Run(tc.platform, func(t *testing.T) {
+			platformStatus := getPlatforms()[tc.platform].platformStatus
+			assert.Equal(t, tc.expectedName, GetCloudConfigTransformerName(platformStatus))
+		})
+	}
+}
+
 func TestGetResources(t *testing.T) {
 	platformsMap := getPlatforms()
 
@@ -329,8 +357,9 @@ func TestRenderedResources(t *testing.T) {
 
 				checkResourceRunsBeforeCNI(t, platformName, podSpec)
 				checkLeaderElection(t, podSpec)
-				checkCloudControllerManagerFlags(t, podSpec)
-				checkTrustedCAMounted(t, podSpec)
+				checkCloudControllerManagerFlags(t, podSpec, false)
+				checkAllocateNodeCIDRs(t, podSpec)
+				checkTrustedCAMounted(t, podSpec, common.IsTrustedCAMountExempt)
 				checkUseServiceAccountCredentials(t, podSpec)
 			}
 		})
@@ -394,7 +423,7 @@ func checkPorts(t *testing.T, podSpec corev1.PodSpec) {
 	for _, container := range podSpec.Containers {
 		for _, port := range container.Ports {
 			switch port.ContainerPort {
-			case cloudControllerManagerDefaultPort, cloudNodeManagerDefaultPort:
+			case common.CloudControllerManagerPort, common.CloudNodeManagerPort:
 				foundValidPort = true
 			default:
 				t.Errorf("Unknown Container Port %d: All ports on Host Network processes must be registered before use", port.ContainerPort)
@@ -403,7 +432,7 @@ func checkPorts(t *testing.T, podSpec corev1.PodSpec) {
 		}
 	}
 	if !foundValidPort {
-		t.Errorf("Container Ports must specify any used ports. CloudControllerManager should use port %d, CloudNodeManager should use port %d.", cloudControllerManagerDefaultPort, cloudNodeManagerDefaultPort)
+		t.Errorf("Container Ports must specify any used ports. CloudControllerManager should use port %d, CloudNodeManager should use port %d.", common.CloudControllerManagerPort, common.CloudNodeManagerPort)
 	}
 }
 
@@ -519,14 +548,11 @@ func checkLeaderElection(t *testing.T, podSpec corev1.PodSpec) {
 	}
 }
 
-func checkCloudControllerManagerFlags(t *testing.T, podSpec corev1.PodSpec) {
-	const (
-		// This flag will disable the cloud route controller.
-		// The route controller is responsible for setting up inter pod networking
-		// using cloud networks, but this isn't required when you have an overlay
-		// network as is used within OpenShift.
-		configureCloudRoutes = "--configure-cloud-routes=false"
-	)
+func checkCloudControllerManagerFlags(t *testing.T, podSpec corev1.PodSpec, configureCloudRoutes bool) {
+	// The cloud route controller is responsible for setting up inter pod networking
+	// using cloud networks, but this isn't required when you have an overlay
+	// network as is used within OpenShift, so it is disabled unless configured otherwise.
+	configureCloudRoutesFlag := fmt.Sprintf("--configure-cloud-routes=%t", configureCloudRoutes)
 
 	for _, container := range podSpec.Containers {
 		if container.Name != "cloud-controller-manager" {
@@ -537,172 +563,1377 @@ func checkCloudControllerManagerFlags(t *testing.T, podSpec corev1.PodSpec) {
 		command := container.Command
 		assert.Len(t, command, 3, "Container Command should have 3 elements")
 
-		for _, flag := range []string{configureCloudRoutes} {
+		for _, flag := range []string{configureCloudRoutesFlag} {
 			assert.Contains(t, command[2], flag, "Container Command third (%q) element should contain flag %q", command[2], flag)
 		}
 	}
 }
 
-func TestDeploymentPodAntiAffinity(t *testing.T) {
-	platforms := getPlatforms()
-	for platformName, platform := range platforms {
+// TestOperandSelectors compares selectors of rendered workloads to golden values.
+// Workload selectors are immutable, changing them forces recreation of workloads on upgrade,
+// so a change here must be deliberate.
+func TestOperandSelectors(t *testing.T) {
+	controller := func(name, platform string) map[string]string {
+		return map[string]string{
+			"k8s-app": name,
+			"infrastructure.openshift.io/cloud-controller-manager": platform,
+		}
+	}
+	nodeManager := func(name, platform string) map[string]string {
+		return map[string]string{
+			"k8s-app": name,
+			"infrastructure.openshift.io/cloud-node-manager": platform,
+		}
+	}
+
+	goldenSelectors := map[string]map[string]map[string]string{
+		string(configv1.AWSPlatformType): {
+			"Deployment/aws-cloud-controller-manager": controller("aws-cloud-controller-manager", "AWS"),
+		},
+		string(configv1.AzurePlatformType): {
+			"Deployment/azure-cloud-controller-manager": controller("azure-cloud-controller-manager", "Azure"),
+			"DaemonSet/azure-cloud-node-manager":        nodeManager("azure-cloud-node-manager", "Azure"),
+		},
+		"AzureStackHub": {
+			"Deployment/azure-cloud-controller-manager": controller("azure-cloud-controller-manager", "Azure"),
+			"DaemonSet/azure-cloud-node-manager":        nodeManager("azure-cloud-node-manager", "Azure"),
+		},
+		string(configv1.GCPPlatformType): {
+			"Deployment/gcp-cloud-controller-manager": controller("gcp-cloud-controller-manager", "GCP"),
+		},
+		string(configv1.IBMCloudPlatformType): {
+			"Deployment/ibm-cloud-controller-manager": controller("ibm-cloud-controller-manager", "IBMCloud"),
+		},
+		string(configv1.NutanixPlatformType): {
+			"Deployment/nutanix-cloud-controller-manager": controller("nutanix-cloud-controller-manager", "Nutanix"),
+		},
+		string(configv1.OpenStackPlatformType): {
+			"Deployment/openstack-cloud-controller-manager": controller("openstack-cloud-controller-manager", "OpenStack"),
+		},
+		string(configv1.PowerVSPlatformType): {
+			"Deployment/powervs-cloud-controller-manager": controller("powervs-cloud-controller-manager", "PowerVS"),
+		},
+		string(configv1.VSpherePlatformType): {
+			"Deployment/vsphere-cloud-controller-manager": controller("vsphere-cloud-controller-manager", "VSphere"),
+		},
+	}
+
+	for platformName, platform := range getPlatforms() {
 		t.Run(platformName, func(t *testing.T) {
 			resources, err := GetResources(platform.getOperatorConfig())
 			assert.NoError(t, err)
 
+			selectors := map[string]map[string]string{}
+			for _, resource := range resources {
+				switch obj := resource.(type) {
+				case *appsv1.Deployment:
+					assert.Empty(t, obj.Spec.Selector.MatchExpressions)
+					selectors["Deployment/"+obj.Name] = obj.Spec.Selector.MatchLabels
+				case *appsv1.DaemonSet:
+					assert.Empty(t, obj.Spec.Selector.MatchExpressions)
+					selectors["DaemonSet/"+obj.Name] = obj.Spec.Selector.MatchLabels
+				}
+			}
+
+			expected, ok := goldenSelectors[platformName]
+			if !ok {
+				expected = map[string]map[string]string{}
+			}
+			assert.Equal(t, expected, selectors)
+		})
+	}
+}
+
+func TestCredentialsMode(t *testing.T) {
+	hasBoundSAToken := func(podSpec corev1.PodSpec) bool {
+		for _, volume := range podSpec.Volumes {
+			if volume.Name == "bound-sa-token" {
+				return true
+			}
+		}
+		return false
+	}
+
+	tc := []struct {
+		mode                    operatorv1.CloudCredentialsMode
+		expectedBoundSATokenVol bool
+	}{
+		{mode: operatorv1.CloudCredentialsModeDefault, expectedBoundSATokenVol: true},
+		{mode: operatorv1.CloudCredentialsModeManual, expectedBoundSATokenVol: true},
+		{mode: operatorv1.CloudCredentialsModeMint, expectedBoundSATokenVol: false},
+		{mode: operatorv1.CloudCredentialsModePassthrough, expectedBoundSATokenVol: false},
+	}
+
+	for _, tc := range tc {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			platform := getPlatforms()[string(configv1.AzurePlatformType)]
+			operatorConfig := platform.getOperatorConfig()
+			operatorConfig.CredentialsMode = tc.mode
+			resources, err := GetResources(operatorConfig)
+			assert.NoError(t, err)
+
+			for _, resource := range resources {
+				switch obj := resource.(type) {
+				case *appsv1.Deployment:
+					assert.Equal(t, tc.expectedBoundSATokenVol, hasBoundSAToken(obj.Spec.Template.Spec))
+				case *appsv1.DaemonSet:
+					assert.Equal(t, tc.expectedBoundSATokenVol, hasBoundSAToken(obj.Spec.Template.Spec))
+				}
+			}
+		})
+	}
+}
+
+func TestStartupScriptsFromConfigMap(t *testing.T) {
+	for platformName, platform := range getPlatforms() {
+		t.Run(platformName, func(t *testing.T) {
+			operatorConfig := platform.getOperatorConfig()
+			operatorConfig.StartupScriptsFromConfigMap = true
+			resources, err := GetResources(operatorConfig)
+			assert.NoError(t, err)
+
+			configMaps := map[string]*corev1.ConfigMap{}
+			for _, resource := range resources {
+				if configMap, ok := resource.(*corev1.ConfigMap); ok {
+					configMaps[configMap.Name] = configMap
+				}
+			}
+
 			for _, resource := range resources {
+				var podSpec corev1.PodSpec
 				switch obj := resource.(type) {
 				case *appsv1.Deployment:
-					checkPodAntiAffinity(t, obj.Spec.Template.Spec, obj.ObjectMeta)
+					podSpec = obj.Spec.Template.Spec
+				case *appsv1.DaemonSet:
+					podSpec = obj.Spec.Template.Spec
 				default:
-					// Nothing to check for non
+					continue
+				}
+
+				configMap := configMaps[common.StartupScriptsConfigMapName(resource.GetName())]
+				if !assert.NotNil(t, configMap, "startup scripts of %s are not rendered", resource.GetName()) {
+					continue
+				}
+				assert.Equal(t, resource.GetNamespace(), configMap.Namespace)
+				for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+					if len(container.Command) == 0 || container.Command[0] != "/bin/bash" {
+						continue
+					}
+					assert.Len(t, container.Command, 2, "container %s still inlines its script", container.Name)
+					script, ok := configMap.Data[container.Name+".sh"]
+					assert.True(t, ok, "script of container %s is not rendered", container.Name)
+					assert.NotContains(t, script, "$(CLOUD_CONFIG)")
+					if container.Name == "cloud-controller-manager" {
+						assert.Contains(t, script, "source /etc/kubernetes/apiserver-url.env")
+					}
 				}
 			}
 		})
 	}
 }
 
-func checkPodAntiAffinity(t *testing.T, podSpec corev1.PodSpec, podMeta metav1.ObjectMeta) {
-	assert.NotNil(t, podSpec.Affinity)
+// unregisteredPortAssets wraps provider assets, setting an unregistered port on the operand containers.
+type unregisteredPortAssets struct {
+	common.CloudProviderAssets
+}
 
-	podAntiAffinity := &corev1.PodAntiAffinity{
-		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
-			{
-				TopologyKey: "kubernetes.io/hostname",
-				LabelSelector: &metav1.LabelSelector{
-					MatchLabels: podMeta.Labels,
-				},
-			},
-		},
+func (a unregisteredPortAssets) GetRenderedResources() []client.Object {
+	resources := a.CloudProviderAssets.GetRenderedResources()
+	for _, resource := range resources {
+		if deployment, ok := resource.(*appsv1.Deployment); ok {
+			for i := range deployment.Spec.Template.Spec.Containers {
+				deployment.Spec.Template.Spec.Containers[i].Ports = []corev1.ContainerPort{{ContainerPort: 8080}}
+			}
+		}
 	}
+	return resources
+}
 
-	assert.EqualValues(t, podAntiAffinity, podSpec.Affinity.PodAntiAffinity)
+func TestUnregisteredHostPort(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+	operatorConfig := platform.getOperatorConfig()
+	assets, err := getAssets(operatorConfig)
+	assert.NoError(t, err)
+
+	_, err = getResourcesFromAssets(operatorConfig, unregisteredPortAssets{assets})
+	assert.EqualError(t, err, `container "cloud-controller-manager" of deployment "aws-cloud-controller-manager" uses port 8080, which is not registered for host network use, expected 10258`)
 }
 
-func TestDeploymentStrategy(t *testing.T) {
-	/*
-		This test is designed to check that when a Pod is created by the CCCMO,
-		we can update the pod when running on an SNO cluster.
-		Because host ports are used by the pods we create, we must release the
-		port before creating the new pod
-	*/
+// webhookAssets wraps provider assets, declaring an admission webhook.
+type webhookAssets struct {
+	common.CloudProviderAssets
+}
 
-	platforms := getPlatforms()
-	for platformName, platform := range platforms {
+func (a webhookAssets) GetWebhookConfig() common.WebhookConfig {
+	return common.WebhookConfig{
+		Command: []string{"/bin/cloud-provider-webhook"},
+		Port:    8443,
+		Path:    "/validate",
+	}
+}
+
+func TestWebhookResources(t *testing.T) {
+	tc := []struct {
+		name           string
+		webhookImage   string
+		supportWebhook bool
+		expectWebhook  bool
+	}{{
+		name:           "Provider with a webhook and the webhook image",
+		webhookImage:   "quay.io/openshift/webhook:latest",
+		supportWebhook: true,
+		expectWebhook:  true,
+	}, {
+		name:           "Provider with a webhook without the webhook image",
+		supportWebhook: true,
+	}, {
+		name:         "Provider without a webhook",
+		webhookImage: "quay.io/openshift/webhook:latest",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			platform := getPlatforms()[string(configv1.AWSPlatformType)]
+			operatorConfig := platform.getOperatorConfig()
+			operatorConfig.ImagesReference.CloudProviderWebhook = tc.webhookImage
+			assets, err := getAssets(operatorConfig)
+			assert.NoError(t, err)
+			if tc.supportWebhook {
+				assets = webhookAssets{assets}
+			}
+
+			resources, err := getResourcesFromAssets(operatorConfig, assets)
+			assert.NoError(t, err)
+
+			var webhookDeployment *appsv1.Deployment
+			var webhookService *corev1.Service
+			var webhookConfiguration *admissionregistrationv1.ValidatingWebhookConfiguration
+			for _, resource := range resources {
+				switch obj := resource.(type) {
+				case *appsv1.Deployment:
+					if obj.Name == "aws-cloud-provider-webhook" {
+						webhookDeployment = obj
+					}
+				case *corev1.Service:
+					webhookService = obj
+				case *admissionregistrationv1.ValidatingWebhookConfiguration:
+					webhookConfiguration = obj
+				}
+			}
 
+			if tc.expectWebhook {
+				assert.NotNil(t, webhookDeployment)
+				assert.NotNil(t, webhookService)
+				assert.NotNil(t, webhookConfiguration)
+			} else {
+				assert.Nil(t, webhookDeployment)
+				assert.Nil(t, webhookService)
+				assert.Nil(t, webhookConfiguration)
+			}
+		})
+	}
+}
+
+func TestAzureCredentials(t *testing.T) {
+	for _, platformName := range []string{string(configv1.AzurePlatformType), "AzureStackHub"} {
 		t.Run(platformName, func(t *testing.T) {
-			resources, err := GetResources(platform.getOperatorConfig())
+			platform := getPlatforms()[platformName]
+			operatorConfig := platform.getOperatorConfig()
+			operatorConfig.AzureCredentials = &config.AzureCredentials{SecretName: "custom-credentials", FilePath: "/etc/azure/credentials"}
+			resources, err := GetResources(operatorConfig)
 			assert.NoError(t, err)
 
+			workloads := 0
 			for _, resource := range resources {
+				var podSpec corev1.PodSpec
 				switch obj := resource.(type) {
 				case *appsv1.Deployment:
-					checkDeploymentStrategy(t, obj.Spec.Strategy)
+					podSpec = obj.Spec.Template.Spec
+				case *appsv1.DaemonSet:
+					podSpec = obj.Spec.Template.Spec
 				default:
-					// Nothing to check for non
+					continue
 				}
+				workloads++
+
+				for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+					for _, env := range container.Env {
+						if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+							assert.Equal(t, "custom-credentials", env.ValueFrom.SecretKeyRef.Name)
+							assert.NotEqual(t, "azure-inject-credentials", container.Name, "injector reads credentials from env")
+						}
+					}
+				}
+
+				assert.Len(t, podSpec.InitContainers, 1)
+				injector := podSpec.InitContainers[0]
+				assert.Contains(t, strings.Join(append(injector.Command, injector.Args...), " "), "--creds-file=/etc/azure/credentials")
+				assert.Contains(t, injector.VolumeMounts, corev1.VolumeMount{Name: "azure-credentials", MountPath: "/etc/azure/credentials", SubPath: "credentials", ReadOnly: true})
+				assert.Contains(t, podSpec.Volumes, corev1.Volume{
+					Name: "azure-credentials",
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+						SecretName: "custom-credentials",
+						Items:      []corev1.KeyToPath{{Key: "credentials", Path: "credentials"}},
+					}},
+				})
 			}
+			assert.Equal(t, 2, workloads)
 		})
 	}
 }
 
-func checkDeploymentStrategy(t *testing.T, strategy appsv1.DeploymentStrategy) {
-	if strategy.Type != appsv1.RecreateDeploymentStrategyType {
-		t.Errorf("Deployment should set strategy type to \"Recreate\"")
+func TestPodSysctls(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AzurePlatformType)]
+	operatorConfig := platform.getOperatorConfig()
+	sysctls := config.Sysctls{{Name: "kernel.shm_rmid_forced", Value: "1"}}
+	operatorConfig.PodSysctls = map[configv1.PlatformType]config.Sysctls{configv1.AzurePlatformType: sysctls}
+
+	resources, err := GetResources(operatorConfig)
+	assert.NoError(t, err)
+
+	workloads := 0
+	for _, resource := range resources {
+		var podSpec corev1.PodSpec
+		switch obj := resource.(type) {
+		case *appsv1.Deployment:
+			podSpec = obj.Spec.Template.Spec
+		case *appsv1.DaemonSet:
+			podSpec = obj.Spec.Template.Spec
+		default:
+			continue
+		}
+		workloads++
+		if assert.NotNil(t, podSpec.SecurityContext, "%s should have a pod security context", resource.GetName()) {
+			assert.Equal(t, []corev1.Sysctl(sysctls), podSpec.SecurityContext.Sysctls)
+		}
 	}
+	assert.Equal(t, 2, workloads)
+
+	operatorConfig.PodSysctls[configv1.AzurePlatformType] = config.Sysctls{{Name: "net.core.somaxconn", Value: "1024"}}
+	_, err = GetResources(operatorConfig)
+	assert.EqualError(t, err, `sysctl "net.core.somaxconn" can not be set on host network pods, since it would change the node network settings`)
 }
 
-func checkTrustedCAMounted(t *testing.T, podSpec corev1.PodSpec) {
-	trustedCAVolume := corev1.Volume{
-		Name: "trusted-ca",
-		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
-			LocalObjectReference: corev1.LocalObjectReference{Name: "ccm-trusted-ca"},
-			Items:                []corev1.KeyToPath{{Key: "ca-bundle.crt", Path: "tls-ca-bundle.pem"}},
-		}},
+func TestHostAliases(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+	operatorConfig := platform.getOperatorConfig()
+	alias := corev1.HostAlias{IP: "10.0.0.10", Hostnames: []string{"ec2.us-east-1.amazonaws.com"}}
+	operatorConfig.HostAliases = []corev1.HostAlias{alias}
+
+	resources, err := GetResources(operatorConfig)
+	assert.NoError(t, err)
+
+	found := false
+	for _, resource := range resources {
+		deployment, ok := resource.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		found = true
+		assert.True(t, deployment.Spec.Template.Spec.HostNetwork)
+		assert.Contains(t, deployment.Spec.Template.Spec.HostAliases, alias)
 	}
-	trustedCAVolumeMount := corev1.VolumeMount{
-		MountPath: "/etc/pki/ca-trust/extracted/pem",
-		Name:      "trusted-ca",
-		ReadOnly:  true,
+	assert.True(t, found, "aws-cloud-controller-manager deployment not found")
+}
+
+func TestDNSConfigHash(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+
+	getTemplateHash := func(dns *configv1.DNS) string {
+		operatorConfig := platform.getOperatorConfig()
+		hash, err := config.GetDNSConfigHash(dns)
+		assert.NoError(t, err)
+		operatorConfig.DNSConfigHash = hash
+
+		resources, err := GetResources(operatorConfig)
+		assert.NoError(t, err)
+		for _, resource := range resources {
+			if deployment, ok := resource.(*appsv1.Deployment); ok {
+				return deployment.Spec.Template.Annotations[common.DNSConfigHashAnnotation]
+			}
+		}
+		t.Fatal("aws-cloud-controller-manager deployment not found")
+		return ""
 	}
-	assert.Contains(t, podSpec.Volumes, trustedCAVolume, "PodSpec %s volumes should contain trusted-ca volume")
-	for _, c := range podSpec.Containers {
-		assert.Contains(t, c.VolumeMounts, trustedCAVolumeMount, "Container VolumeMounts should contain trusted ca volume mount")
+
+	dns := &configv1.DNS{Spec: configv1.DNSSpec{BaseDomain: "example.com"}}
+	initialHash := getTemplateHash(dns)
+	assert.NotEmpty(t, initialHash)
+
+	dns.Spec.PrivateZone = &configv1.DNSZone{ID: "private-zone"}
+	assert.NotEqual(t, initialHash, getTemplateHash(dns), "a DNS config change should update the pod template annotation")
+}
+
+func TestLeaderElectResourceNamespace(t *testing.T) {
+	const customNamespace = "custom-cloud-controller-manager"
+
+	for platformName, platform := range getPlatforms() {
+		t.Run(platformName, func(t *testing.T) {
+			operatorConfig := platform.getOperatorConfig()
+			operatorConfig.ManagedNamespace = customNamespace
+
+			resources, err := GetResources(operatorConfig)
+			assert.NoError(t, err)
+
+			for _, resource := range resources {
+				deployment, ok := resource.(*appsv1.Deployment)
+				if !ok {
+					continue
+				}
+				for _, container := range deployment.Spec.Template.Spec.Containers {
+					if container.Name != "cloud-controller-manager" {
+						continue
+					}
+					assert.Contains(t, container.Command[2], "--leader-elect-resource-namespace="+customNamespace)
+					assert.NotContains(t, container.Command[2], "--leader-elect-resource-namespace=openshift-cloud-controller-manager")
+				}
+			}
+		})
 	}
 }
 
-func checkUseServiceAccountCredentials(t *testing.T, podSpec corev1.PodSpec) {
+func TestConfigureCloudRoutes(t *testing.T) {
+	for platformName, platform := range getPlatforms() {
+		t.Run(platformName, func(t *testing.T) {
+			operatorConfig := platform.getOperatorConfig()
+			operatorConfig.ConfigureCloudRoutes = ptr.To(true)
+			resources, err := GetResources(operatorConfig)
+			assert.NoError(t, err)
+
+			for _, resource := range resources {
+				if deployment, ok := resource.(*appsv1.Deployment); ok {
+					checkCloudControllerManagerFlags(t, deployment.Spec.Template.Spec, true)
+				}
+			}
+		})
+	}
+}
+
+func checkAllocateNodeCIDRs(t *testing.T, podSpec corev1.PodSpec) {
 	const (
-		useServiceAccountCredentials = "--use-service-account-credentials=true"
+		// OpenShift networking allocates node CIDRs, so cloud-controller-manager should not.
+		// The flag defaults to false, so it is only set when overridden.
+		allocateNodeCIDRs = "--allocate-node-cidrs"
 	)
 
 	for _, container := range podSpec.Containers {
 		if container.Name != "cloud-controller-manager" {
-			// Only the cloud-controller-manager container needs leader election
+			// Only the cloud-controller-manager container needs these flags checking
 			continue
 		}
 
 		command := container.Command
 		assert.Len(t, command, 3, "Container Command should have 3 elements")
-
-		assert.Contains(t, command[2], useServiceAccountCredentials, "Container Command third (%q) element should contain flag %q", command[2], useServiceAccountCredentials)
+		assert.NotContains(t, command[2], allocateNodeCIDRs, "Container Command third (%q) element should not contain flag %q", command[2], allocateNodeCIDRs)
 	}
 }
 
-func TestSelectorLabels(t *testing.T) {
-	/*
-		This test checks consistency between all Deployment pod template labels and selector labels
-	*/
+func TestCCMVerbosity(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
 
-	platforms := getPlatforms()
-	for platformName, platform := range platforms {
+	operatorConfig := platform.getOperatorConfig()
+	resources, err := GetResources(operatorConfig)
+	assert.NoError(t, err)
+	checkCCMVerbosity(t, resources, "-v=2")
 
-		t.Run(platformName, func(t *testing.T) {
-			resources, err := GetResources(platform.getOperatorConfig())
-			assert.NoError(t, err)
+	operatorConfig.CCMVerbosity = ptr.To(6)
+	resources, err = GetResources(operatorConfig)
+	assert.NoError(t, err)
+	checkCCMVerbosity(t, resources, "-v=6")
+}
 
-			for _, resource := range resources {
-				switch obj := resource.(type) {
-				case *appsv1.Deployment:
-					checkDeployementSelectorLabels(t, obj, platform.platformStatus.Type)
-				case *appsv1.DaemonSet:
-					checkDaemonSetSelectorLabels(t, obj, platform.platformStatus.Type)
-				default:
-					// Nothing to check for
-				}
+func checkCCMVerbosity(t *testing.T, resources []client.Object, expectedFlag string) {
+	var found bool
+	for _, resource := range resources {
+		deployment, ok := resource.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			if container.Name != "cloud-controller-manager" {
+				continue
 			}
-		})
+			found = true
+
+			checkContainerCommand(t, deployment.Spec.Template.Spec)
+			assert.Contains(t, container.Command[2], expectedFlag)
+			assert.Equal(t, 1, strings.Count(container.Command[2], "v="), "Container Command should contain exactly one verbosity flag")
+		}
 	}
+	assert.True(t, found, "cloud-controller-manager container should be rendered")
 }
 
-func checkDeployementSelectorLabels(t *testing.T, deployment *appsv1.Deployment, platformType configv1.PlatformType) {
-	assert.Contains(t, deployment.Labels, "k8s-app")
-	assert.Contains(t, deployment.Spec.Template.Labels, "k8s-app")
-	assert.Contains(t, deployment.Spec.Selector.MatchLabels, "k8s-app")
+func TestLoggingFormat(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
 
-	assert.Contains(t, deployment.Labels, common.CloudControllerManagerProviderLabel)
-	assert.Contains(t, deployment.Spec.Template.Labels, common.CloudControllerManagerProviderLabel)
-	assert.Contains(t, deployment.Spec.Selector.MatchLabels, common.CloudControllerManagerProviderLabel)
+	operatorConfig := platform.getOperatorConfig()
+	resources, err := GetResources(operatorConfig)
+	assert.NoError(t, err)
+	checkCCMLoggingFormat(t, resources, "")
 
-	assert.Equal(t, string(platformType), deployment.Labels[common.CloudControllerManagerProviderLabel])
-	assert.Equal(t, string(platformType), deployment.Spec.Template.Labels[common.CloudControllerManagerProviderLabel])
-	assert.Equal(t, string(platformType), deployment.Spec.Selector.MatchLabels[common.CloudControllerManagerProviderLabel])
+	operatorConfig.LoggingFormat = config.LoggingFormatJSON
+	resources, err = GetResources(operatorConfig)
+	assert.NoError(t, err)
+	checkCCMLoggingFormat(t, resources, "--logging-format=json")
+
+	operatorConfig.LoggingFormat = "yaml"
+	_, err = GetResources(operatorConfig)
+	assert.EqualError(t, err, "unsupported logging format \"yaml\", expected one of \"text\", \"json\"")
 }
 
-func checkDaemonSetSelectorLabels(t *testing.T, ds *appsv1.DaemonSet, platformType configv1.PlatformType) {
-	assert.Contains(t, ds.Labels, "k8s-app")
-	assert.Contains(t, ds.Spec.Template.Labels, "k8s-app")
-	assert.Contains(t, ds.Spec.Selector.MatchLabels, "k8s-app")
+func checkCCMLoggingFormat(t *testing.T, resources []client.Object, expectedFlag string) {
+	var found bool
+	for _, resource := range resources {
+		deployment, ok := resource.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			if container.Name != "cloud-controller-manager" {
+				continue
+			}
+			found = true
 
-	assert.Contains(t, ds.Labels, common.CloudNodeManagerCloudProviderLabel)
-	assert.Contains(t, ds.Spec.Template.Labels, common.CloudNodeManagerCloudProviderLabel)
-	assert.Contains(t, ds.Spec.Selector.MatchLabels, common.CloudNodeManagerCloudProviderLabel)
+			checkContainerCommand(t, deployment.Spec.Template.Spec)
+			if expectedFlag == "" {
+				assert.NotContains(t, container.Command[2], "--logging-format")
+				continue
+			}
+			assert.Contains(t, container.Command[2], expectedFlag)
+			assert.Equal(t, 1, strings.Count(container.Command[2], "--logging-format"), "Container Command should contain exactly one logging format flag")
+		}
+	}
+	assert.True(t, found, "cloud-controller-manager container should be rendered")
+}
 
-	assert.Equal(t, string(platformType), ds.Labels[common.CloudNodeManagerCloudProviderLabel])
-	assert.Equal(t, string(platformType), ds.Spec.Template.Labels[common.CloudNodeManagerCloudProviderLabel])
+func TestCloudEndpointOverrides(t *testing.T) {
+	override := config.CloudEndpointOverride{
+		EnvName: "AWS_ENDPOINT_URL_EC2",
+		URL:     "https://ec2.custom-region.example.com",
+	}
+
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+	operatorConfig := platform.getOperatorConfig()
+	operatorConfig.CloudEndpointOverrides = []config.CloudEndpointOverride{override}
+
+	resources, err := GetResources(operatorConfig)
+	assert.NoError(t, err)
+
+	var found bool
+	for _, resource := range resources {
+		deployment, ok := resource.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			found = true
+			assert.Contains(t, container.Env, corev1.EnvVar{Name: override.EnvName, Value: override.URL})
+		}
+	}
+	assert.True(t, found, "operand containers should be rendered")
+
+	operatorConfig.CloudEndpointOverrides = []config.CloudEndpointOverride{{EnvName: "AWS_ENDPOINT_URL_EC2", URL: "https://10.0.0.1"}}
+	_, err = GetResources(operatorConfig)
+	assert.EqualError(t, err, "cloud endpoint override AWS_ENDPOINT_URL_EC2: URL \"https://10.0.0.1\" should use a DNS name instead of an IP address")
+}
+
+func TestExtraVolumes(t *testing.T) {
+	extraVolume := corev1.Volume{
+		Name: "custom-ca",
+		VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{
+			Path: "/etc/custom-ca",
+			Type: ptr.To(corev1.HostPathDirectory),
+		}},
+	}
+	extraMount := corev1.VolumeMount{
+		Name:      "custom-ca",
+		MountPath: "/etc/custom-ca",
+		ReadOnly:  true,
+	}
+
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+	operatorConfig := platform.getOperatorConfig()
+	operatorConfig.ExtraVolumes = []corev1.Volume{extraVolume}
+	operatorConfig.ExtraVolumeMounts = []corev1.VolumeMount{extraMount}
+
+	resources, err := GetResources(operatorConfig)
+	assert.NoError(t, err)
+
+	var found bool
+	for _, resource := range resources {
+		deployment, ok := resource.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		found = true
+		podSpec := deployment.Spec.Template.Spec
+
+		assert.Contains(t, podSpec.Volumes, extraVolume, "PodSpec Volumes should contain extra volume")
+		checkTrustedCAMounted(t, podSpec, common.IsTrustedCAMountExempt)
+		for _, container := range podSpec.Containers {
+			assert.Contains(t, container.VolumeMounts, extraMount, "Container VolumeMounts should contain extra volume mount")
+		}
+	}
+	assert.True(t, found, "cloud-controller-manager deployment should be rendered")
+
+	operatorConfig.ExtraVolumes = []corev1.Volume{{Name: "trusted-ca", VolumeSource: extraVolume.VolumeSource}}
+	operatorConfig.ExtraVolumeMounts = nil
+	_, err = GetResources(operatorConfig)
+	assert.EqualError(t, err, "extra volume \"trusted-ca\" collides with a volume of deployment aws-cloud-controller-manager")
+}
+
+func TestStartupTimeout(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+	operatorConfig := platform.getOperatorConfig()
+	operatorConfig.StartupTimeouts = map[configv1.PlatformType]time.Duration{configv1.AWSPlatformType: 2 * time.Minute}
+
+	resources, err := GetResources(operatorConfig)
+	assert.NoError(t, err)
+
+	sourceAPIEnv := `if [[ -f /etc/kubernetes/apiserver-url.env ]]; then
+  source /etc/kubernetes/apiserver-url.env
+fi
+kill "$startup_watchdog_pid" 2>/dev/null || true
+exec `
+
+	var found bool
+	for _, resource := range resources {
+		deployment, ok := resource.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			found = true
+			assert.True(t, strings.HasPrefix(container.Command[2], "#!/bin/bash\nstartup_script_pid=$BASHPID\n( sleep 120; "), "Container Command should start with the startup watchdog")
+			assert.Contains(t, container.Command[2], sourceAPIEnv, "Container Command should source the API server URL env file before the watchdog is stopped")
+		}
+	}
+	assert.True(t, found, "operand containers should be rendered")
+}
+
+func TestLogEffectiveFlags(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+	operatorConfig := platform.getOperatorConfig()
+	operatorConfig.LogEffectiveFlags = true
+	operatorConfig.CCMVerbosity = ptr.To(4)
+
+	resources, err := GetResources(operatorConfig)
+	assert.NoError(t, err)
+
+	var found bool
+	for _, resource := range resources {
+		deployment, ok := resource.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			if container.Name != "cloud-controller-manager" {
+				continue
+			}
+			found = true
+			echoLine := `echo "Effective cloud-controller-manager command:" /bin/aws-cloud-controller-manager \`
+			assert.Contains(t, container.Command[2], echoLine, "Container Command should echo the operand command")
+
+			echoed, executed, ok := strings.Cut(container.Command[2][strings.Index(container.Command[2], echoLine):], "\nexec ")
+			assert.True(t, ok, "Container Command should execute the operand after echoing it")
+			assert.Contains(t, echoed, "-v=4", "Echoed command should include flags substituted by the operator")
+			assert.Contains(t, executed, "-v=4")
+		}
+	}
+	assert.True(t, found, "aws-cloud-controller-manager container not found")
+}
+
+func TestDropPrivileges(t *testing.T) {
+	for platformName, platform := range getPlatforms() {
+		t.Run(platformName, func(t *testing.T) {
+			operatorConfig := platform.getOperatorConfig()
+			operatorConfig.DropPrivilegesToUser = 1001
+
+			resources, err := GetResources(operatorConfig)
+			assert.NoError(t, err)
+
+			for _, resource := range resources {
+				var containerName string
+				var podSpec corev1.PodSpec
+				switch obj := resource.(type) {
+				case *appsv1.Deployment:
+					containerName, podSpec = "cloud-controller-manager", obj.Spec.Template.Spec
+				case *appsv1.DaemonSet:
+					containerName, podSpec = "cloud-node-manager", obj.Spec.Template.Spec
+				default:
+					continue
+				}
+				for _, container := range podSpec.Containers {
+					if container.Name != containerName {
+						assert.Nil(t, container.SecurityContext, "Container %q should not drop privileges", container.Name)
+						continue
+					}
+					assert.Regexp(t, `(?m)^\s*exec setpriv --reuid=1001 --regid=1001 --clear-groups /`, container.Command[2], "Operand binary should be executed as the non-root user")
+					if assert.NotNil(t, container.SecurityContext) {
+						assert.Equal(t, ptr.To(false), container.SecurityContext.AllowPrivilegeEscalation)
+						assert.Equal(t, &corev1.Capabilities{
+							Add:  []corev1.Capability{"SETUID", "SETGID"},
+							Drop: []corev1.Capability{"ALL"},
+						}, container.SecurityContext.Capabilities)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDropPrivilegesValidation(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+	operatorConfig := platform.getOperatorConfig()
+	operatorConfig.DropPrivilegesToUser = -1
+
+	_, err := GetResources(operatorConfig)
+	assert.EqualError(t, err, "user ID -1 to drop operand privileges to is not valid")
+}
+
+func TestExtraHostPathMounts(t *testing.T) {
+	extraMount := config.HostPathMount{
+		Name:      "host-cloud-creds",
+		HostPath:  "/var/lib/cloud-creds",
+		MountPath: "/etc/cloud-creds",
+		Type:      corev1.HostPathDirectory,
+		ReadOnly:  true,
+	}
+
+	for platformName, platform := range getPlatforms() {
+		t.Run(platformName, func(t *testing.T) {
+			operatorConfig := platform.getOperatorConfig()
+			operatorConfig.ExtraHostPathMounts = []config.HostPathMount{extraMount}
+
+			resources, err := GetResources(operatorConfig)
+			assert.NoError(t, err)
+
+			for _, resource := range resources {
+				var podSpec corev1.PodSpec
+				switch obj := resource.(type) {
+				case *appsv1.Deployment:
+					podSpec = obj.Spec.Template.Spec
+				case *appsv1.DaemonSet:
+					podSpec = obj.Spec.Template.Spec
+				default:
+					continue
+				}
+
+				assert.Contains(t, podSpec.Volumes, corev1.Volume{
+					Name: extraMount.Name,
+					VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{
+						Path: extraMount.HostPath,
+						Type: ptr.To(corev1.HostPathDirectory),
+					}},
+				}, "PodSpec Volumes should contain extra host path volume")
+				for _, container := range podSpec.Containers {
+					assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{
+						Name:      extraMount.Name,
+						MountPath: extraMount.MountPath,
+						ReadOnly:  true,
+					}, "Container VolumeMounts should contain extra host path volume mount")
+				}
+			}
+		})
+	}
+}
+
+func TestExtraHostPathMountsValidation(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+
+	operatorConfig := platform.getOperatorConfig()
+	operatorConfig.ExtraHostPathMounts = []config.HostPathMount{{
+		Name:      "host-cloud-creds",
+		HostPath:  "/var/lib/cloud-creds",
+		MountPath: "/etc/cloud-creds",
+		Type:      "Folder",
+	}}
+
+	_, err := GetResources(operatorConfig)
+	assert.EqualError(t, err, "host path mount host-cloud-creds: unsupported host path type \"Folder\"")
+
+	operatorConfig.ExtraHostPathMounts = []config.HostPathMount{{
+		Name:      "host-etc-kube",
+		HostPath:  "/var/lib/cloud-creds",
+		MountPath: "/etc/cloud-creds",
+		Type:      corev1.HostPathDirectory,
+	}}
+	_, err = GetResources(operatorConfig)
+	assert.EqualError(t, err, "extra host path mount \"host-etc-kube\" collides with a volume of deployment aws-cloud-controller-manager")
+}
+
+func TestExtraNodeManagerArgs(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AzurePlatformType)]
+
+	tc := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{{
+		name: "Extra args are appended to the node-manager command",
+		args: []string{"--kube-api-qps=50"},
+	}, {
+		name:        "Managed flag is rejected",
+		args:        []string{"--node-name=foo"},
+		expectedErr: "extra node-manager arg \"--node-name=foo\" conflicts with a flag managed by the operator",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorConfig := platform.getOperatorConfig()
+			operatorConfig.ExtraNodeManagerArgs = tc.args
+
+			resources, err := GetResources(operatorConfig)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			var nodeManagerCommands []string
+			for _, resource := range resources {
+				if ds, ok := resource.(*appsv1.DaemonSet); ok {
+					for _, container := range ds.Spec.Template.Spec.Containers {
+						if container.Name == "cloud-node-manager" {
+							nodeManagerCommands = append(nodeManagerCommands, container.Command[2])
+						}
+					}
+				}
+			}
+			assert.NotEmpty(t, nodeManagerCommands)
+			for _, command := range nodeManagerCommands {
+				assert.Contains(t, command, "'--kube-api-qps=50'")
+			}
+		})
+	}
+}
+
+func TestServiceAccountAnnotations(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+
+	operatorConfig := platform.getOperatorConfig()
+	operatorConfig.ServiceAccountAnnotations = map[string]string{
+		"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/cloud-controller-manager",
+	}
+
+	resources, err := GetResources(operatorConfig)
+	assert.NoError(t, err)
+
+	var serviceAccounts []*corev1.ServiceAccount
+	for _, resource := range resources {
+		if serviceAccount, ok := resource.(*corev1.ServiceAccount); ok {
+			serviceAccounts = append(serviceAccounts, serviceAccount)
+		}
+	}
+	assert.Len(t, serviceAccounts, 1)
+	assert.Equal(t, "cloud-controller-manager", serviceAccounts[0].Name)
+	assert.Equal(t, operatorConfig.ManagedNamespace, serviceAccounts[0].Namespace)
+	assert.Equal(t, operatorConfig.ServiceAccountAnnotations, serviceAccounts[0].Annotations)
+}
+
+func TestInitContainerResources(t *testing.T) {
+	customResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+	}
+
+	tc := []struct {
+		name              string
+		resources         *corev1.ResourceRequirements
+		expectedResources corev1.ResourceRequirements
+	}{{
+		name: "Default resources",
+		expectedResources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("20Mi"),
+			},
+		},
+	}, {
+		name:              "Custom resources",
+		resources:         &customResources,
+		expectedResources: customResources,
+	}}
+
+	platforms := getPlatforms()
+	for _, platformName := range []string{string(configv1.AzurePlatformType), "AzureStackHub"} {
+		platform := platforms[platformName]
+		for _, tc := range tc {
+			t.Run(fmt.Sprintf("%s: %s", platformName, tc.name), func(t *testing.T) {
+				operatorConfig := platform.getOperatorConfig()
+				operatorConfig.InitContainerResources = tc.resources
+
+				resources, err := GetResources(operatorConfig)
+				assert.NoError(t, err)
+
+				var initContainers []corev1.Container
+				for _, object := range resources {
+					switch obj := object.(type) {
+					case *appsv1.Deployment:
+						initContainers = append(initContainers, obj.Spec.Template.Spec.InitContainers...)
+					case *appsv1.DaemonSet:
+						initContainers = append(initContainers, obj.Spec.Template.Spec.InitContainers...)
+					}
+				}
+				assert.NotEmpty(t, initContainers)
+				for _, container := range initContainers {
+					assert.True(t, equality.Semantic.DeepEqual(tc.expectedResources, container.Resources),
+						"unexpected resources of %s init container: %v", container.Name, container.Resources)
+				}
+			})
+		}
+	}
+}
+
+func TestTrustedCAMountExemptions(t *testing.T) {
+	platforms := getPlatforms()
+	for _, platformName := range []string{string(configv1.AzurePlatformType), "AzureStackHub"} {
+		platform := platforms[platformName]
+		t.Run(platformName, func(t *testing.T) {
+			resources, err := GetResources(platform.getOperatorConfig())
+			assert.NoError(t, err)
+
+			var podSpecs []corev1.PodSpec
+			for _, object := range resources {
+				switch obj := object.(type) {
+				case *appsv1.Deployment:
+					podSpecs = append(podSpecs, obj.Spec.Template.Spec)
+				case *appsv1.DaemonSet:
+					podSpecs = append(podSpecs, obj.Spec.Template.Spec)
+				}
+			}
+			assert.Len(t, podSpecs, 2)
+
+			for _, podSpec := range podSpecs {
+				assert.True(t, podVolumeExists(podSpec, "trusted-ca"), "trusted-ca volume should be kept on pods with exempt containers")
+				for _, container := range podSpec.InitContainers {
+					assert.Equal(t, "azure-inject-credentials", container.Name)
+					assert.False(t, containerMountExists(container, "trusted-ca"), "%s init container should not mount trusted-ca", container.Name)
+				}
+				for _, container := range podSpec.Containers {
+					assert.True(t, containerMountExists(container, "trusted-ca"), "%s container should mount trusted-ca", container.Name)
+				}
+			}
+		})
+	}
+}
+
+func podVolumeExists(podSpec corev1.PodSpec, name string) bool {
+	for _, volume := range podSpec.Volumes {
+		if volume.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containerMountExists(container corev1.Container, name string) bool {
+	for _, mount := range container.VolumeMounts {
+		if mount.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCloudAPIReadinessProbe(t *testing.T) {
+	platformsWithMetadataEndpoint := map[string]bool{
+		string(configv1.AWSPlatformType):       true,
+		string(configv1.AzurePlatformType):     true,
+		"AzureStackHub":                        true,
+		string(configv1.GCPPlatformType):       true,
+		string(configv1.OpenStackPlatformType): true,
+	}
+
+	for platformName, platform := range getPlatforms() {
+		for _, enabled := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s: enabled %t", platformName, enabled), func(t *testing.T) {
+				operatorConfig := platform.getOperatorConfig()
+				operatorConfig.CloudAPIReadinessProbe = enabled
+
+				resources, err := GetResources(operatorConfig)
+				assert.NoError(t, err)
+
+				for _, resource := range resources {
+					deployment, ok := resource.(*appsv1.Deployment)
+					if !ok {
+						continue
+					}
+					for _, container := range deployment.Spec.Template.Spec.Containers {
+						if container.Name != "cloud-controller-manager" {
+							continue
+						}
+						if enabled && platformsWithMetadataEndpoint[platformName] {
+							if assert.NotNil(t, container.ReadinessProbe) && assert.NotNil(t, container.ReadinessProbe.Exec) {
+								assert.Equal(t, []string{"/bin/bash", "-c", "exec 3<>/dev/tcp/169.254.169.254/80"}, container.ReadinessProbe.Exec.Command)
+							}
+						} else {
+							assert.Nil(t, container.ReadinessProbe)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestPriorityClassPerComponent(t *testing.T) {
+	tc := []struct {
+		name                         string
+		nodeManagerPriorityClassName string
+		expectedNodeManagerClass     string
+	}{{
+		name:                     "Default node-manager priority class",
+		expectedNodeManagerClass: "system-node-critical",
+	}, {
+		name:                         "Custom node-manager priority class",
+		nodeManagerPriorityClassName: "custom-node-critical",
+		expectedNodeManagerClass:     "custom-node-critical",
+	}}
+
+	for platformName, platform := range getPlatforms() {
+		for _, tc := range tc {
+			t.Run(fmt.Sprintf("%s: %s", platformName, tc.name), func(t *testing.T) {
+				operatorConfig := platform.getOperatorConfig()
+				operatorConfig.NodeManagerPriorityClassName = tc.nodeManagerPriorityClassName
+
+				resources, err := GetResources(operatorConfig)
+				assert.NoError(t, err)
+
+				for _, object := range resources {
+					switch obj := object.(type) {
+					case *appsv1.Deployment:
+						assert.Equal(t, "system-cluster-critical", obj.Spec.Template.Spec.PriorityClassName)
+					case *appsv1.DaemonSet:
+						assert.Equal(t, tc.expectedNodeManagerClass, obj.Spec.Template.Spec.PriorityClassName)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestNodeManagerMinReadySeconds(t *testing.T) {
+	for platformName, platform := range getPlatforms() {
+		t.Run(platformName, func(t *testing.T) {
+			operatorConfig := platform.getOperatorConfig()
+			defaultResources, err := GetResources(operatorConfig)
+			assert.NoError(t, err)
+
+			operatorConfig.NodeManagerMinReadySeconds = ptr.To[int32](45)
+			resources, err := GetResources(operatorConfig)
+			assert.NoError(t, err)
+
+			for i, object := range resources {
+				switch obj := object.(type) {
+				case *appsv1.DaemonSet:
+					assert.Equal(t, int32(45), obj.Spec.MinReadySeconds)
+				case *appsv1.Deployment:
+					// Controller deployments are not affected
+					assert.Equal(t, defaultResources[i].(*appsv1.Deployment).Spec.MinReadySeconds, obj.Spec.MinReadySeconds)
+				}
+			}
+		})
+	}
+}
+
+func TestPDBPolicy(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+
+	tc := []struct {
+		name                   string
+		singleReplica          bool
+		expectedMinAvailable   *intstr.IntOrString
+		expectedMaxUnavailable *intstr.IntOrString
+		expectedPDBs           int
+	}{{
+		name:                   "Max unavailable policy is honored",
+		expectedMaxUnavailable: ptr.To(intstr.FromInt(1)),
+		expectedPDBs:           1,
+	}, {
+		name:          "PDB is omitted on single replica cluster",
+		singleReplica: true,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorConfig := platform.getOperatorConfig()
+			operatorConfig.IsSingleReplica = tc.singleReplica
+			operatorConfig.PDBPolicy = &config.PDBPolicy{
+				Type:  config.PDBPolicyMaxUnavailable,
+				Value: intstr.FromInt(1),
+			}
+
+			resources, err := GetResources(operatorConfig)
+			assert.NoError(t, err)
+
+			var pdbs []*policyv1.PodDisruptionBudget
+			for _, resource := range resources {
+				if pdb, ok := resource.(*policyv1.PodDisruptionBudget); ok {
+					pdbs = append(pdbs, pdb)
+				}
+			}
+			assert.Len(t, pdbs, tc.expectedPDBs)
+			for _, pdb := range pdbs {
+				assert.Equal(t, tc.expectedMinAvailable, pdb.Spec.MinAvailable)
+				assert.Equal(t, tc.expectedMaxUnavailable, pdb.Spec.MaxUnavailable)
+			}
+		})
+	}
+}
+
+func TestDisablePodDisruptionBudget(t *testing.T) {
+	for _, platformName := range []string{string(configv1.AWSPlatformType), string(configv1.AzurePlatformType)} {
+		t.Run(platformName, func(t *testing.T) {
+			platform := getPlatforms()[platformName]
+
+			countPDBs := func(operatorConfig config.OperatorConfig) int {
+				resources, err := GetResources(operatorConfig)
+				assert.NoError(t, err)
+
+				pdbs := 0
+				for _, resource := range resources {
+					if _, ok := resource.(*policyv1.PodDisruptionBudget); ok {
+						pdbs++
+					}
+				}
+				return pdbs
+			}
+
+			operatorConfig := platform.getOperatorConfig()
+			assert.Equal(t, 1, countPDBs(operatorConfig))
+
+			operatorConfig.DisablePodDisruptionBudget = true
+			assert.Zero(t, countPDBs(operatorConfig))
+		})
+	}
+}
+
+func TestDeploymentPodAntiAffinity(t *testing.T) {
+	platforms := getPlatforms()
+	for platformName, platform := range platforms {
+		t.Run(platformName, func(t *testing.T) {
+			resources, err := GetResources(platform.getOperatorConfig())
+			assert.NoError(t, err)
+
+			for _, resource := range resources {
+				switch obj := resource.(type) {
+				case *appsv1.Deployment:
+					checkPodAntiAffinity(t, obj.Spec.Template.Spec, obj.Spec.Selector.MatchLabels)
+				default:
+					// Nothing to check for non
+				}
+			}
+		})
+	}
+}
+
+func checkPodAntiAffinity(t *testing.T, podSpec corev1.PodSpec, selectorLabels map[string]string) {
+	assert.NotNil(t, podSpec.Affinity)
+
+	podAntiAffinity := &corev1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+			{
+				TopologyKey: "kubernetes.io/hostname",
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: selectorLabels,
+				},
+			},
+		},
+	}
+
+	assert.EqualValues(t, podAntiAffinity, podSpec.Affinity.PodAntiAffinity)
+}
+
+func TestAffinity(t *testing.T) {
+	platform := getPlatforms()[string(configv1.AWSPlatformType)]
+	operatorConfig := platform.getOperatorConfig()
+	nodeAffinity := &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      "example.com/cloud-controllers",
+					Operator: corev1.NodeSelectorOpExists,
+				}},
+			}},
+		},
+	}
+	operatorConfig.Affinity = &corev1.Affinity{NodeAffinity: nodeAffinity}
+
+	resources, err := GetResources(operatorConfig)
+	assert.NoError(t, err)
+
+	found := false
+	for _, resource := range resources {
+		deployment, ok := resource.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		found = true
+		checkPodAntiAffinity(t, deployment.Spec.Template.Spec, deployment.Spec.Selector.MatchLabels)
+		assert.Equal(t, nodeAffinity, deployment.Spec.Template.Spec.Affinity.NodeAffinity)
+	}
+	assert.True(t, found, "aws-cloud-controller-manager deployment not found")
+}
+
+func TestDeploymentStrategy(t *testing.T) {
+	/*
+		This test is designed to check that when a Pod is created by the CCCMO,
+		we can update the pod when running on an SNO cluster.
+		Because host ports are used by the pods we create, we must release the
+		port before creating the new pod
+	*/
+
+	platforms := getPlatforms()
+	for platformName, platform := range platforms {
+
+		t.Run(platformName, func(t *testing.T) {
+			resources, err := GetResources(platform.getOperatorConfig())
+			assert.NoError(t, err)
+
+			for _, resource := range resources {
+				switch obj := resource.(type) {
+				case *appsv1.Deployment:
+					checkDeploymentStrategy(t, obj.Spec.Strategy)
+				default:
+					// Nothing to check for non
+				}
+			}
+		})
+	}
+}
+
+func checkDeploymentStrategy(t *testing.T, strategy appsv1.DeploymentStrategy) {
+	if strategy.Type != appsv1.RecreateDeploymentStrategyType {
+		t.Errorf("Deployment should set strategy type to \"Recreate\"")
+	}
+}
+
+func checkTrustedCAMounted(t *testing.T, podSpec corev1.PodSpec, isExempt func(containerName string) bool) {
+	trustedCAVolume := corev1.Volume{
+		Name: "trusted-ca",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "ccm-trusted-ca"},
+			Items:                []corev1.KeyToPath{{Key: "ca-bundle.crt", Path: "tls-ca-bundle.pem"}},
+		}},
+	}
+	trustedCAVolumeMount := corev1.VolumeMount{
+		MountPath: "/etc/pki/ca-trust/extracted/pem",
+		Name:      "trusted-ca",
+		ReadOnly:  true,
+	}
+	assert.Contains(t, podSpec.Volumes, trustedCAVolume, "PodSpec %s volumes should contain trusted-ca volume")
+	containers := append([]corev1.Container{}, podSpec.InitContainers...)
+	for _, c := range append(containers, podSpec.Containers...) {
+		if isExempt(c.Name) {
+			assert.NotContains(t, c.VolumeMounts, trustedCAVolumeMount, "Container %s is exempt from trusted ca volume mount", c.Name)
+			continue
+		}
+		assert.Contains(t, c.VolumeMounts, trustedCAVolumeMount, "Container %s VolumeMounts should contain trusted ca volume mount", c.Name)
+	}
+}
+
+func checkUseServiceAccountCredentials(t *testing.T, podSpec corev1.PodSpec) {
+	const (
+		useServiceAccountCredentials = "--use-service-account-credentials=true"
+	)
+
+	for _, container := range podSpec.Containers {
+		if container.Name != "cloud-controller-manager" {
+			// Only the cloud-controller-manager container needs leader election
+			continue
+		}
+
+		command := container.Command
+		assert.Len(t, command, 3, "Container Command should have 3 elements")
+
+		assert.Contains(t, command[2], useServiceAccountCredentials, "Container Command third (%q) element should contain flag %q", command[2], useServiceAccountCredentials)
+	}
+}
+
+func TestSelectorLabels(t *testing.T) {
+	/*
+		This test checks consistency between all Deployment pod template labels and selector labels
+	*/
+
+	platforms := getPlatforms()
+	for platformName, platform := range platforms {
+
+		t.Run(platformName, func(t *testing.T) {
+			resources, err := GetResources(platform.getOperatorConfig())
+			assert.NoError(t, err)
+
+			for _, resource := range resources {
+				switch obj := resource.(type) {
+				case *appsv1.Deployment:
+					checkDeployementSelectorLabels(t, obj, platform.platformStatus.Type)
+				case *appsv1.DaemonSet:
+					checkDaemonSetSelectorLabels(t, obj, platform.platformStatus.Type)
+				default:
+					// Nothing to check for
+				}
+			}
+		})
+	}
+}
+
+func checkDeployementSelectorLabels(t *testing.T, deployment *appsv1.Deployment, platformType configv1.PlatformType) {
+	assert.Contains(t, deployment.Labels, "k8s-app")
+	assert.Contains(t, deployment.Spec.Template.Labels, "k8s-app")
+	assert.Contains(t, deployment.Spec.Selector.MatchLabels, "k8s-app")
+
+	assert.Contains(t, deployment.Labels, common.CloudControllerManagerProviderLabel)
+	assert.Contains(t, deployment.Spec.Template.Labels, common.CloudControllerManagerProviderLabel)
+	assert.Contains(t, deployment.Spec.Selector.MatchLabels, common.CloudControllerManagerProviderLabel)
+
+	assert.Equal(t, string(platformType), deployment.Labels[common.CloudControllerManagerProviderLabel])
+	assert.Equal(t, string(platformType), deployment.Spec.Template.Labels[common.CloudControllerManagerProviderLabel])
+	assert.Equal(t, string(platformType), deployment.Spec.Selector.MatchLabels[common.CloudControllerManagerProviderLabel])
+}
+
+func checkDaemonSetSelectorLabels(t *testing.T, ds *appsv1.DaemonSet, platformType configv1.PlatformType) {
+	assert.Contains(t, ds.Labels, "k8s-app")
+	assert.Contains(t, ds.Spec.Template.Labels, "k8s-app")
+	assert.Contains(t, ds.Spec.Selector.MatchLabels, "k8s-app")
+
+	assert.Contains(t, ds.Labels, common.CloudNodeManagerCloudProviderLabel)
+	assert.Contains(t, ds.Spec.Template.Labels, common.CloudNodeManagerCloudProviderLabel)
+	assert.Contains(t, ds.Spec.Selector.MatchLabels, common.CloudNodeManagerCloudProviderLabel)
+
+	assert.Equal(t, string(platformType), ds.Labels[common.CloudNodeManagerCloudProviderLabel])
+	assert.Equal(t, string(platformType), ds.Spec.Template.Labels[common.CloudNodeManagerCloudProviderLabel])
 	assert.Equal(t, string(platformType), ds.Spec.Selector.MatchLabels[common.CloudNodeManagerCloudProviderLabel])
 }
 
+func TestCommonLabels(t *testing.T) {
+	/*
+		This test checks that every resource, and every pod created by workloads, carries labels
+		support tooling such as must-gather selects operands on.
+	*/
+
+	platforms := getPlatforms()
+	for platformName, platform := range platforms {
+		t.Run(platformName, func(t *testing.T) {
+			resources, err := GetResources(platform.getOperatorConfig())
+			assert.NoError(t, err)
+
+			for _, resource := range resources {
+				assert.Equal(t, common.PartOfLabelValue, resource.GetLabels()[common.PartOfLabel], "%T %s should have common labels", resource, resource.GetName())
+				assert.Equal(t, common.OwnedByLabelValue, resource.GetLabels()[common.OwnedByLabel], "%T %s should have ownership labels", resource, resource.GetName())
+
+				switch obj := resource.(type) {
+				case *appsv1.Deployment:
+					assert.Equal(t, common.PartOfLabelValue, obj.Spec.Template.Labels[common.PartOfLabel])
+				case *appsv1.DaemonSet:
+					assert.Equal(t, common.PartOfLabelValue, obj.Spec.Template.Labels[common.PartOfLabel])
+				}
+			}
+		})
+	}
+}
+
 func TestReplicas(t *testing.T) {
 	platforms := getPlatforms()
 	for platformName, platform := range platforms {
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AdmissionPolicyParams describes provider-specific invariants enforced by the validating admission policies
+// of the provider, in addition to validations the policy templates define.
+type AdmissionPolicyParams struct {
+	// ProtectedNodeLabels are labels which node updates matched by the policies may not add, change or remove,
+	// e.g. role labels, which would let a compromised node manager attract workloads to its node.
+	ProtectedNodeLabels []string
+}
+
+// SetAdmissionPolicyParams appends validations enforcing the given params to the rendered validating admission policies.
+func SetAdmissionPolicyParams(params AdmissionPolicyParams, renderedObjects []client.Object) []client.Object {
+	substitutedObjects := make([]client.Object, len(renderedObjects))
+	for i, objectTemplate := range renderedObjects {
+		templateCopy := objectTemplate.DeepCopyObject().(client.Object)
+
+		if policy, ok := templateCopy.(*admissionregistrationv1.ValidatingAdmissionPolicy); ok {
+			for _, label := range params.ProtectedNodeLabels {
+				policy.Spec.Validations = append(policy.Spec.Validations, protectedLabelValidation(label))
+			}
+		}
+		substitutedObjects[i] = templateCopy
+	}
+	return substitutedObjects
+}
+
+// protectedLabelValidation returns a validation denying updates which add, change or remove the given label.
+// The label is expected to be a valid label key, which never contains quotes.
+func protectedLabelValidation(label string) admissionregistrationv1.Validation {
+	hasOldLabel := fmt.Sprintf("(has(oldObject.metadata.labels) && '%s' in oldObject.metadata.labels)", label)
+	hasLabel := fmt.Sprintf("(has(object.metadata.labels) && '%s' in object.metadata.labels)", label)
+	return admissionregistrationv1.Validation{
+		Expression: fmt.Sprintf("%s == %s && (!%s || object.metadata.labels['%s'] == oldObject.metadata.labels['%s'])",
+			hasOldLabel, hasLabel, hasLabel, label, label),
+		Message: fmt.Sprintf("updates to Node may not add, change or remove the %q label", label),
+	}
+}
@@ -0,0 +1,57 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSetAdmissionPolicyParams(t *testing.T) {
+	templateValidation := admissionregistrationv1.Validation{
+		Expression: "has(request.userInfo.extra)",
+		Message:    "node-name claim is required",
+	}
+	policy := &admissionregistrationv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-admission"},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregistrationv1.Validation{templateValidation},
+		},
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "cloud-controller-manager"}}
+
+	tc := []struct {
+		name                string
+		params              AdmissionPolicyParams
+		expectedValidations []admissionregistrationv1.Validation
+	}{{
+		name:                "Template validations are kept without params",
+		expectedValidations: []admissionregistrationv1.Validation{templateValidation},
+	}, {
+		name:   "Protected node labels are validated",
+		params: AdmissionPolicyParams{ProtectedNodeLabels: []string{"node-role.kubernetes.io/master"}},
+		expectedValidations: []admissionregistrationv1.Validation{templateValidation, {
+			Expression: "(has(oldObject.metadata.labels) && 'node-role.kubernetes.io/master' in oldObject.metadata.labels) == " +
+				"(has(object.metadata.labels) && 'node-role.kubernetes.io/master' in object.metadata.labels) && " +
+				"(!(has(object.metadata.labels) && 'node-role.kubernetes.io/master' in object.metadata.labels) || " +
+				"object.metadata.labels['node-role.kubernetes.io/master'] == oldObject.metadata.labels['node-role.kubernetes.io/master'])",
+			Message: `updates to Node may not add, change or remove the "node-role.kubernetes.io/master" label`,
+		}},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			initialPolicy := policy.DeepCopy()
+
+			objects := SetAdmissionPolicyParams(tc.params, []client.Object{policy, deployment})
+			assert.Len(t, objects, 2)
+			assert.Equal(t, tc.expectedValidations, objects[0].(*admissionregistrationv1.ValidatingAdmissionPolicy).Spec.Validations)
+			assert.Equal(t, deployment, objects[1])
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, initialPolicy, policy)
+		})
+	}
+}
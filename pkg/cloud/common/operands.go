@@ -0,0 +1,252 @@
+package common
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+const (
+	// operandAppSelectorLabel is set to the name of the operand workload in its selector.
+	operandAppSelectorLabel = "k8s-app"
+
+	// controllerSelectorLabel and nodeManagerSelectorLabel are set to the platform name
+	// in selectors of the controller Deployments and node-manager DaemonSets respectively.
+	controllerSelectorLabel  = "infrastructure.openshift.io/cloud-controller-manager"
+	nodeManagerSelectorLabel = "infrastructure.openshift.io/cloud-node-manager"
+
+	// CloudControllerManagerPort and CloudNodeManagerPort are the host ports registered for
+	// the cloud-controller-manager and cloud-node-manager respectively.
+	CloudControllerManagerPort = 10258
+	CloudNodeManagerPort       = 10263
+)
+
+// registeredHostPorts maps kinds of operand workloads to the only port their containers may use on the host network,
+// the cloud-controller-manager one for deployments and the cloud-node-manager one for daemonsets.
+var registeredHostPorts = map[string]int32{
+	"deployment": CloudControllerManagerPort,
+	"daemonset":  CloudNodeManagerPort,
+}
+
+// OperandImages describes images of the cloud provider components and the containers which run them.
+// Controller runs within a Deployment, node manager, if the provider has one, runs within a DaemonSet.
+// It allows providers with distinct controller and node-manager images to declare them
+// in one place instead of spreading image references across templates.
+type OperandImages struct {
+	ControllerContainerName string
+	ControllerImage         string
+
+	NodeManagerContainerName string
+	NodeManagerImage         string
+}
+
+// SubstituteOperandImages sets the controller image on the controller container of the Deployments
+// and the node-manager image on the node-manager container of the DaemonSets.
+// Empty container names or images are skipped.
+func SubstituteOperandImages(images OperandImages, renderedObjects []client.Object) []client.Object {
+	substitutedObjects := make([]client.Object, len(renderedObjects))
+	for i, objectTemplate := range renderedObjects {
+		templateCopy := objectTemplate.DeepCopyObject().(client.Object)
+
+		switch obj := templateCopy.(type) {
+		case *appsv1.Deployment:
+			setContainerImage(&obj.Spec.Template.Spec, images.ControllerContainerName, images.ControllerImage)
+		case *appsv1.DaemonSet:
+			setContainerImage(&obj.Spec.Template.Spec, images.NodeManagerContainerName, images.NodeManagerImage)
+		}
+		substitutedObjects[i] = templateCopy
+	}
+	return substitutedObjects
+}
+
+// setContainerImage sets the image of the container with the given name in the pod spec.
+func setContainerImage(p *corev1.PodSpec, containerName, image string) {
+	if containerName == "" || image == "" {
+		return
+	}
+	for i, container := range p.Containers {
+		if container.Name != containerName {
+			continue
+		}
+		klog.V(4).Infof("Substituting image for container %q", containerName)
+		p.Containers[i].Image = image
+	}
+}
+
+// OperandSelector returns the selector operand workloads of the given kind and name are expected to have.
+// Only Deployments and DaemonSets are operand workloads, nil is returned for other objects.
+func OperandSelector(config config.OperatorConfig, object client.Object) *metav1.LabelSelector {
+	var platformLabel string
+	switch object.(type) {
+	case *appsv1.Deployment:
+		platformLabel = controllerSelectorLabel
+	case *appsv1.DaemonSet:
+		platformLabel = nodeManagerSelectorLabel
+	default:
+		return nil
+	}
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			operandAppSelectorLabel: object.GetName(),
+			platformLabel:           config.GetPlatformNameString(),
+		},
+	}
+}
+
+// ValidateSelectorLabels checks that selectors of the rendered operand workloads match the ones returned by
+// OperandSelector. Workload selectors are immutable, so a changed selector would force workloads
+// to be recreated on upgrade. This catches templates changing selectors by accident.
+func ValidateSelectorLabels(config config.OperatorConfig, renderedObjects []client.Object) error {
+	for _, object := range renderedObjects {
+		var kind string
+		var selector *metav1.LabelSelector
+		switch obj := object.(type) {
+		case *appsv1.Deployment:
+			kind, selector = "deployment", obj.Spec.Selector
+		case *appsv1.DaemonSet:
+			kind, selector = "daemonset", obj.Spec.Selector
+		default:
+			continue
+		}
+
+		expected := OperandSelector(config, object)
+		if !equality.Semantic.DeepEqual(selector, expected) {
+			return fmt.Errorf("selector of %s %q is %q, expected %q",
+				kind, object.GetName(), metav1.FormatLabelSelector(selector), metav1.FormatLabelSelector(expected))
+		}
+	}
+	return nil
+}
+
+// ValidateOperandImages verifies that cloud-controller-manager and cloud-node-manager containers of the operand
+// workloads run images the active platform requires, so images file or provider mistakes which would run
+// another provider's operands are reported instead of being applied.
+func ValidateOperandImages(config config.OperatorConfig, renderedObjects []client.Object) error {
+	if config.PlatformStatus == nil {
+		return nil
+	}
+	for _, object := range renderedObjects {
+		var kind string
+		var podSpec corev1.PodSpec
+		switch obj := object.(type) {
+		case *appsv1.Deployment:
+			kind, podSpec = "deployment", obj.Spec.Template.Spec
+		case *appsv1.DaemonSet:
+			kind, podSpec = "daemonset", obj.Spec.Template.Spec
+		default:
+			continue
+		}
+		for _, container := range podSpec.Containers {
+			if container.Name != cloudControllerManagerContainerName && container.Name != cloudNodeManagerContainerName {
+				continue
+			}
+			if err := config.ImagesReference.ValidatePlatformImage(config.PlatformStatus.Type, container.Image); err != nil {
+				return fmt.Errorf("container %q of %s %q: %w", container.Name, kind, object.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateHostPorts verifies that host network containers of the operand workloads only listen on, and are probed
+// at, the port reserved for the workload in the host port registry
+// (https://github.com/openshift/enhancements/blob/master/dev-guide/host-port-registry.md), since an
+// unregistered port may conflict with other components running on the host network.
+// Objects are expected to be substituted, so ports added from the config are checked as well.
+func ValidateHostPorts(substitutedObjects []client.Object) error {
+	for _, object := range substitutedObjects {
+		var kind string
+		var podSpec corev1.PodSpec
+		switch obj := object.(type) {
+		case *appsv1.Deployment:
+			kind, podSpec = "deployment", obj.Spec.Template.Spec
+		case *appsv1.DaemonSet:
+			kind, podSpec = "daemonset", obj.Spec.Template.Spec
+		default:
+			continue
+		}
+		if !podSpec.HostNetwork {
+			continue
+		}
+		registeredPort := registeredHostPorts[kind]
+		for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+			for _, port := range containerPorts(container) {
+				if port != registeredPort {
+					return fmt.Errorf("container %q of %s %q uses port %d, which is not registered for host network use, expected %d",
+						container.Name, kind, object.GetName(), port, registeredPort)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// containerPorts returns ports the container listens on, including numeric ports of its probes. Named probe ports
+// refer to ports of the container.
+func containerPorts(container corev1.Container) []int32 {
+	var ports []int32
+	for _, port := range container.Ports {
+		ports = append(ports, port.ContainerPort)
+	}
+	for _, probe := range []*corev1.Probe{container.LivenessProbe, container.ReadinessProbe, container.StartupProbe} {
+		if probe == nil {
+			continue
+		}
+		var port intstr.IntOrString
+		switch {
+		case probe.HTTPGet != nil:
+			port = probe.HTTPGet.Port
+		case probe.TCPSocket != nil:
+			port = probe.TCPSocket.Port
+		case probe.GRPC != nil:
+			port = intstr.FromInt32(probe.GRPC.Port)
+		default:
+			continue
+		}
+		if port.Type == intstr.Int {
+			ports = append(ports, port.IntVal)
+		}
+	}
+	return ports
+}
+
+// ValidateDefaultNodeSelector verifies that the node selector the cluster adds to operand pods on admission does
+// not change the nodes operand workloads select. A default selector which conflicts with the node selector of
+// a workload makes the admission reject its pods, any other label narrows down the nodes the pods run on,
+// e.g. node managers, which have to run on every node, would be kept off control plane nodes.
+func ValidateDefaultNodeSelector(operatorConfig config.OperatorConfig, renderedObjects []client.Object) error {
+	if len(operatorConfig.DefaultNodeSelector) == 0 {
+		return nil
+	}
+
+	keys := sets.List(sets.KeySet(operatorConfig.DefaultNodeSelector))
+	for _, object := range renderedObjects {
+		var kind string
+		var podSpec corev1.PodSpec
+		switch obj := object.(type) {
+		case *appsv1.Deployment:
+			kind, podSpec = "deployment", obj.Spec.Template.Spec
+		case *appsv1.DaemonSet:
+			kind, podSpec = "daemonset", obj.Spec.Template.Spec
+		default:
+			continue
+		}
+		for _, key := range keys {
+			value, ok := podSpec.NodeSelector[key]
+			if !ok || value != operatorConfig.DefaultNodeSelector[key] {
+				return fmt.Errorf("default node selector %s=%s of namespace %s would change the nodes %s %q runs on, set the %s annotation of the namespace to keep operand node selectors",
+					key, operatorConfig.DefaultNodeSelector[key], operatorConfig.ManagedNamespace, kind, object.GetName(), config.NamespaceNodeSelectorAnnotation)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+package common
+
+import (
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+func TestSubstituteOperandImages(t *testing.T) {
+	podSpec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "inject-credentials", Image: "injector"}},
+		Containers: []corev1.Container{
+			{Name: "cloud-controller-manager"},
+			{Name: "cloud-node-manager"},
+			{Name: "sidecar", Image: "sidecar"},
+		},
+	}
+	deployment := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: *podSpec.DeepCopy()}}}
+	daemonSet := &appsv1.DaemonSet{Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: *podSpec.DeepCopy()}}}
+
+	tc := []struct {
+		name                  string
+		images                OperandImages
+		expectedControllerPod []string
+		expectedNodePod       []string
+	}{{
+		name: "Distinct controller and node-manager images",
+		images: OperandImages{
+			ControllerContainerName:  "cloud-controller-manager",
+			ControllerImage:          "controller-image",
+			NodeManagerContainerName: "cloud-node-manager",
+			NodeManagerImage:         "node-manager-image",
+		},
+		expectedControllerPod: []string{"controller-image", "", "sidecar"},
+		expectedNodePod:       []string{"", "node-manager-image", "sidecar"},
+	}, {
+		name: "No node manager",
+		images: OperandImages{
+			ControllerContainerName: "cloud-controller-manager",
+			ControllerImage:         "controller-image",
+		},
+		expectedControllerPod: []string{"controller-image", "", "sidecar"},
+		expectedNodePod:       []string{"", "", "sidecar"},
+	}, {
+		name:                  "Empty images",
+		images:                OperandImages{},
+		expectedControllerPod: []string{"", "", "sidecar"},
+		expectedNodePod:       []string{"", "", "sidecar"},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := SubstituteOperandImages(tc.images, []client.Object{deployment, daemonSet})
+			assert.Len(t, objects, 2)
+
+			getImages := func(p corev1.PodSpec) []string {
+				images := []string{}
+				for _, container := range p.Containers {
+					images = append(images, container.Image)
+				}
+				// Init containers are never substituted
+				assert.Equal(t, "injector", p.InitContainers[0].Image)
+				return images
+			}
+
+			assert.Equal(t, tc.expectedControllerPod, getImages(objects[0].(*appsv1.Deployment).Spec.Template.Spec))
+			assert.Equal(t, tc.expectedNodePod, getImages(objects[1].(*appsv1.DaemonSet).Spec.Template.Spec))
+
+			// Source objects should not be modified
+			assert.Empty(t, deployment.Spec.Template.Spec.Containers[0].Image)
+			assert.Empty(t, daemonSet.Spec.Template.Spec.Containers[1].Image)
+		})
+	}
+}
+
+func TestValidateSelectorLabels(t *testing.T) {
+	operatorConfig := config.OperatorConfig{
+		PlatformStatus: &configv1.PlatformStatus{Type: configv1.AzurePlatformType},
+	}
+	deployment := func(selector map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure-cloud-controller-manager"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: selector}},
+		}
+	}
+	daemonSet := func(selector map[string]string) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure-cloud-node-manager"},
+			Spec:       appsv1.DaemonSetSpec{Selector: &metav1.LabelSelector{MatchLabels: selector}},
+		}
+	}
+
+	tc := []struct {
+		name          string
+		objects       []client.Object
+		expectedError string
+	}{{
+		name: "Expected selectors",
+		objects: []client.Object{
+			deployment(map[string]string{
+				"k8s-app": "azure-cloud-controller-manager",
+				"infrastructure.openshift.io/cloud-controller-manager": "Azure",
+			}),
+			daemonSet(map[string]string{
+				"k8s-app": "azure-cloud-node-manager",
+				"infrastructure.openshift.io/cloud-node-manager": "Azure",
+			}),
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
+		},
+	}, {
+		name: "Deployment selector with an extra label",
+		objects: []client.Object{
+			deployment(map[string]string{
+				"k8s-app": "azure-cloud-controller-manager",
+				"infrastructure.openshift.io/cloud-controller-manager": "Azure",
+				"foo": "bar",
+			}),
+		},
+		expectedError: `selector of deployment "azure-cloud-controller-manager" is "foo=bar,infrastructure.openshift.io/cloud-controller-manager=Azure,k8s-app=azure-cloud-controller-manager", expected "infrastructure.openshift.io/cloud-controller-manager=Azure,k8s-app=azure-cloud-controller-manager"`,
+	}, {
+		name: "DaemonSet selector with the controller label",
+		objects: []client.Object{
+			daemonSet(map[string]string{
+				"k8s-app": "azure-cloud-node-manager",
+				"infrastructure.openshift.io/cloud-controller-manager": "Azure",
+			}),
+		},
+		expectedError: `selector of daemonset "azure-cloud-node-manager" is "infrastructure.openshift.io/cloud-controller-manager=Azure,k8s-app=azure-cloud-node-manager", expected "infrastructure.openshift.io/cloud-node-manager=Azure,k8s-app=azure-cloud-node-manager"`,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSelectorLabels(operatorConfig, tc.objects)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateOperandImages(t *testing.T) {
+	images := config.ImagesReference{
+		CloudControllerManagerOperator: "operator",
+		CloudControllerManagerAWS:      "aws-ccm",
+		CloudControllerManagerAzure:    "azure-ccm",
+		CloudNodeManagerAzure:          "azure-cnm",
+	}
+	deployment := func(containers ...corev1.Container) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloud-controller-manager"},
+			Spec:       appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: containers}}},
+		}
+	}
+	daemonSet := func(containers ...corev1.Container) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloud-node-manager"},
+			Spec:       appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: containers}}},
+		}
+	}
+
+	tc := []struct {
+		name          string
+		platform      configv1.PlatformType
+		objects       []client.Object
+		expectedError string
+	}{{
+		name:     "Platform images",
+		platform: configv1.AzurePlatformType,
+		objects: []client.Object{
+			deployment(corev1.Container{Name: "cloud-controller-manager", Image: "azure-ccm"}),
+			daemonSet(corev1.Container{Name: "cloud-node-manager", Image: "azure-cnm"}),
+		},
+	}, {
+		name:     "Other containers are not checked",
+		platform: configv1.AWSPlatformType,
+		objects: []client.Object{
+			deployment(
+				corev1.Container{Name: "cloud-controller-manager", Image: "aws-ccm"},
+				corev1.Container{Name: "sidecar", Image: "operator"},
+			),
+		},
+	}, {
+		name:     "Image of another provider",
+		platform: configv1.AWSPlatformType,
+		objects: []client.Object{
+			deployment(corev1.Container{Name: "cloud-controller-manager", Image: "azure-ccm"}),
+		},
+		expectedError: `container "cloud-controller-manager" of deployment "cloud-controller-manager": image "azure-ccm" is set as cloudControllerManagerAzure in the images file, expected one of cloudControllerManagerAWS for platform AWS`,
+	}, {
+		name:     "Node manager image of another provider",
+		platform: configv1.AzurePlatformType,
+		objects: []client.Object{
+			daemonSet(corev1.Container{Name: "cloud-node-manager", Image: "aws-ccm"}),
+		},
+		expectedError: `container "cloud-node-manager" of daemonset "cloud-node-manager": image "aws-ccm" is set as cloudControllerManagerAWS in the images file, expected one of cloudControllerManagerAzure, cloudNodeManagerAzure for platform Azure`,
+	}, {
+		name:     "Image missing from the images file",
+		platform: configv1.AWSPlatformType,
+		objects: []client.Object{
+			deployment(corev1.Container{Name: "cloud-controller-manager", Image: "unknown"}),
+		},
+		expectedError: `container "cloud-controller-manager" of deployment "cloud-controller-manager": image "unknown" is not set in the images file, expected one of cloudControllerManagerAWS for platform AWS`,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorConfig := config.OperatorConfig{
+				ImagesReference: images,
+				PlatformStatus:  &configv1.PlatformStatus{Type: tc.platform},
+			}
+			err := ValidateOperandImages(operatorConfig, tc.objects)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateHostPorts(t *testing.T) {
+	deployment := func(hostNetwork bool, ports ...int32) *appsv1.Deployment {
+		container := corev1.Container{Name: "cloud-controller-manager"}
+		for _, port := range ports {
+			container.Ports = append(container.Ports, corev1.ContainerPort{ContainerPort: port})
+		}
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloud-controller-manager"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				HostNetwork: hostNetwork,
+				Containers:  []corev1.Container{container},
+			}}},
+		}
+	}
+
+	tc := []struct {
+		name          string
+		objects       []client.Object
+		expectedError string
+	}{{
+		name: "Registered ports",
+		objects: []client.Object{
+			deployment(true, CloudControllerManagerPort),
+			&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "cloud-node-manager"},
+				Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					HostNetwork: true,
+					Containers: []corev1.Container{{
+						Name:  "cloud-node-manager",
+						Ports: []corev1.ContainerPort{{ContainerPort: CloudNodeManagerPort}},
+					}},
+				}}},
+			},
+		},
+	}, {
+		name:    "Pods outside of the host network are not checked",
+		objects: []client.Object{deployment(false, 8080)},
+	}, {
+		name:          "Unregistered port",
+		objects:       []client.Object{deployment(true, CloudControllerManagerPort, 8080)},
+		expectedError: `container "cloud-controller-manager" of deployment "cloud-controller-manager" uses port 8080, which is not registered for host network use, expected 10258`,
+	}, {
+		name:          "Port registered for another workload",
+		objects:       []client.Object{deployment(true, CloudNodeManagerPort)},
+		expectedError: `container "cloud-controller-manager" of deployment "cloud-controller-manager" uses port 10263, which is not registered for host network use, expected 10258`,
+	}, {
+		name: "Unregistered probe port",
+		objects: []client.Object{&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloud-controller-manager"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				HostNetwork: true,
+				Containers: []corev1.Container{{
+					Name:  "cloud-controller-manager",
+					Ports: []corev1.ContainerPort{{Name: "https", ContainerPort: CloudControllerManagerPort}},
+					LivenessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+						HTTPGet: &corev1.HTTPGetAction{Port: intstr.FromString("https")},
+					}},
+					ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+						HTTPGet: &corev1.HTTPGetAction{Port: intstr.FromInt32(6060)},
+					}},
+				}},
+			}}},
+		}},
+		expectedError: `container "cloud-controller-manager" of deployment "cloud-controller-manager" uses port 6060, which is not registered for host network use, expected 10258`,
+	}, {
+		name: "Unregistered init container port",
+		objects: []client.Object{&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloud-node-manager"},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				HostNetwork: true,
+				InitContainers: []corev1.Container{{
+					Name:  "init",
+					Ports: []corev1.ContainerPort{{ContainerPort: 9000}},
+				}},
+			}}},
+		}},
+		expectedError: `container "init" of daemonset "cloud-node-manager" uses port 9000, which is not registered for host network use, expected 10263`,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateHostPorts(tc.objects)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateDefaultNodeSelector(t *testing.T) {
+	controlPlaneDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloud-controller-manager"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"node-role.kubernetes.io/master": ""},
+		}}},
+	}
+	nodeManagerDaemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloud-node-manager"},
+	}
+
+	tc := []struct {
+		name                string
+		defaultNodeSelector map[string]string
+		expectedError       string
+	}{{
+		name: "No default node selector",
+	}, {
+		name:                "Default node selector conflicting with a node selector",
+		defaultNodeSelector: map[string]string{"node-role.kubernetes.io/master": "false"},
+		expectedError:       `default node selector node-role.kubernetes.io/master=false of namespace openshift-cloud-controller-manager would change the nodes deployment "cloud-controller-manager" runs on, set the openshift.io/node-selector annotation of the namespace to keep operand node selectors`,
+	}, {
+		name:                "Default node selector narrowing down nodes",
+		defaultNodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+		expectedError:       `default node selector node-role.kubernetes.io/worker= of namespace openshift-cloud-controller-manager would change the nodes deployment "cloud-controller-manager" runs on, set the openshift.io/node-selector annotation of the namespace to keep operand node selectors`,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorConfig := config.OperatorConfig{
+				ManagedNamespace:    "openshift-cloud-controller-manager",
+				DefaultNodeSelector: tc.defaultNodeSelector,
+			}
+			err := ValidateDefaultNodeSelector(operatorConfig, []client.Object{controlPlaneDeployment, nodeManagerDaemonSet})
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("Default node selector already selected by operands", func(t *testing.T) {
+		operatorConfig := config.OperatorConfig{DefaultNodeSelector: map[string]string{"node-role.kubernetes.io/master": ""}}
+		assert.NoError(t, ValidateDefaultNodeSelector(operatorConfig, []client.Object{controlPlaneDeployment}))
+
+		err := ValidateDefaultNodeSelector(operatorConfig, []client.Object{nodeManagerDaemonSet})
+		assert.ErrorContains(t, err, `would change the nodes daemonset "cloud-node-manager" runs on`)
+	})
+}
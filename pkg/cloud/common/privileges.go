@@ -0,0 +1,186 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+const (
+	// dropPrivilegesCommand is prepended to the operand binary in the final exec call of the startup script.
+	// setpriv switches the real, effective and saved user and group IDs, so the binary keeps no capabilities.
+	dropPrivilegesCommand = "setpriv --reuid=%d --regid=%d --clear-groups "
+
+	// privilegedPortLimit is the lowest port which can be bound without CAP_NET_BIND_SERVICE.
+	privilegedPortLimit = 1024
+
+	// otherReadMode is the file mode bit which lets a user other than the file owner read it.
+	otherReadMode = 0o004
+)
+
+// dropPrivilegesContainerName returns the name of the workload container whose operand binary drops privileges,
+// when enabled in the config.
+func dropPrivilegesContainerName(object client.Object) string {
+	switch object.(type) {
+	case *appsv1.Deployment:
+		return cloudControllerManagerContainerName
+	case *appsv1.DaemonSet:
+		return cloudNodeManagerContainerName
+	}
+	return ""
+}
+
+// ValidateDropPrivileges checks, when dropping operand privileges is enabled in the config, that the operand
+// binaries can run as the configured user. The binaries have to be executed from bash scripts, to run them
+// with setpriv, must not listen on privileged ports, which the user can not bind, and the user has to be able
+// to read the credentials, cloud-config and CA bundle files mounted for the cloud SDK.
+// Privileges are dropped before the binary starts rather than after it binds its ports, since operands only
+// listen on their registered unprivileged ports.
+// Modes of host files are not known when rendering, so host path volumes, i.e. /etc/kubernetes, may only be
+// read by the startup script, which runs as root. The binary must not be passed paths on host path volumes.
+func ValidateDropPrivileges(config config.OperatorConfig, renderedObjects []client.Object) error {
+	uid := config.DropPrivilegesToUser
+	if uid == 0 {
+		return nil
+	}
+	if uid < 0 {
+		return fmt.Errorf("user ID %d to drop operand privileges to is not valid", uid)
+	}
+
+	for _, object := range renderedObjects {
+		var kind string
+		var podSpec corev1.PodSpec
+		switch obj := object.(type) {
+		case *appsv1.Deployment:
+			kind, podSpec = "deployment", obj.Spec.Template.Spec
+		case *appsv1.DaemonSet:
+			kind, podSpec = "daemonset", obj.Spec.Template.Spec
+		default:
+			continue
+		}
+		volumes := map[string]corev1.Volume{}
+		for _, volume := range podSpec.Volumes {
+			volumes[volume.Name] = volume
+		}
+
+		for _, container := range podSpec.Containers {
+			if container.Name != dropPrivilegesContainerName(object) {
+				continue
+			}
+			if len(container.Command) != 3 || container.Command[0] != "/bin/bash" || !execLineRegexp.MatchString(container.Command[2]) {
+				return fmt.Errorf("can not drop privileges of container %q of %s %q, it does not exec the operand binary from a bash script",
+					container.Name, kind, object.GetName())
+			}
+			execs := execLineRegexp.FindAllStringIndex(container.Command[2], -1)
+			operandCommand := container.Command[2][execs[len(execs)-1][0]:] + " " + strings.Join(container.Args, " ")
+			for _, port := range container.Ports {
+				if port.ContainerPort < privilegedPortLimit {
+					return fmt.Errorf("can not drop privileges of container %q of %s %q, user %d can not bind its privileged port %d",
+						container.Name, kind, object.GetName(), uid, port.ContainerPort)
+				}
+			}
+			for _, mount := range container.VolumeMounts {
+				if volumes[mount.Name].HostPath != nil && referencesPath(operandCommand, mount.MountPath) {
+					return fmt.Errorf("can not drop privileges of container %q of %s %q, user %d may not be able to read files of host path volume %q passed to the operand binary",
+						container.Name, kind, object.GetName(), uid, mount.Name)
+				}
+				if mode, ok := unreadableVolumeMode(volumes[mount.Name]); ok {
+					return fmt.Errorf("can not drop privileges of container %q of %s %q, user %d can not read files of volume %q with mode %#o",
+						container.Name, kind, object.GetName(), uid, mount.Name, mode)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// referencesPath returns whether the command refers to the path or anything below it.
+func referencesPath(command, path string) bool {
+	pathRegexp := regexp.MustCompile(regexp.QuoteMeta(strings.TrimSuffix(path, "/")) + `(/|[\s"',;]|$)`)
+	return pathRegexp.MatchString(command)
+}
+
+// unreadableVolumeMode returns the first mode of files projected by the volume which does not let users other
+// than root read them. Files of other volume types keep their permissions, which are not known when rendering.
+func unreadableVolumeMode(volume corev1.Volume) (int32, bool) {
+	var modes []*int32
+	switch {
+	case volume.Secret != nil:
+		modes = append(modes, volume.Secret.DefaultMode)
+		for _, item := range volume.Secret.Items {
+			modes = append(modes, item.Mode)
+		}
+	case volume.ConfigMap != nil:
+		modes = append(modes, volume.ConfigMap.DefaultMode)
+		for _, item := range volume.ConfigMap.Items {
+			modes = append(modes, item.Mode)
+		}
+	case volume.Projected != nil:
+		modes = append(modes, volume.Projected.DefaultMode)
+		for _, source := range volume.Projected.Sources {
+			switch {
+			case source.Secret != nil:
+				for _, item := range source.Secret.Items {
+					modes = append(modes, item.Mode)
+				}
+			case source.ConfigMap != nil:
+				for _, item := range source.ConfigMap.Items {
+					modes = append(modes, item.Mode)
+				}
+			}
+		}
+	}
+	for _, mode := range modes {
+		if mode != nil && *mode&otherReadMode == 0 {
+			return *mode, true
+		}
+	}
+	return 0, false
+}
+
+// setDropPrivileges makes the operand container of the workload execute its binary as the non-root user
+// from the config, once the startup script, which may source root owned host files, is done.
+// The container keeps only the capabilities setpriv needs to switch the user, and can not regain others.
+// Containers are expected to be validated with ValidateDropPrivileges beforehand.
+func setDropPrivileges(config config.OperatorConfig, containerName string, p corev1.PodSpec) corev1.PodSpec {
+	uid := config.DropPrivilegesToUser
+	if uid <= 0 {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for i, container := range p.Containers {
+		if container.Name != containerName || len(container.Command) != 3 {
+			continue
+		}
+		script := container.Command[2]
+		execs := execLineRegexp.FindAllStringSubmatchIndex(script, -1)
+		if len(execs) == 0 {
+			continue
+		}
+
+		lastExec := execs[len(execs)-1]
+		dropPrivileges := fmt.Sprintf(dropPrivilegesCommand, uid, uid)
+		updatedPod.Containers[i].Command[2] = script[:lastExec[1]] + dropPrivileges + script[lastExec[1]:]
+
+		securityContext := updatedPod.Containers[i].SecurityContext
+		if securityContext == nil {
+			securityContext = &corev1.SecurityContext{}
+		}
+		securityContext.AllowPrivilegeEscalation = ptr.To(false)
+		securityContext.Capabilities = &corev1.Capabilities{
+			Add:  []corev1.Capability{"SETUID", "SETGID"},
+			Drop: []corev1.Capability{"ALL"},
+		}
+		updatedPod.Containers[i].SecurityContext = securityContext
+	}
+
+	return updatedPod
+}
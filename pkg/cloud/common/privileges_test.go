@@ -0,0 +1,224 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+func TestSetDropPrivileges(t *testing.T) {
+	script := `#!/bin/bash
+source /etc/kubernetes/apiserver-url.env
+exec /bin/ccm \
+--v=2
+`
+	droppedScript := `#!/bin/bash
+source /etc/kubernetes/apiserver-url.env
+exec setpriv --reuid=1001 --regid=1001 --clear-groups /bin/ccm \
+--v=2
+`
+	droppedSecurityContext := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities: &corev1.Capabilities{
+			Add:  []corev1.Capability{"SETUID", "SETGID"},
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+
+	tc := []struct {
+		name                     string
+		config                   config.OperatorConfig
+		containers               []corev1.Container
+		expectedScripts          []string
+		expectedSecurityContexts []*corev1.SecurityContext
+	}{{
+		name: "Privileges are not dropped when not enabled",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		expectedScripts:          []string{script},
+		expectedSecurityContexts: []*corev1.SecurityContext{nil},
+	}, {
+		name:   "Only the cloud-controller-manager container drops privileges",
+		config: config.OperatorConfig{DropPrivilegesToUser: 1001},
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}, {
+			Name:    "cloud-config-sidecar",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		expectedScripts:          []string{droppedScript, script},
+		expectedSecurityContexts: []*corev1.SecurityContext{droppedSecurityContext, nil},
+	}, {
+		name:   "Only the last exec call drops privileges, keeping its indentation",
+		config: config.OperatorConfig{DropPrivilegesToUser: 1001},
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec 3>&1\nif true; then\n  exec /bin/ccm --v=2\nfi\n"},
+		}},
+		expectedScripts:          []string{"exec 3>&1\nif true; then\n  exec setpriv --reuid=1001 --regid=1001 --clear-groups /bin/ccm --v=2\nfi\n"},
+		expectedSecurityContexts: []*corev1.SecurityContext{droppedSecurityContext},
+	}, {
+		name:   "Existing security context settings are kept",
+		config: config.OperatorConfig{DropPrivilegesToUser: 1001},
+		containers: []corev1.Container{{
+			Name:            "cloud-controller-manager",
+			Command:         []string{"/bin/bash", "-c", script},
+			SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: ptr.To(true)},
+		}},
+		expectedScripts: []string{droppedScript},
+		expectedSecurityContexts: []*corev1.SecurityContext{{
+			ReadOnlyRootFilesystem:   ptr.To(true),
+			AllowPrivilegeEscalation: droppedSecurityContext.AllowPrivilegeEscalation,
+			Capabilities:             droppedSecurityContext.Capabilities,
+		}},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{Containers: tc.containers}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setDropPrivileges(tc.config, cloudControllerManagerContainerName, podSpec)
+			for i, container := range spec.Containers {
+				assert.Equal(t, tc.expectedScripts[i], container.Command[2])
+				assert.Equal(t, tc.expectedSecurityContexts[i], container.SecurityContext)
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestValidateDropPrivileges(t *testing.T) {
+	newDaemonSet := func(container corev1.Container, volumes ...corev1.Volume) client.Object {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloud-node-manager"},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{container},
+						Volumes:    volumes,
+					},
+				},
+			},
+		}
+	}
+	command := []string{"/bin/bash", "-c", "exec /bin/cnm --v=2"}
+	credentialsMount := corev1.VolumeMount{Name: "cloud-credentials", MountPath: "/etc/cloud-credentials"}
+	credentialsVolume := func(mode *int32) corev1.Volume {
+		return corev1.Volume{
+			Name: "cloud-credentials",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: "cloud-credentials", DefaultMode: mode},
+			},
+		}
+	}
+	hostEtcKubeMount := corev1.VolumeMount{Name: "host-etc-kube", MountPath: "/etc/kubernetes"}
+	hostEtcKubeVolume := corev1.Volume{
+		Name:         "host-etc-kube",
+		VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc/kubernetes"}},
+	}
+
+	tc := []struct {
+		name          string
+		uid           int64
+		object        client.Object
+		expectedError string
+	}{{
+		name:   "Privileges are not dropped",
+		object: newDaemonSet(corev1.Container{Name: "cloud-node-manager", Command: []string{"/bin/cnm"}}),
+	}, {
+		name:          "Negative user ID",
+		uid:           -1,
+		object:        newDaemonSet(corev1.Container{Name: "cloud-node-manager", Command: command}),
+		expectedError: "user ID -1 to drop operand privileges to is not valid",
+	}, {
+		name: "Operand which can run as the user",
+		uid:  1001,
+		object: newDaemonSet(corev1.Container{
+			Name:         "cloud-node-manager",
+			Command:      command,
+			Ports:        []corev1.ContainerPort{{ContainerPort: CloudNodeManagerPort}},
+			VolumeMounts: []corev1.VolumeMount{credentialsMount},
+		}, credentialsVolume(ptr.To[int32](0o644))),
+	}, {
+		name:          "Operand which is not run from a bash script",
+		uid:           1001,
+		object:        newDaemonSet(corev1.Container{Name: "cloud-node-manager", Command: []string{"/bin/cnm", "--v=2"}}),
+		expectedError: `can not drop privileges of container "cloud-node-manager" of daemonset "cloud-node-manager", it does not exec the operand binary from a bash script`,
+	}, {
+		name: "Operand which listens on a privileged port",
+		uid:  1001,
+		object: newDaemonSet(corev1.Container{
+			Name:    "cloud-node-manager",
+			Command: command,
+			Ports:   []corev1.ContainerPort{{ContainerPort: 443}},
+		}),
+		expectedError: `can not drop privileges of container "cloud-node-manager" of daemonset "cloud-node-manager", user 1001 can not bind its privileged port 443`,
+	}, {
+		name: "Operand which mounts credentials only root can read",
+		uid:  1001,
+		object: newDaemonSet(corev1.Container{
+			Name:         "cloud-node-manager",
+			Command:      command,
+			VolumeMounts: []corev1.VolumeMount{credentialsMount},
+		}, credentialsVolume(ptr.To[int32](0o600))),
+		expectedError: `can not drop privileges of container "cloud-node-manager" of daemonset "cloud-node-manager", user 1001 can not read files of volume "cloud-credentials" with mode 0600`,
+	}, {
+		name: "Startup script which reads files of a host path volume",
+		uid:  1001,
+		object: newDaemonSet(corev1.Container{
+			Name:         "cloud-node-manager",
+			Command:      []string{"/bin/bash", "-c", "source /etc/kubernetes/apiserver-url.env\nexec /bin/cnm --cloud-config=/etc/kubernetes-cloud-config/cloud.conf"},
+			VolumeMounts: []corev1.VolumeMount{hostEtcKubeMount},
+		}, hostEtcKubeVolume),
+	}, {
+		name: "Operand which is passed files of a host path volume",
+		uid:  1001,
+		object: newDaemonSet(corev1.Container{
+			Name:         "cloud-node-manager",
+			Command:      []string{"/bin/bash", "-c", "exec /bin/cnm \\\n--cloud-config=/etc/kubernetes/cloud.conf"},
+			VolumeMounts: []corev1.VolumeMount{hostEtcKubeMount},
+		}, hostEtcKubeVolume),
+		expectedError: `can not drop privileges of container "cloud-node-manager" of daemonset "cloud-node-manager", user 1001 may not be able to read files of host path volume "host-etc-kube" passed to the operand binary`,
+	}, {
+		name: "Operand which is passed files of a host path volume in arguments",
+		uid:  1001,
+		object: newDaemonSet(corev1.Container{
+			Name:         "cloud-node-manager",
+			Command:      command,
+			Args:         []string{"--cloud-config", "/etc/kubernetes/cloud.conf"},
+			VolumeMounts: []corev1.VolumeMount{hostEtcKubeMount},
+		}, hostEtcKubeVolume),
+		expectedError: `can not drop privileges of container "cloud-node-manager" of daemonset "cloud-node-manager", user 1001 may not be able to read files of host path volume "host-etc-kube" passed to the operand binary`,
+	}, {
+		name: "Other containers are not checked",
+		uid:  1001,
+		object: newDaemonSet(corev1.Container{
+			Name:         "cloud-config-sidecar",
+			Command:      []string{"/bin/sidecar"},
+			VolumeMounts: []corev1.VolumeMount{credentialsMount},
+		}, credentialsVolume(ptr.To[int32](0o600))),
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDropPrivileges(config.OperatorConfig{DropPrivilegesToUser: tc.uid}, []client.Object{tc.object})
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
@@ -15,22 +19,66 @@ import (
 const (
 	CloudControllerManagerProviderLabel = "infrastructure.openshift.io/cloud-controller-manager"
 	CloudNodeManagerCloudProviderLabel  = "infrastructure.openshift.io/cloud-node-manager"
+
+	// PartOfLabel is set on every operand resource, so support tooling such as must-gather
+	// can select all of them regardless of the platform.
+	PartOfLabel      = "app.kubernetes.io/part-of"
+	PartOfLabelValue = "cloud-controller-manager"
+
+	// OwnedByLabel is set by the operator on every resource it applies, so resources it prunes are told apart
+	// from resources of other components, which may carry the part-of label as well.
+	OwnedByLabel      = "cloud-controller-manager.openshift.io/owned-by"
+	OwnedByLabelValue = "cluster-cloud-controller-manager-operator"
 )
 
+// GetCommonLabels returns labels which are set on every resource managed by the operator.
+func GetCommonLabels() map[string]string {
+	return map[string]string{
+		PartOfLabel: PartOfLabelValue,
+	}
+}
+
+// GetOwnershipLabels returns labels which identify resources applied by the operator.
+func GetOwnershipLabels() map[string]string {
+	return map[string]string{
+		OwnedByLabel: OwnedByLabelValue,
+	}
+}
+
+// SetOwnershipLabels adds ownership labels to metadata of the given objects. Pod templates are left as is,
+// so operand pods are not restarted for it.
+func SetOwnershipLabels(objects []client.Object) {
+	for _, object := range objects {
+		labels := object.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for key, value := range GetOwnershipLabels() {
+			labels[key] = value
+		}
+		object.SetLabels(labels)
+	}
+}
+
 func GetCommonResources(config config.OperatorConfig) ([]client.Object, error) {
 	commonResources := make([]client.Object, 0, 1)
-	if !config.IsSingleReplica {
-		pdb, err := getPDB(config)
-		if err != nil {
-			return nil, err
-		}
-		commonResources = append(commonResources, pdb)
+	if config.IsSingleReplica || config.DisablePodDisruptionBudget {
+		return commonResources, nil
 	}
-	return commonResources, nil
+
+	pdb, err := getPDB(config)
+	if err != nil {
+		return nil, err
+	}
+	return append(commonResources, pdb), nil
 }
 
+// getPDB returns PodDisruptionBudget for cloud-controller-manager pods.
 func getPDB(config config.OperatorConfig) (*policyv1.PodDisruptionBudget, error) {
-	minAvailable := intstr.FromInt(1)
+	spec, err := getPDBSpec(config.PDBPolicy)
+	if err != nil {
+		return nil, err
+	}
 	matchLabels := map[string]string{
 		CloudControllerManagerProviderLabel: config.GetPlatformNameString(),
 	}
@@ -44,12 +92,82 @@ func getPDB(config config.OperatorConfig) (*policyv1.PodDisruptionBudget, error)
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      pdbName,
 			Namespace: config.ManagedNamespace,
+			Labels:    GetCommonLabels(),
 		},
 		Spec: policyv1.PodDisruptionBudgetSpec{
-			MinAvailable: &minAvailable,
+			MinAvailable:   spec.MinAvailable,
+			MaxUnavailable: spec.MaxUnavailable,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: matchLabels,
 			},
 		},
 	}, nil
 }
+
+// getPDBSpec returns PodDisruptionBudget spec with disruption policy set according to the given policy.
+// Defaults to at least one available pod if policy is not set.
+func getPDBSpec(policy *config.PDBPolicy) (policyv1.PodDisruptionBudgetSpec, error) {
+	if policy == nil {
+		return policyv1.PodDisruptionBudgetSpec{MinAvailable: ptr.To(intstr.FromInt(1))}, nil
+	}
+
+	value := policy.Value
+	switch policy.Type {
+	case config.PDBPolicyMinAvailable:
+		return policyv1.PodDisruptionBudgetSpec{MinAvailable: &value}, nil
+	case config.PDBPolicyMaxUnavailable:
+		return policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &value}, nil
+	default:
+		return policyv1.PodDisruptionBudgetSpec{}, fmt.Errorf("unknown pod disruption budget policy type %q", policy.Type)
+	}
+}
+
+// GetServiceAccounts returns service accounts used by operand workloads among the given resources,
+// carrying annotations from the config. Nothing is returned if no service account annotations are configured.
+func GetServiceAccounts(config config.OperatorConfig, resources []client.Object) []client.Object {
+	serviceAccounts := []client.Object{}
+	if len(config.ServiceAccountAnnotations) == 0 {
+		return serviceAccounts
+	}
+
+	seen := map[types.NamespacedName]struct{}{}
+	for _, resource := range resources {
+		var podSpec corev1.PodSpec
+		switch obj := resource.(type) {
+		case *appsv1.Deployment:
+			podSpec = obj.Spec.Template.Spec
+		case *appsv1.DaemonSet:
+			podSpec = obj.Spec.Template.Spec
+		default:
+			continue
+		}
+
+		name := podSpec.ServiceAccountName
+		if name == "" {
+			name = podSpec.DeprecatedServiceAccount
+		}
+		key := types.NamespacedName{Namespace: resource.GetNamespace(), Name: name}
+		if _, found := seen[key]; name == "" || found {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		annotations := make(map[string]string, len(config.ServiceAccountAnnotations))
+		for k, v := range config.ServiceAccountAnnotations {
+			annotations[k] = v
+		}
+		serviceAccounts = append(serviceAccounts, &corev1.ServiceAccount{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ServiceAccount",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   key.Namespace,
+				Labels:      GetCommonLabels(),
+				Annotations: annotations,
+			},
+		})
+	}
+	return serviceAccounts
+}
@@ -0,0 +1,176 @@
+package common
+
+import (
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+func TestGetCommonResources(t *testing.T) {
+	tc := []struct {
+		name         string
+		config       config.OperatorConfig
+		expectedPDBs []string
+	}{{
+		name: "Single replica",
+		config: config.OperatorConfig{
+			IsSingleReplica: true,
+		},
+	}, {
+		name:         "Cluster-wide PDB",
+		config:       config.OperatorConfig{},
+		expectedPDBs: []string{"aws-cloud-controller-manager"},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.config.ManagedNamespace = "openshift-cloud-controller-manager"
+			tc.config.PlatformStatus = &configv1.PlatformStatus{Type: configv1.AWSPlatformType}
+
+			resources, err := GetCommonResources(tc.config)
+			assert.NoError(t, err)
+			assert.Len(t, resources, len(tc.expectedPDBs))
+
+			for i, resource := range resources {
+				pdb, ok := resource.(*policyv1.PodDisruptionBudget)
+				assert.True(t, ok)
+				assert.Equal(t, tc.expectedPDBs[i], pdb.Name)
+				assert.Equal(t, tc.config.ManagedNamespace, pdb.Namespace)
+				assert.Equal(t, 1, pdb.Spec.MinAvailable.IntValue())
+
+				assert.Equal(t, map[string]string{CloudControllerManagerProviderLabel: string(configv1.AWSPlatformType)}, pdb.Spec.Selector.MatchLabels)
+			}
+		})
+	}
+}
+
+func TestGetPDBSpec(t *testing.T) {
+	tc := []struct {
+		name                   string
+		policy                 *config.PDBPolicy
+		expectedMinAvailable   *intstr.IntOrString
+		expectedMaxUnavailable *intstr.IntOrString
+		expectedErr            string
+	}{{
+		name:                 "Default policy",
+		expectedMinAvailable: ptr.To(intstr.FromInt(1)),
+	}, {
+		name:                 "Min available",
+		policy:               &config.PDBPolicy{Type: config.PDBPolicyMinAvailable, Value: intstr.FromString("50%")},
+		expectedMinAvailable: ptr.To(intstr.FromString("50%")),
+	}, {
+		name:                   "Max unavailable",
+		policy:                 &config.PDBPolicy{Type: config.PDBPolicyMaxUnavailable, Value: intstr.FromInt(1)},
+		expectedMaxUnavailable: ptr.To(intstr.FromInt(1)),
+	}, {
+		name:        "Unknown policy type",
+		policy:      &config.PDBPolicy{Type: "MaxAvailable", Value: intstr.FromInt(1)},
+		expectedErr: "unknown pod disruption budget policy type \"MaxAvailable\"",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, err := getPDBSpec(tc.policy)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedMinAvailable, spec.MinAvailable)
+			assert.Equal(t, tc.expectedMaxUnavailable, spec.MaxUnavailable)
+		})
+	}
+}
+
+func TestGetServiceAccounts(t *testing.T) {
+	irsaAnnotation := map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/ccm"}
+
+	deployment := func(namespace, serviceAccount string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ccm", Namespace: namespace},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				ServiceAccountName: serviceAccount,
+			}}},
+		}
+	}
+
+	tc := []struct {
+		name                    string
+		config                  config.OperatorConfig
+		resources               []client.Object
+		expectedServiceAccounts []types.NamespacedName
+	}{{
+		name:      "No annotations configured",
+		resources: []client.Object{deployment("test", "cloud-controller-manager")},
+	}, {
+		name:   "Service accounts of workloads are annotated",
+		config: config.OperatorConfig{ServiceAccountAnnotations: irsaAnnotation},
+		resources: []client.Object{
+			deployment("test", "cloud-controller-manager"),
+			&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "cnm", Namespace: "test"},
+				Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					ServiceAccountName: "cloud-node-manager",
+				}}},
+			},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "test"}},
+		},
+		expectedServiceAccounts: []types.NamespacedName{
+			{Namespace: "test", Name: "cloud-controller-manager"},
+			{Namespace: "test", Name: "cloud-node-manager"},
+		},
+	}, {
+		name:   "Shared service account is returned once",
+		config: config.OperatorConfig{ServiceAccountAnnotations: irsaAnnotation},
+		resources: []client.Object{
+			deployment("test", "cloud-controller-manager"),
+			deployment("test", "cloud-controller-manager"),
+			deployment("other", "cloud-controller-manager"),
+		},
+		expectedServiceAccounts: []types.NamespacedName{
+			{Namespace: "test", Name: "cloud-controller-manager"},
+			{Namespace: "other", Name: "cloud-controller-manager"},
+		},
+	}, {
+		name:   "Deprecated service account field is respected",
+		config: config.OperatorConfig{ServiceAccountAnnotations: irsaAnnotation},
+		resources: []client.Object{&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ccm", Namespace: "test"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				DeprecatedServiceAccount: "cloud-controller-manager",
+			}}},
+		}},
+		expectedServiceAccounts: []types.NamespacedName{
+			{Namespace: "test", Name: "cloud-controller-manager"},
+		},
+	}, {
+		name:      "Workload without service account is skipped",
+		config:    config.OperatorConfig{ServiceAccountAnnotations: irsaAnnotation},
+		resources: []client.Object{deployment("test", "")},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			serviceAccounts := GetServiceAccounts(tc.config, tc.resources)
+			assert.Len(t, serviceAccounts, len(tc.expectedServiceAccounts))
+			for i, obj := range serviceAccounts {
+				serviceAccount, ok := obj.(*corev1.ServiceAccount)
+				assert.True(t, ok)
+				assert.Equal(t, tc.expectedServiceAccounts[i], types.NamespacedName{Namespace: serviceAccount.Namespace, Name: serviceAccount.Name})
+				assert.Equal(t, tc.config.ServiceAccountAnnotations, serviceAccount.Annotations)
+				assert.Equal(t, GetCommonLabels(), serviceAccount.Labels)
+			}
+		})
+	}
+}
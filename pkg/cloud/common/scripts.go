@@ -0,0 +1,165 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+const (
+	// startupScriptsVolumeName is the name of the volume holding command scripts of operand containers,
+	// populated from the startup scripts config map of the workload.
+	startupScriptsVolumeName      = "startup-scripts"
+	startupScriptsMountPath       = "/etc/cloud-controller-manager/startup-scripts"
+	startupScriptsConfigMapSuffix = "-startup-scripts"
+)
+
+// StartupScriptsConfigMapName returns the name of the config map holding command scripts of the given workload.
+func StartupScriptsConfigMapName(workloadName string) string {
+	return workloadName + startupScriptsConfigMapSuffix
+}
+
+// ExternalizeStartupScripts moves bash command scripts of operand containers into a config map rendered per
+// workload, if enabled in the config. Containers run the script mounted from the config map instead of
+// the inline one, so scripts can be audited on the cluster. Scripts are moved as they are rendered,
+// including the API server URL env file sourcing. A provider may render the config map of a workload
+// itself, scripts it defines take precedence over the inline ones. Returned objects are copies.
+func ExternalizeStartupScripts(config config.OperatorConfig, renderedObjects []client.Object) []client.Object {
+	if !config.StartupScriptsFromConfigMap {
+		return renderedObjects
+	}
+
+	providedConfigMaps := map[string]int{}
+	objects := make([]client.Object, 0, len(renderedObjects))
+	for _, object := range renderedObjects {
+		if configMap, ok := object.(*corev1.ConfigMap); ok && strings.HasSuffix(configMap.Name, startupScriptsConfigMapSuffix) {
+			providedConfigMaps[configMap.Name] = len(objects)
+			object = configMap.DeepCopy()
+		}
+		objects = append(objects, object)
+	}
+
+	var configMaps []client.Object
+	for i, object := range objects {
+		var podSpec *corev1.PodSpec
+		switch obj := object.(type) {
+		case *appsv1.Deployment:
+			obj = obj.DeepCopy()
+			podSpec, objects[i] = &obj.Spec.Template.Spec, obj
+		case *appsv1.DaemonSet:
+			obj = obj.DeepCopy()
+			podSpec, objects[i] = &obj.Spec.Template.Spec, obj
+		default:
+			continue
+		}
+
+		configMapName := StartupScriptsConfigMapName(object.GetName())
+		scripts := externalizePodScripts(podSpec, configMapName)
+		if len(scripts) == 0 {
+			continue
+		}
+		if index, ok := providedConfigMaps[configMapName]; ok {
+			configMap := objects[index].(*corev1.ConfigMap)
+			if configMap.Data == nil {
+				configMap.Data = map[string]string{}
+			}
+			for fileName, script := range scripts {
+				if _, ok := configMap.Data[fileName]; !ok {
+					configMap.Data[fileName] = script
+				}
+			}
+			continue
+		}
+		configMaps = append(configMaps, &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      configMapName,
+				Namespace: object.GetNamespace(),
+				Labels:    GetCommonLabels(),
+			},
+			Data: scripts,
+		})
+	}
+	return append(objects, configMaps...)
+}
+
+// externalizePodScripts replaces bash command scripts of the pod containers with a call of the script mounted
+// from the given config map, and returns the scripts keyed by their file name.
+func externalizePodScripts(p *corev1.PodSpec, configMapName string) map[string]string {
+	scripts := map[string]string{}
+	externalize := func(container *corev1.Container) {
+		if len(container.Command) != 3 || container.Command[0] != "/bin/bash" || container.Command[1] != "-c" {
+			return
+		}
+		fileName := container.Name + ".sh"
+		scripts[fileName] = expandContainerVariables(container.Command[2], container.Env)
+		container.Command = []string{"/bin/bash", fmt.Sprintf("%s/%s", startupScriptsMountPath, fileName)}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      startupScriptsVolumeName,
+			MountPath: startupScriptsMountPath,
+			ReadOnly:  true,
+		})
+	}
+	for i := range p.InitContainers {
+		externalize(&p.InitContainers[i])
+	}
+	for i := range p.Containers {
+		externalize(&p.Containers[i])
+	}
+
+	if len(scripts) > 0 {
+		p.Volumes = append(p.Volumes, corev1.Volume{
+			Name: startupScriptsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+					DefaultMode:          ptr.To[int32](0o555),
+				},
+			},
+		})
+	}
+	return scripts
+}
+
+// expandContainerVariables rewrites references the kubelet expands in container commands into their bash
+// equivalents, so the script behaves the same when it is run from a file: $(NAME) of a variable defined
+// in the container env becomes ${NAME} and $$ becomes $. Other $(...) are bash command substitutions.
+func expandContainerVariables(script string, env []corev1.EnvVar) string {
+	defined := sets.New[string]()
+	for _, envVar := range env {
+		defined.Insert(envVar.Name)
+	}
+
+	var expanded strings.Builder
+	for i := 0; i < len(script); i++ {
+		if script[i] != '$' || i+1 == len(script) {
+			expanded.WriteByte(script[i])
+			continue
+		}
+		switch script[i+1] {
+		case '$':
+			expanded.WriteByte('$')
+			i++
+			continue
+		case '(':
+			if end := strings.IndexByte(script[i+2:], ')'); end >= 0 && defined.Has(script[i+2:i+2+end]) {
+				expanded.WriteString("${" + script[i+2:i+2+end] + "}")
+				i += end + 2
+				continue
+			}
+		}
+		expanded.WriteByte(script[i])
+	}
+	return expanded.String()
+}
@@ -0,0 +1,128 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+func TestExpandContainerVariables(t *testing.T) {
+	env := []corev1.EnvVar{{Name: "CLOUD_CONFIG"}, {Name: "NODE_NAME"}}
+
+	tc := []struct {
+		name     string
+		script   string
+		expected string
+	}{{
+		name:     "No references",
+		script:   "exec /bin/cloud-controller-manager --v=2",
+		expected: "exec /bin/cloud-controller-manager --v=2",
+	}, {
+		name:     "Defined variables",
+		script:   "exec /bin/ccm --cloud-config=$(CLOUD_CONFIG) --node-name=$(NODE_NAME)",
+		expected: "exec /bin/ccm --cloud-config=${CLOUD_CONFIG} --node-name=${NODE_NAME}",
+	}, {
+		name:     "Command substitution",
+		script:   "echo $(hostname) $(UNDEFINED)",
+		expected: "echo $(hostname) $(UNDEFINED)",
+	}, {
+		name:     "Escaped dollar",
+		script:   "echo $$(CLOUD_CONFIG) $$BASHPID",
+		expected: "echo $(CLOUD_CONFIG) $BASHPID",
+	}, {
+		name:     "Bash variables",
+		script:   "source /etc/kubernetes/apiserver-url.env; echo ${KUBERNETES_SERVICE_HOST} $! $",
+		expected: "source /etc/kubernetes/apiserver-url.env; echo ${KUBERNETES_SERVICE_HOST} $! $",
+	}, {
+		name:     "Unterminated reference",
+		script:   "echo $(CLOUD_CONFIG",
+		expected: "echo $(CLOUD_CONFIG",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, expandContainerVariables(tc.script, env))
+		})
+	}
+}
+
+func TestExternalizeStartupScripts(t *testing.T) {
+	script := "#!/bin/bash\nset -o allexport\nif [[ -f /etc/kubernetes/apiserver-url.env ]]; then\n  source /etc/kubernetes/apiserver-url.env\nfi\nexec /bin/ccm --cloud-config=$(CLOUD_CONFIG)\n"
+	podSpec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "inject-credentials", Command: []string{"/bin/bash", "-c", "echo inject"}}},
+		Containers: []corev1.Container{
+			{Name: "cloud-controller-manager", Command: []string{"/bin/bash", "-c", script}, Env: []corev1.EnvVar{{Name: "CLOUD_CONFIG"}}},
+			{Name: "sidecar", Command: []string{"/bin/sidecar", "--v=2"}},
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "ccm", Namespace: "openshift-cloud-controller-manager"},
+		Spec:       appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: *podSpec.DeepCopy()}},
+	}
+	initialDeployment := deployment.DeepCopy()
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "ccm"}}
+
+	t.Run("Disabled", func(t *testing.T) {
+		objects := ExternalizeStartupScripts(config.OperatorConfig{}, []client.Object{deployment, serviceAccount})
+		assert.Equal(t, []client.Object{deployment, serviceAccount}, objects)
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		objects := ExternalizeStartupScripts(config.OperatorConfig{StartupScriptsFromConfigMap: true}, []client.Object{deployment, serviceAccount})
+		assert.EqualValues(t, initialDeployment, deployment)
+		assert.Len(t, objects, 3)
+		assert.Equal(t, serviceAccount, objects[1])
+
+		configMap, ok := objects[2].(*corev1.ConfigMap)
+		assert.True(t, ok)
+		assert.Equal(t, "ccm-startup-scripts", configMap.Name)
+		assert.Equal(t, "openshift-cloud-controller-manager", configMap.Namespace)
+		assert.Equal(t, GetCommonLabels(), configMap.Labels)
+		assert.Equal(t, map[string]string{
+			"inject-credentials.sh":       "echo inject",
+			"cloud-controller-manager.sh": "#!/bin/bash\nset -o allexport\nif [[ -f /etc/kubernetes/apiserver-url.env ]]; then\n  source /etc/kubernetes/apiserver-url.env\nfi\nexec /bin/ccm --cloud-config=${CLOUD_CONFIG}\n",
+		}, configMap.Data)
+
+		renderedSpec := objects[0].(*appsv1.Deployment).Spec.Template.Spec
+		mount := corev1.VolumeMount{Name: "startup-scripts", MountPath: "/etc/cloud-controller-manager/startup-scripts", ReadOnly: true}
+		assert.Equal(t, []string{"/bin/bash", "/etc/cloud-controller-manager/startup-scripts/inject-credentials.sh"}, renderedSpec.InitContainers[0].Command)
+		assert.Equal(t, []corev1.VolumeMount{mount}, renderedSpec.InitContainers[0].VolumeMounts)
+		assert.Equal(t, []string{"/bin/bash", "/etc/cloud-controller-manager/startup-scripts/cloud-controller-manager.sh"}, renderedSpec.Containers[0].Command)
+		assert.Equal(t, []corev1.VolumeMount{mount}, renderedSpec.Containers[0].VolumeMounts)
+		assert.Equal(t, podSpec.Containers[1], renderedSpec.Containers[1])
+		assert.Len(t, renderedSpec.Volumes, 1)
+		assert.Equal(t, "startup-scripts", renderedSpec.Volumes[0].Name)
+		assert.Equal(t, "ccm-startup-scripts", renderedSpec.Volumes[0].ConfigMap.Name)
+	})
+}
+
+func TestExternalizeStartupScriptsProvided(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "ccm", Namespace: "openshift-cloud-controller-manager"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "inject-credentials", Command: []string{"/bin/bash", "-c", "echo inject"}}},
+			Containers:     []corev1.Container{{Name: "cloud-controller-manager", Command: []string{"/bin/bash", "-c", "exec /bin/ccm"}}},
+		}}},
+	}
+	providedConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ccm-startup-scripts", Namespace: "openshift-cloud-controller-manager"},
+		Data:       map[string]string{"cloud-controller-manager.sh": "exec /bin/ccm --provider-specific"},
+	}
+	initialConfigMap := providedConfigMap.DeepCopy()
+
+	objects := ExternalizeStartupScripts(config.OperatorConfig{StartupScriptsFromConfigMap: true}, []client.Object{providedConfigMap, deployment})
+	assert.EqualValues(t, initialConfigMap, providedConfigMap)
+	assert.Len(t, objects, 2)
+	assert.Equal(t, map[string]string{
+		"inject-credentials.sh":       "echo inject",
+		"cloud-controller-manager.sh": "exec /bin/ccm --provider-specific",
+	}, objects[0].(*corev1.ConfigMap).Data)
+	assert.Equal(t, []string{"/bin/bash", "/etc/cloud-controller-manager/startup-scripts/cloud-controller-manager.sh"},
+		objects[1].(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Command)
+}
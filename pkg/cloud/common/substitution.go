@@ -1,9 +1,19 @@
 package common
 
 import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
 	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -11,6 +21,117 @@ import (
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
 )
 
+const (
+	// cloudControllerManagerContainerName is the name of the container running the cloud-controller-manager
+	// binary, the same across all providers.
+	cloudControllerManagerContainerName = "cloud-controller-manager"
+
+	// cloudNodeManagerContainerName is the name of the container running the cloud-node-manager binary
+	// on platforms which have one.
+	cloudNodeManagerContainerName = "cloud-node-manager"
+
+	// allocateNodeCIDRsFlag controls whether cloud-controller-manager allocates CIDRs for nodes.
+	// OpenShift networking allocates pod CIDRs itself, so this is disabled unless a provider needs it.
+	allocateNodeCIDRsFlag = "--allocate-node-cidrs"
+
+	// configureCloudRoutesFlag controls whether cloud-controller-manager runs the cloud route controller.
+	// The route controller sets up inter pod networking using cloud networks, which isn't required
+	// with an overlay network as used within OpenShift, so it is disabled unless configured otherwise.
+	configureCloudRoutesFlag = "--configure-cloud-routes"
+
+	defaultNodeManagerPriorityClassName = "system-node-critical"
+
+	// DNSConfigHashAnnotation is set on operand pod templates to the hash of the cluster DNS config,
+	// so operand pods are restarted when the DNS config changes.
+	DNSConfigHashAnnotation = "operator.openshift.io/dns-config-hash"
+
+	// trustedCAVolumeName is the name of the volume holding the merged trusted CA bundle,
+	// populated from the ccm-trusted-ca config map maintained by the trusted CA bundle controller.
+	trustedCAVolumeName      = "trusted-ca"
+	trustedCAConfigMapName   = "ccm-trusted-ca"
+	trustedCABundleKey       = "ca-bundle.crt"
+	trustedCABundleFileName  = "tls-ca-bundle.pem"
+	trustedCAVolumeMountPath = "/etc/pki/ca-trust/extracted/pem"
+)
+
+const (
+	// azureCredentialsSecretName is the name of the secret Azure operand templates read credentials from.
+	azureCredentialsSecretName = "azure-cloud-credentials"
+	// azureCredentialsInjectorContainerName is the name of the init container merging Azure credentials
+	// into the cloud config, see Azure assets.
+	azureCredentialsInjectorContainerName = "azure-inject-credentials"
+	// azureCredentialsVolumeName is the name of the volume holding the Azure credentials file.
+	azureCredentialsVolumeName = "azure-credentials"
+)
+
+// boundSATokenVolumeName is the name of the volume projecting a bound service account token into operands,
+// which they exchange for short-lived cloud credentials when workload identity is used.
+const boundSATokenVolumeName = "bound-sa-token"
+
+// cloudMetadataEndpoints maps platforms to the address of their instance metadata endpoint,
+// which the cloud API readiness probe checks.
+var cloudMetadataEndpoints = map[configv1.PlatformType]string{
+	configv1.AWSPlatformType:       "169.254.169.254:80",
+	configv1.AzurePlatformType:     "169.254.169.254:80",
+	configv1.GCPPlatformType:       "169.254.169.254:80",
+	configv1.OpenStackPlatformType: "169.254.169.254:80",
+}
+
+// loggingFormatPlatforms lists platforms whose cloud-controller-manager supports the logging-format flag.
+var loggingFormatPlatforms = sets.New[configv1.PlatformType](
+	configv1.AWSPlatformType,
+	configv1.AzurePlatformType,
+	configv1.GCPPlatformType,
+	configv1.OpenStackPlatformType,
+	configv1.VSpherePlatformType,
+)
+
+// trustedCAMountExemptContainers lists names of containers which do not talk to cloud APIs over TLS,
+// so mounting the trusted CA bundle into them is unnecessary.
+var trustedCAMountExemptContainers = sets.New[string](
+	"azure-inject-credentials",
+)
+
+// IsTrustedCAMountExempt returns true if the container with the given name does not get the trusted CA bundle mounted.
+func IsTrustedCAMountExempt(containerName string) bool {
+	return trustedCAMountExemptContainers.Has(containerName)
+}
+
+// verbosityFlagRegexp matches both -v=N and --v=N forms of the log verbosity flag.
+var verbosityFlagRegexp = regexp.MustCompile(`(^|\s)(--?v=)\d+(\s|$)`)
+
+// configureCloudRoutesFlagRegexp matches the configure-cloud-routes flag with its value.
+var configureCloudRoutesFlagRegexp = regexp.MustCompile(`(^|\s)(--configure-cloud-routes=)\S+`)
+
+// leaderElectResourceNamespaceFlagRegexp matches the leader election resource namespace flag with its value.
+var leaderElectResourceNamespaceFlagRegexp = regexp.MustCompile(`(^|\s)(--leader-elect-resource-namespace=)\S+`)
+
+// loggingFormatFlagRegexp matches the logging format flag with its value.
+var loggingFormatFlagRegexp = regexp.MustCompile(`(^|\s)(--logging-format=)\S+`)
+
+// execLineRegexp matches lines of a command script executing a binary, capturing the line indentation.
+var execLineRegexp = regexp.MustCompile(`(?m)^([ \t]*)exec `)
+
+const (
+	// startupWatchdogStart starts a background watchdog terminating the script once the startup timeout,
+	// given in seconds, expires. The script PID is taken from BASHPID, since the kubelet reduces $$
+	// in container commands to a single $.
+	startupWatchdogStart = `startup_script_pid=$BASHPID
+( sleep %d; echo "Startup did not complete within %ds, terminating" >&2; kill -TERM "$startup_script_pid" ) &
+startup_watchdog_pid=$!
+`
+	// startupWatchdogStop stops the startup watchdog before the operand binary is executed.
+	startupWatchdogStop = `kill "$startup_watchdog_pid" 2>/dev/null || true`
+)
+
+// effectiveFlagsEchoPrefix is echoed, followed by the operand command, by cloud-controller-manager containers
+// logging their effective flags.
+const effectiveFlagsEchoPrefix = `echo "Effective cloud-controller-manager command:" `
+
+// commandFlagRegexp matches names of the flags passed in a command script, with either one or two leading dashes,
+// since operand binaries accept both forms.
+var commandFlagRegexp = regexp.MustCompile(`(?:^|\s)-{1,2}([A-Za-z0-9][A-Za-z0-9-]*)`)
+
 // setProxySettings substitutes controller containers in provided pod specs with cluster wide proxy settings
 func setProxySettings(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
 	clusterProxyEnvVars := getProxyArgs(config.ClusterProxy)
@@ -27,6 +148,30 @@ func setProxySettings(config config.OperatorConfig, p corev1.PodSpec) corev1.Pod
 	return updatedPod
 }
 
+// setCloudEndpointOverrides sets cloud endpoint override environment variables on operand containers,
+// replacing variables with the same name set by provider manifests.
+func setCloudEndpointOverrides(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if len(config.CloudEndpointOverrides) == 0 {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for i := range updatedPod.Containers {
+		container := &updatedPod.Containers[i]
+		for _, override := range config.CloudEndpointOverrides {
+			envVar := corev1.EnvVar{Name: override.EnvName, Value: override.URL}
+			idx := slices.IndexFunc(container.Env, func(e corev1.EnvVar) bool { return e.Name == override.EnvName })
+			if idx >= 0 {
+				container.Env[idx] = envVar
+			} else {
+				container.Env = append(container.Env, envVar)
+			}
+		}
+	}
+
+	return updatedPod
+}
+
 // getProxyArg converts a cluster wide proxy configuration into a list of
 // env variable objects for pods.
 func getProxyArgs(proxy *configv1.Proxy) []corev1.EnvVar {
@@ -56,19 +201,878 @@ func getProxyArgs(proxy *configv1.Proxy) []corev1.EnvVar {
 	return envVars
 }
 
+// appendCommandFlag appends the flag to the end of the container command script.
+// Command is expected to be a bash script ending with the cloud-controller-manager exec call.
+func appendCommandFlag(script, flag string) string {
+	return fmt.Sprintf("%s \\\n%s\n", strings.TrimRight(script, " \n"), flag)
+}
+
+// setAllocateNodeCIDRs appends the allocate-node-cidrs flag to the cloud-controller-manager container command,
+// if the config overrides it. Templates which already set the flag are left untouched.
+func setAllocateNodeCIDRs(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if config.AllocateNodeCIDRs == nil {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for i, container := range p.Containers {
+		if container.Name != cloudControllerManagerContainerName || len(container.Command) != 3 {
+			continue
+		}
+		script := container.Command[2]
+		if strings.Contains(script, allocateNodeCIDRsFlag) {
+			continue
+		}
+		updatedPod.Containers[i].Command[2] = appendCommandFlag(script, fmt.Sprintf("%s=%t", allocateNodeCIDRsFlag, *config.AllocateNodeCIDRs))
+	}
+
+	return updatedPod
+}
+
+// setConfigureCloudRoutes rewrites the configure-cloud-routes flag in the cloud-controller-manager container command
+// according to the config, or adds the flag if the command does not have it.
+func setConfigureCloudRoutes(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	configureCloudRoutes := ptr.Deref(config.ConfigureCloudRoutes, false)
+
+	updatedPod := *p.DeepCopy()
+	for i, container := range p.Containers {
+		if container.Name != cloudControllerManagerContainerName || len(container.Command) != 3 {
+			continue
+		}
+		script := container.Command[2]
+		if configureCloudRoutesFlagRegexp.MatchString(script) {
+			script = configureCloudRoutesFlagRegexp.ReplaceAllString(script, fmt.Sprintf("${1}${2}%t", configureCloudRoutes))
+		} else {
+			script = appendCommandFlag(script, fmt.Sprintf("%s=%t", configureCloudRoutesFlag, configureCloudRoutes))
+		}
+		updatedPod.Containers[i].Command[2] = script
+	}
+
+	return updatedPod
+}
+
+// setLeaderElectResourceNamespace rewrites the leader election resource namespace flag in the cloud-controller-manager
+// container command to the managed namespace, or adds the flag if the command does not have it, so the leader election
+// lease is kept next to the operands when the operator runs with a non-default managed namespace.
+func setLeaderElectResourceNamespace(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if config.ManagedNamespace == "" {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for i, container := range p.Containers {
+		if container.Name != cloudControllerManagerContainerName || len(container.Command) != 3 {
+			continue
+		}
+		script := container.Command[2]
+		if leaderElectResourceNamespaceFlagRegexp.MatchString(script) {
+			script = leaderElectResourceNamespaceFlagRegexp.ReplaceAllString(script, fmt.Sprintf("${1}${2}%s", config.ManagedNamespace))
+		} else {
+			script = appendCommandFlag(script, fmt.Sprintf("--leader-elect-resource-namespace=%s", config.ManagedNamespace))
+		}
+		updatedPod.Containers[i].Command[2] = script
+	}
+
+	return updatedPod
+}
+
+// setVerbosity rewrites the log verbosity flag in the cloud-controller-manager container command
+// if verbosity is set in the config, or adds the flag if the command does not have it.
+func setVerbosity(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if config.CCMVerbosity == nil {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for i, container := range p.Containers {
+		if container.Name != cloudControllerManagerContainerName || len(container.Command) != 3 {
+			continue
+		}
+		script := container.Command[2]
+		if verbosityFlagRegexp.MatchString(script) {
+			script = verbosityFlagRegexp.ReplaceAllString(script, fmt.Sprintf("${1}${2}%d${3}", *config.CCMVerbosity))
+		} else {
+			script = appendCommandFlag(script, fmt.Sprintf("--v=%d", *config.CCMVerbosity))
+		}
+		updatedPod.Containers[i].Command[2] = script
+	}
+
+	return updatedPod
+}
+
+// setLoggingFormat rewrites the logging format flag in the cloud-controller-manager container command
+// if the format is set in the config, or adds the flag if the command does not have it.
+// Platforms whose cloud-controller-manager does not support the flag are left untouched.
+// The format is expected to be validated beforehand.
+func setLoggingFormat(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if config.LoggingFormat == "" || config.PlatformStatus == nil || !loggingFormatPlatforms.Has(config.PlatformStatus.Type) {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for i, container := range p.Containers {
+		if container.Name != cloudControllerManagerContainerName || len(container.Command) != 3 {
+			continue
+		}
+		script := container.Command[2]
+		if loggingFormatFlagRegexp.MatchString(script) {
+			script = loggingFormatFlagRegexp.ReplaceAllString(script, fmt.Sprintf("${1}${2}%s", config.LoggingFormat))
+		} else {
+			script = appendCommandFlag(script, fmt.Sprintf("--logging-format=%s", config.LoggingFormat))
+		}
+		updatedPod.Containers[i].Command[2] = script
+	}
+
+	return updatedPod
+}
+
+// setCloudAPIReadinessProbe sets a readiness probe checking that the cloud metadata endpoint is reachable
+// on the cloud-controller-manager container, if enabled in the config and the platform has a metadata endpoint.
+// The probe relies on bash /dev/tcp redirection, since operand images do not ship network tools.
+func setCloudAPIReadinessProbe(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if !config.CloudAPIReadinessProbe || config.PlatformStatus == nil {
+		return p
+	}
+	endpoint, ok := cloudMetadataEndpoints[config.PlatformStatus.Type]
+	if !ok {
+		return p
+	}
+	host, port, _ := strings.Cut(endpoint, ":")
+
+	updatedPod := *p.DeepCopy()
+	for i, container := range updatedPod.Containers {
+		if container.Name != cloudControllerManagerContainerName {
+			continue
+		}
+		updatedPod.Containers[i].ReadinessProbe = &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				Exec: &corev1.ExecAction{
+					Command: []string{"/bin/bash", "-c", fmt.Sprintf("exec 3<>/dev/tcp/%s/%s", host, port)},
+				},
+			},
+			TimeoutSeconds:   5,
+			PeriodSeconds:    10,
+			FailureThreshold: 3,
+		}
+	}
+	return updatedPod
+}
+
+// setAutosizedResources sets resource requests of the cloud-controller-manager container
+// from the cluster node count, if resource autosizing is enabled in the config.
+// Requests for resources autosizing does not cover are kept.
+func setAutosizedResources(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if config.ResourceAutosizing == nil {
+		return p
+	}
+
+	requests := config.ResourceAutosizing.Requests(config.NodeCount)
+	updatedPod := *p.DeepCopy()
+	for i, container := range updatedPod.Containers {
+		if container.Name != cloudControllerManagerContainerName {
+			continue
+		}
+		if updatedPod.Containers[i].Resources.Requests == nil {
+			updatedPod.Containers[i].Resources.Requests = corev1.ResourceList{}
+		}
+		for name, quantity := range requests {
+			updatedPod.Containers[i].Resources.Requests[name] = quantity
+		}
+	}
+	return updatedPod
+}
+
+// setNodeManagerPriorityClass sets the priority class of node-manager pods from the config,
+// falling back to system-node-critical, since node-manager has to run on every node.
+func setNodeManagerPriorityClass(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	updatedPod := *p.DeepCopy()
+	updatedPod.PriorityClassName = defaultNodeManagerPriorityClassName
+	if config.NodeManagerPriorityClassName != "" {
+		updatedPod.PriorityClassName = config.NodeManagerPriorityClassName
+	}
+	return updatedPod
+}
+
+// setPodSysctls sets sysctls configured for the platform on the pod security context. Configured values
+// take precedence over sysctls of the same name set by the provider manifests.
+func setPodSysctls(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if config.PlatformStatus == nil || len(config.PodSysctls[config.PlatformStatus.Type]) == 0 {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	if updatedPod.SecurityContext == nil {
+		updatedPod.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	for _, sysctl := range config.PodSysctls[config.PlatformStatus.Type] {
+		i := slices.IndexFunc(updatedPod.SecurityContext.Sysctls, func(existing corev1.Sysctl) bool {
+			return existing.Name == sysctl.Name
+		})
+		if i < 0 {
+			updatedPod.SecurityContext.Sysctls = append(updatedPod.SecurityContext.Sysctls, sysctl)
+		} else {
+			updatedPod.SecurityContext.Sysctls[i] = sysctl
+		}
+	}
+	return updatedPod
+}
+
+// setHostAliases adds host aliases from the config to the pod. Kubelet writes them to /etc/hosts of
+// host network pods as well, so they do not depend on the node resolver configuration. Hostnames are
+// merged into an alias of the pod with the same IP address.
+func setHostAliases(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if len(config.HostAliases) == 0 {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for _, alias := range config.HostAliases {
+		i := slices.IndexFunc(updatedPod.HostAliases, func(existing corev1.HostAlias) bool {
+			return existing.IP == alias.IP
+		})
+		if i < 0 {
+			updatedPod.HostAliases = append(updatedPod.HostAliases, *alias.DeepCopy())
+			continue
+		}
+		for _, hostname := range alias.Hostnames {
+			if !slices.Contains(updatedPod.HostAliases[i].Hostnames, hostname) {
+				updatedPod.HostAliases[i].Hostnames = append(updatedPod.HostAliases[i].Hostnames, hostname)
+			}
+		}
+	}
+	return updatedPod
+}
+
+// setAffinity merges the affinity from the config into the pod affinity. Required node selector terms are
+// combined with the pod ones, so pods have to match both, preferred terms are appended. Pod affinity and
+// pod anti-affinity of the pod are replaced only if they are set in the config.
+func setAffinity(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if config.Affinity == nil {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	if updatedPod.Affinity == nil {
+		updatedPod.Affinity = &corev1.Affinity{}
+	}
+	override := config.Affinity.DeepCopy()
+	if override.NodeAffinity != nil {
+		updatedPod.Affinity.NodeAffinity = mergeNodeAffinity(updatedPod.Affinity.NodeAffinity, override.NodeAffinity)
+	}
+	if override.PodAffinity != nil {
+		updatedPod.Affinity.PodAffinity = override.PodAffinity
+	}
+	if override.PodAntiAffinity != nil {
+		updatedPod.Affinity.PodAntiAffinity = override.PodAntiAffinity
+	}
+	return updatedPod
+}
+
+// mergeNodeAffinity returns node affinity requiring nodes to match both required node selectors,
+// and preferring nodes matching any of the preferred terms.
+func mergeNodeAffinity(affinity, override *corev1.NodeAffinity) *corev1.NodeAffinity {
+	if affinity == nil {
+		return override
+	}
+
+	merged := affinity.DeepCopy()
+	merged.PreferredDuringSchedulingIgnoredDuringExecution = append(merged.PreferredDuringSchedulingIgnoredDuringExecution,
+		override.PreferredDuringSchedulingIgnoredDuringExecution...)
+
+	required, overrideRequired := merged.RequiredDuringSchedulingIgnoredDuringExecution, override.RequiredDuringSchedulingIgnoredDuringExecution
+	switch {
+	case overrideRequired == nil:
+	case required == nil:
+		merged.RequiredDuringSchedulingIgnoredDuringExecution = overrideRequired
+	default:
+		// Node selector terms are ORed, while requirements within a term are ANDed, so each pair of terms
+		// is combined into a term with requirements of both.
+		var terms []corev1.NodeSelectorTerm
+		for _, term := range required.NodeSelectorTerms {
+			for _, overrideTerm := range overrideRequired.NodeSelectorTerms {
+				terms = append(terms, corev1.NodeSelectorTerm{
+					MatchExpressions: append(slices.Clone(term.MatchExpressions), overrideTerm.MatchExpressions...),
+					MatchFields:      append(slices.Clone(term.MatchFields), overrideTerm.MatchFields...),
+				})
+			}
+		}
+		merged.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{NodeSelectorTerms: terms}
+	}
+	return merged
+}
+
+// setInitContainerResources replaces resource requirements of every init container with ones from the config.
+func setInitContainerResources(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if config.InitContainerResources == nil {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for i := range updatedPod.InitContainers {
+		updatedPod.InitContainers[i].Resources = *config.InitContainerResources.DeepCopy()
+	}
+	return updatedPod
+}
+
+// setCredentialsMode adjusts credential mounts to the cloud-credential-operator mode. In Mint and Passthrough
+// modes the operator writes long-lived credentials into operand secrets, so the bound service account token,
+// only exchanged for credentials with workload identity set up in Manual mode, is not mounted.
+func setCredentialsMode(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if config.CredentialsMode != operatorv1.CloudCredentialsModeMint && config.CredentialsMode != operatorv1.CloudCredentialsModePassthrough {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	updatedPod.Volumes = slices.DeleteFunc(updatedPod.Volumes, func(volume corev1.Volume) bool {
+		return volume.Name == boundSATokenVolumeName
+	})
+	isBoundSATokenMount := func(mount corev1.VolumeMount) bool {
+		return mount.Name == boundSATokenVolumeName
+	}
+	for i := range updatedPod.InitContainers {
+		updatedPod.InitContainers[i].VolumeMounts = slices.DeleteFunc(updatedPod.InitContainers[i].VolumeMounts, isBoundSATokenMount)
+	}
+	for i := range updatedPod.Containers {
+		updatedPod.Containers[i].VolumeMounts = slices.DeleteFunc(updatedPod.Containers[i].VolumeMounts, isBoundSATokenMount)
+	}
+
+	return updatedPod
+}
+
+// setAzureCredentials points Azure operands to the credentials secret configured in the config. If a credentials
+// file path is configured, the secret is mounted into the credentials injector, which reads credentials from
+// the file instead of env variables referencing the secret.
+func setAzureCredentials(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if config.AzureCredentials == nil || config.PlatformStatus == nil || config.PlatformStatus.Type != configv1.AzurePlatformType {
+		return p
+	}
+	secretName := config.AzureCredentials.SecretName
+	if secretName == "" {
+		secretName = azureCredentialsSecretName
+	}
+
+	updatedPod := *p.DeepCopy()
+	setSecretName := func(container *corev1.Container) {
+		for i, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == azureCredentialsSecretName {
+				container.Env[i].ValueFrom.SecretKeyRef.Name = secretName
+			}
+		}
+	}
+	for i := range updatedPod.InitContainers {
+		setSecretName(&updatedPod.InitContainers[i])
+	}
+	for i := range updatedPod.Containers {
+		setSecretName(&updatedPod.Containers[i])
+	}
+
+	filePath := config.AzureCredentials.FilePath
+	if filePath == "" {
+		return updatedPod
+	}
+	injectorFound := false
+	for i, container := range updatedPod.InitContainers {
+		if container.Name != azureCredentialsInjectorContainerName {
+			continue
+		}
+		injectorFound = true
+		updatedPod.InitContainers[i].Env = slices.DeleteFunc(container.Env, func(env corev1.EnvVar) bool {
+			return env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == secretName
+		})
+		// The injector is either run by a bash script, or directly with flags passed as args
+		credsFileFlag := fmt.Sprintf("--creds-file=%s", filePath)
+		if len(container.Command) == 3 && container.Command[0] == "/bin/bash" {
+			updatedPod.InitContainers[i].Command[2] = appendCommandFlag(container.Command[2], credsFileFlag)
+		} else {
+			updatedPod.InitContainers[i].Args = append(container.Args, credsFileFlag)
+		}
+		// Only the credentials file is mounted, so files of its directory in the container image are kept
+		updatedPod.InitContainers[i].VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      azureCredentialsVolumeName,
+			MountPath: filePath,
+			SubPath:   filepath.Base(filePath),
+			ReadOnly:  true,
+		})
+	}
+	if injectorFound {
+		updatedPod.Volumes = append(updatedPod.Volumes, corev1.Volume{
+			Name: azureCredentialsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secretName,
+					Items:      []corev1.KeyToPath{{Key: filepath.Base(filePath), Path: filepath.Base(filePath)}},
+				},
+			},
+		})
+	}
+	return updatedPod
+}
+
+// setTrustedCAMounts ensures the pod spec has the trusted CA volume and that it is mounted into every container
+// and init container, except the ones exempt from the trusted CA mount.
+func setTrustedCAMounts(p corev1.PodSpec) corev1.PodSpec {
+	updatedPod := *p.DeepCopy()
+
+	hasVolume := false
+	for _, volume := range updatedPod.Volumes {
+		if volume.Name == trustedCAVolumeName {
+			hasVolume = true
+			break
+		}
+	}
+	if !hasVolume {
+		updatedPod.Volumes = append(updatedPod.Volumes, corev1.Volume{
+			Name: trustedCAVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: trustedCAConfigMapName},
+					Items:                []corev1.KeyToPath{{Key: trustedCABundleKey, Path: trustedCABundleFileName}},
+				},
+			},
+		})
+	}
+
+	ensureMount := func(container *corev1.Container) {
+		if IsTrustedCAMountExempt(container.Name) {
+			return
+		}
+		for _, mount := range container.VolumeMounts {
+			if mount.Name == trustedCAVolumeName {
+				return
+			}
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      trustedCAVolumeName,
+			MountPath: trustedCAVolumeMountPath,
+			ReadOnly:  true,
+		})
+	}
+	for i := range updatedPod.InitContainers {
+		ensureMount(&updatedPod.InitContainers[i])
+	}
+	for i := range updatedPod.Containers {
+		ensureMount(&updatedPod.Containers[i])
+	}
+
+	return updatedPod
+}
+
+// setExtraHostPathMounts adds extra host path volumes from the config to the pod spec and mounts them
+// into every container. Mounts are expected to be validated with ValidateExtraHostPathMounts beforehand.
+func setExtraHostPathMounts(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if len(config.ExtraHostPathMounts) == 0 {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for _, mount := range config.ExtraHostPathMounts {
+		updatedPod.Volumes = append(updatedPod.Volumes, corev1.Volume{
+			Name: mount.Name,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: mount.HostPath,
+					Type: ptr.To(mount.Type),
+				},
+			},
+		})
+		for i := range updatedPod.Containers {
+			updatedPod.Containers[i].VolumeMounts = append(updatedPod.Containers[i].VolumeMounts, corev1.VolumeMount{
+				Name:      mount.Name,
+				MountPath: mount.MountPath,
+				ReadOnly:  mount.ReadOnly,
+			})
+		}
+	}
+
+	return updatedPod
+}
+
+// operandPodSpec is the pod spec of a rendered workload along with the workload it belongs to.
+type operandPodSpec struct {
+	owner string
+	spec  corev1.PodSpec
+}
+
+// operandPodSpecs returns pod specs of rendered workloads, in the order of rendered objects.
+func operandPodSpecs(renderedObjects []client.Object) []operandPodSpec {
+	var podSpecs []operandPodSpec
+	for _, object := range renderedObjects {
+		switch obj := object.(type) {
+		case *appsv1.Deployment:
+			podSpecs = append(podSpecs, operandPodSpec{owner: "deployment " + obj.Name, spec: obj.Spec.Template.Spec})
+		case *appsv1.DaemonSet:
+			podSpecs = append(podSpecs, operandPodSpec{owner: "daemonset " + obj.Name, spec: obj.Spec.Template.Spec})
+		}
+	}
+	return podSpecs
+}
+
+// operandVolumes returns names of volumes of operand pods, both added by the operator and defined by rendered
+// pod templates, along with what they belong to.
+func operandVolumes(config config.OperatorConfig, renderedObjects []client.Object) map[string]string {
+	volumes := map[string]string{trustedCAVolumeName: "the trusted CA bundle"}
+	if config.StartupScriptsFromConfigMap {
+		volumes[startupScriptsVolumeName] = "the startup scripts"
+	}
+	if config.AzureCredentials != nil && config.AzureCredentials.FilePath != "" {
+		volumes[azureCredentialsVolumeName] = "the azure credentials"
+	}
+	for _, podSpec := range operandPodSpecs(renderedObjects) {
+		for _, volume := range podSpec.spec.Volumes {
+			volumes[volume.Name] = podSpec.owner
+		}
+	}
+	return volumes
+}
+
+// operandMountPaths returns paths volumes are mounted at within operand containers, both by the operator and by
+// rendered pod templates, along with what they belong to.
+func operandMountPaths(config config.OperatorConfig, renderedObjects []client.Object) map[string]string {
+	mountPaths := map[string]string{trustedCAVolumeMountPath: "the trusted CA bundle"}
+	if config.StartupScriptsFromConfigMap {
+		mountPaths[startupScriptsMountPath] = "the startup scripts"
+	}
+	if config.AzureCredentials != nil && config.AzureCredentials.FilePath != "" {
+		mountPaths[config.AzureCredentials.FilePath] = "the azure credentials"
+	}
+	for _, podSpec := range operandPodSpecs(renderedObjects) {
+		for _, container := range podSpec.spec.Containers {
+			for _, mount := range container.VolumeMounts {
+				mountPaths[filepath.Clean(mount.MountPath)] = podSpec.owner
+			}
+		}
+	}
+	return mountPaths
+}
+
+// ValidateExtraHostPathMounts checks that extra host path mounts from the config have unique names and mount
+// paths, which do not collide with volumes or mounts of rendered pod templates or the ones added by the operator.
+func ValidateExtraHostPathMounts(config config.OperatorConfig, renderedObjects []client.Object) error {
+	if len(config.ExtraHostPathMounts) == 0 {
+		return nil
+	}
+
+	reservedVolumes := operandVolumes(config, renderedObjects)
+	reservedMountPaths := operandMountPaths(config, renderedObjects)
+	for _, mount := range config.ExtraHostPathMounts {
+		if owner, ok := reservedVolumes[mount.Name]; ok {
+			return fmt.Errorf("extra host path mount %q collides with a volume of %s", mount.Name, owner)
+		}
+		mountPath := filepath.Clean(mount.MountPath)
+		if owner, ok := reservedMountPaths[mountPath]; ok {
+			return fmt.Errorf("extra host path mount %q mount path %s collides with a mount of %s", mount.Name, mountPath, owner)
+		}
+		reservedVolumes[mount.Name] = "an extra host path mount"
+		reservedMountPaths[mountPath] = "extra host path mount " + mount.Name
+	}
+	return nil
+}
+
+// ValidateExtraVolumes checks that extra volumes from the config have unique names, which do not collide
+// with volumes of rendered pod templates or volumes added by the operator, and that extra volume mounts
+// refer to extra volumes at unique mount paths, which do not shadow other mounts of operand containers.
+func ValidateExtraVolumes(config config.OperatorConfig, renderedObjects []client.Object) error {
+	if len(config.ExtraVolumes) == 0 && len(config.ExtraVolumeMounts) == 0 {
+		return nil
+	}
+
+	reservedVolumes := operandVolumes(config, renderedObjects)
+	for _, mount := range config.ExtraHostPathMounts {
+		reservedVolumes[mount.Name] = "an extra host path mount"
+	}
+
+	extraVolumes := sets.New[string]()
+	for _, volume := range config.ExtraVolumes {
+		if volume.Name == "" {
+			return fmt.Errorf("extra volume name is required")
+		}
+		if owner, ok := reservedVolumes[volume.Name]; ok {
+			return fmt.Errorf("extra volume %q collides with a volume of %s", volume.Name, owner)
+		}
+		if extraVolumes.Has(volume.Name) {
+			return fmt.Errorf("extra volume %q is defined more than once", volume.Name)
+		}
+		extraVolumes.Insert(volume.Name)
+	}
+
+	reservedMountPaths := operandMountPaths(config, renderedObjects)
+	for _, mount := range config.ExtraHostPathMounts {
+		reservedMountPaths[filepath.Clean(mount.MountPath)] = "extra host path mount " + mount.Name
+	}
+	for _, mount := range config.ExtraVolumeMounts {
+		if !extraVolumes.Has(mount.Name) {
+			return fmt.Errorf("extra volume mount %q does not refer to an extra volume", mount.Name)
+		}
+		mountPath := filepath.Clean(mount.MountPath)
+		if owner, ok := reservedMountPaths[mountPath]; ok {
+			return fmt.Errorf("extra volume mount %q mount path %s collides with a mount of %s", mount.Name, mountPath, owner)
+		}
+		reservedMountPaths[mountPath] = "extra volume mount " + mount.Name
+	}
+	return nil
+}
+
+// setExtraVolumes adds extra volumes from the config to the pod spec and mounts extra volume mounts
+// into every container. Volumes are expected to be validated with ValidateExtraVolumes beforehand.
+func setExtraVolumes(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if len(config.ExtraVolumes) == 0 {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for _, volume := range config.ExtraVolumes {
+		updatedPod.Volumes = append(updatedPod.Volumes, *volume.DeepCopy())
+	}
+	for i := range updatedPod.Containers {
+		for _, mount := range config.ExtraVolumeMounts {
+			updatedPod.Containers[i].VolumeMounts = append(updatedPod.Containers[i].VolumeMounts, *mount.DeepCopy())
+		}
+	}
+
+	return updatedPod
+}
+
+// flagName returns the name of the flag without leading dashes and value.
+func flagName(arg string) string {
+	name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	return name
+}
+
+// shellQuote quotes the argument to be passed as is within a bash script.
+func shellQuote(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// ValidateExtraNodeManagerArgs checks that extra node-manager args from the config are flags,
+// and do not override flags which are already set for the cloud-node-manager container by the operator.
+func ValidateExtraNodeManagerArgs(config config.OperatorConfig, renderedObjects []client.Object) error {
+	if len(config.ExtraNodeManagerArgs) == 0 {
+		return nil
+	}
+
+	managedFlags := sets.New[string]()
+	for _, object := range renderedObjects {
+		daemonSet, ok := object.(*appsv1.DaemonSet)
+		if !ok {
+			continue
+		}
+		for _, container := range daemonSet.Spec.Template.Spec.Containers {
+			if container.Name != cloudNodeManagerContainerName || len(container.Command) != 3 {
+				continue
+			}
+			for _, match := range commandFlagRegexp.FindAllStringSubmatch(container.Command[2], -1) {
+				managedFlags.Insert(match[1])
+			}
+		}
+	}
+
+	for _, arg := range config.ExtraNodeManagerArgs {
+		if !strings.HasPrefix(arg, "-") || flagName(arg) == "" {
+			return fmt.Errorf("extra node-manager arg %q is not a flag", arg)
+		}
+		if managedFlags.Has(flagName(arg)) {
+			return fmt.Errorf("extra node-manager arg %q conflicts with a flag managed by the operator", arg)
+		}
+	}
+	return nil
+}
+
+// setExtraNodeManagerArgs appends extra args from the config to the cloud-node-manager container command.
+// Args are expected to be validated with ValidateExtraNodeManagerArgs beforehand.
+func setExtraNodeManagerArgs(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if len(config.ExtraNodeManagerArgs) == 0 {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for i, container := range p.Containers {
+		if container.Name != cloudNodeManagerContainerName || len(container.Command) != 3 {
+			continue
+		}
+		script := container.Command[2]
+		for _, arg := range config.ExtraNodeManagerArgs {
+			script = appendCommandFlag(script, shellQuote(arg))
+		}
+		updatedPod.Containers[i].Command[2] = script
+	}
+
+	return updatedPod
+}
+
+// setLogEffectiveFlags makes the cloud-controller-manager container command echo the final exec call,
+// with all flags assembled into it, right before it is executed, if enabled in the config.
+// Variables in the command are expanded by the echo the same way as by the exec.
+func setLogEffectiveFlags(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if !config.LogEffectiveFlags {
+		return p
+	}
+
+	updatedPod := *p.DeepCopy()
+	for i, container := range p.Containers {
+		if container.Name != cloudControllerManagerContainerName || len(container.Command) != 3 {
+			continue
+		}
+		script := container.Command[2]
+		execs := execLineRegexp.FindAllStringSubmatchIndex(script, -1)
+		if len(execs) == 0 {
+			continue
+		}
+
+		// Echo the last exec call, which runs the operand binary, including its continuation lines.
+		lastExec := execs[len(execs)-1]
+		indent := script[lastExec[2]:lastExec[3]]
+		end := lastExec[1]
+		for {
+			lineEnd := strings.IndexByte(script[end:], '\n')
+			if lineEnd < 0 {
+				end = len(script)
+				break
+			}
+			line := script[end : end+lineEnd]
+			end += lineEnd
+			if !strings.HasSuffix(strings.TrimRight(line, " "), "\\") {
+				break
+			}
+			end++
+		}
+		echo := indent + effectiveFlagsEchoPrefix + script[lastExec[1]:end] + "\n"
+		updatedPod.Containers[i].Command[2] = script[:lastExec[0]] + echo + script[lastExec[0]:]
+	}
+
+	return updatedPod
+}
+
+// setStartupTimeout wraps bash scripts of the pod containers with a watchdog which terminates the script
+// if it does not reach the final exec of the operand binary within the startup timeout of the platform.
+// The watchdog is stopped right before the exec, so it does not affect the running operand.
+func setStartupTimeout(config config.OperatorConfig, p corev1.PodSpec) corev1.PodSpec {
+	if config.PlatformStatus == nil {
+		return p
+	}
+	timeout, ok := config.StartupTimeouts[config.PlatformStatus.Type]
+	if !ok || timeout <= 0 {
+		return p
+	}
+	seconds := int64(math.Ceil(timeout.Seconds()))
+
+	updatedPod := *p.DeepCopy()
+	for i, container := range p.Containers {
+		if len(container.Command) != 3 || container.Command[0] != "/bin/bash" {
+			continue
+		}
+		script := container.Command[2]
+		execs := execLineRegexp.FindAllStringSubmatchIndex(script, -1)
+		if len(execs) == 0 {
+			continue
+		}
+
+		// Stop the watchdog before the last exec, which replaces the script with the operand binary.
+		lastExec := execs[len(execs)-1]
+		indent := script[lastExec[2]:lastExec[3]]
+		script = script[:lastExec[0]] + indent + startupWatchdogStop + "\n" + script[lastExec[0]:]
+
+		watchdog := fmt.Sprintf(startupWatchdogStart, seconds, seconds)
+		if strings.HasPrefix(script, "#!") {
+			shebang, rest, _ := strings.Cut(script, "\n")
+			script = shebang + "\n" + watchdog + rest
+		} else {
+			script = watchdog + script
+		}
+		updatedPod.Containers[i].Command[2] = script
+	}
+
+	return updatedPod
+}
+
+// setConfigHashSources annotates the given object metadata with the config maps
+// which should roll the workload on change, if any are configured.
+func setConfigHashSources(sources []string, meta metav1.Object) {
+	if len(sources) == 0 {
+		return
+	}
+	annotations := meta.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[config.ConfigHashSourcesAnnotation] = strings.Join(sources, ",")
+	meta.SetAnnotations(annotations)
+}
+
+// setDNSConfigHash annotates the pod template with the cluster DNS config hash, if it is set.
+func setDNSConfigHash(config config.OperatorConfig, template *corev1.PodTemplateSpec) {
+	if config.DNSConfigHash == "" {
+		return
+	}
+	metav1.SetMetaDataAnnotation(&template.ObjectMeta, DNSConfigHashAnnotation, config.DNSConfigHash)
+}
+
+// setCommonLabels adds labels common for all operands to the given object metadata.
+func setCommonLabels(meta metav1.Object) {
+	labels := meta.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for key, value := range GetCommonLabels() {
+		labels[key] = value
+	}
+	meta.SetLabels(labels)
+}
+
 func SubstituteCommonPartsFromConfig(config config.OperatorConfig, renderedObjects []client.Object) []client.Object {
 	substitutedObjects := make([]client.Object, len(renderedObjects))
 	for i, objectTemplate := range renderedObjects {
 		templateCopy := objectTemplate.DeepCopyObject().(client.Object)
+		setCommonLabels(templateCopy)
 
 		switch obj := templateCopy.(type) {
 		case *appsv1.Deployment:
 			obj.Spec.Template.Spec = setProxySettings(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setCloudEndpointOverrides(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setLeaderElectResourceNamespace(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setVerbosity(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setLoggingFormat(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setAllocateNodeCIDRs(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setConfigureCloudRoutes(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setLogEffectiveFlags(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setDropPrivileges(config, cloudControllerManagerContainerName, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setCloudAPIReadinessProbe(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setCredentialsMode(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setAzureCredentials(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setTrustedCAMounts(obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setExtraHostPathMounts(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setExtraVolumes(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setPodSysctls(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setHostAliases(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setAffinity(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setInitContainerResources(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setAutosizedResources(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setStartupTimeout(config, obj.Spec.Template.Spec)
+			setDNSConfigHash(config, &obj.Spec.Template)
+			setCommonLabels(&obj.Spec.Template)
 			if config.IsSingleReplica {
 				obj.Spec.Replicas = ptr.To[int32](1)
 			}
 		case *appsv1.DaemonSet:
 			obj.Spec.Template.Spec = setProxySettings(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setCloudEndpointOverrides(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setExtraNodeManagerArgs(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setDropPrivileges(config, cloudNodeManagerContainerName, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setNodeManagerPriorityClass(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setCredentialsMode(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setAzureCredentials(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setTrustedCAMounts(obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setExtraHostPathMounts(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setExtraVolumes(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setPodSysctls(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setHostAliases(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setInitContainerResources(config, obj.Spec.Template.Spec)
+			obj.Spec.Template.Spec = setStartupTimeout(config, obj.Spec.Template.Spec)
+			setConfigHashSources(config.NodeManagerConfigHashSources, obj)
+			if config.NodeManagerMinReadySeconds != nil {
+				obj.Spec.MinReadySeconds = *config.NodeManagerMinReadySeconds
+			}
+			setDNSConfigHash(config, &obj.Spec.Template)
+			setCommonLabels(&obj.Spec.Template)
 		}
 		substitutedObjects[i] = templateCopy
 	}
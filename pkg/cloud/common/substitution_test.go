@@ -1,12 +1,18 @@
 package common
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -185,8 +191,1743 @@ func TestSetProxySettings(t *testing.T) {
 	}
 }
 
+func TestSetCloudEndpointOverrides(t *testing.T) {
+	override := config.CloudEndpointOverride{EnvName: "AWS_ENDPOINT_URL_EC2", URL: "https://ec2.example.com"}
+
+	tc := []struct {
+		name        string
+		containers  []corev1.Container
+		overrides   []config.CloudEndpointOverride
+		expectedEnv [][]corev1.EnvVar
+	}{{
+		name: "No overrides",
+		containers: []corev1.Container{{
+			Name: "cloud-controller-manager",
+			Env:  []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+		}},
+		expectedEnv: [][]corev1.EnvVar{{{Name: "FOO", Value: "bar"}}},
+	}, {
+		name: "Overrides are added to all containers",
+		containers: []corev1.Container{{
+			Name: "cloud-controller-manager",
+			Env:  []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+		}, {
+			Name: "cloud-node-manager",
+		}},
+		overrides: []config.CloudEndpointOverride{override},
+		expectedEnv: [][]corev1.EnvVar{
+			{{Name: "FOO", Value: "bar"}, {Name: override.EnvName, Value: override.URL}},
+			{{Name: override.EnvName, Value: override.URL}},
+		},
+	}, {
+		name: "Existing variable is replaced",
+		containers: []corev1.Container{{
+			Name: "cloud-controller-manager",
+			Env:  []corev1.EnvVar{{Name: override.EnvName, Value: "https://ec2.default.example.com"}, {Name: "FOO", Value: "bar"}},
+		}},
+		overrides:   []config.CloudEndpointOverride{override},
+		expectedEnv: [][]corev1.EnvVar{{{Name: override.EnvName, Value: override.URL}, {Name: "FOO", Value: "bar"}}},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Containers: tc.containers,
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setCloudEndpointOverrides(config.OperatorConfig{CloudEndpointOverrides: tc.overrides}, podSpec)
+			for i, container := range spec.Containers {
+				assert.Equal(t, tc.expectedEnv[i], container.Env)
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetAllocateNodeCIDRs(t *testing.T) {
+	script := `#!/bin/bash
+exec /bin/cloud-controller-manager \
+--configure-cloud-routes=false \
+-v=2
+`
+
+	tc := []struct {
+		name             string
+		containers       []corev1.Container
+		config           config.OperatorConfig
+		expectedCommands [][]string
+	}{{
+		name: "Not set",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", script},
+		},
+	}, {
+		name: "Override to false",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		config: config.OperatorConfig{
+			AllocateNodeCIDRs: ptr.To(false),
+		},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", "#!/bin/bash\nexec /bin/cloud-controller-manager \\\n--configure-cloud-routes=false \\\n-v=2 \\\n--allocate-node-cidrs=false\n"},
+		},
+	}, {
+		name: "Override to true",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		config: config.OperatorConfig{
+			AllocateNodeCIDRs: ptr.To(true),
+		},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", "#!/bin/bash\nexec /bin/cloud-controller-manager \\\n--configure-cloud-routes=false \\\n-v=2 \\\n--allocate-node-cidrs=true\n"},
+		},
+	}, {
+		name: "Flag set in template is kept",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/cloud-controller-manager --allocate-node-cidrs=true"},
+		}},
+		config: config.OperatorConfig{
+			AllocateNodeCIDRs: ptr.To(false),
+		},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", "exec /bin/cloud-controller-manager --allocate-node-cidrs=true"},
+		},
+	}, {
+		name: "Other containers are not changed",
+		containers: []corev1.Container{{
+			Name:    "cloud-node-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		config: config.OperatorConfig{
+			AllocateNodeCIDRs: ptr.To(false),
+		},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", script},
+		},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Containers: tc.containers,
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setAllocateNodeCIDRs(tc.config, podSpec)
+			for i, container := range spec.Containers {
+				assert.Equal(t, tc.expectedCommands[i], container.Command)
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetConfigureCloudRoutes(t *testing.T) {
+	script := `#!/bin/bash
+exec /bin/cloud-controller-manager \
+--configure-cloud-routes=false \
+-v=2
+`
+
+	tc := []struct {
+		name             string
+		containers       []corev1.Container
+		config           config.OperatorConfig
+		expectedCommands [][]string
+	}{{
+		name: "Default to false",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", script},
+		},
+	}, {
+		name: "Override to true",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		config: config.OperatorConfig{
+			ConfigureCloudRoutes: ptr.To(true),
+		},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", "#!/bin/bash\nexec /bin/cloud-controller-manager \\\n--configure-cloud-routes=true \\\n-v=2\n"},
+		},
+	}, {
+		name: "Explicitly set to false",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/cloud-controller-manager --configure-cloud-routes=true"},
+		}},
+		config: config.OperatorConfig{
+			ConfigureCloudRoutes: ptr.To(false),
+		},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", "exec /bin/cloud-controller-manager --configure-cloud-routes=false"},
+		},
+	}, {
+		name: "Flag is added if missing",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/cloud-controller-manager"},
+		}},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", "exec /bin/cloud-controller-manager \\\n--configure-cloud-routes=false\n"},
+		},
+	}, {
+		name: "Other containers are not changed",
+		containers: []corev1.Container{{
+			Name:    "cloud-node-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		config: config.OperatorConfig{
+			ConfigureCloudRoutes: ptr.To(true),
+		},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", script},
+		},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Containers: tc.containers,
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setConfigureCloudRoutes(tc.config, podSpec)
+			for i, container := range spec.Containers {
+				assert.Equal(t, tc.expectedCommands[i], container.Command)
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetAzureCredentials(t *testing.T) {
+	secretEnv := func(name, secretName string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  strings.ToLower(name),
+			}},
+		}
+	}
+	podSpec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{
+			Name:    "azure-inject-credentials",
+			Command: []string{"/bin/bash", "-c", "exec /azure-config-credentials-injector \\\n--output-file-path=/etc/merged-cloud-config/cloud.conf\n"},
+			Env:     []corev1.EnvVar{secretEnv("AZURE_CLIENT_ID", "azure-cloud-credentials"), {Name: "OTHER", Value: "value"}},
+		}},
+		Containers: []corev1.Container{{
+			Name: "cloud-controller-manager",
+			Env:  []corev1.EnvVar{secretEnv("AZURE_FEDERATED_TOKEN_FILE", "azure-cloud-credentials")},
+		}},
+	}
+	azureStatus := &configv1.PlatformStatus{Type: configv1.AzurePlatformType}
+
+	tc := []struct {
+		name     string
+		config   config.OperatorConfig
+		expected corev1.PodSpec
+	}{{
+		name:     "Not configured",
+		config:   config.OperatorConfig{PlatformStatus: azureStatus},
+		expected: podSpec,
+	}, {
+		name: "Other platforms are not changed",
+		config: config.OperatorConfig{
+			PlatformStatus:   &configv1.PlatformStatus{Type: configv1.AWSPlatformType},
+			AzureCredentials: &config.AzureCredentials{SecretName: "custom", FilePath: "/etc/azure/credentials"},
+		},
+		expected: podSpec,
+	}, {
+		name: "Secret name",
+		config: config.OperatorConfig{
+			PlatformStatus:   azureStatus,
+			AzureCredentials: &config.AzureCredentials{SecretName: "custom"},
+		},
+		expected: corev1.PodSpec{
+			InitContainers: []corev1.Container{{
+				Name:    "azure-inject-credentials",
+				Command: podSpec.InitContainers[0].Command,
+				Env:     []corev1.EnvVar{secretEnv("AZURE_CLIENT_ID", "custom"), {Name: "OTHER", Value: "value"}},
+			}},
+			Containers: []corev1.Container{{
+				Name: "cloud-controller-manager",
+				Env:  []corev1.EnvVar{secretEnv("AZURE_FEDERATED_TOKEN_FILE", "custom")},
+			}},
+		},
+	}, {
+		name: "Secret name and file path",
+		config: config.OperatorConfig{
+			PlatformStatus:   azureStatus,
+			AzureCredentials: &config.AzureCredentials{SecretName: "custom", FilePath: "/etc/azure/credentials"},
+		},
+		expected: corev1.PodSpec{
+			InitContainers: []corev1.Container{{
+				Name: "azure-inject-credentials",
+				Command: []string{"/bin/bash", "-c",
+					"exec /azure-config-credentials-injector \\\n--output-file-path=/etc/merged-cloud-config/cloud.conf \\\n--creds-file=/etc/azure/credentials\n"},
+				Env:          []corev1.EnvVar{{Name: "OTHER", Value: "value"}},
+				VolumeMounts: []corev1.VolumeMount{{Name: "azure-credentials", MountPath: "/etc/azure/credentials", SubPath: "credentials", ReadOnly: true}},
+			}},
+			Containers: []corev1.Container{{
+				Name: "cloud-controller-manager",
+				Env:  []corev1.EnvVar{secretEnv("AZURE_FEDERATED_TOKEN_FILE", "custom")},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "azure-credentials",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+					SecretName: "custom",
+					Items:      []corev1.KeyToPath{{Key: "credentials", Path: "credentials"}},
+				}},
+			}},
+		},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setAzureCredentials(tc.config, podSpec)
+			assert.Equal(t, tc.expected, spec)
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetCredentialsMode(t *testing.T) {
+	podSpec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{
+			Name: "azure-inject-credentials",
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "config-accm", MountPath: "/etc/cloud-config"},
+			},
+		}},
+		Containers: []corev1.Container{{
+			Name: "cloud-controller-manager",
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "merged-cloud-config", MountPath: "/etc/kubernetes-cloud-config"},
+				{Name: "bound-sa-token", MountPath: "/var/run/secrets/openshift/serviceaccount"},
+			},
+		}},
+		Volumes: []corev1.Volume{
+			{Name: "config-accm"},
+			{Name: "merged-cloud-config"},
+			{Name: "bound-sa-token"},
+		},
+	}
+
+	tc := []struct {
+		name               string
+		mode               operatorv1.CloudCredentialsMode
+		expectedMounts     []string
+		expectedVolumes    []string
+		expectedInitMounts []string
+	}{{
+		name:               "Default mode",
+		mode:               operatorv1.CloudCredentialsModeDefault,
+		expectedMounts:     []string{"merged-cloud-config", "bound-sa-token"},
+		expectedVolumes:    []string{"config-accm", "merged-cloud-config", "bound-sa-token"},
+		expectedInitMounts: []string{"config-accm"},
+	}, {
+		name:               "Manual mode",
+		mode:               operatorv1.CloudCredentialsModeManual,
+		expectedMounts:     []string{"merged-cloud-config", "bound-sa-token"},
+		expectedVolumes:    []string{"config-accm", "merged-cloud-config", "bound-sa-token"},
+		expectedInitMounts: []string{"config-accm"},
+	}, {
+		name:               "Mint mode",
+		mode:               operatorv1.CloudCredentialsModeMint,
+		expectedMounts:     []string{"merged-cloud-config"},
+		expectedVolumes:    []string{"config-accm", "merged-cloud-config"},
+		expectedInitMounts: []string{"config-accm"},
+	}, {
+		name:               "Passthrough mode",
+		mode:               operatorv1.CloudCredentialsModePassthrough,
+		expectedMounts:     []string{"merged-cloud-config"},
+		expectedVolumes:    []string{"config-accm", "merged-cloud-config"},
+		expectedInitMounts: []string{"config-accm"},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setCredentialsMode(config.OperatorConfig{CredentialsMode: tc.mode}, podSpec)
+
+			var volumes, mounts, initMounts []string
+			for _, volume := range spec.Volumes {
+				volumes = append(volumes, volume.Name)
+			}
+			for _, mount := range spec.Containers[0].VolumeMounts {
+				mounts = append(mounts, mount.Name)
+			}
+			for _, mount := range spec.InitContainers[0].VolumeMounts {
+				initMounts = append(initMounts, mount.Name)
+			}
+			assert.Equal(t, tc.expectedVolumes, volumes)
+			assert.Equal(t, tc.expectedMounts, mounts)
+			assert.Equal(t, tc.expectedInitMounts, initMounts)
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetExtraHostPathMounts(t *testing.T) {
+	existingVolume := corev1.Volume{Name: "host-etc-kube", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc/kubernetes"}}}
+
+	tc := []struct {
+		name            string
+		config          config.OperatorConfig
+		expectedVolumes []corev1.Volume
+		expectedMounts  []corev1.VolumeMount
+	}{{
+		name:            "No extra mounts",
+		expectedVolumes: []corev1.Volume{existingVolume},
+	}, {
+		name: "Extra host path is mounted",
+		config: config.OperatorConfig{
+			ExtraHostPathMounts: []config.HostPathMount{{
+				Name:      "host-creds",
+				HostPath:  "/var/lib/creds",
+				MountPath: "/etc/creds",
+				Type:      corev1.HostPathDirectory,
+				ReadOnly:  true,
+			}},
+		},
+		expectedVolumes: []corev1.Volume{existingVolume, {
+			Name: "host-creds",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{
+				Path: "/var/lib/creds",
+				Type: ptr.To(corev1.HostPathDirectory),
+			}},
+		}},
+		expectedMounts: []corev1.VolumeMount{{Name: "host-creds", MountPath: "/etc/creds", ReadOnly: true}},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Volumes: []corev1.Volume{existingVolume},
+				Containers: []corev1.Container{
+					{Name: "cloud-controller-manager"},
+					{Name: "cloud-node-manager"},
+				},
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setExtraHostPathMounts(tc.config, podSpec)
+			assert.Equal(t, tc.expectedVolumes, spec.Volumes)
+			for _, container := range spec.Containers {
+				assert.Equal(t, tc.expectedMounts, container.VolumeMounts)
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestValidateExtraHostPathMounts(t *testing.T) {
+	deployment := &v1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloud-controller-manager"},
+		Spec: v1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:         "cloud-controller-manager",
+						VolumeMounts: []corev1.VolumeMount{{Name: "host-etc-kube", MountPath: "/etc/kubernetes"}},
+					}},
+					Volumes: []corev1.Volume{{Name: "host-etc-kube"}},
+				},
+			},
+		},
+	}
+	extraMount := config.HostPathMount{Name: "host-creds", HostPath: "/var/lib/creds", MountPath: "/etc/creds"}
+
+	tc := []struct {
+		name        string
+		config      config.OperatorConfig
+		expectedErr string
+	}{{
+		name: "No extra mounts",
+	}, {
+		name:   "Extra host path mount",
+		config: config.OperatorConfig{ExtraHostPathMounts: []config.HostPathMount{extraMount}},
+	}, {
+		name: "Collision with a rendered volume",
+		config: config.OperatorConfig{
+			ExtraHostPathMounts: []config.HostPathMount{{Name: "host-etc-kube", HostPath: "/var/lib/creds", MountPath: "/etc/creds"}},
+		},
+		expectedErr: `extra host path mount "host-etc-kube" collides with a volume of deployment cloud-controller-manager`,
+	}, {
+		name: "Collision with the trusted CA volume",
+		config: config.OperatorConfig{
+			ExtraHostPathMounts: []config.HostPathMount{{Name: "trusted-ca", HostPath: "/var/lib/creds", MountPath: "/etc/creds"}},
+		},
+		expectedErr: `extra host path mount "trusted-ca" collides with a volume of the trusted CA bundle`,
+	}, {
+		name: "Collision with a rendered mount path",
+		config: config.OperatorConfig{
+			ExtraHostPathMounts: []config.HostPathMount{{Name: "host-creds", HostPath: "/var/lib/creds", MountPath: "/etc/kubernetes/"}},
+		},
+		expectedErr: `extra host path mount "host-creds" mount path /etc/kubernetes collides with a mount of deployment cloud-controller-manager`,
+	}, {
+		name: "Collision with the trusted CA mount path",
+		config: config.OperatorConfig{
+			ExtraHostPathMounts: []config.HostPathMount{{Name: "host-creds", HostPath: "/var/lib/creds", MountPath: "/etc/pki/ca-trust/extracted/pem"}},
+		},
+		expectedErr: `extra host path mount "host-creds" mount path /etc/pki/ca-trust/extracted/pem collides with a mount of the trusted CA bundle`,
+	}, {
+		name: "Duplicated extra host path mount",
+		config: config.OperatorConfig{
+			ExtraHostPathMounts: []config.HostPathMount{extraMount, extraMount},
+		},
+		expectedErr: `extra host path mount "host-creds" collides with a volume of an extra host path mount`,
+	}, {
+		name: "Extra host path mounts sharing a mount path",
+		config: config.OperatorConfig{
+			ExtraHostPathMounts: []config.HostPathMount{extraMount, {Name: "other-creds", HostPath: "/var/lib/other-creds", MountPath: "/etc/creds"}},
+		},
+		expectedErr: `extra host path mount "other-creds" mount path /etc/creds collides with a mount of extra host path mount host-creds`,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateExtraHostPathMounts(tc.config, []client.Object{deployment})
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetExtraVolumes(t *testing.T) {
+	existingVolume := corev1.Volume{Name: "host-etc-kube", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc/kubernetes"}}}
+	extraVolume := corev1.Volume{Name: "custom-ca", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc/custom-ca"}}}
+	extraMount := corev1.VolumeMount{Name: "custom-ca", MountPath: "/etc/custom-ca", ReadOnly: true}
+
+	tc := []struct {
+		name            string
+		config          config.OperatorConfig
+		expectedVolumes []corev1.Volume
+		expectedMounts  []corev1.VolumeMount
+	}{{
+		name:            "No extra volumes",
+		expectedVolumes: []corev1.Volume{existingVolume},
+	}, {
+		name: "Extra volume is added and mounted",
+		config: config.OperatorConfig{
+			ExtraVolumes:      []corev1.Volume{extraVolume},
+			ExtraVolumeMounts: []corev1.VolumeMount{extraMount},
+		},
+		expectedVolumes: []corev1.Volume{existingVolume, extraVolume},
+		expectedMounts:  []corev1.VolumeMount{extraMount},
+	}, {
+		name: "Extra volume is added without mounts",
+		config: config.OperatorConfig{
+			ExtraVolumes: []corev1.Volume{extraVolume},
+		},
+		expectedVolumes: []corev1.Volume{existingVolume, extraVolume},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Volumes: []corev1.Volume{existingVolume},
+				Containers: []corev1.Container{
+					{Name: "cloud-controller-manager"},
+					{Name: "cloud-node-manager"},
+				},
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setExtraVolumes(tc.config, podSpec)
+			assert.Equal(t, tc.expectedVolumes, spec.Volumes)
+			for _, container := range spec.Containers {
+				assert.Equal(t, tc.expectedMounts, container.VolumeMounts)
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestValidateExtraVolumes(t *testing.T) {
+	deployment := &v1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloud-controller-manager"},
+		Spec: v1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:         "cloud-controller-manager",
+						VolumeMounts: []corev1.VolumeMount{{Name: "host-etc-kube", MountPath: "/etc/kubernetes"}},
+					}},
+					Volumes: []corev1.Volume{{Name: "host-etc-kube"}},
+				},
+			},
+		},
+	}
+	extraVolume := corev1.Volume{Name: "custom-ca", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc/custom-ca"}}}
+
+	tc := []struct {
+		name        string
+		config      config.OperatorConfig
+		expectedErr string
+	}{{
+		name: "No extra volumes",
+	}, {
+		name: "Extra volume with a mount",
+		config: config.OperatorConfig{
+			ExtraVolumes:      []corev1.Volume{extraVolume},
+			ExtraVolumeMounts: []corev1.VolumeMount{{Name: "custom-ca", MountPath: "/etc/custom-ca"}},
+		},
+	}, {
+		name: "Collision with a rendered volume",
+		config: config.OperatorConfig{
+			ExtraVolumes: []corev1.Volume{{Name: "host-etc-kube"}},
+		},
+		expectedErr: `extra volume "host-etc-kube" collides with a volume of deployment cloud-controller-manager`,
+	}, {
+		name: "Collision with the trusted CA volume",
+		config: config.OperatorConfig{
+			ExtraVolumes: []corev1.Volume{{Name: "trusted-ca"}},
+		},
+		expectedErr: `extra volume "trusted-ca" collides with a volume of the trusted CA bundle`,
+	}, {
+		name: "Collision with an extra host path mount",
+		config: config.OperatorConfig{
+			ExtraHostPathMounts: []config.HostPathMount{{Name: "custom-ca"}},
+			ExtraVolumes:        []corev1.Volume{extraVolume},
+		},
+		expectedErr: `extra volume "custom-ca" collides with a volume of an extra host path mount`,
+	}, {
+		name: "Duplicated extra volume",
+		config: config.OperatorConfig{
+			ExtraVolumes: []corev1.Volume{extraVolume, extraVolume},
+		},
+		expectedErr: `extra volume "custom-ca" is defined more than once`,
+	}, {
+		name: "Unnamed extra volume",
+		config: config.OperatorConfig{
+			ExtraVolumes: []corev1.Volume{{}},
+		},
+		expectedErr: "extra volume name is required",
+	}, {
+		name: "Mount of an unknown volume",
+		config: config.OperatorConfig{
+			ExtraVolumes:      []corev1.Volume{extraVolume},
+			ExtraVolumeMounts: []corev1.VolumeMount{{Name: "host-etc-kube", MountPath: "/etc/kubernetes"}},
+		},
+		expectedErr: `extra volume mount "host-etc-kube" does not refer to an extra volume`,
+	}, {
+		name: "Mount at a rendered mount path",
+		config: config.OperatorConfig{
+			ExtraVolumes:      []corev1.Volume{extraVolume},
+			ExtraVolumeMounts: []corev1.VolumeMount{{Name: "custom-ca", MountPath: "/etc/kubernetes"}},
+		},
+		expectedErr: `extra volume mount "custom-ca" mount path /etc/kubernetes collides with a mount of deployment cloud-controller-manager`,
+	}, {
+		name: "Mount at the trusted CA mount path",
+		config: config.OperatorConfig{
+			ExtraVolumes:      []corev1.Volume{extraVolume},
+			ExtraVolumeMounts: []corev1.VolumeMount{{Name: "custom-ca", MountPath: "/etc/pki/ca-trust/extracted/pem/"}},
+		},
+		expectedErr: `extra volume mount "custom-ca" mount path /etc/pki/ca-trust/extracted/pem collides with a mount of the trusted CA bundle`,
+	}, {
+		name: "Mount at an extra host path mount path",
+		config: config.OperatorConfig{
+			ExtraHostPathMounts: []config.HostPathMount{{Name: "host-creds", HostPath: "/var/lib/creds", MountPath: "/etc/custom-ca"}},
+			ExtraVolumes:        []corev1.Volume{extraVolume},
+			ExtraVolumeMounts:   []corev1.VolumeMount{{Name: "custom-ca", MountPath: "/etc/custom-ca"}},
+		},
+		expectedErr: `extra volume mount "custom-ca" mount path /etc/custom-ca collides with a mount of extra host path mount host-creds`,
+	}, {
+		name: "Extra volume mounts sharing a mount path",
+		config: config.OperatorConfig{
+			ExtraVolumes: []corev1.Volume{extraVolume},
+			ExtraVolumeMounts: []corev1.VolumeMount{
+				{Name: "custom-ca", MountPath: "/etc/custom-ca"},
+				{Name: "custom-ca", MountPath: "/etc/custom-ca", SubPath: "ca.pem"},
+			},
+		},
+		expectedErr: `extra volume mount "custom-ca" mount path /etc/custom-ca collides with a mount of extra volume mount custom-ca`,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateExtraVolumes(tc.config, []client.Object{deployment})
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetExtraNodeManagerArgs(t *testing.T) {
+	script := `#!/bin/bash
+exec /bin/cloud-node-manager \
+  --node-name=$(NODE_NAME) \
+  --wait-routes=false
+`
+
+	tc := []struct {
+		name             string
+		containers       []corev1.Container
+		config           config.OperatorConfig
+		expectedCommands [][]string
+	}{{
+		name: "No extra args",
+		containers: []corev1.Container{{
+			Name:    "cloud-node-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", script},
+		},
+	}, {
+		name: "Extra args are appended",
+		containers: []corev1.Container{{
+			Name:    "cloud-node-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		config: config.OperatorConfig{
+			ExtraNodeManagerArgs: []string{"--kube-api-qps=50", "--node-labels=foo='bar'"},
+		},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", `#!/bin/bash
+exec /bin/cloud-node-manager \
+  --node-name=$(NODE_NAME) \
+  --wait-routes=false \
+'--kube-api-qps=50' \
+'--node-labels=foo='\''bar'\'''
+`},
+		},
+	}, {
+		name: "Other containers are not changed",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		config: config.OperatorConfig{
+			ExtraNodeManagerArgs: []string{"--kube-api-qps=50"},
+		},
+		expectedCommands: [][]string{
+			{"/bin/bash", "-c", script},
+		},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Containers: tc.containers,
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setExtraNodeManagerArgs(tc.config, podSpec)
+			for i, container := range spec.Containers {
+				assert.Equal(t, tc.expectedCommands[i], container.Command)
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestValidateExtraNodeManagerArgs(t *testing.T) {
+	daemonSet := &v1.DaemonSet{
+		Spec: v1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: "cloud-node-manager",
+						Command: []string{"/bin/bash", "-c", `#!/bin/bash
+set -o allexport
+exec /bin/cloud-node-manager \
+  --node-name=$(NODE_NAME) \
+  --wait-routes=false \
+  -v=2 \
+  --enable-deprecated-beta-topology-labels
+`},
+					}},
+				},
+			},
+		},
+	}
+
+	tc := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{{
+		name: "No extra args",
+	}, {
+		name: "Flags not managed by the operator are allowed",
+		args: []string{"--kube-api-qps=50", "-kube-api-burst=100"},
+	}, {
+		name:        "Managed flag is rejected",
+		args:        []string{"--kube-api-qps=50", "--wait-routes=true"},
+		expectedErr: `extra node-manager arg "--wait-routes=true" conflicts with a flag managed by the operator`,
+	}, {
+		name:        "Managed flag with a single dash is rejected",
+		args:        []string{"-node-name=foo"},
+		expectedErr: `extra node-manager arg "-node-name=foo" conflicts with a flag managed by the operator`,
+	}, {
+		name:        "Managed boolean flag is rejected",
+		args:        []string{"--enable-deprecated-beta-topology-labels"},
+		expectedErr: `extra node-manager arg "--enable-deprecated-beta-topology-labels" conflicts with a flag managed by the operator`,
+	}, {
+		name:        "Managed single dash flag is rejected in either form",
+		args:        []string{"--v=4"},
+		expectedErr: `extra node-manager arg "--v=4" conflicts with a flag managed by the operator`,
+	}, {
+		name:        "Positional arg is rejected",
+		args:        []string{"foo"},
+		expectedErr: `extra node-manager arg "foo" is not a flag`,
+	}, {
+		name:        "Dashes only arg is rejected",
+		args:        []string{"--"},
+		expectedErr: `extra node-manager arg "--" is not a flag`,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateExtraNodeManagerArgs(config.OperatorConfig{ExtraNodeManagerArgs: tc.args}, []client.Object{daemonSet})
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetInitContainerResources(t *testing.T) {
+	defaultResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("10m"),
+			corev1.ResourceMemory: resource.MustParse("20Mi"),
+		},
+	}
+	customResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+	}
+
+	tc := []struct {
+		name                       string
+		config                     config.OperatorConfig
+		expectedInitResources      corev1.ResourceRequirements
+		expectedContainerResources corev1.ResourceRequirements
+	}{{
+		name:                       "Defaults are kept when not configured",
+		expectedInitResources:      defaultResources,
+		expectedContainerResources: defaultResources,
+	}, {
+		name: "Init container resources are replaced",
+		config: config.OperatorConfig{
+			InitContainerResources: &customResources,
+		},
+		expectedInitResources:      customResources,
+		expectedContainerResources: defaultResources,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				InitContainers: []corev1.Container{{
+					Name:      "azure-inject-credentials",
+					Resources: *defaultResources.DeepCopy(),
+				}},
+				Containers: []corev1.Container{{
+					Name:      "cloud-controller-manager",
+					Resources: *defaultResources.DeepCopy(),
+				}},
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setInitContainerResources(tc.config, podSpec)
+			assert.Equal(t, tc.expectedInitResources, spec.InitContainers[0].Resources)
+			assert.Equal(t, tc.expectedContainerResources, spec.Containers[0].Resources)
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetPodSysctls(t *testing.T) {
+	tc := []struct {
+		name            string
+		config          config.OperatorConfig
+		expectedSysctls []corev1.Sysctl
+	}{{
+		name:            "Manifest sysctls are kept when not configured",
+		config:          config.OperatorConfig{PlatformStatus: &configv1.PlatformStatus{Type: configv1.AWSPlatformType}},
+		expectedSysctls: []corev1.Sysctl{{Name: "kernel.shm_rmid_forced", Value: "0"}},
+	}, {
+		name: "Sysctls of another platform are not set",
+		config: config.OperatorConfig{
+			PlatformStatus: &configv1.PlatformStatus{Type: configv1.AWSPlatformType},
+			PodSysctls: map[configv1.PlatformType]config.Sysctls{
+				configv1.AzurePlatformType: {{Name: "kernel.msgmax", Value: "65536"}},
+			},
+		},
+		expectedSysctls: []corev1.Sysctl{{Name: "kernel.shm_rmid_forced", Value: "0"}},
+	}, {
+		name: "Configured sysctls are added and override manifest values",
+		config: config.OperatorConfig{
+			PlatformStatus: &configv1.PlatformStatus{Type: configv1.AWSPlatformType},
+			PodSysctls: map[configv1.PlatformType]config.Sysctls{
+				configv1.AWSPlatformType: {
+					{Name: "kernel.shm_rmid_forced", Value: "1"},
+					{Name: "kernel.msgmax", Value: "65536"},
+				},
+			},
+		},
+		expectedSysctls: []corev1.Sysctl{
+			{Name: "kernel.shm_rmid_forced", Value: "1"},
+			{Name: "kernel.msgmax", Value: "65536"},
+		},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				SecurityContext: &corev1.PodSecurityContext{
+					Sysctls: []corev1.Sysctl{{Name: "kernel.shm_rmid_forced", Value: "0"}},
+				},
+				Containers: []corev1.Container{{Name: "cloud-controller-manager"}},
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setPodSysctls(tc.config, podSpec)
+			assert.Equal(t, tc.expectedSysctls, spec.SecurityContext.Sysctls)
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetHostAliases(t *testing.T) {
+	tc := []struct {
+		name            string
+		config          config.OperatorConfig
+		expectedAliases []corev1.HostAlias
+	}{{
+		name:            "Manifest aliases are kept when not configured",
+		expectedAliases: []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"metadata.internal"}}},
+	}, {
+		name: "Configured aliases are added",
+		config: config.OperatorConfig{
+			HostAliases: []corev1.HostAlias{{IP: "10.0.0.10", Hostnames: []string{"ec2.us-east-1.amazonaws.com"}}},
+		},
+		expectedAliases: []corev1.HostAlias{
+			{IP: "10.0.0.1", Hostnames: []string{"metadata.internal"}},
+			{IP: "10.0.0.10", Hostnames: []string{"ec2.us-east-1.amazonaws.com"}},
+		},
+	}, {
+		name: "Hostnames are merged into an alias with the same IP address",
+		config: config.OperatorConfig{
+			HostAliases: []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"metadata.internal", "sts.amazonaws.com"}}},
+		},
+		expectedAliases: []corev1.HostAlias{
+			{IP: "10.0.0.1", Hostnames: []string{"metadata.internal", "sts.amazonaws.com"}},
+		},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				HostNetwork: true,
+				HostAliases: []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"metadata.internal"}}},
+				Containers:  []corev1.Container{{Name: "cloud-controller-manager"}},
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setHostAliases(tc.config, podSpec)
+			assert.Equal(t, tc.expectedAliases, spec.HostAliases)
+			assert.True(t, spec.HostNetwork)
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetDNSConfigHash(t *testing.T) {
+	tc := []struct {
+		name                string
+		config              config.OperatorConfig
+		expectedAnnotations map[string]string
+	}{{
+		name:                "No annotation is set without a DNS config hash",
+		expectedAnnotations: map[string]string{"existing": "annotation"},
+	}, {
+		name:   "DNS config hash is set as an annotation",
+		config: config.OperatorConfig{DNSConfigHash: "abc123"},
+		expectedAnnotations: map[string]string{
+			"existing":              "annotation",
+			DNSConfigHashAnnotation: "abc123",
+		},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			template := &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"existing": "annotation"}},
+			}
+
+			setDNSConfigHash(tc.config, template)
+			assert.Equal(t, tc.expectedAnnotations, template.Annotations)
+		})
+	}
+}
+
+func TestSetAffinity(t *testing.T) {
+	antiAffinity := &corev1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+			TopologyKey:   "kubernetes.io/hostname",
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "cloud-controller-manager"}},
+		}},
+	}
+	controlPlane := corev1.NodeSelectorRequirement{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.NodeSelectorOpExists}
+	zone := corev1.NodeSelectorRequirement{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a", "b"}}
+	preferred := corev1.PreferredSchedulingTerm{Weight: 10, Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zone}}}
+
+	tc := []struct {
+		name             string
+		affinity         *corev1.Affinity
+		podAffinity      *corev1.Affinity
+		expectedAffinity *corev1.Affinity
+	}{{
+		name:             "Affinity is kept when not configured",
+		podAffinity:      &corev1.Affinity{PodAntiAffinity: antiAffinity},
+		expectedAffinity: &corev1.Affinity{PodAntiAffinity: antiAffinity},
+	}, {
+		name: "Node affinity is added, pod anti-affinity is kept",
+		affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{zone}}},
+			},
+		}},
+		podAffinity: &corev1.Affinity{PodAntiAffinity: antiAffinity},
+		expectedAffinity: &corev1.Affinity{
+			PodAntiAffinity: antiAffinity,
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{zone}}},
+				},
+			},
+		},
+	}, {
+		name: "Required node selector terms are combined",
+		affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{zone}}},
+			},
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{preferred},
+		}},
+		podAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{controlPlane}}},
+			},
+		}},
+		expectedAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{controlPlane, zone}}},
+			},
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{preferred},
+		}},
+	}, {
+		name: "Pod anti-affinity is replaced when configured",
+		affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0]}},
+		}},
+		podAffinity: &corev1.Affinity{PodAntiAffinity: antiAffinity},
+		expectedAffinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0]}},
+		}},
+	}, {
+		name:             "Affinity is set on pods without one",
+		affinity:         &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{preferred}}},
+		expectedAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{preferred}}},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Affinity:   tc.podAffinity,
+				Containers: []corev1.Container{{Name: "cloud-controller-manager"}},
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setAffinity(config.OperatorConfig{Affinity: tc.affinity}, podSpec)
+			assert.Equal(t, tc.expectedAffinity, spec.Affinity)
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetTrustedCAMounts(t *testing.T) {
+	trustedCAVolume := corev1.Volume{
+		Name: "trusted-ca",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "ccm-trusted-ca"},
+			Items:                []corev1.KeyToPath{{Key: "ca-bundle.crt", Path: "tls-ca-bundle.pem"}},
+		}},
+	}
+	trustedCAVolumeMount := corev1.VolumeMount{
+		Name:      "trusted-ca",
+		MountPath: "/etc/pki/ca-trust/extracted/pem",
+		ReadOnly:  true,
+	}
+	otherMount := corev1.VolumeMount{Name: "host-etc-kube", MountPath: "/etc/kubernetes"}
+
+	tc := []struct {
+		name     string
+		podSpec  corev1.PodSpec
+		expected corev1.PodSpec
+	}{{
+		name: "Volume and mounts are added",
+		podSpec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers:     []corev1.Container{{Name: "cloud-controller-manager", VolumeMounts: []corev1.VolumeMount{otherMount}}},
+		},
+		expected: corev1.PodSpec{
+			Volumes:        []corev1.Volume{trustedCAVolume},
+			InitContainers: []corev1.Container{{Name: "init", VolumeMounts: []corev1.VolumeMount{trustedCAVolumeMount}}},
+			Containers: []corev1.Container{{
+				Name:         "cloud-controller-manager",
+				VolumeMounts: []corev1.VolumeMount{otherMount, trustedCAVolumeMount},
+			}},
+		},
+	}, {
+		name: "Existing volume and mounts are kept",
+		podSpec: corev1.PodSpec{
+			Volumes:    []corev1.Volume{trustedCAVolume},
+			Containers: []corev1.Container{{Name: "cloud-controller-manager", VolumeMounts: []corev1.VolumeMount{trustedCAVolumeMount}}},
+		},
+		expected: corev1.PodSpec{
+			Volumes:    []corev1.Volume{trustedCAVolume},
+			Containers: []corev1.Container{{Name: "cloud-controller-manager", VolumeMounts: []corev1.VolumeMount{trustedCAVolumeMount}}},
+		},
+	}, {
+		name: "Exempt containers do not get the mount",
+		podSpec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "azure-inject-credentials", VolumeMounts: []corev1.VolumeMount{otherMount}}},
+			Containers:     []corev1.Container{{Name: "cloud-node-manager"}},
+		},
+		expected: corev1.PodSpec{
+			Volumes:        []corev1.Volume{trustedCAVolume},
+			InitContainers: []corev1.Container{{Name: "azure-inject-credentials", VolumeMounts: []corev1.VolumeMount{otherMount}}},
+			Containers:     []corev1.Container{{Name: "cloud-node-manager", VolumeMounts: []corev1.VolumeMount{trustedCAVolumeMount}}},
+		},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			initialPodSpec := tc.podSpec.DeepCopy()
+
+			spec := setTrustedCAMounts(tc.podSpec)
+			assert.Equal(t, tc.expected, spec)
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, tc.podSpec)
+		})
+	}
+}
+
+func TestSetNodeManagerPriorityClass(t *testing.T) {
+	tc := []struct {
+		name                  string
+		priorityClassName     string
+		config                config.OperatorConfig
+		expectedPriorityClass string
+	}{{
+		name:                  "Default priority class is set",
+		expectedPriorityClass: "system-node-critical",
+	}, {
+		name:                  "Template priority class is replaced with the default one",
+		priorityClassName:     "system-cluster-critical",
+		expectedPriorityClass: "system-node-critical",
+	}, {
+		name:              "Priority class from the config is set",
+		priorityClassName: "system-node-critical",
+		config: config.OperatorConfig{
+			NodeManagerPriorityClassName: "custom-node-critical",
+		},
+		expectedPriorityClass: "custom-node-critical",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				PriorityClassName: tc.priorityClassName,
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setNodeManagerPriorityClass(tc.config, podSpec)
+			assert.Equal(t, tc.expectedPriorityClass, spec.PriorityClassName)
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetCloudAPIReadinessProbe(t *testing.T) {
+	metadataProbe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"/bin/bash", "-c", "exec 3<>/dev/tcp/169.254.169.254/80"},
+			},
+		},
+		TimeoutSeconds:   5,
+		PeriodSeconds:    10,
+		FailureThreshold: 3,
+	}
+
+	tc := []struct {
+		name          string
+		config        config.OperatorConfig
+		expectedProbe *corev1.Probe
+	}{{
+		name: "Probe is not enabled",
+		config: config.OperatorConfig{
+			PlatformStatus: &configv1.PlatformStatus{Type: configv1.AWSPlatformType},
+		},
+	}, {
+		name: "Probe is enabled",
+		config: config.OperatorConfig{
+			PlatformStatus:         &configv1.PlatformStatus{Type: configv1.AWSPlatformType},
+			CloudAPIReadinessProbe: true,
+		},
+		expectedProbe: metadataProbe,
+	}, {
+		name: "Platform without metadata endpoint",
+		config: config.OperatorConfig{
+			PlatformStatus:         &configv1.PlatformStatus{Type: configv1.VSpherePlatformType},
+			CloudAPIReadinessProbe: true,
+		},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "cloud-controller-manager"}, {Name: "cloud-node-manager"}},
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setCloudAPIReadinessProbe(tc.config, podSpec)
+			assert.Equal(t, tc.expectedProbe, spec.Containers[0].ReadinessProbe)
+			assert.Nil(t, spec.Containers[1].ReadinessProbe)
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetLeaderElectResourceNamespace(t *testing.T) {
+	tc := []struct {
+		name             string
+		containers       []corev1.Container
+		managedNamespace string
+		expectedScripts  []string
+	}{{
+		name: "Managed namespace is not set",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm \\\n--leader-elect-resource-namespace=openshift-cloud-controller-manager \\\n-v=2\n"},
+		}},
+		expectedScripts: []string{"exec /bin/ccm \\\n--leader-elect-resource-namespace=openshift-cloud-controller-manager \\\n-v=2\n"},
+	}, {
+		name: "Leader election resource namespace flag is rewritten",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm \\\n--leader-elect-resource-namespace=openshift-cloud-controller-manager \\\n-v=2\n"},
+		}},
+		managedNamespace: "custom-ccm",
+		expectedScripts:  []string{"exec /bin/ccm \\\n--leader-elect-resource-namespace=custom-ccm \\\n-v=2\n"},
+	}, {
+		name: "Leader election resource namespace flag is added",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm \\\n--foo=bar\n"},
+		}},
+		managedNamespace: "custom-ccm",
+		expectedScripts:  []string{"exec /bin/ccm \\\n--foo=bar \\\n--leader-elect-resource-namespace=custom-ccm\n"},
+	}, {
+		name: "Other containers are not changed",
+		containers: []corev1.Container{{
+			Name:    "cloud-node-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/cnm -v=2"},
+		}},
+		managedNamespace: "custom-ccm",
+		expectedScripts:  []string{"exec /bin/cnm -v=2"},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Containers: tc.containers,
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setLeaderElectResourceNamespace(config.OperatorConfig{ManagedNamespace: tc.managedNamespace}, podSpec)
+			for i, container := range spec.Containers {
+				assert.Equal(t, []string{"/bin/bash", "-c", tc.expectedScripts[i]}, container.Command)
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetVerbosity(t *testing.T) {
+	tc := []struct {
+		name            string
+		containers      []corev1.Container
+		verbosity       *int
+		expectedScripts []string
+	}{{
+		name: "Verbosity is not set",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm \\\n--foo=bar \\\n-v=2\n"},
+		}},
+		expectedScripts: []string{"exec /bin/ccm \\\n--foo=bar \\\n-v=2\n"},
+	}, {
+		name: "Verbosity flag is rewritten",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm \\\n--foo=bar \\\n-v=2\n"},
+		}},
+		verbosity:       ptr.To(6),
+		expectedScripts: []string{"exec /bin/ccm \\\n--foo=bar \\\n-v=6\n"},
+	}, {
+		name: "Double dash verbosity flag is rewritten",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm --v=2 --foo=bar"},
+		}},
+		verbosity:       ptr.To(4),
+		expectedScripts: []string{"exec /bin/ccm --v=4 --foo=bar"},
+	}, {
+		name: "Verbosity flag is added",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm \\\n--foo=bar\n"},
+		}},
+		verbosity:       ptr.To(4),
+		expectedScripts: []string{"exec /bin/ccm \\\n--foo=bar \\\n--v=4\n"},
+	}, {
+		name: "Other containers are not changed",
+		containers: []corev1.Container{{
+			Name:    "cloud-node-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/cnm -v=2"},
+		}},
+		verbosity:       ptr.To(4),
+		expectedScripts: []string{"exec /bin/cnm -v=2"},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Containers: tc.containers,
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setVerbosity(config.OperatorConfig{CCMVerbosity: tc.verbosity}, podSpec)
+			for i, container := range spec.Containers {
+				assert.Equal(t, []string{"/bin/bash", "-c", tc.expectedScripts[i]}, container.Command)
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetLoggingFormat(t *testing.T) {
+	tc := []struct {
+		name            string
+		containers      []corev1.Container
+		platform        configv1.PlatformType
+		format          config.LoggingFormat
+		expectedScripts []string
+	}{{
+		name: "Format is not set",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm \\\n--foo=bar\n"},
+		}},
+		platform:        configv1.AWSPlatformType,
+		expectedScripts: []string{"exec /bin/ccm \\\n--foo=bar\n"},
+	}, {
+		name: "Format flag is added",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm \\\n--foo=bar\n"},
+		}},
+		platform:        configv1.AWSPlatformType,
+		format:          config.LoggingFormatJSON,
+		expectedScripts: []string{"exec /bin/ccm \\\n--foo=bar \\\n--logging-format=json\n"},
+	}, {
+		name: "Format flag is rewritten",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm --logging-format=text --foo=bar"},
+		}},
+		platform:        configv1.AzurePlatformType,
+		format:          config.LoggingFormatJSON,
+		expectedScripts: []string{"exec /bin/ccm --logging-format=json --foo=bar"},
+	}, {
+		name: "Unsupported platform is not changed",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm \\\n--foo=bar\n"},
+		}},
+		platform:        configv1.NutanixPlatformType,
+		format:          config.LoggingFormatJSON,
+		expectedScripts: []string{"exec /bin/ccm \\\n--foo=bar\n"},
+	}, {
+		name: "Other containers are not changed",
+		containers: []corev1.Container{{
+			Name:    "cloud-node-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/cnm -v=2"},
+		}},
+		platform:        configv1.AzurePlatformType,
+		format:          config.LoggingFormatJSON,
+		expectedScripts: []string{"exec /bin/cnm -v=2"},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Containers: tc.containers,
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			operatorConfig := config.OperatorConfig{
+				PlatformStatus: &configv1.PlatformStatus{Type: tc.platform},
+				LoggingFormat:  tc.format,
+			}
+			spec := setLoggingFormat(operatorConfig, podSpec)
+			for i, container := range spec.Containers {
+				assert.Equal(t, []string{"/bin/bash", "-c", tc.expectedScripts[i]}, container.Command)
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetLogEffectiveFlags(t *testing.T) {
+	script := `#!/bin/bash
+set -o allexport
+exec /bin/ccm \
+--foo=bar \
+--v=2
+`
+	echoedScript := `#!/bin/bash
+set -o allexport
+echo "Effective cloud-controller-manager command:" /bin/ccm \
+--foo=bar \
+--v=2
+exec /bin/ccm \
+--foo=bar \
+--v=2
+`
+
+	tc := []struct {
+		name            string
+		config          config.OperatorConfig
+		containers      []corev1.Container
+		expectedScripts []string
+	}{{
+		name: "Flags are not echoed when not enabled",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		expectedScripts: []string{script},
+	}, {
+		name:   "Flags of the cloud-controller-manager container are echoed",
+		config: config.OperatorConfig{LogEffectiveFlags: true},
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}, {
+			Name:    "cloud-node-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		expectedScripts: []string{echoedScript, script},
+	}, {
+		name:   "Only the exec call is echoed, with its indentation",
+		config: config.OperatorConfig{LogEffectiveFlags: true},
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "if true; then\n  exec /bin/ccm --foo=bar\nfi\n"},
+		}},
+		expectedScripts: []string{"if true; then\n  echo \"Effective cloud-controller-manager command:\" /bin/ccm --foo=bar\n  exec /bin/ccm --foo=bar\nfi\n"},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{Containers: tc.containers}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setLogEffectiveFlags(tc.config, podSpec)
+			for i, container := range spec.Containers {
+				assert.Equal(t, tc.expectedScripts[i], container.Command[2])
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetStartupTimeout(t *testing.T) {
+	script := `#!/bin/bash
+set -o allexport
+if [[ -f /etc/kubernetes/apiserver-url.env ]]; then
+  source /etc/kubernetes/apiserver-url.env
+fi
+exec /bin/ccm \
+--foo=bar
+`
+	wrappedScript := `#!/bin/bash
+startup_script_pid=$BASHPID
+( sleep 90; echo "Startup did not complete within 90s, terminating" >&2; kill -TERM "$startup_script_pid" ) &
+startup_watchdog_pid=$!
+set -o allexport
+if [[ -f /etc/kubernetes/apiserver-url.env ]]; then
+  source /etc/kubernetes/apiserver-url.env
+fi
+kill "$startup_watchdog_pid" 2>/dev/null || true
+exec /bin/ccm \
+--foo=bar
+`
+
+	tc := []struct {
+		name            string
+		containers      []corev1.Container
+		platform        configv1.PlatformType
+		timeouts        map[configv1.PlatformType]time.Duration
+		expectedScripts []string
+	}{{
+		name: "Timeout is not set",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		platform:        configv1.AWSPlatformType,
+		expectedScripts: []string{script},
+	}, {
+		name: "Timeout of another platform is not applied",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		platform:        configv1.AWSPlatformType,
+		timeouts:        map[configv1.PlatformType]time.Duration{configv1.GCPPlatformType: 90 * time.Second},
+		expectedScripts: []string{script},
+	}, {
+		name: "Scripts of all containers are wrapped",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}, {
+			Name:    "cloud-node-manager",
+			Command: []string{"/bin/bash", "-c", script},
+		}},
+		platform:        configv1.AWSPlatformType,
+		timeouts:        map[configv1.PlatformType]time.Duration{configv1.AWSPlatformType: 90 * time.Second},
+		expectedScripts: []string{wrappedScript, wrappedScript},
+	}, {
+		name: "Timeout is rounded up to seconds",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "exec /bin/ccm"},
+		}},
+		platform:        configv1.AWSPlatformType,
+		timeouts:        map[configv1.PlatformType]time.Duration{configv1.AWSPlatformType: 1500 * time.Millisecond},
+		expectedScripts: []string{"startup_script_pid=$BASHPID\n( sleep 2; echo \"Startup did not complete within 2s, terminating\" >&2; kill -TERM \"$startup_script_pid\" ) &\nstartup_watchdog_pid=$!\nkill \"$startup_watchdog_pid\" 2>/dev/null || true\nexec /bin/ccm"},
+	}, {
+		name: "Indentation of the exec line is kept",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "#!/bin/bash\nif true; then\n  exec /bin/ccm\nfi\n"},
+		}},
+		platform:        configv1.AWSPlatformType,
+		timeouts:        map[configv1.PlatformType]time.Duration{configv1.AWSPlatformType: time.Minute},
+		expectedScripts: []string{"#!/bin/bash\nstartup_script_pid=$BASHPID\n( sleep 60; echo \"Startup did not complete within 60s, terminating\" >&2; kill -TERM \"$startup_script_pid\" ) &\nstartup_watchdog_pid=$!\nif true; then\n  kill \"$startup_watchdog_pid\" 2>/dev/null || true\n  exec /bin/ccm\nfi\n"},
+	}, {
+		name: "Scripts without exec are not changed",
+		containers: []corev1.Container{{
+			Name:    "cloud-controller-manager",
+			Command: []string{"/bin/bash", "-c", "/bin/ccm --foo=bar"},
+		}},
+		platform:        configv1.AWSPlatformType,
+		timeouts:        map[configv1.PlatformType]time.Duration{configv1.AWSPlatformType: time.Minute},
+		expectedScripts: []string{"/bin/ccm --foo=bar"},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Containers: tc.containers,
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			operatorConfig := config.OperatorConfig{
+				PlatformStatus:  &configv1.PlatformStatus{Type: tc.platform},
+				StartupTimeouts: tc.timeouts,
+			}
+			spec := setStartupTimeout(operatorConfig, podSpec)
+			for i, container := range spec.Containers {
+				assert.Equal(t, []string{"/bin/bash", "-c", tc.expectedScripts[i]}, container.Command)
+			}
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
+func TestSetConfigHashSources(t *testing.T) {
+	tc := []struct {
+		name                string
+		sources             []string
+		object              client.Object
+		expectedAnnotations map[string]string
+	}{{
+		name:   "No sources configured",
+		object: &v1.DaemonSet{},
+	}, {
+		name:    "Sources are set on node-manager daemonsets",
+		sources: []string{"cloud-conf", "node-config"},
+		object: &v1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"foo": "bar"}},
+		},
+		expectedAnnotations: map[string]string{
+			"foo":                              "bar",
+			config.ConfigHashSourcesAnnotation: "cloud-conf,node-config",
+		},
+	}, {
+		name:    "Deployments are not annotated",
+		sources: []string{"cloud-conf"},
+		object:  &v1.Deployment{},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			initialObject := tc.object.DeepCopyObject()
+
+			operatorConfig := config.OperatorConfig{
+				NodeManagerConfigHashSources: tc.sources,
+			}
+			substituted := SubstituteCommonPartsFromConfig(operatorConfig, []client.Object{tc.object})
+			assert.Equal(t, tc.expectedAnnotations, substituted[0].GetAnnotations())
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, initialObject, tc.object)
+		})
+	}
+}
+
+func TestSetAutosizedResources(t *testing.T) {
+	autosizing := &config.ResourceAutosizing{
+		Min:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		PerNode: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+		Max:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+	}
+	templateRequests := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("200m"),
+		corev1.ResourceMemory: resource.MustParse("50Mi"),
+	}
+
+	tc := []struct {
+		name             string
+		config           config.OperatorConfig
+		expectedRequests corev1.ResourceList
+	}{{
+		name:             "Autosizing is not enabled",
+		config:           config.OperatorConfig{NodeCount: 10},
+		expectedRequests: templateRequests,
+	}, {
+		name:   "Small cluster",
+		config: config.OperatorConfig{ResourceAutosizing: autosizing, NodeCount: 3},
+		expectedRequests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("200m"),
+			corev1.ResourceMemory: resource.MustParse("50Mi"),
+		},
+	}, {
+		name:   "Large cluster",
+		config: config.OperatorConfig{ResourceAutosizing: autosizing, NodeCount: 500},
+		expectedRequests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("50Mi"),
+		},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:      "cloud-controller-manager",
+					Resources: corev1.ResourceRequirements{Requests: templateRequests.DeepCopy()},
+				}, {
+					Name:      "sidecar",
+					Resources: corev1.ResourceRequirements{Requests: templateRequests.DeepCopy()},
+				}},
+			}
+			initialPodSpec := podSpec.DeepCopy()
+
+			spec := setAutosizedResources(tc.config, podSpec)
+			assert.Len(t, spec.Containers[0].Resources.Requests, len(tc.expectedRequests))
+			for name, quantity := range tc.expectedRequests {
+				assert.Zero(t, quantity.Cmp(spec.Containers[0].Resources.Requests[name]), "unexpected %s request", name)
+			}
+			assert.Equal(t, templateRequests, spec.Containers[1].Resources.Requests)
+			// Ensure there is no mutation in place
+			assert.EqualValues(t, *initialPodSpec, podSpec)
+		})
+	}
+}
+
 func TestFillConfigValues(t *testing.T) {
 	testManagementNamespace := "test-namespace"
+	trustedCAVolume := corev1.Volume{
+		Name: "trusted-ca",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "ccm-trusted-ca"},
+			Items:                []corev1.KeyToPath{{Key: "ca-bundle.crt", Path: "tls-ca-bundle.pem"}},
+		}},
+	}
 
 	tc := []struct {
 		name            string
@@ -206,10 +1947,17 @@ func TestFillConfigValues(t *testing.T) {
 			},
 		}},
 		expectedObjects: []client.Object{&v1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: GetCommonLabels(),
+			},
 			Spec: v1.DeploymentSpec{
 				Replicas: ptr.To[int32](1),
 				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: GetCommonLabels(),
+					},
 					Spec: corev1.PodSpec{
+						Volumes:    []corev1.Volume{trustedCAVolume},
 						Containers: []corev1.Container{},
 					},
 				},
@@ -219,6 +1967,72 @@ func TestFillConfigValues(t *testing.T) {
 			ManagedNamespace: testManagementNamespace,
 			IsSingleReplica:  true,
 		},
+	}, {
+		name: "Substitute common labels",
+		objects: []client.Object{&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"foo": "bar"},
+			},
+		}, &v1.DaemonSet{
+			Spec: v1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{"k8s-app": "cloud-node-manager"},
+					},
+				},
+			},
+		}},
+		expectedObjects: []client.Object{&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"foo": "bar", PartOfLabel: PartOfLabelValue},
+			},
+		}, &v1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{PartOfLabel: PartOfLabelValue},
+			},
+			Spec: v1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{"k8s-app": "cloud-node-manager", PartOfLabel: PartOfLabelValue},
+					},
+					Spec: corev1.PodSpec{
+						Volumes:           []corev1.Volume{trustedCAVolume},
+						PriorityClassName: "system-node-critical",
+					},
+				},
+			},
+		}},
+		config: config.OperatorConfig{
+			ManagedNamespace: testManagementNamespace,
+		},
+	}, {
+		name: "Substitute node-manager min ready seconds",
+		objects: []client.Object{&v1.DaemonSet{
+			Spec: v1.DaemonSetSpec{
+				MinReadySeconds: 5,
+			},
+		}},
+		expectedObjects: []client.Object{&v1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: GetCommonLabels(),
+			},
+			Spec: v1.DaemonSetSpec{
+				MinReadySeconds: 30,
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: GetCommonLabels(),
+					},
+					Spec: corev1.PodSpec{
+						Volumes:           []corev1.Volume{trustedCAVolume},
+						PriorityClassName: "system-node-critical",
+					},
+				},
+			},
+		}},
+		config: config.OperatorConfig{
+			ManagedNamespace:           testManagementNamespace,
+			NodeManagerMinReadySeconds: ptr.To[int32](30),
+		},
 	}}
 
 	for _, tc := range tc {
@@ -0,0 +1,192 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+const (
+	// WebhookCertDir is where the serving certificate and key of the webhook Service are mounted in webhook pods,
+	// as tls.crt and tls.key.
+	WebhookCertDir = "/etc/webhook/certs"
+
+	webhookContainerName = "cloud-provider-webhook"
+	webhookSelectorLabel = "infrastructure.openshift.io/cloud-provider-webhook"
+	webhookServicePort   = 443
+
+	// servingCertSecretAnnotation and injectCABundleAnnotation make the service CA operator issue a serving
+	// certificate for the webhook Service and inject its CA bundle into the webhook configuration.
+	servingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+	injectCABundleAnnotation    = "service.beta.openshift.io/inject-cabundle"
+)
+
+// WebhookConfig describes an admission webhook a provider ships besides its cloud controller manager.
+type WebhookConfig struct {
+	// Command runs the webhook server in the webhook image. The server is expected to serve TLS on Port
+	// with the certificate and key found in WebhookCertDir.
+	Command []string
+	// Port is the container port the webhook server listens on.
+	Port int32
+	// Path is the URL path admission reviews are sent to.
+	Path string
+	// Rules select the requests which are sent to the webhook.
+	Rules []admissionregistrationv1.RuleWithOperations
+}
+
+// WebhookProvider is implemented by CloudProviderAssets of providers which ship an admission webhook.
+// The webhook is only deployed when the webhook image is set in the images file.
+type WebhookProvider interface {
+	GetWebhookConfig() WebhookConfig
+}
+
+// GetWebhookResources returns the Deployment and Service running the given admission webhook with the webhook
+// image from the config, and the ValidatingWebhookConfiguration registering it. Nothing is returned if the
+// webhook image is not set.
+func GetWebhookResources(config config.OperatorConfig, webhook WebhookConfig) []client.Object {
+	image := config.ImagesReference.CloudProviderWebhook
+	if image == "" {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-cloud-provider-webhook", strings.ToLower(config.GetPlatformNameString()))
+	labels := map[string]string{
+		operandAppSelectorLabel: name,
+		webhookSelectorLabel:    config.GetPlatformNameString(),
+	}
+	servingCertSecretName := name + "-serving-cert"
+
+	replicas := int32(2)
+	if config.IsSingleReplica {
+		replicas = 1
+	}
+
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: config.ManagedNamespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"target.workload.openshift.io/management": `{"effect": "PreferredDuringScheduling"}`,
+					},
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					PriorityClassName:            "system-cluster-critical",
+					AutomountServiceAccountToken: ptr.To(false),
+					Containers: []corev1.Container{{
+						Name:    webhookContainerName,
+						Image:   image,
+						Command: webhook.Command,
+						Ports: []corev1.ContainerPort{{
+							Name:          "webhook",
+							ContainerPort: webhook.Port,
+							Protocol:      corev1.ProtocolTCP,
+						}},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("10m"),
+								corev1.ResourceMemory: resource.MustParse("50Mi"),
+							},
+						},
+						TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "serving-cert",
+							MountPath: WebhookCertDir,
+							ReadOnly:  true,
+						}},
+					}},
+					NodeSelector: map[string]string{
+						"node-role.kubernetes.io/master": "",
+					},
+					Tolerations: []corev1.Toleration{{
+						Key:      "node-role.kubernetes.io/master",
+						Operator: corev1.TolerationOpExists,
+						Effect:   corev1.TaintEffectNoSchedule,
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "serving-cert",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: servingCertSecretName},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: config.ManagedNamespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				servingCertSecretAnnotation: servingCertSecretName,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports: []corev1.ServicePort{{
+				Name:       "webhook",
+				Port:       webhookServicePort,
+				TargetPort: intstr.FromInt32(webhook.Port),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+
+	webhookConfiguration := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ValidatingWebhookConfiguration",
+			APIVersion: "admissionregistration.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: GetCommonLabels(),
+			Annotations: map[string]string{
+				injectCABundleAnnotation: "true",
+			},
+		},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name: name + ".cloud-controller-manager.openshift.io",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{
+					Namespace: config.ManagedNamespace,
+					Name:      name,
+					Path:      ptr.To(webhook.Path),
+					Port:      ptr.To[int32](webhookServicePort),
+				},
+			},
+			Rules:                   webhook.Rules,
+			FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+			AdmissionReviewVersions: []string{"v1"},
+		}},
+	}
+
+	return []client.Object{deployment, service, webhookConfiguration}
+}
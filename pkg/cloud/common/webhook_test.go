@@ -0,0 +1,102 @@
+package common
+
+import (
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+func TestGetWebhookResources(t *testing.T) {
+	webhook := WebhookConfig{
+		Command: []string{"/bin/cloud-provider-webhook", "--tls-cert-dir=" + WebhookCertDir},
+		Port:    8443,
+		Path:    "/validate-nodes",
+		Rules: []admissionregistrationv1.RuleWithOperations{{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Update},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{""},
+				APIVersions: []string{"v1"},
+				Resources:   []string{"nodes"},
+			},
+		}},
+	}
+
+	tc := []struct {
+		name             string
+		config           config.OperatorConfig
+		expectedReplicas int32
+		expectResources  bool
+	}{{
+		name: "No resources without the webhook image",
+		config: config.OperatorConfig{
+			ManagedNamespace: "openshift-cloud-controller-manager",
+			PlatformStatus:   &configv1.PlatformStatus{Type: configv1.AzurePlatformType},
+		},
+	}, {
+		name: "Resources are rendered with the webhook image",
+		config: config.OperatorConfig{
+			ManagedNamespace: "openshift-cloud-controller-manager",
+			PlatformStatus:   &configv1.PlatformStatus{Type: configv1.AzurePlatformType},
+			ImagesReference:  config.ImagesReference{CloudProviderWebhook: "quay.io/openshift/webhook:latest"},
+		},
+		expectedReplicas: 2,
+		expectResources:  true,
+	}, {
+		name: "Single replica topology",
+		config: config.OperatorConfig{
+			ManagedNamespace: "openshift-cloud-controller-manager",
+			PlatformStatus:   &configv1.PlatformStatus{Type: configv1.AzurePlatformType},
+			ImagesReference:  config.ImagesReference{CloudProviderWebhook: "quay.io/openshift/webhook:latest"},
+			IsSingleReplica:  true,
+		},
+		expectedReplicas: 1,
+		expectResources:  true,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := GetWebhookResources(tc.config, webhook)
+			if !tc.expectResources {
+				assert.Empty(t, resources)
+				return
+			}
+			if !assert.Len(t, resources, 3) {
+				return
+			}
+
+			deployment := resources[0].(*appsv1.Deployment)
+			assert.Equal(t, "azure-cloud-provider-webhook", deployment.Name)
+			assert.Equal(t, "openshift-cloud-controller-manager", deployment.Namespace)
+			assert.Equal(t, tc.expectedReplicas, *deployment.Spec.Replicas)
+			assert.Equal(t, deployment.Spec.Selector.MatchLabels, deployment.Spec.Template.Labels)
+			container := deployment.Spec.Template.Spec.Containers[0]
+			assert.Equal(t, "quay.io/openshift/webhook:latest", container.Image)
+			assert.Equal(t, webhook.Command, container.Command)
+			assert.Equal(t, int32(8443), container.Ports[0].ContainerPort)
+			assert.Equal(t, "azure-cloud-provider-webhook-serving-cert", deployment.Spec.Template.Spec.Volumes[0].Secret.SecretName)
+
+			service := resources[1].(*corev1.Service)
+			assert.Equal(t, "azure-cloud-provider-webhook", service.Name)
+			assert.Equal(t, deployment.Spec.Selector.MatchLabels, service.Spec.Selector)
+			assert.Equal(t, intstr.FromInt32(8443), service.Spec.Ports[0].TargetPort)
+			assert.Equal(t, "azure-cloud-provider-webhook-serving-cert", service.Annotations[servingCertSecretAnnotation])
+
+			webhookConfiguration := resources[2].(*admissionregistrationv1.ValidatingWebhookConfiguration)
+			assert.Equal(t, "true", webhookConfiguration.Annotations[injectCABundleAnnotation])
+			if assert.Len(t, webhookConfiguration.Webhooks, 1) {
+				serviceRef := webhookConfiguration.Webhooks[0].ClientConfig.Service
+				assert.Equal(t, "openshift-cloud-controller-manager", serviceRef.Namespace)
+				assert.Equal(t, service.Name, serviceRef.Name)
+				assert.Equal(t, "/validate-nodes", *serviceRef.Path)
+				assert.Equal(t, webhook.Rules, webhookConfiguration.Webhooks[0].Rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package cloud
+
+import (
+	"sync"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+// StatusConditionsProvider returns platform specific conditions to be merged into the cluster operator status,
+// e.g. to report a misconfiguration of vSphere zones. Providers should use condition types specific to their
+// platform, since the returned conditions take precedence over ones computed by the operator.
+type StatusConditionsProvider func(config config.OperatorConfig) []configv1.ClusterOperatorStatusCondition
+
+var (
+	statusConditionsProvidersMu sync.RWMutex
+	statusConditionsProviders   = map[configv1.PlatformType]StatusConditionsProvider{}
+)
+
+// RegisterStatusConditionsProvider sets the status conditions provider for the given platform,
+// replacing a previously registered one. Passing a nil provider unregisters it.
+func RegisterStatusConditionsProvider(platform configv1.PlatformType, provider StatusConditionsProvider) {
+	statusConditionsProvidersMu.Lock()
+	defer statusConditionsProvidersMu.Unlock()
+
+	if provider == nil {
+		delete(statusConditionsProviders, platform)
+		return
+	}
+	statusConditionsProviders[platform] = provider
+}
+
+// GetStatusConditions returns conditions contributed by the status conditions provider registered
+// for the platform of the given OperatorConfig, if any.
+func GetStatusConditions(operatorConfig config.OperatorConfig) []configv1.ClusterOperatorStatusCondition {
+	if operatorConfig.PlatformStatus == nil {
+		return nil
+	}
+
+	statusConditionsProvidersMu.RLock()
+	provider, ok := statusConditionsProviders[operatorConfig.PlatformStatus.Type]
+	statusConditionsProvidersMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return provider(operatorConfig)
+}
@@ -0,0 +1,61 @@
+package cloud
+
+import (
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+func TestGetStatusConditions(t *testing.T) {
+	zonesCondition := configv1.ClusterOperatorStatusCondition{
+		Type:    "VSphereZonesMisconfigured",
+		Status:  configv1.ConditionTrue,
+		Reason:  "ZonesMisconfigured",
+		Message: "zones are misconfigured",
+	}
+
+	RegisterStatusConditionsProvider(configv1.VSpherePlatformType, func(_ config.OperatorConfig) []configv1.ClusterOperatorStatusCondition {
+		return []configv1.ClusterOperatorStatusCondition{zonesCondition}
+	})
+	defer RegisterStatusConditionsProvider(configv1.VSpherePlatformType, nil)
+
+	tc := []struct {
+		name               string
+		config             config.OperatorConfig
+		expectedConditions []configv1.ClusterOperatorStatusCondition
+	}{{
+		name: "Provider registered for the platform",
+		config: config.OperatorConfig{
+			PlatformStatus: &configv1.PlatformStatus{Type: configv1.VSpherePlatformType},
+		},
+		expectedConditions: []configv1.ClusterOperatorStatusCondition{zonesCondition},
+	}, {
+		name: "No provider registered for the platform",
+		config: config.OperatorConfig{
+			PlatformStatus: &configv1.PlatformStatus{Type: configv1.GCPPlatformType},
+		},
+	}, {
+		name:   "Platform status is not set",
+		config: config.OperatorConfig{},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedConditions, GetStatusConditions(tc.config))
+		})
+	}
+}
+
+func TestUnregisterStatusConditionsProvider(t *testing.T) {
+	RegisterStatusConditionsProvider(configv1.AWSPlatformType, func(_ config.OperatorConfig) []configv1.ClusterOperatorStatusCondition {
+		return []configv1.ClusterOperatorStatusCondition{{Type: "AWSSomething", Status: configv1.ConditionTrue}}
+	})
+	operatorConfig := config.OperatorConfig{PlatformStatus: &configv1.PlatformStatus{Type: configv1.AWSPlatformType}}
+	assert.Len(t, GetStatusConditions(operatorConfig), 1)
+
+	RegisterStatusConditionsProvider(configv1.AWSPlatformType, nil)
+	assert.Empty(t, GetStatusConditions(operatorConfig))
+}
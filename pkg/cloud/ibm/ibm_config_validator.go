@@ -0,0 +1,18 @@
+package ibm
+
+import (
+	"fmt"
+
+	gcfg "gopkg.in/gcfg.v1"
+)
+
+// CloudConfigValidator checks that the cloud config can be read by the IBM cloud provider, which is used on
+// IBM Cloud and Power VS. The provider reads its config with gcfg, ignoring unknown sections and keys,
+// so only syntax errors make the config unreadable.
+func CloudConfigValidator(config string) error {
+	var cfg struct{}
+	if err := gcfg.FatalOnly(gcfg.ReadStringInto(&cfg, config)); err != nil {
+		return fmt.Errorf("cloud config can not be read by the IBM cloud provider: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package ibm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudConfigValidator(t *testing.T) {
+	tc := []struct {
+		name      string
+		config    string
+		expectErr string
+	}{{
+		name: "Valid config",
+		config: `[global]
+version = 1.1.0
+[kubernetes]
+config-file = ""
+[provider]
+accountID = 1e1f75646aef447814a6d907cc83fb3c
+clusterID = ocp4-8pxks
+`,
+	}, {
+		name:      "Unterminated section header",
+		config:    "[global\nversion = 1.1.0\n",
+		expectErr: "cloud config can not be read by the IBM cloud provider: 1:8: expected subsection name or right bracket",
+	}, {
+		name:      "Key outside of a section",
+		config:    "version = 1.1.0\n",
+		expectErr: "cloud config can not be read by the IBM cloud provider: 1:1: expected section header",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CloudConfigValidator(tc.config)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
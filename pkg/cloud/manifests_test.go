@@ -0,0 +1,77 @@
+package cloud
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/cloud/common"
+)
+
+// platformAssetsDirs maps platforms from getPlatforms to the provider package
+// which embeds their manifests.
+var platformAssetsDirs = map[string]string{
+	"AWS":           "aws",
+	"Azure":         "azure",
+	"AzureStackHub": "azurestack",
+	"GCP":           "gcp",
+	"IBMCloud":      "ibm",
+	"Nutanix":       "nutanix",
+	"OpenStack":     "openstack",
+	"PowerVS":       "powervs",
+	"VSphere":       "vsphere",
+}
+
+func TestEmbeddedManifests(t *testing.T) {
+	/*
+		This test loads every manifest embedded into the provider packages, before
+		any substitution from the operator config happens, and ensures that each of them
+		is rendered and satisfies invariants shared across all platforms.
+		New manifests must follow these invariants, otherwise this test will fail.
+	*/
+
+	platforms := getPlatforms()
+	for platformName, platform := range platforms {
+		t.Run(platformName, func(t *testing.T) {
+			assets, err := getAssets(platform.getOperatorConfig())
+			if _, isPlatformNotFoundError := err.(*platformNotFoundError); isPlatformNotFoundError {
+				assert.NotContains(t, platformAssetsDirs, platformName, "Platform with embedded manifests should have assets")
+				return
+			}
+			assert.NoError(t, err)
+
+			assetsDir, ok := platformAssetsDirs[platformName]
+			if !assert.True(t, ok, "Embedded manifests directory is not known for platform %s", platformName) {
+				return
+			}
+
+			manifests, err := os.ReadDir(filepath.Join(assetsDir, "assets"))
+			assert.NoError(t, err)
+
+			resources := assets.GetRenderedResources()
+			assert.Len(t, resources, len(manifests), "Every embedded manifest should be rendered")
+
+			for _, resource := range resources {
+				var podSpec corev1.PodSpec
+				switch obj := resource.(type) {
+				case *appsv1.Deployment:
+					podSpec = obj.Spec.Template.Spec
+				case *appsv1.DaemonSet:
+					podSpec = obj.Spec.Template.Spec
+				default:
+					// Nothing to check for non pod producing types
+					continue
+				}
+
+				checkResourceRunsBeforeCNI(t, platformName, podSpec)
+				checkLeaderElection(t, podSpec)
+				checkCloudControllerManagerFlags(t, podSpec, false)
+				checkTrustedCAMounted(t, podSpec, common.IsTrustedCAMountExempt)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package nutanix
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+const (
+	prismCentralKey      = "prismCentral"
+	addressKey           = "address"
+	portKey              = "port"
+	topologyDiscoveryKey = "topologyDiscovery"
+	topologyTypeKey      = "type"
+	topologyCategoryKey  = "topologyCategories"
+	regionCategoryKey    = "regionCategory"
+	zoneCategoryKey      = "zoneCategory"
+
+	// topologyDiscoveryCategories instructs nutanix-cloud-controller-manager to derive
+	// node topology from the categories assigned to the VMs.
+	topologyDiscoveryCategories = "Categories"
+
+	// Category keys assigned by the installer to the VMs of a zonal cluster.
+	regionCategoryValue = "openshift-region"
+	zoneCategoryValue   = "openshift-zone"
+)
+
+// CloudConfigTransformer implements the cloudConfigTransformer. It takes
+// the user-provided nutanix-cloud-controller-manager configuration and supplements it
+// with Prism Central endpoint and topology settings from the Infrastructure resource.
+// An empty source config is passed through as is, since the cloud provider runs with defaults then.
+// It returns an error if the platform is not NutanixPlatformType or if the source config can not be parsed.
+func CloudConfigTransformer(source string, infra *configv1.Infrastructure, network *configv1.Network) (string, error) {
+	if infra.Status.PlatformStatus == nil ||
+		infra.Status.PlatformStatus.Type != configv1.NutanixPlatformType {
+		return "", fmt.Errorf("invalid platform, expected to be %s", configv1.NutanixPlatformType)
+	}
+
+	if strings.TrimSpace(source) == "" {
+		return source, nil
+	}
+
+	// The config is kept as a generic map, so keys which are not known to the operator are preserved as is.
+	cfg := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(source), &cfg); err != nil {
+		return "", fmt.Errorf("failed to unmarshal the cloud.conf: %w", err)
+	}
+
+	changed := false
+	if infra.Spec.PlatformSpec.Nutanix != nil {
+		changed = setPrismCentral(cfg, infra.Spec.PlatformSpec.Nutanix.PrismCentral)
+
+		// topology categories should only be applied if length of failuredomains is
+		// greater than one so existing single (or non-zonal) installs function.
+		if len(infra.Spec.PlatformSpec.Nutanix.FailureDomains) > 1 {
+			changed = setTopologyCategories(cfg) || changed
+		}
+	}
+
+	// Marshalling compacts the config and sorts its keys, so the source is kept as is
+	// unless a value has to change, to not rewrite the synced config needlessly.
+	if !changed {
+		return source, nil
+	}
+
+	cfgBytes, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the cloud.conf: %w", err)
+	}
+	return string(cfgBytes), nil
+}
+
+// setPrismCentral overrides Prism Central endpoint in the config with the one from the Infrastructure resource.
+// It returns whether the config changed.
+func setPrismCentral(cfg map[string]interface{}, prismCentral configv1.NutanixPrismEndpoint) bool {
+	changed := false
+	section := getSection(cfg, prismCentralKey)
+	if prismCentral.Address != "" && section[addressKey] != prismCentral.Address {
+		section[addressKey] = prismCentral.Address
+		changed = true
+	}
+	// Numbers of the unmarshalled config are float64.
+	if port, ok := section[portKey].(float64); prismCentral.Port != 0 && (!ok || port != float64(prismCentral.Port)) {
+		section[portKey] = prismCentral.Port
+		changed = true
+	}
+	return changed
+}
+
+// setTopologyCategories configures topology discovery from the VM categories,
+// unless the config already defines how topology is discovered. It returns whether the config changed.
+func setTopologyCategories(cfg map[string]interface{}) bool {
+	section := getSection(cfg, topologyDiscoveryKey)
+	if _, ok := section[topologyTypeKey]; ok {
+		return false
+	}
+	section[topologyTypeKey] = topologyDiscoveryCategories
+
+	categories := getSection(section, topologyCategoryKey)
+	categories[regionCategoryKey] = regionCategoryValue
+	categories[zoneCategoryKey] = zoneCategoryValue
+	return true
+}
+
+// getSection returns the nested config section with the given key, creating it if it does not exist.
+func getSection(cfg map[string]interface{}, key string) map[string]interface{} {
+	section, ok := cfg[key].(map[string]interface{})
+	if !ok {
+		section = map[string]interface{}{}
+		cfg[key] = section
+	}
+	return section
+}
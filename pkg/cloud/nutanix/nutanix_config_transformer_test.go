@@ -0,0 +1,178 @@
+package nutanix
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	sourceConfig = `{
+  "prismCentral": {
+    "address": "pc.example.com",
+    "port": 9440,
+    "credentialRef": {
+      "kind": "Secret",
+      "name": "nutanix-credentials",
+      "namespace": "openshift-cloud-controller-manager"
+    }
+  },
+  "enableCustomLabeling": true
+}`
+
+	sourceConfigWithTopology = `{
+  "prismCentral": {
+    "address": "pc.example.com",
+    "port": 9440
+  },
+  "topologyDiscovery": {
+    "type": "Prism"
+  }
+}`
+)
+
+func makeInfrastructureResource(platform configv1.PlatformType, spec *configv1.NutanixPlatformSpec) *configv1.Infrastructure {
+	return &configv1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Spec: configv1.InfrastructureSpec{
+			PlatformSpec: configv1.PlatformSpec{
+				Type:    platform,
+				Nutanix: spec,
+			},
+		},
+		Status: configv1.InfrastructureStatus{
+			PlatformStatus: &configv1.PlatformStatus{
+				Type: platform,
+			},
+		},
+	}
+}
+
+func TestCloudConfigTransformer(t *testing.T) {
+	failureDomains := []configv1.NutanixFailureDomain{{Name: "fd-1"}, {Name: "fd-2"}}
+
+	tc := []struct {
+		name     string
+		source   string
+		infra    *configv1.Infrastructure
+		expected string
+		// unchanged expects the source to be returned byte for byte
+		unchanged bool
+		errMsg    string
+	}{
+		{
+			name:      "No platform spec keeps config untouched",
+			source:    sourceConfig,
+			infra:     makeInfrastructureResource(configv1.NutanixPlatformType, nil),
+			unchanged: true,
+		},
+		{
+			name:   "Config matching the platform spec is not reformatted",
+			source: "{\n\t\"prismCentral\": {\"port\": 9440,   \"address\": \"pc.example.com\"},\n\t\"enableCustomLabeling\": true\n}\n",
+			infra: makeInfrastructureResource(configv1.NutanixPlatformType, &configv1.NutanixPlatformSpec{
+				PrismCentral: configv1.NutanixPrismEndpoint{Address: "pc.example.com", Port: 9440},
+			}),
+			unchanged: true,
+		},
+		{
+			name:   "Prism Central endpoint is overridden from platform spec",
+			source: sourceConfig,
+			infra: makeInfrastructureResource(configv1.NutanixPlatformType, &configv1.NutanixPlatformSpec{
+				PrismCentral: configv1.NutanixPrismEndpoint{Address: "pc.override.example.com", Port: 9441},
+			}),
+			expected: `{
+  "prismCentral": {
+    "address": "pc.override.example.com",
+    "port": 9441,
+    "credentialRef": {
+      "kind": "Secret",
+      "name": "nutanix-credentials",
+      "namespace": "openshift-cloud-controller-manager"
+    }
+  },
+  "enableCustomLabeling": true
+}`,
+		},
+		{
+			name:   "Prism Central section is created when missing",
+			source: `{}`,
+			infra: makeInfrastructureResource(configv1.NutanixPlatformType, &configv1.NutanixPlatformSpec{
+				PrismCentral: configv1.NutanixPrismEndpoint{Address: "pc.override.example.com", Port: 9441},
+			}),
+			expected: `{"prismCentral": {"address": "pc.override.example.com", "port": 9441}}`,
+		},
+		{
+			name:   "Topology categories are set for multiple failure domains",
+			source: `{"prismCentral": {"address": "pc.example.com", "port": 9440}}`,
+			infra: makeInfrastructureResource(configv1.NutanixPlatformType, &configv1.NutanixPlatformSpec{
+				PrismCentral:   configv1.NutanixPrismEndpoint{Address: "pc.example.com", Port: 9440},
+				FailureDomains: failureDomains,
+			}),
+			expected: `{
+  "prismCentral": {"address": "pc.example.com", "port": 9440},
+  "topologyDiscovery": {
+    "type": "Categories",
+    "topologyCategories": {"regionCategory": "openshift-region", "zoneCategory": "openshift-zone"}
+  }
+}`,
+		},
+		{
+			name:   "Existing topology discovery is preserved",
+			source: sourceConfigWithTopology,
+			infra: makeInfrastructureResource(configv1.NutanixPlatformType, &configv1.NutanixPlatformSpec{
+				PrismCentral:   configv1.NutanixPrismEndpoint{Address: "pc.example.com", Port: 9440},
+				FailureDomains: failureDomains,
+			}),
+			unchanged: true,
+		},
+		{
+			name:   "Empty source is passed through",
+			source: "",
+			infra: makeInfrastructureResource(configv1.NutanixPlatformType, &configv1.NutanixPlatformSpec{
+				PrismCentral:   configv1.NutanixPrismEndpoint{Address: "pc.example.com", Port: 9440},
+				FailureDomains: failureDomains,
+			}),
+			expected: "",
+		},
+		{
+			name:   "Non Nutanix platform returns an error",
+			source: sourceConfig,
+			infra:  makeInfrastructureResource(configv1.AWSPlatformType, nil),
+			errMsg: "invalid platform, expected to be Nutanix",
+		},
+		{
+			name:   "Malformed source returns an error",
+			source: `{"prismCentral": `,
+			infra:  makeInfrastructureResource(configv1.NutanixPlatformType, nil),
+			errMsg: "failed to unmarshal the cloud.conf: unexpected end of JSON input",
+		},
+	}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := CloudConfigTransformer(tc.source, tc.infra, nil)
+			if tc.errMsg != "" {
+				g.Expect(err).Should(MatchError(tc.errMsg))
+				g.Expect(actual).Should(Equal(""))
+				return
+			}
+
+			g.Expect(err).ShouldNot(HaveOccurred())
+			if tc.unchanged {
+				g.Expect(actual).Should(Equal(tc.source))
+				return
+			}
+			if tc.expected == "" {
+				g.Expect(actual).Should(BeEmpty())
+				return
+			}
+			g.Expect(actual).Should(MatchJSON(tc.expected))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package powervs
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	configv1 "github.com/openshift/api/config/v1"
+	ini "gopkg.in/ini.v1"
+	"k8s.io/klog/v2"
+)
+
+const providerSectionName = "provider"
+
+// endpointOverrideKeys maps names of Power VS service endpoints from the Infrastructure resource
+// to the provider section keys the IBM cloud provider reads endpoint overrides from.
+var endpointOverrideKeys = map[string]string{
+	"iam":             "iamEndpointOverride",
+	"vpc":             "g2EndpointOverride",
+	"resourcemanager": "rmEndpointOverride",
+	"power":           "powerVSEndpointOverride",
+}
+
+// CloudConfigTransformer implements the cloudConfigTransformer. It takes the user-provided
+// ibm-cloud-controller-manager configuration and sets the Power VS region, zone and resource group,
+// along with service endpoint overrides, from the Infrastructure resource in the provider section.
+// Keys of the provider section are written in alphabetical order. This is the only transformer of the IBM cloud
+// provider config, on IBM Cloud the user-provided config is synced as is.
+// The service instance ID is kept as provided, since the Infrastructure resource does not carry it.
+// It returns an error if the platform is not PowerVSPlatformType, if the source config can not be parsed
+// or if a service endpoint is listed more than once.
+func CloudConfigTransformer(source string, infra *configv1.Infrastructure, network *configv1.Network) (string, error) {
+	if infra.Status.PlatformStatus == nil ||
+		infra.Status.PlatformStatus.Type != configv1.PowerVSPlatformType {
+		return "", fmt.Errorf("invalid platform, expected to be %s", configv1.PowerVSPlatformType)
+	}
+	powerVSStatus := infra.Status.PlatformStatus.PowerVS
+	if powerVSStatus == nil {
+		return "", fmt.Errorf("%s platform status is not populated on infrastructure", configv1.PowerVSPlatformType)
+	}
+
+	cfg, err := ini.Load([]byte(source))
+	if err != nil {
+		return "", fmt.Errorf("failed to read the cloud.conf: %w", err)
+	}
+
+	section, err := cfg.GetSection(providerSectionName)
+	if err != nil {
+		section, err = cfg.NewSection(providerSectionName)
+		if err != nil {
+			return "", fmt.Errorf("failed to modify the provided configuration: %w", err)
+		}
+	}
+
+	// Use a slice to preserve keys order
+	for _, o := range []struct{ k, v string }{
+		{"powerVSRegion", powerVSStatus.Region},
+		{"powerVSZone", powerVSStatus.Zone},
+		{"g2ResourceGroupName", powerVSStatus.ResourceGroup},
+	} {
+		if o.v == "" {
+			continue
+		}
+		section.Key(o.k).SetValue(o.v)
+	}
+
+	if err := setEndpointOverrides(section, powerVSStatus.ServiceEndpoints); err != nil {
+		return "", err
+	}
+	if err := sortSectionKeys(section); err != nil {
+		return "", fmt.Errorf("failed to modify the provided configuration: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := cfg.WriteTo(&buf); err != nil {
+		return "", fmt.Errorf("failed to modify the provided configuration: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// setEndpointOverrides sets endpoint override keys of the provider section for the service endpoints
+// known to the IBM cloud provider. Endpoints of other services are not used by the cloud provider and are skipped.
+func setEndpointOverrides(section *ini.Section, endpoints []configv1.PowerVSServiceEndpoint) error {
+	seen := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		if seen[endpoint.Name] {
+			return fmt.Errorf("duplicate service endpoint name %q", endpoint.Name)
+		}
+		seen[endpoint.Name] = true
+
+		key, ok := endpointOverrideKeys[endpoint.Name]
+		if !ok {
+			klog.V(2).Infof("Service endpoint %q is not used by the cloud provider, skipping", endpoint.Name)
+			continue
+		}
+		section.Key(key).SetValue(endpoint.URL)
+	}
+	return nil
+}
+
+// sortSectionKeys rewrites keys of the section in alphabetical order, so the output does not depend
+// on the order of keys in the source config and on the order keys are set in.
+func sortSectionKeys(section *ini.Section) error {
+	keys := section.Keys()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].Name() < keys[j].Name()
+	})
+	for _, key := range keys {
+		section.DeleteKey(key.Name())
+	}
+	for _, key := range keys {
+		sortedKey, err := section.NewKey(key.Name(), key.Value())
+		if err != nil {
+			return err
+		}
+		sortedKey.Comment = key.Comment
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package powervs
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makeInfrastructureResource(platform configv1.PlatformType, endpoints []configv1.PowerVSServiceEndpoint) *configv1.Infrastructure {
+	infra := &configv1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Status: configv1.InfrastructureStatus{
+			PlatformStatus: &configv1.PlatformStatus{
+				Type: platform,
+			},
+		},
+	}
+	if platform == configv1.PowerVSPlatformType {
+		infra.Status.PlatformStatus.PowerVS = &configv1.PowerVSPlatformStatus{
+			Region:           "dal",
+			Zone:             "dal10",
+			ResourceGroup:    "ocp-rg",
+			ServiceEndpoints: endpoints,
+		}
+	}
+	return infra
+}
+
+func TestCloudConfigTransformer(t *testing.T) {
+	tc := []struct {
+		name     string
+		source   string
+		infra    *configv1.Infrastructure
+		expected string
+		errMsg   string
+	}{
+		{
+			name:   "Provider settings are set from the infrastructure",
+			source: "[global]\nversion = 1.1.0\n\n[provider]\ncluster-default-provider = g2\npowerVSCloudInstanceID = 0a1b2c3d\npowerVSRegion = wdc\n",
+			infra:  makeInfrastructureResource(configv1.PowerVSPlatformType, nil),
+			expected: `[global]
+version = 1.1.0
+
+[provider]
+cluster-default-provider = g2
+g2ResourceGroupName      = ocp-rg
+powerVSCloudInstanceID   = 0a1b2c3d
+powerVSRegion            = dal
+powerVSZone              = dal10
+`,
+		},
+		{
+			name:   "Provider section is created when missing",
+			source: "",
+			infra:  makeInfrastructureResource(configv1.PowerVSPlatformType, nil),
+			expected: `[provider]
+g2ResourceGroupName = ocp-rg
+powerVSRegion       = dal
+powerVSZone         = dal10
+`,
+		},
+		{
+			name:   "Service endpoint overrides are injected",
+			source: "[provider]\npowerVSCloudInstanceID = 0a1b2c3d\niamEndpointOverride = https://iam.example.com\n",
+			infra: makeInfrastructureResource(configv1.PowerVSPlatformType, []configv1.PowerVSServiceEndpoint{
+				{Name: "iam", URL: "https://private.iam.cloud.ibm.com"},
+				{Name: "power", URL: "https://private.dal.power-iaas.cloud.ibm.com"},
+				{Name: "resourcemanager", URL: "https://private.resource-controller.cloud.ibm.com"},
+				{Name: "vpc", URL: "https://us-south.private.iaas.cloud.ibm.com"},
+				{Name: "cos", URL: "https://s3.direct.us-south.cloud-object-storage.appdomain.cloud"},
+			}),
+			expected: `[provider]
+g2EndpointOverride      = https://us-south.private.iaas.cloud.ibm.com
+g2ResourceGroupName     = ocp-rg
+iamEndpointOverride     = https://private.iam.cloud.ibm.com
+powerVSCloudInstanceID  = 0a1b2c3d
+powerVSEndpointOverride = https://private.dal.power-iaas.cloud.ibm.com
+powerVSRegion           = dal
+powerVSZone             = dal10
+rmEndpointOverride      = https://private.resource-controller.cloud.ibm.com
+`,
+		},
+		{
+			name:   "Provider keys are written in canonical order regardless of the source order",
+			source: "[global]\nversion = 1.1.0\n\n[provider]\npowerVSZone = wdc06\ncluster-default-provider = g2\n; instance of the cluster\npowerVSCloudInstanceID = 0a1b2c3d\naccountID = 1e1f75646aef\n",
+			infra:  makeInfrastructureResource(configv1.PowerVSPlatformType, nil),
+			expected: `[global]
+version = 1.1.0
+
+[provider]
+accountID                = 1e1f75646aef
+cluster-default-provider = g2
+g2ResourceGroupName      = ocp-rg
+; instance of the cluster
+powerVSCloudInstanceID   = 0a1b2c3d
+powerVSRegion            = dal
+powerVSZone              = dal10
+`,
+		},
+		{
+			name:   "Duplicate service endpoints are rejected",
+			source: "[provider]\n",
+			infra: makeInfrastructureResource(configv1.PowerVSPlatformType, []configv1.PowerVSServiceEndpoint{
+				{Name: "iam", URL: "https://private.iam.cloud.ibm.com"},
+				{Name: "iam", URL: "https://iam.cloud.ibm.com"},
+			}),
+			errMsg: `duplicate service endpoint name "iam"`,
+		},
+		{
+			name:   "Non PowerVS platform returns an error",
+			source: "[provider]\n",
+			infra:  makeInfrastructureResource(configv1.IBMCloudPlatformType, nil),
+			errMsg: "invalid platform, expected to be PowerVS",
+		},
+		{
+			name:   "Missing PowerVS platform status returns an error",
+			source: "[provider]\n",
+			infra: &configv1.Infrastructure{
+				Status: configv1.InfrastructureStatus{
+					PlatformStatus: &configv1.PlatformStatus{Type: configv1.PowerVSPlatformType},
+				},
+			},
+			errMsg: "PowerVS platform status is not populated on infrastructure",
+		},
+		{
+			name:   "Malformed source returns an error",
+			source: "[provider\npowerVSZone = dal10\n",
+			infra:  makeInfrastructureResource(configv1.PowerVSPlatformType, nil),
+			errMsg: "failed to read the cloud.conf: unclosed section: [provider\n",
+		},
+	}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := CloudConfigTransformer(tc.source, tc.infra, nil)
+			if tc.errMsg != "" {
+				g.Expect(err).To(MatchError(tc.errMsg))
+				g.Expect(actual).To(Equal(""))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(actual).To(Equal(tc.expected))
+			}
+		})
+	}
+}
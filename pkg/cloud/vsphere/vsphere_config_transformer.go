@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	configv1 "github.com/openshift/api/config/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/utils/net"
 
 	ccmConfig "github.com/openshift/cluster-cloud-controller-manager-operator/pkg/cloud/vsphere/vsphere_cloud_config"
@@ -43,6 +44,10 @@ func CloudConfigTransformer(source string, infra *configv1.Infrastructure, netwo
 	// https://github.com/openshift/enhancements/blob/f6b33eb0cd4ba060af71fee6192297cf6bc31e5a/enhancements/installer/vsphere-ipi-zonal.md
 	// https://github.com/openshift/api/pull/1278
 	if infra.Spec.PlatformSpec.VSphere != nil {
+		if err := validateFailureDomains(infra.Spec.PlatformSpec.VSphere.FailureDomains); err != nil {
+			return "", fmt.Errorf("invalid failure domains: %w", err)
+		}
+
 		setIPFamilies(cpiCfg, infra.Status.PlatformStatus.VSphere, &infra.Spec.PlatformSpec.VSphere.NodeNetworking, network)
 		setExcludeNetworkSubnetCIDR(cpiCfg, infra.Status.PlatformStatus.VSphere, &infra.Spec.PlatformSpec.VSphere.NodeNetworking, network)
 		setNodes(cpiCfg, &infra.Spec.PlatformSpec.VSphere.NodeNetworking)
@@ -59,6 +64,40 @@ func CloudConfigTransformer(source string, infra *configv1.Infrastructure, netwo
 	return ccmConfig.MarshalConfig(cpiCfg)
 }
 
+// validateFailureDomains checks that failure domains set the topology fields vcenter sections are populated from,
+// so an incomplete failure domain is reported instead of producing an incomplete config.
+// The returned error lists missing fields per failure domain.
+func validateFailureDomains(failureDomains []configv1.VSpherePlatformFailureDomainSpec) error {
+	var errs []error
+	for i, fd := range failureDomains {
+		var missing []string
+		if fd.Server == "" {
+			missing = append(missing, "server")
+		}
+		if fd.Topology.Datacenter == "" {
+			missing = append(missing, "topology.datacenter")
+		}
+		if fd.Topology.ComputeCluster == "" {
+			missing = append(missing, "topology.computeCluster")
+		}
+		if fd.Topology.Datastore == "" {
+			missing = append(missing, "topology.datastore")
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		name := fd.Name
+		if name == "" {
+			name = fmt.Sprintf("at index %d", i)
+		} else {
+			name = fmt.Sprintf("%q", name)
+		}
+		errs = append(errs, fmt.Errorf("failure domain %s is missing %s", name, strings.Join(missing, ", ")))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
 // setNodes sets Nodes section in vsphere-cloud-provider config according passed VSpherePlatformNodeNetworking spec
 func setNodes(cfg *ccmConfig.CPIConfig, nodeNetworking *configv1.VSpherePlatformNodeNetworking) {
 	cfg.Nodes.ExternalVMNetworkName = nodeNetworking.External.Network
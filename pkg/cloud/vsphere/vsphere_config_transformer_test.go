@@ -142,6 +142,33 @@ func (b infraBuilder) withVSphereZones() infraBuilder {
 	return b
 }
 
+func (b infraBuilder) withIncompleteFailureDomains() infraBuilder {
+	failureDomainSpec := []configv1.VSpherePlatformFailureDomainSpec{
+		{
+			Name:   "east-1a",
+			Region: "east",
+			Zone:   "east-1a",
+			Server: "test-server",
+			Topology: configv1.VSpherePlatformTopology{
+				ComputeCluster: "C1",
+				Networks:       []string{"N1"},
+			},
+		}, {
+			Region: "east",
+			Zone:   "east-2a",
+			Topology: configv1.VSpherePlatformTopology{
+				Datacenter:     "DC2",
+				Datastore:      "DS2",
+				ComputeCluster: "C2",
+				Networks:       []string{"N2"},
+			},
+		},
+	}
+	vspereSpecRef := b.platformSpec.VSphere
+	vspereSpecRef.FailureDomains = append(vspereSpecRef.FailureDomains, failureDomainSpec...)
+	return b
+}
+
 func (b infraBuilder) withPrimaryIPv4VIP() infraBuilder {
 	b.platformStatus.VSphere.APIServerInternalIPs = []string{"192.168.96.3", "fd65:a1a8:60ad:271c::200"}
 	b.platformStatus.VSphere.IngressIPs = []string{"192.168.96.4", "fd65:a1a8:60ad:271c::201"}
@@ -490,6 +517,14 @@ func TestCloudConfigTransformer(t *testing.T) {
 			networkBuilder: makeDummyNetworkConfig(),
 			errMsg:         "invalid platform, expected to be VSphere",
 		},
+		{
+			name:           "incomplete failure domains",
+			infraBuilder:   newVsphereInfraBuilder().withIncompleteFailureDomains(),
+			networkBuilder: makeDummyNetworkConfig(),
+			inputConfig:    yamlConfig,
+			errMsg: "invalid failure domains: [failure domain \"east-1a\" is missing topology.datacenter, topology.datastore, " +
+				"failure domain at index 1 is missing server]",
+		},
 		{
 			name:           "invalid ini input",
 			infraBuilder:   newVsphereInfraBuilder(),
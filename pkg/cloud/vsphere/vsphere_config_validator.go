@@ -0,0 +1,16 @@
+package vsphere
+
+import (
+	"fmt"
+
+	ccm "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+)
+
+// CloudConfigValidator checks that the transformed cloud config can be read by the vSphere cloud provider,
+// using the same reader as the cloud-controller-manager.
+func CloudConfigValidator(config string) error {
+	if _, err := ccm.ReadCPIConfig([]byte(config)); err != nil {
+		return fmt.Errorf("cloud config can not be read by the vSphere cloud provider: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package vsphere
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudConfigValidator(t *testing.T) {
+	tc := []struct {
+		name      string
+		config    string
+		expectErr string
+	}{{
+		name:   "YAML config",
+		config: yamlConfig,
+	}, {
+		name:   "Legacy INI config",
+		config: iniConfigWithoutWorkspace,
+	}, {
+		name:      "Empty config",
+		config:    "",
+		expectErr: "cloud config can not be read by the vSphere cloud provider: no vSphere cloud provider config file given",
+	}, {
+		name:      "Malformed config",
+		config:    "vcenter: [",
+		expectErr: "cloud config can not be read by the vSphere cloud provider",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CloudConfigValidator(tc.config)
+			if tc.expectErr != "" {
+				assert.ErrorContains(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package vsphere
 
 import (
+	"strings"
 	"testing"
 
 	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
 
 	"github.com/stretchr/testify/assert"
 
@@ -59,3 +61,29 @@ func TestResourcesRenderingSmoke(t *testing.T) {
 		})
 	}
 }
+
+func TestFeatureGatesRendering(t *testing.T) {
+	operatorConfig := config.OperatorConfig{
+		ManagedNamespace: "my-cool-namespace",
+		ImagesReference: config.ImagesReference{
+			CloudControllerManagerVSphere: "CloudControllerManagerVsphere",
+		},
+		PlatformStatus:     &configv1.PlatformStatus{Type: configv1.VSpherePlatformType},
+		InfrastructureName: "infra",
+		FeatureGates:       "CloudDualStackNodeIPs=true",
+	}
+
+	assets, err := NewProviderAssets(operatorConfig)
+	assert.NoError(t, err)
+
+	var commands []string
+	for _, resource := range assets.GetRenderedResources() {
+		if deployment, ok := resource.(*appsv1.Deployment); ok {
+			for _, container := range deployment.Spec.Template.Spec.Containers {
+				commands = append(commands, container.Command...)
+			}
+		}
+	}
+	assert.NotEmpty(t, commands)
+	assert.Contains(t, strings.Join(commands, " "), "--feature-gates=CloudDualStackNodeIPs=true")
+}
@@ -1,14 +1,28 @@
 package config
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 
 	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
 
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/util"
@@ -29,6 +43,20 @@ type ImagesReference struct {
 	CloudControllerManagerVSphere   string `json:"cloudControllerManagerVSphere"`
 	CloudControllerManagerPowerVS   string `json:"cloudControllerManagerPowerVS"`
 	CloudControllerManagerNutanix   string `json:"cloudControllerManagerNutanix"`
+	// CredentialsInjector is an optional override for the image running credentials injection init containers,
+	// the operator image is used if not set.
+	CredentialsInjector string `json:"credentialsInjector,omitempty"`
+	// CloudProviderWebhook is an optional image of the admission webhook some providers ship besides their
+	// cloud controller manager. The webhook is only deployed on providers which support it when the image is set.
+	CloudProviderWebhook string `json:"cloudProviderWebhook,omitempty"`
+}
+
+// GetCredentialsInjector returns the image to use for credentials injection init containers
+func (images ImagesReference) GetCredentialsInjector() string {
+	if images.CredentialsInjector != "" {
+		return images.CredentialsInjector
+	}
+	return images.CloudControllerManagerOperator
 }
 
 // OperatorConfig contains configuration values for templating resources
@@ -40,6 +68,373 @@ type OperatorConfig struct {
 	PlatformStatus     *configv1.PlatformStatus
 	ClusterProxy       *configv1.Proxy
 	FeatureGates       string
+	// AllocateNodeCIDRs, if set, overrides whether cloud-controller-manager allocates node CIDRs,
+	// intended for providers which do the allocation themselves. The provider default is kept when not set.
+	AllocateNodeCIDRs *bool
+	// ConfigureCloudRoutes enables the cloud route controller of cloud-controller-manager, for platforms
+	// which set up pod networking with cloud routes instead of an overlay network. Disabled when not set.
+	ConfigureCloudRoutes *bool
+	// CCMVerbosity overrides log verbosity of the cloud-controller-manager container, if set.
+	CCMVerbosity *int
+	// ExtraHostPathMounts lists additional host paths to mount into operand containers,
+	// for providers which need something from the host besides /etc/kubernetes. Names and mount paths
+	// must not collide with operand volumes and mounts.
+	ExtraHostPathMounts []HostPathMount
+	// ExtraVolumes are added to every operand pod template, for environments which need something
+	// not covered by host path mounts, e.g. a custom CA directory. Names must not collide with operand volumes.
+	ExtraVolumes []corev1.Volume
+	// ExtraVolumeMounts mount ExtraVolumes into every operand container. Mount paths must not collide with
+	// operand mounts, such as the trusted CA bundle.
+	ExtraVolumeMounts []corev1.VolumeMount
+	// ExtraNodeManagerArgs are appended to the cloud-node-manager container command, on platforms
+	// running a node manager. Flags already set by the operator can not be overridden.
+	ExtraNodeManagerArgs []string
+	// ServiceAccountAnnotations are set on service accounts used by operands, e.g. to bind them to a cloud
+	// identity with AWS IRSA or GCP Workload Identity. Annotations set on service accounts by other parties are kept.
+	ServiceAccountAnnotations map[string]string
+	// NodeManagerPriorityClassName overrides the priority class of node-manager pods,
+	// system-node-critical is used when not set.
+	NodeManagerPriorityClassName string
+	// NodeManagerMinReadySeconds, if set, is how long new node-manager pods have to be ready before they are
+	// considered available, so node-manager rollouts do not proceed before nodes are initialized by new pods.
+	NodeManagerMinReadySeconds *int32
+	// InitContainerResources overrides resource requests and limits of operand init containers,
+	// such as the Azure credentials injector. Defaults from the provider manifests are used when not set.
+	InitContainerResources *corev1.ResourceRequirements
+	// CloudAPIReadinessProbe adds a readiness probe to the cloud-controller-manager container which checks
+	// that the cloud metadata endpoint is reachable, on platforms which have one. This keeps operands
+	// from being reported as ready during cloud outages.
+	CloudAPIReadinessProbe bool
+	// LoggingFormat sets the log format of the cloud-controller-manager container, on platforms
+	// which support it. Format defaults of the provider are used when not set.
+	LoggingFormat LoggingFormat
+	// ResourceAutosizing, if set, scales resource requests of the cloud-controller-manager container
+	// with the number of cluster nodes, replacing requests from the provider manifests.
+	ResourceAutosizing *ResourceAutosizing
+	// NodeCount is the number of nodes in the cluster, used for resource autosizing.
+	NodeCount int
+	// PDBPolicy overrides the disruption policy of operand PodDisruptionBudgets.
+	// When not set, PodDisruptionBudgets require at least one pod to be available.
+	PDBPolicy *PDBPolicy
+	// DisablePodDisruptionBudget omits operand PodDisruptionBudgets on all platforms, for environments
+	// which manage PodDisruptionBudgets centrally. PodDisruptionBudgets created before are pruned.
+	DisablePodDisruptionBudget bool
+	// CloudEndpointOverrides are injected as environment variables into operand containers,
+	// for providers which resolve their cloud API endpoints by DNS and read overrides from the environment,
+	// e.g. AWS_ENDPOINT_URL_EC2.
+	CloudEndpointOverrides []CloudEndpointOverride
+	// StartupTimeouts limit, per platform, how long operand container scripts may spend in startup steps,
+	// such as sourcing the API server URL env file, before the operand binary is executed. A container
+	// hanging in startup is terminated and restarted. No limit is enforced for platforms which are not listed.
+	StartupTimeouts map[configv1.PlatformType]time.Duration
+	// NodeManagerConfigHashSources are names of config maps in the managed namespace whose content is
+	// included in the config hash of node-manager pod templates, so node-managers are restarted when
+	// they change, even if pods do not reference them, e.g. node config rendered on kernel or OS updates.
+	// Config maps referenced by pod templates, such as the cloud-config, are always included.
+	NodeManagerConfigHashSources []string
+	// CredentialsMode is the mode the cloud-credential-operator runs in, which determines how operand
+	// credentials secrets are provisioned. Operands are rendered as the provider manifests define them
+	// when the mode is not known.
+	CredentialsMode operatorv1.CloudCredentialsMode
+	// StartupScriptsFromConfigMap moves command scripts of operand containers into a config map per workload,
+	// which containers run the scripts from, instead of inlining them into container commands.
+	StartupScriptsFromConfigMap bool
+	// LogEffectiveFlags makes the cloud-controller-manager container echo its final command, with all
+	// flags the operator assembled, to stdout right before the binary is executed, to aid debugging.
+	LogEffectiveFlags bool
+	// DropPrivilegesToUser is the ID of the non-root user operand binaries are executed as once their startup
+	// scripts, which may need root to read host files, are done. Operands run as root when it is zero.
+	// Files of extra host path mounts the binaries read have to be readable by the user.
+	DropPrivilegesToUser int64
+	// AzureCredentials configures where Azure operands read cloud credentials from,
+	// the azure-cloud-credentials secret is passed with env variables when not set.
+	AzureCredentials *AzureCredentials
+	// PodSysctls are set, per platform, on operand pods, for cloud SDKs which need kernel limits above
+	// the defaults. Kubernetes does not expose ulimits, and only sysctls the kubelet allows by default
+	// which do not change host network settings can be tuned.
+	PodSysctls map[configv1.PlatformType]Sysctls
+	// HostAliases are added to /etc/hosts of operand pods, e.g. to resolve cloud API endpoints
+	// in disconnected environments without a DNS record for them.
+	HostAliases []corev1.HostAlias
+	// Affinity is merged into the affinity of controller pods. Node affinity is added to the node affinity
+	// of the provider manifests, pod affinity and pod anti-affinity replace the manifest ones if set, so the
+	// anti-affinity spreading controller replicas across nodes is kept unless explicitly replaced.
+	Affinity *corev1.Affinity
+	// DNSConfigHash is a hash of the cluster DNS config. When set, it is added to operand pod templates,
+	// so host network operands, which resolve names with the node resolver, are restarted once it changes.
+	DNSConfigHash string
+	// DefaultNodeSelector is the node selector the cluster adds to operand pods on admission. Operands are
+	// only rendered if it does not change the nodes their own node selectors select.
+	DefaultNodeSelector map[string]string
+}
+
+// NamespaceNodeSelectorAnnotation set on a namespace replaces the cluster default node selector for pods
+// of the namespace, an empty value keeps pods from getting any default node selector.
+const NamespaceNodeSelectorAnnotation = "openshift.io/node-selector"
+
+// ConfigHashSourcesAnnotation lists, comma separated, names of config maps whose content is included
+// in the config hash of a workload pod template, in addition to config maps the pod template references.
+const ConfigHashSourcesAnnotation = "operator.openshift.io/config-hash-sources"
+
+// PDBPolicyType is a field of PodDisruptionBudget spec which PDBPolicy sets.
+type PDBPolicyType string
+
+const (
+	PDBPolicyMinAvailable   PDBPolicyType = "MinAvailable"
+	PDBPolicyMaxUnavailable PDBPolicyType = "MaxUnavailable"
+)
+
+// PDBPolicy describes the disruption policy of operand PodDisruptionBudgets,
+// either as a minimum number of available pods or a maximum number of unavailable ones.
+type PDBPolicy struct {
+	Type  PDBPolicyType      `json:"type"`
+	Value intstr.IntOrString `json:"value"`
+}
+
+// LoggingFormat is a log format supported by cloud-controller-manager.
+type LoggingFormat string
+
+const (
+	LoggingFormatText LoggingFormat = "text"
+	LoggingFormatJSON LoggingFormat = "json"
+)
+
+// Validate checks that the logging format is either unset or known.
+func (f LoggingFormat) Validate() error {
+	switch f {
+	case "", LoggingFormatText, LoggingFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("unsupported logging format %q, expected one of %q, %q", f, LoggingFormatText, LoggingFormatJSON)
+	}
+}
+
+// defaultAutosizingNodeBucketSize is the number of nodes requests are quantized by, when not set.
+const defaultAutosizingNodeBucketSize = 10
+
+// ResourceAutosizing describes how resource requests grow with the cluster size.
+// Requests are Min plus PerNode for every cluster node, capped at Max. The node count is rounded up to
+// a multiple of NodeBucketSize, so requests, and thus operand pods, do not change whenever a node is added
+// or removed, e.g. during autoscaling.
+type ResourceAutosizing struct {
+	Min     corev1.ResourceList `json:"min,omitempty"`
+	PerNode corev1.ResourceList `json:"perNode,omitempty"`
+	Max     corev1.ResourceList `json:"max,omitempty"`
+	// NodeBucketSize is the number of nodes requests are quantized by, 10 when not set.
+	NodeBucketSize int `json:"nodeBucketSize,omitempty"`
+}
+
+// Validate checks that quantities are not negative and bounds are consistent.
+func (a ResourceAutosizing) Validate() error {
+	if a.NodeBucketSize < 0 {
+		return fmt.Errorf("resource autosizing: node bucket size %d should not be negative", a.NodeBucketSize)
+	}
+	for _, list := range []corev1.ResourceList{a.Min, a.PerNode, a.Max} {
+		for name, quantity := range list {
+			if quantity.Sign() < 0 {
+				return fmt.Errorf("resource autosizing: %s quantity %s should not be negative", name, quantity.String())
+			}
+		}
+	}
+	for name, maxQuantity := range a.Max {
+		if minQuantity, ok := a.Min[name]; ok && minQuantity.Cmp(maxQuantity) > 0 {
+			return fmt.Errorf("resource autosizing: %s minimum %s is greater than maximum %s", name, minQuantity.String(), maxQuantity.String())
+		}
+	}
+	return nil
+}
+
+// Requests returns resource requests for a cluster with the given number of nodes, rounded up to the node bucket.
+func (a ResourceAutosizing) Requests(nodeCount int) corev1.ResourceList {
+	bucketSize := a.NodeBucketSize
+	if bucketSize <= 0 {
+		bucketSize = defaultAutosizingNodeBucketSize
+	}
+	nodeCount = (nodeCount + bucketSize - 1) / bucketSize * bucketSize
+
+	requests := corev1.ResourceList{}
+	for _, list := range []corev1.ResourceList{a.Min, a.PerNode} {
+		for name, quantity := range list {
+			if _, ok := requests[name]; ok {
+				continue
+			}
+			minQuantity, perNode := a.Min[name], a.PerNode[name]
+			value := minQuantity.MilliValue() + perNode.MilliValue()*int64(nodeCount)
+			if maxQuantity, ok := a.Max[name]; ok && value > maxQuantity.MilliValue() {
+				value = maxQuantity.MilliValue()
+			}
+			if value%1000 == 0 {
+				requests[name] = *resource.NewQuantity(value/1000, quantity.Format)
+			} else {
+				requests[name] = *resource.NewMilliQuantity(value, quantity.Format)
+			}
+		}
+	}
+	return requests
+}
+
+// defaultStartupTimeout is long enough for startup steps of operand scripts to complete on slow or busy
+// control plane nodes, so only scripts which hang are restarted.
+const defaultStartupTimeout = 5 * time.Minute
+
+// DefaultStartupTimeouts returns startup timeouts of operand container scripts for platforms
+// which run cloud-controller-manager operands.
+func DefaultStartupTimeouts() map[configv1.PlatformType]time.Duration {
+	return map[configv1.PlatformType]time.Duration{
+		configv1.AWSPlatformType:       defaultStartupTimeout,
+		configv1.AzurePlatformType:     defaultStartupTimeout,
+		configv1.GCPPlatformType:       defaultStartupTimeout,
+		configv1.IBMCloudPlatformType:  defaultStartupTimeout,
+		configv1.NutanixPlatformType:   defaultStartupTimeout,
+		configv1.OpenStackPlatformType: defaultStartupTimeout,
+		configv1.PowerVSPlatformType:   defaultStartupTimeout,
+		configv1.VSpherePlatformType:   defaultStartupTimeout,
+	}
+}
+
+// CloudEndpointOverride describes a cloud API endpoint override passed to operands with an environment variable.
+type CloudEndpointOverride struct {
+	EnvName string
+	URL     string
+}
+
+// Validate checks that the environment variable name is valid and the endpoint is an http(s) URL
+// with a DNS host name, since overrides are meant for endpoints resolved by DNS.
+func (o CloudEndpointOverride) Validate() error {
+	if errs := validation.IsCIdentifier(o.EnvName); len(errs) > 0 {
+		return fmt.Errorf("cloud endpoint override: invalid environment variable name %q: %s", o.EnvName, strings.Join(errs, ", "))
+	}
+	endpoint, err := url.Parse(o.URL)
+	if err != nil {
+		return fmt.Errorf("cloud endpoint override %s: invalid URL %q: %w", o.EnvName, o.URL, err)
+	}
+	if endpoint.Scheme != "https" && endpoint.Scheme != "http" {
+		return fmt.Errorf("cloud endpoint override %s: URL %q should use http or https scheme", o.EnvName, o.URL)
+	}
+	hostname := endpoint.Hostname()
+	if net.ParseIP(hostname) != nil {
+		return fmt.Errorf("cloud endpoint override %s: URL %q should use a DNS name instead of an IP address", o.EnvName, o.URL)
+	}
+	if errs := validation.IsDNS1123Subdomain(hostname); len(errs) > 0 {
+		return fmt.Errorf("cloud endpoint override %s: invalid host name %q in URL: %s", o.EnvName, hostname, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// AzureCredentials describes the secret Azure operands read cloud credentials from, for credential operators
+// which do not provision the default azure-cloud-credentials secret.
+type AzureCredentials struct {
+	// SecretName is the name of the secret in the managed namespace, azure-cloud-credentials if not set.
+	SecretName string
+	// FilePath, if set, makes the credentials injector read credentials from a single JSON or INI file at the path,
+	// instead of env variables. The file is the secret key named as the base name of the path.
+	FilePath string
+}
+
+// Validate checks that the secret name is a valid object name and the file path is absolute
+// and names a valid secret key.
+func (c AzureCredentials) Validate() error {
+	if c.SecretName != "" {
+		if errs := validation.IsDNS1123Subdomain(c.SecretName); len(errs) > 0 {
+			return fmt.Errorf("azure credentials: invalid secret name %q: %s", c.SecretName, strings.Join(errs, ", "))
+		}
+	}
+	if c.FilePath == "" {
+		return nil
+	}
+	if !filepath.IsAbs(c.FilePath) || filepath.Clean(c.FilePath) != c.FilePath {
+		return fmt.Errorf("azure credentials: file path %q should be absolute and clean", c.FilePath)
+	}
+	if errs := validation.IsConfigMapKey(filepath.Base(c.FilePath)); len(errs) > 0 {
+		return fmt.Errorf("azure credentials: file name of %q is not a valid secret key: %s", c.FilePath, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Sysctls are kernel parameters set on operand pods.
+type Sysctls []corev1.Sysctl
+
+// hostNetworkSafeSysctls are sysctls of the kubelet safe set which can be set on host network pods. Other sysctls
+// are rejected by the kubelet unless every node allows them, and the remaining safe ones are all net.* sysctls,
+// which would change the node settings, as operand pods share the network namespace of the host.
+var hostNetworkSafeSysctls = []string{"kernel.shm_rmid_forced"}
+
+// Validate checks that every sysctl is set once, has a value, and is safe to set on host network pods.
+func (s Sysctls) Validate() error {
+	seen := map[string]bool{}
+	for _, sysctl := range s {
+		if seen[sysctl.Name] {
+			return fmt.Errorf("sysctl %q is set more than once", sysctl.Name)
+		}
+		seen[sysctl.Name] = true
+		if sysctl.Value == "" {
+			return fmt.Errorf("sysctl %q should have a value", sysctl.Name)
+		}
+		if strings.HasPrefix(sysctl.Name, "net.") {
+			return fmt.Errorf("sysctl %q can not be set on host network pods, since it would change the node network settings", sysctl.Name)
+		}
+		if !slices.Contains(hostNetworkSafeSysctls, sysctl.Name) {
+			return fmt.Errorf("sysctl %q is not allowed by the kubelet by default, expected one of %s",
+				sysctl.Name, strings.Join(hostNetworkSafeSysctls, ", "))
+		}
+	}
+	return nil
+}
+
+// ValidateHostAliases checks that host aliases map valid IP addresses to valid DNS names.
+func ValidateHostAliases(aliases []corev1.HostAlias) error {
+	for _, alias := range aliases {
+		if net.ParseIP(alias.IP) == nil {
+			return fmt.Errorf("host alias: invalid IP address %q", alias.IP)
+		}
+		if len(alias.Hostnames) == 0 {
+			return fmt.Errorf("host alias %s: at least one hostname should be set", alias.IP)
+		}
+		for _, hostname := range alias.Hostnames {
+			if errs := validation.IsDNS1123Subdomain(hostname); len(errs) > 0 {
+				return fmt.Errorf("host alias %s: invalid hostname %q: %s", alias.IP, hostname, strings.Join(errs, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// HostPathMount describes a host path volume and where it is mounted within operand containers.
+type HostPathMount struct {
+	Name      string              `json:"name"`
+	HostPath  string              `json:"hostPath"`
+	MountPath string              `json:"mountPath"`
+	Type      corev1.HostPathType `json:"type,omitempty"`
+	ReadOnly  bool                `json:"readOnly,omitempty"`
+}
+
+// allowedHostPathTypes is a set of host path types which could be used for extra host path mounts.
+var allowedHostPathTypes = map[corev1.HostPathType]bool{
+	corev1.HostPathUnset:             true,
+	corev1.HostPathDirectoryOrCreate: true,
+	corev1.HostPathDirectory:         true,
+	corev1.HostPathFileOrCreate:      true,
+	corev1.HostPathFile:              true,
+	corev1.HostPathSocket:            true,
+	corev1.HostPathCharDev:           true,
+	corev1.HostPathBlockDev:          true,
+}
+
+// Validate checks that the host path mount is complete, paths are absolute and the host path type is known.
+func (m HostPathMount) Validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("host path mount name is required")
+	}
+	if !filepath.IsAbs(m.HostPath) {
+		return fmt.Errorf("host path mount %s: host path %q should be absolute", m.Name, m.HostPath)
+	}
+	if !filepath.IsAbs(m.MountPath) {
+		return fmt.Errorf("host path mount %s: mount path %q should be absolute", m.Name, m.MountPath)
+	}
+	if !allowedHostPathTypes[m.Type] {
+		return fmt.Errorf("host path mount %s: unsupported host path type %q", m.Name, m.Type)
+	}
+	return nil
 }
 
 func (cfg *OperatorConfig) GetPlatformNameString() string {
@@ -50,6 +445,64 @@ func (cfg *OperatorConfig) GetPlatformNameString() string {
 	return platformName
 }
 
+// GetIPFamilies returns IP families of the cluster network, primary family first.
+// Service networks determine the families, their order the primary one, as for a dual-stack
+// cluster the installer validates exactly two service networks, one of each family.
+func GetIPFamilies(network *configv1.Network) []string {
+	if network == nil {
+		return nil
+	}
+	var families []string
+	for _, cidr := range network.Spec.ServiceNetwork {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			klog.Warningf("Ignoring invalid service network %q: %v", cidr, err)
+			continue
+		}
+		family := "ipv4"
+		if ip.To4() == nil {
+			family = "ipv6"
+		}
+		if !slices.Contains(families, family) {
+			families = append(families, family)
+		}
+	}
+	return families
+}
+
+// GetDNSConfigHash returns a hash of the cluster DNS config spec.
+func GetDNSConfigHash(dns *configv1.DNS) (string, error) {
+	spec, err := json.Marshal(dns.Spec)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal DNS config spec: %w", err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(spec)), nil
+}
+
+// GetDefaultNodeSelector returns the node selector the project node selector admission adds to pods of the given
+// namespace: the node selector annotation of the namespace if it is set, even if empty, the default node selector
+// of the cluster scheduler config otherwise. Either object may be nil if it does not exist.
+func GetDefaultNodeSelector(scheduler *configv1.Scheduler, namespace *corev1.Namespace) (map[string]string, error) {
+	selector := ""
+	if scheduler != nil {
+		selector = scheduler.Spec.DefaultNodeSelector
+	}
+	if namespace != nil {
+		if value, ok := namespace.Annotations[NamespaceNodeSelectorAnnotation]; ok {
+			selector = value
+		}
+	}
+
+	nodeSelector, err := labels.ConvertSelectorToLabelsMap(selector)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse default node selector %q: %w", selector, err)
+	}
+	if len(nodeSelector) == 0 {
+		return nil, nil
+	}
+	return nodeSelector, nil
+}
+
 // checkInfrastructureResource checks Infrastructure resource for platform status presence
 func checkInfrastructureResource(infra *configv1.Infrastructure) error {
 	if infra == nil || infra.Status.PlatformStatus == nil {
@@ -63,18 +516,165 @@ func checkInfrastructureResource(infra *configv1.Infrastructure) error {
 }
 
 // getImagesFromJSONFile is used in operator to read the content of mounted ConfigMap
-// containing images for substitution in templates
+// containing images for substitution in templates.
+// A comma-separated list of files may be passed to overlay a base images file with overrides,
+// images set in later files take precedence over the ones set in earlier files.
 func getImagesFromJSONFile(filePath string) (ImagesReference, error) {
-	data, err := os.ReadFile(filepath.Clean(filePath))
+	i := ImagesReference{}
+	for _, path := range strings.Split(filePath, ",") {
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return ImagesReference{}, err
+		}
+
+		// Unmarshalling into the same structure only overrides images present in the file
+		if err := json.Unmarshal(data, &i); err != nil {
+			return ImagesReference{}, err
+		}
+	}
+	return i, nil
+}
+
+// CheckImagesFile verifies that the images file at the given location can be read and decoded
+func CheckImagesFile(filePath string) error {
+	_, err := getImagesFromJSONFile(filePath)
+	return err
+}
+
+// requiredPlatformImages lists keys of the images file which must be set to provision cloud controller managers
+// on each platform. The operator image is required on every platform.
+var requiredPlatformImages = map[configv1.PlatformType][]string{
+	configv1.AWSPlatformType:       {"cloudControllerManagerAWS"},
+	configv1.AzurePlatformType:     {"cloudControllerManagerAzure", "cloudNodeManagerAzure"},
+	configv1.GCPPlatformType:       {"cloudControllerManagerGCP"},
+	configv1.IBMCloudPlatformType:  {"cloudControllerManagerIBM"},
+	configv1.NutanixPlatformType:   {"cloudControllerManagerNutanix"},
+	configv1.OpenStackPlatformType: {"cloudControllerManagerOpenStack"},
+	configv1.PowerVSPlatformType:   {"cloudControllerManagerPowerVS"},
+	configv1.VSpherePlatformType:   {"cloudControllerManagerVSphere"},
+}
+
+// imagesMap returns images of the reference keyed by their images file keys.
+func (images ImagesReference) imagesMap() (map[string]string, error) {
+	var imagesMap map[string]string
+	data, err := json.Marshal(images)
 	if err != nil {
-		return ImagesReference{}, err
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &imagesMap); err != nil {
+		return nil, err
 	}
+	return imagesMap, nil
+}
 
-	i := ImagesReference{}
-	if err := json.Unmarshal(data, &i); err != nil {
-		return ImagesReference{}, err
+// ValidatePlatformImage verifies that the operand image is one of the images the given platform requires,
+// catching operands rendered with an image of another provider. The returned error names the images file
+// keys holding the image, if any, and the keys the platform expects.
+func (images ImagesReference) ValidatePlatformImage(platform configv1.PlatformType, image string) error {
+	expectedKeys, ok := requiredPlatformImages[platform]
+	if !ok {
+		return nil
 	}
-	return i, nil
+	imagesMap, err := images.imagesMap()
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	for key, value := range imagesMap {
+		if value != image {
+			continue
+		}
+		if slices.Contains(expectedKeys, key) {
+			return nil
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("image %q is not set in the images file, expected one of %s for platform %s",
+			image, strings.Join(expectedKeys, ", "), platform)
+	}
+	sort.Strings(keys)
+	return fmt.Errorf("image %q is set as %s in the images file, expected one of %s for platform %s",
+		image, strings.Join(keys, ", "), strings.Join(expectedKeys, ", "), platform)
+}
+
+// ValidateImagesFile verifies that the images file at the given location sets every image required by the operator.
+// The returned error lists missing images per platform.
+func ValidateImagesFile(filePath string) error {
+	images, err := getImagesFromJSONFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	imagesMap, err := images.imagesMap()
+	if err != nil {
+		return err
+	}
+
+	platforms := make([]string, 0, len(requiredPlatformImages))
+	for platform := range requiredPlatformImages {
+		platforms = append(platforms, string(platform))
+	}
+	sort.Strings(platforms)
+
+	var errs []error
+	if imagesMap["cloudControllerManagerOperator"] == "" {
+		errs = append(errs, fmt.Errorf("all platforms: missing images: cloudControllerManagerOperator"))
+	}
+	for _, platform := range platforms {
+		var missing []string
+		for _, key := range requiredPlatformImages[configv1.PlatformType(platform)] {
+			if imagesMap[key] == "" {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			errs = append(errs, fmt.Errorf("%s: missing images: %s", platform, strings.Join(missing, ", ")))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// platformFeatureGates lists feature gates which only take effect on the given platforms. Enabling one of them
+// on any other platform is not supported.
+var platformFeatureGates = map[configv1.FeatureGateName][]configv1.PlatformType{
+	"AzureWorkloadIdentity":         {configv1.AzurePlatformType},
+	"GCPClusterHostedDNS":           {configv1.GCPPlatformType},
+	"GCPLabelsTags":                 {configv1.GCPPlatformType},
+	"VSphereControlPlaneMachineSet": {configv1.VSpherePlatformType},
+	"VSphereMultiNetworks":          {configv1.VSpherePlatformType},
+	"VSphereMultiVCenters":          {configv1.VSpherePlatformType},
+	"VSphereStaticIPs":              {configv1.VSpherePlatformType},
+}
+
+// UnsupportedFeatureGatesError is returned when enabled feature gates are not supported on the cluster platform.
+type UnsupportedFeatureGatesError struct {
+	Platform     configv1.PlatformType
+	FeatureGates []string
+}
+
+func (e *UnsupportedFeatureGatesError) Error() string {
+	return fmt.Sprintf("feature gates %s are enabled but not supported on platform %s", strings.Join(e.FeatureGates, ", "), e.Platform)
+}
+
+// ValidateFeatureGates checks that no feature gate specific to other platforms is enabled on the given platform.
+// An *UnsupportedFeatureGatesError listing the offending feature gates is returned otherwise.
+func ValidateFeatureGates(platform configv1.PlatformType, features featuregates.FeatureGate) error {
+	var unsupported []string
+	for _, feature := range features.KnownFeatures() {
+		platforms, ok := platformFeatureGates[feature]
+		if !ok || slices.Contains(platforms, platform) || !features.Enabled(feature) {
+			continue
+		}
+		unsupported = append(unsupported, string(feature))
+	}
+	if len(unsupported) == 0 {
+		return nil
+	}
+
+	sort.Strings(unsupported)
+	return &UnsupportedFeatureGatesError{Platform: platform, FeatureGates: unsupported}
 }
 
 // ComposeConfig creates a Config for operator
@@ -98,9 +698,15 @@ func ComposeConfig(infrastructure *configv1.Infrastructure, clusterProxy *config
 		return OperatorConfig{}, fmt.Errorf("unable to get upstream feature gates: %w", err)
 	}
 	if featureGateAccessor != nil {
-		features, _ := featureGateAccessor.CurrentFeatureGates()
-		enabled, _ := util.GetEnabledDisabledFeatures(features, upstreamGates)
-		featureGatesString = util.BuildFeatureGateString(enabled, nil)
+		features, err := featureGateAccessor.CurrentFeatureGates()
+		if err != nil {
+			// The FeatureGate object might be briefly absent, e.g. during upgrades. Operands are rendered
+			// with provider defaults instead of blocking, they are updated once feature gates are observed.
+			klog.Warningf("Unable to get current feature gates, using defaults: %v", err)
+		} else {
+			enabled, _ := util.GetEnabledDisabledFeatures(features, upstreamGates)
+			featureGatesString = util.BuildFeatureGateString(enabled, nil)
+		}
 	}
 
 	config := OperatorConfig{
@@ -1,12 +1,19 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"testing"
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/util"
 )
 
 func TestGetImagesFromJSONFile(t *testing.T) {
@@ -29,6 +36,28 @@ func TestGetImagesFromJSONFile(t *testing.T) {
 			CloudControllerManagerAWS:       "registry.ci.openshift.org/openshift:aws-cloud-controller-manager",
 			CloudControllerManagerOpenStack: "registry.ci.openshift.org/openshift:openstack-cloud-controller-manager",
 		},
+	}, {
+		name: "Unmarshal credentials injector override",
+		path: "images_file",
+		imagesContent: `{
+			"cloudControllerManagerOperator": "registry.ci.openshift.org/openshift:cluster-cloud-controller-manager-operator",
+			"credentialsInjector": "registry.ci.openshift.org/openshift:patched-injector"
+		}`,
+		expectedImages: ImagesReference{
+			CloudControllerManagerOperator: "registry.ci.openshift.org/openshift:cluster-cloud-controller-manager-operator",
+			CredentialsInjector:            "registry.ci.openshift.org/openshift:patched-injector",
+		},
+	}, {
+		name: "Unmarshal optional webhook image",
+		path: "images_file",
+		imagesContent: `{
+			"cloudControllerManagerOperator": "registry.ci.openshift.org/openshift:cluster-cloud-controller-manager-operator",
+			"cloudProviderWebhook": "registry.ci.openshift.org/openshift:cloud-provider-webhook"
+		}`,
+		expectedImages: ImagesReference{
+			CloudControllerManagerOperator: "registry.ci.openshift.org/openshift:cluster-cloud-controller-manager-operator",
+			CloudProviderWebhook:           "registry.ci.openshift.org/openshift:cloud-provider-webhook",
+		},
 	}, {
 		name:        "Error on non present file",
 		expectError: "open not_found: no such file or directory",
@@ -105,6 +134,137 @@ func TestGetImagesFromJSONFile(t *testing.T) {
 	}
 }
 
+func TestGetImagesFromJSONFileOverlay(t *testing.T) {
+	tc := []struct {
+		name           string
+		path           string
+		expectedImages ImagesReference
+		expectError    string
+	}{{
+		name: "Overlay overrides only images it sets",
+		path: "_testdata/images-complete.json,_testdata/images-overlay-azure.json",
+		expectedImages: ImagesReference{
+			CloudControllerManagerOperator:  "quay.io/openshift/origin-cluster-cloud-controller-manager-operator",
+			CloudControllerManagerAWS:       "quay.io/openshift/origin-aws-cloud-controller-manager",
+			CloudControllerManagerAzure:     "registry.example.com/openshift/patched-azure-cloud-controller-manager",
+			CloudNodeManagerAzure:           "quay.io/openshift/origin-azure-cloud-node-manager",
+			CloudControllerManagerGCP:       "quay.io/openshift/origin-gcp-cloud-controller-manager",
+			CloudControllerManagerIBM:       "quay.io/openshift/origin-ibm-cloud-controller-manager",
+			CloudControllerManagerOpenStack: "quay.io/openshift/origin-openstack-cloud-controller-manager",
+			CloudControllerManagerPowerVS:   "quay.io/openshift/origin-powervs-cloud-controller-manager",
+			CloudControllerManagerVSphere:   "quay.io/openshift/origin-vsphere-cloud-controller-manager",
+			CloudControllerManagerNutanix:   "quay.io/openshift/origin-nutanix-cloud-controller-manager",
+		},
+	}, {
+		name: "Base file overrides earlier overlay",
+		path: "_testdata/images-overlay-azure.json,_testdata/images-complete.json",
+		expectedImages: ImagesReference{
+			CloudControllerManagerOperator:  "quay.io/openshift/origin-cluster-cloud-controller-manager-operator",
+			CloudControllerManagerAWS:       "quay.io/openshift/origin-aws-cloud-controller-manager",
+			CloudControllerManagerAzure:     "quay.io/openshift/origin-azure-cloud-controller-manager",
+			CloudNodeManagerAzure:           "quay.io/openshift/origin-azure-cloud-node-manager",
+			CloudControllerManagerGCP:       "quay.io/openshift/origin-gcp-cloud-controller-manager",
+			CloudControllerManagerIBM:       "quay.io/openshift/origin-ibm-cloud-controller-manager",
+			CloudControllerManagerOpenStack: "quay.io/openshift/origin-openstack-cloud-controller-manager",
+			CloudControllerManagerPowerVS:   "quay.io/openshift/origin-powervs-cloud-controller-manager",
+			CloudControllerManagerVSphere:   "quay.io/openshift/origin-vsphere-cloud-controller-manager",
+			CloudControllerManagerNutanix:   "quay.io/openshift/origin-nutanix-cloud-controller-manager",
+		},
+	}, {
+		name:        "Missing overlay file",
+		path:        "_testdata/images-complete.json,_testdata/non-existent.json",
+		expectError: "open _testdata/non-existent.json: no such file or directory",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			images, err := getImagesFromJSONFile(tc.path)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.EqualValues(t, tc.expectedImages, images)
+		})
+	}
+}
+
+func TestValidateImagesFile(t *testing.T) {
+	tc := []struct {
+		name        string
+		path        string
+		expectedErr string
+	}{{
+		name: "Complete images file",
+		path: "_testdata/images-complete.json",
+	}, {
+		name:        "Incomplete images file",
+		path:        "_testdata/images-incomplete.json",
+		expectedErr: "[Azure: missing images: cloudNodeManagerAzure, VSphere: missing images: cloudControllerManagerVSphere]",
+	}, {
+		name:        "Missing images file",
+		path:        "_testdata/non-existent.json",
+		expectedErr: "open _testdata/non-existent.json: no such file or directory",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateImagesFile(tc.path)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatePlatformImage(t *testing.T) {
+	images := ImagesReference{
+		CloudControllerManagerOperator: "operator",
+		CloudControllerManagerAWS:      "shared",
+		CloudControllerManagerGCP:      "shared",
+		CloudControllerManagerAzure:    "azure-ccm",
+	}
+
+	tc := []struct {
+		name          string
+		platform      configv1.PlatformType
+		image         string
+		expectedError string
+	}{{
+		name:     "Image shared by providers",
+		platform: configv1.GCPPlatformType,
+		image:    "shared",
+	}, {
+		name:     "Platform without operand images",
+		platform: configv1.BareMetalPlatformType,
+		image:    "azure-ccm",
+	}, {
+		name:          "Operator image",
+		platform:      configv1.AWSPlatformType,
+		image:         "operator",
+		expectedError: `image "operator" is set as cloudControllerManagerOperator in the images file, expected one of cloudControllerManagerAWS for platform AWS`,
+	}, {
+		name:          "Image of another provider",
+		platform:      configv1.AWSPlatformType,
+		image:         "azure-ccm",
+		expectedError: `image "azure-ccm" is set as cloudControllerManagerAzure in the images file, expected one of cloudControllerManagerAWS for platform AWS`,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := images.ValidatePlatformImage(tc.platform, tc.image)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestCheckInfrastructure(t *testing.T) {
 	tc := []struct {
 		name      string
@@ -145,6 +305,402 @@ func TestCheckInfrastructure(t *testing.T) {
 	}
 }
 
+func TestHostPathMountValidate(t *testing.T) {
+	tc := []struct {
+		name      string
+		mount     HostPathMount
+		expectErr string
+	}{{
+		name:  "Valid mount",
+		mount: HostPathMount{Name: "creds", HostPath: "/var/lib/creds", MountPath: "/etc/creds", Type: corev1.HostPathDirectory},
+	}, {
+		name:  "Unset type is valid",
+		mount: HostPathMount{Name: "creds", HostPath: "/var/lib/creds", MountPath: "/etc/creds"},
+	}, {
+		name:      "Missing name",
+		mount:     HostPathMount{HostPath: "/var/lib/creds", MountPath: "/etc/creds"},
+		expectErr: "host path mount name is required",
+	}, {
+		name:      "Relative host path",
+		mount:     HostPathMount{Name: "creds", HostPath: "var/lib/creds", MountPath: "/etc/creds"},
+		expectErr: "host path mount creds: host path \"var/lib/creds\" should be absolute",
+	}, {
+		name:      "Relative mount path",
+		mount:     HostPathMount{Name: "creds", HostPath: "/var/lib/creds", MountPath: "etc/creds"},
+		expectErr: "host path mount creds: mount path \"etc/creds\" should be absolute",
+	}, {
+		name:      "Unknown type",
+		mount:     HostPathMount{Name: "creds", HostPath: "/var/lib/creds", MountPath: "/etc/creds", Type: "Folder"},
+		expectErr: "host path mount creds: unsupported host path type \"Folder\"",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.mount.Validate()
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoggingFormatValidate(t *testing.T) {
+	tc := []struct {
+		name      string
+		format    LoggingFormat
+		expectErr string
+	}{{
+		name: "Unset format is valid",
+	}, {
+		name:   "Text format",
+		format: LoggingFormatText,
+	}, {
+		name:   "JSON format",
+		format: LoggingFormatJSON,
+	}, {
+		name:      "Unknown format",
+		format:    "yaml",
+		expectErr: "unsupported logging format \"yaml\", expected one of \"text\", \"json\"",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.format.Validate()
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCloudEndpointOverrideValidate(t *testing.T) {
+	tc := []struct {
+		name      string
+		override  CloudEndpointOverride
+		expectErr string
+	}{{
+		name:     "Valid override",
+		override: CloudEndpointOverride{EnvName: "AWS_ENDPOINT_URL_EC2", URL: "https://ec2.us-east-1.example.com"},
+	}, {
+		name:     "Valid override with port and path",
+		override: CloudEndpointOverride{EnvName: "OS_AUTH_URL", URL: "http://keystone.example.com:5000/v3"},
+	}, {
+		name:      "Invalid environment variable name",
+		override:  CloudEndpointOverride{EnvName: "AWS-ENDPOINT", URL: "https://ec2.example.com"},
+		expectErr: "cloud endpoint override: invalid environment variable name \"AWS-ENDPOINT\": a valid C identifier must start with alphabetic character or '_', followed by a string of alphanumeric characters or '_' (e.g. 'my_name',  or 'MY_NAME',  or 'MyName', regex used for validation is '[A-Za-z_][A-Za-z0-9_]*')",
+	}, {
+		name:      "Unsupported scheme",
+		override:  CloudEndpointOverride{EnvName: "AWS_ENDPOINT_URL_EC2", URL: "ftp://ec2.example.com"},
+		expectErr: "cloud endpoint override AWS_ENDPOINT_URL_EC2: URL \"ftp://ec2.example.com\" should use http or https scheme",
+	}, {
+		name:      "IP address",
+		override:  CloudEndpointOverride{EnvName: "AWS_ENDPOINT_URL_EC2", URL: "https://10.0.0.1:443"},
+		expectErr: "cloud endpoint override AWS_ENDPOINT_URL_EC2: URL \"https://10.0.0.1:443\" should use a DNS name instead of an IP address",
+	}, {
+		name:      "Invalid host name",
+		override:  CloudEndpointOverride{EnvName: "AWS_ENDPOINT_URL_EC2", URL: "https://EC2_endpoint"},
+		expectErr: "cloud endpoint override AWS_ENDPOINT_URL_EC2: invalid host name \"EC2_endpoint\" in URL: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+	}, {
+		name:      "Missing host",
+		override:  CloudEndpointOverride{EnvName: "AWS_ENDPOINT_URL_EC2", URL: "https:///path"},
+		expectErr: "cloud endpoint override AWS_ENDPOINT_URL_EC2: invalid host name \"\" in URL: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.override.Validate()
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAzureCredentialsValidate(t *testing.T) {
+	tc := []struct {
+		name        string
+		credentials AzureCredentials
+		expectErr   string
+	}{{
+		name:        "Defaults",
+		credentials: AzureCredentials{},
+	}, {
+		name:        "Secret name and file path",
+		credentials: AzureCredentials{SecretName: "azure-credentials", FilePath: "/etc/azure/credentials"},
+	}, {
+		name:        "Invalid secret name",
+		credentials: AzureCredentials{SecretName: "Azure_Credentials"},
+		expectErr:   "azure credentials: invalid secret name \"Azure_Credentials\": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+	}, {
+		name:        "Relative file path",
+		credentials: AzureCredentials{FilePath: "etc/azure/credentials"},
+		expectErr:   "azure credentials: file path \"etc/azure/credentials\" should be absolute and clean",
+	}, {
+		name:        "File path which is not clean",
+		credentials: AzureCredentials{FilePath: "/etc/azure/../credentials"},
+		expectErr:   "azure credentials: file path \"/etc/azure/../credentials\" should be absolute and clean",
+	}, {
+		name:        "File in the root directory",
+		credentials: AzureCredentials{FilePath: "/credentials"},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.credentials.Validate()
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestResourceAutosizingRequests(t *testing.T) {
+	autosizing := ResourceAutosizing{
+		Min: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("50Mi"),
+		},
+		PerNode: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("5m"),
+			corev1.ResourceMemory: resource.MustParse("2Mi"),
+		},
+		Max: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+
+	tc := []struct {
+		name           string
+		autosizing     ResourceAutosizing
+		nodeCount      int
+		expectedCPU    string
+		expectedMemory string
+	}{{
+		name:           "No nodes",
+		autosizing:     autosizing,
+		expectedCPU:    "100m",
+		expectedMemory: "50Mi",
+	}, {
+		name:           "Small cluster is rounded up to the node bucket",
+		autosizing:     autosizing,
+		nodeCount:      3,
+		expectedCPU:    "150m",
+		expectedMemory: "70Mi",
+	}, {
+		name:           "Clusters within the same node bucket get the same requests",
+		autosizing:     autosizing,
+		nodeCount:      91,
+		expectedCPU:    "600m",
+		expectedMemory: "250Mi",
+	}, {
+		name: "Custom node bucket size",
+		autosizing: ResourceAutosizing{
+			Min:            autosizing.Min,
+			PerNode:        autosizing.PerNode,
+			NodeBucketSize: 1,
+		},
+		nodeCount:      3,
+		expectedCPU:    "115m",
+		expectedMemory: "56Mi",
+	}, {
+		name:           "Medium cluster",
+		autosizing:     autosizing,
+		nodeCount:      100,
+		expectedCPU:    "600m",
+		expectedMemory: "250Mi",
+	}, {
+		name:           "Large cluster is capped at maximum",
+		autosizing:     autosizing,
+		nodeCount:      2000,
+		expectedCPU:    "1",
+		expectedMemory: "1Gi",
+	}, {
+		name: "Unbounded growth without maximum",
+		autosizing: ResourceAutosizing{
+			Min:     autosizing.Min,
+			PerNode: autosizing.PerNode,
+		},
+		nodeCount:      2000,
+		expectedCPU:    "10100m",
+		expectedMemory: "4050Mi",
+	}, {
+		name: "Per node growth only",
+		autosizing: ResourceAutosizing{
+			PerNode: autosizing.PerNode,
+		},
+		nodeCount:      10,
+		expectedCPU:    "50m",
+		expectedMemory: "20Mi",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := tc.autosizing.Requests(tc.nodeCount)
+			assert.Len(t, requests, 2)
+			assert.Equal(t, tc.expectedCPU, ptr.To(requests[corev1.ResourceCPU]).String())
+			assert.Equal(t, tc.expectedMemory, ptr.To(requests[corev1.ResourceMemory]).String())
+		})
+	}
+}
+
+func TestResourceAutosizingValidate(t *testing.T) {
+	tc := []struct {
+		name       string
+		autosizing ResourceAutosizing
+		expectErr  string
+	}{{
+		name: "Valid autosizing",
+		autosizing: ResourceAutosizing{
+			Min:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			PerNode: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("5m")},
+			Max:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		},
+	}, {
+		name: "Negative quantity",
+		autosizing: ResourceAutosizing{
+			PerNode: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("-5m")},
+		},
+		expectErr: "resource autosizing: cpu quantity -5m should not be negative",
+	}, {
+		name: "Minimum is greater than maximum",
+		autosizing: ResourceAutosizing{
+			Min: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			Max: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+		expectErr: "resource autosizing: memory minimum 2Gi is greater than maximum 1Gi",
+	}, {
+		name:       "Negative node bucket size",
+		autosizing: ResourceAutosizing{NodeBucketSize: -1},
+		expectErr:  "resource autosizing: node bucket size -1 should not be negative",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.autosizing.Validate()
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetIPFamilies(t *testing.T) {
+	tc := []struct {
+		name             string
+		network          *configv1.Network
+		expectedFamilies []string
+	}{{
+		name:             "No network",
+		network:          nil,
+		expectedFamilies: nil,
+	}, {
+		name:             "IPv4 single-stack",
+		network:          &configv1.Network{Spec: configv1.NetworkSpec{ServiceNetwork: []string{"172.30.0.0/16"}}},
+		expectedFamilies: []string{"ipv4"},
+	}, {
+		name:             "IPv6 single-stack",
+		network:          &configv1.Network{Spec: configv1.NetworkSpec{ServiceNetwork: []string{"fd02::/112"}}},
+		expectedFamilies: []string{"ipv6"},
+	}, {
+		name:             "IPv4-primary dual-stack",
+		network:          &configv1.Network{Spec: configv1.NetworkSpec{ServiceNetwork: []string{"172.30.0.0/16", "fd02::/112"}}},
+		expectedFamilies: []string{"ipv4", "ipv6"},
+	}, {
+		name:             "IPv6-primary dual-stack",
+		network:          &configv1.Network{Spec: configv1.NetworkSpec{ServiceNetwork: []string{"fd02::/112", "172.30.0.0/16"}}},
+		expectedFamilies: []string{"ipv6", "ipv4"},
+	}, {
+		name:             "Invalid service networks are ignored",
+		network:          &configv1.Network{Spec: configv1.NetworkSpec{ServiceNetwork: []string{"invalid", "fd02::/112"}}},
+		expectedFamilies: []string{"ipv6"},
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedFamilies, GetIPFamilies(tc.network))
+		})
+	}
+}
+
+func TestGetDNSConfigHash(t *testing.T) {
+	dns := &configv1.DNS{Spec: configv1.DNSSpec{BaseDomain: "example.com"}}
+	hash, err := GetDNSConfigHash(dns)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, hash)
+
+	sameHash, err := GetDNSConfigHash(dns.DeepCopy())
+	assert.NoError(t, err)
+	assert.Equal(t, hash, sameHash, "identical DNS configs should have the same hash")
+
+	changed := dns.DeepCopy()
+	changed.Spec.PrivateZone = &configv1.DNSZone{ID: "private-zone"}
+	changedHash, err := GetDNSConfigHash(changed)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash, "a DNS config change should change the hash")
+}
+
+func TestGetDefaultNodeSelector(t *testing.T) {
+	scheduler := &configv1.Scheduler{Spec: configv1.SchedulerSpec{DefaultNodeSelector: "node-role.kubernetes.io/worker=,region=east"}}
+	namespace := func(annotations map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-cloud-controller-manager", Annotations: annotations}}
+	}
+
+	tc := []struct {
+		name          string
+		scheduler     *configv1.Scheduler
+		namespace     *corev1.Namespace
+		expected      map[string]string
+		expectedError string
+	}{{
+		name: "No scheduler config and namespace",
+	}, {
+		name:      "Cluster default node selector",
+		scheduler: scheduler,
+		namespace: namespace(nil),
+		expected:  map[string]string{"node-role.kubernetes.io/worker": "", "region": "east"},
+	}, {
+		name:      "Cluster default node selector of a namespace which does not exist yet",
+		scheduler: scheduler,
+		expected:  map[string]string{"node-role.kubernetes.io/worker": "", "region": "east"},
+	}, {
+		name:      "Empty namespace node selector disables the cluster default",
+		scheduler: scheduler,
+		namespace: namespace(map[string]string{NamespaceNodeSelectorAnnotation: ""}),
+	}, {
+		name:      "Namespace node selector replaces the cluster default",
+		scheduler: scheduler,
+		namespace: namespace(map[string]string{NamespaceNodeSelectorAnnotation: "node-role.kubernetes.io/master="}),
+		expected:  map[string]string{"node-role.kubernetes.io/master": ""},
+	}, {
+		name:          "Invalid node selector",
+		namespace:     namespace(map[string]string{NamespaceNodeSelectorAnnotation: "region!=east"}),
+		expectedError: `unable to parse default node selector "region!=east"`,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			nodeSelector, err := GetDefaultNodeSelector(tc.scheduler, tc.namespace)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, nodeSelector)
+		})
+	}
+}
+
 func TestComposeConfig(t *testing.T) {
 	defaultManagementNamespace := "test-namespace"
 
@@ -166,6 +722,9 @@ func TestComposeConfig(t *testing.T) {
 		CloudControllerManagerOpenStack: "registry.ci.openshift.org/openshift:openstack-cloud-controller-manager",
 	}
 
+	staticFeatureGates, err := util.NewStaticFeatureGateAccess("CloudDualStackNodeIPs=true,ChocobombVanilla=true,ChocobombBanana=false")
+	assert.NoError(t, err)
+
 	tc := []struct {
 		name          string
 		namespace     string
@@ -231,6 +790,44 @@ func TestComposeConfig(t *testing.T) {
 			// For more details look into k8s.io/controller-manager/pkg/features
 			FeatureGates: "CloudDualStackNodeIPs=true",
 		},
+	}, {
+		name:      "Static feature gates",
+		namespace: defaultManagementNamespace,
+		infra: &configv1.Infrastructure{
+			Status: configv1.InfrastructureStatus{
+				PlatformStatus: &configv1.PlatformStatus{
+					Type: configv1.VSpherePlatformType,
+				},
+			},
+		},
+		featureGates: staticFeatureGates,
+		expectConfig: OperatorConfig{
+			ManagedNamespace: defaultManagementNamespace,
+			ImagesReference:  defaultImagesReference,
+			PlatformStatus:   &configv1.PlatformStatus{Type: configv1.VSpherePlatformType},
+			FeatureGates:     "CloudDualStackNodeIPs=true",
+		},
+	}, {
+		name:      "Missing FeatureGate object falls back to defaults",
+		namespace: defaultManagementNamespace,
+		infra: &configv1.Infrastructure{
+			Status: configv1.InfrastructureStatus{
+				PlatformStatus: &configv1.PlatformStatus{
+					Type: configv1.AWSPlatformType,
+				},
+			},
+		},
+		featureGates: featuregates.NewHardcodedFeatureGateAccessForTesting(
+			[]configv1.FeatureGateName{"CloudDualStackNodeIPs"},
+			nil,
+			make(chan struct{}),
+			errors.New("featureGates not yet observed"),
+		),
+		expectConfig: OperatorConfig{
+			ManagedNamespace: defaultManagementNamespace,
+			ImagesReference:  defaultImagesReference,
+			PlatformStatus:   &configv1.PlatformStatus{Type: configv1.AWSPlatformType},
+		},
 	}, {
 		name:        "Empty infrastructure should return error",
 		expectError: "platform status is not populated on infrastructure",
@@ -297,3 +894,121 @@ func TestComposeConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestSysctlsValidate(t *testing.T) {
+	tc := []struct {
+		name      string
+		sysctls   Sysctls
+		expectErr string
+	}{{
+		name:    "Safe sysctl",
+		sysctls: Sysctls{{Name: "kernel.shm_rmid_forced", Value: "1"}},
+	}, {
+		name:      "Network sysctl",
+		sysctls:   Sysctls{{Name: "net.ipv4.ip_local_port_range", Value: "1024 65535"}},
+		expectErr: "sysctl \"net.ipv4.ip_local_port_range\" can not be set on host network pods, since it would change the node network settings",
+	}, {
+		name:      "Sysctl which is not namespaced",
+		sysctls:   Sysctls{{Name: "fs.file-max", Value: "1048576"}},
+		expectErr: "sysctl \"fs.file-max\" is not allowed by the kubelet by default, expected one of kernel.shm_rmid_forced",
+	}, {
+		name:      "Unsafe namespaced sysctl",
+		sysctls:   Sysctls{{Name: "kernel.msgmax", Value: "65536"}},
+		expectErr: "sysctl \"kernel.msgmax\" is not allowed by the kubelet by default, expected one of kernel.shm_rmid_forced",
+	}, {
+		name:      "Empty value",
+		sysctls:   Sysctls{{Name: "kernel.shm_rmid_forced"}},
+		expectErr: "sysctl \"kernel.shm_rmid_forced\" should have a value",
+	}, {
+		name: "Duplicate sysctl",
+		sysctls: Sysctls{
+			{Name: "kernel.shm_rmid_forced", Value: "1"},
+			{Name: "kernel.shm_rmid_forced", Value: "0"},
+		},
+		expectErr: "sysctl \"kernel.shm_rmid_forced\" is set more than once",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.sysctls.Validate()
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateHostAliases(t *testing.T) {
+	tc := []struct {
+		name      string
+		aliases   []corev1.HostAlias
+		expectErr string
+	}{{
+		name: "Valid aliases",
+		aliases: []corev1.HostAlias{
+			{IP: "10.0.0.10", Hostnames: []string{"ec2.us-east-1.amazonaws.com"}},
+			{IP: "fd00::10", Hostnames: []string{"elasticloadbalancing.us-east-1.amazonaws.com", "sts.amazonaws.com"}},
+		},
+	}, {
+		name:      "Invalid IP address",
+		aliases:   []corev1.HostAlias{{IP: "ec2.internal", Hostnames: []string{"ec2.us-east-1.amazonaws.com"}}},
+		expectErr: "host alias: invalid IP address \"ec2.internal\"",
+	}, {
+		name:      "No hostnames",
+		aliases:   []corev1.HostAlias{{IP: "10.0.0.10"}},
+		expectErr: "host alias 10.0.0.10: at least one hostname should be set",
+	}, {
+		name:      "Invalid hostname",
+		aliases:   []corev1.HostAlias{{IP: "10.0.0.10", Hostnames: []string{"EC2_API"}}},
+		expectErr: "host alias 10.0.0.10: invalid hostname \"EC2_API\": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateHostAliases(tc.aliases)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateFeatureGates(t *testing.T) {
+	tc := []struct {
+		name      string
+		platform  configv1.PlatformType
+		enabled   []configv1.FeatureGateName
+		disabled  []configv1.FeatureGateName
+		expectErr string
+	}{{
+		name:     "Platform feature gate on its platform",
+		platform: configv1.VSpherePlatformType,
+		enabled:  []configv1.FeatureGateName{"VSphereMultiVCenters", "CloudControllerManagerWebhook"},
+	}, {
+		name:     "Disabled feature gate of another platform",
+		platform: configv1.AWSPlatformType,
+		disabled: []configv1.FeatureGateName{"VSphereMultiVCenters", "GCPLabelsTags"},
+	}, {
+		name:      "Enabled feature gates of other platforms",
+		platform:  configv1.AWSPlatformType,
+		enabled:   []configv1.FeatureGateName{"VSphereStaticIPs", "CloudControllerManagerWebhook", "GCPLabelsTags"},
+		expectErr: "feature gates GCPLabelsTags, VSphereStaticIPs are enabled but not supported on platform AWS",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateFeatureGates(tc.platform, featuregates.NewFeatureGate(tc.enabled, tc.disabled))
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				var unsupportedErr *UnsupportedFeatureGatesError
+				assert.True(t, errors.As(err, &unsupportedErr))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
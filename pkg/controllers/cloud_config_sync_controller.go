@@ -1,19 +1,32 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
+	goerrors "errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	configv1 "github.com/openshift/api/config/v1"
 
@@ -25,17 +38,70 @@ const (
 
 	defaultConfigKey = "cloud.conf"
 
+	// cloudConfigSourceKeyAnnotation records the key of the source config the synced cloud config was read from,
+	// so the previously synced key can be pruned once the key referenced by the infrastructure resource changes.
+	cloudConfigSourceKeyAnnotation = "operator.openshift.io/cloud-config-source-key"
+	// cloudConfigPrunedKeysAnnotation lists keys of the source config which were used by previous syncs
+	// and are kept out of the synced cloud config.
+	cloudConfigPrunedKeysAnnotation = "operator.openshift.io/cloud-config-pruned-keys"
+
+	// managedNamespaceRequeuePeriod is how often the sync is retried while the managed namespace is missing
+	// or terminating. Recreation of the namespace triggers a sync right away as well.
+	managedNamespaceRequeuePeriod = 30 * time.Second
+
+	// Transient sync failures, e.g. API throttling, are retried with exponential backoff
+	// starting at cloudConfigSyncBaseDelay and capped at cloudConfigSyncMaxDelay.
+	cloudConfigSyncBaseDelay = time.Second
+	cloudConfigSyncMaxDelay  = 5 * time.Minute
+	// cloudConfigSyncFailuresEventThreshold is the number of consecutive transient sync failures
+	// after which a warning event is recorded.
+	cloudConfigSyncFailuresEventThreshold = 5
+
 	// Controller conditions for the Cluster Operator resource
 	cloudConfigControllerAvailableCondition = "CloudConfigControllerAvailable"
 	cloudConfigControllerDegradedCondition  = "CloudConfigControllerDegraded"
+
+	cloudConfigControllerAvailableMessage = "Cloud Config Controller works as expected"
 )
 
 type CloudConfigReconciler struct {
 	ClusterOperatorStatusClient
 	Scheme *runtime.Scheme
+	// CloudConfigSecretName is the name of a Secret in the openshift-config namespace to read the
+	// cloud config from instead of the ConfigMap referenced by the infrastructure resource.
+	// The key from the infrastructure resource cloud config reference is looked up the same way.
+	// Secrets carrying credentials are refused, since the config is synced into a ConfigMap.
+	CloudConfigSecretName string
+
+	// consecutiveFailures counts transient sync failures since the last successful or permanently failed sync.
+	consecutiveFailures atomic.Int32
+}
+
+// permanentSyncError marks errors which can not be fixed by retrying the sync, e.g. an invalid infrastructure
+// resource or source config. Such errors are not requeued, the sync is retried once watched resources change.
+func permanentSyncError(err error) error {
+	return reconcile.TerminalError(err)
 }
 
 func (r *CloudConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	result, err := r.reconcile(ctx)
+	if err == nil || goerrors.Is(err, reconcile.TerminalError(nil)) {
+		r.consecutiveFailures.Store(0)
+		return result, err
+	}
+
+	failures := r.consecutiveFailures.Add(1)
+	if failures%cloudConfigSyncFailuresEventThreshold == 0 && r.Recorder != nil {
+		target := &corev1.ConfigMap{}
+		target.SetName(syncedCloudConfigMapName)
+		target.SetNamespace(r.ManagedNamespace)
+		r.Recorder.Eventf(target, corev1.EventTypeWarning, "CloudConfigSyncFailing",
+			"Cloud config sync failed %d times in a row: %v", failures, err)
+	}
+	return result, err
+}
+
+func (r *CloudConfigReconciler) reconcile(ctx context.Context) (ctrl.Result, error) {
 	klog.V(1).Infof("Syncing cloud-conf ConfigMap")
 
 	infra := &configv1.Infrastructure{}
@@ -60,10 +126,10 @@ func (r *CloudConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		if err := r.setDegradedCondition(ctx); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
 		}
-		return ctrl.Result{}, err
+		return ctrl.Result{}, permanentSyncError(err)
 	}
 	if !syncNeeded {
-		if err := r.setAvailableCondition(ctx); err != nil {
+		if err := r.setAvailableCondition(ctx, cloudConfigSyncMessage(infra.Status.PlatformStatus, false)); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
 		}
 		klog.Infof("cloud-config sync is not needed, returning early")
@@ -76,50 +142,17 @@ func (r *CloudConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		if err := r.setDegradedCondition(ctx); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
 		}
-		return ctrl.Result{}, err
-	}
-
-	sourceCM := &corev1.ConfigMap{}
-	managedConfigFound := false
-
-	// NOTE: We know that there is some transformation logic in place in the
-	// Cluster Config Operator (CCO) for AWS and Azure. We have not implemented
-	// this logic here yet so we've intentionally chosen to lookup up config
-	// from the (CCO-) managed namespace **only for these cloud platforms**
-	// TODO: Drop this once we implement the AWS and Azure transformers here in
-	// CCCMO, allowing us to drop this kinda-sorta reliance on CCO stuff. We
-	// may also wish to merge the use of cloudConfigTransformerFn into the
-	// prepareSourceConfigMap helper function
-	if needsManagedConfigLookup {
-		defaultSourceCMObjectKey := client.ObjectKey{
-			Name:      managedCloudConfigMapName,
-			Namespace: OpenshiftManagedConfigNamespace,
-		}
-		if err := r.Get(ctx, defaultSourceCMObjectKey, sourceCM); err == nil {
-			managedConfigFound = true
-		} else if errors.IsNotFound(err) {
-			klog.Warningf("managed cloud-config is not found, falling back to infrastructure config")
-		} else if err != nil {
-			klog.Errorf("unable to get managed cloud-config for sync")
-			if err := r.setDegradedCondition(ctx); err != nil {
-				return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
-			}
-			return ctrl.Result{}, err
-		}
+		return ctrl.Result{}, permanentSyncError(err)
 	}
+	availableMessage := cloudConfigSyncMessage(infra.Status.PlatformStatus, true)
 
-	if !managedConfigFound {
-		openshiftUnmanagedCMKey := client.ObjectKey{
-			Name:      infra.Spec.CloudConfig.Name,
-			Namespace: OpenshiftConfigNamespace,
-		}
-		if err := r.Get(ctx, openshiftUnmanagedCMKey, sourceCM); err != nil {
-			klog.Errorf("unable to get cloud-config for sync")
-			if err := r.setDegradedCondition(ctx); err != nil {
-				return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
-			}
-			return ctrl.Result{}, err
+	sourceCM, err := r.getSourceCloudConfigMap(ctx, infra, needsManagedConfigLookup)
+	if err != nil {
+		klog.Errorf("unable to get cloud-config for sync")
+		if err := r.setDegradedCondition(ctx); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
 		}
+		return ctrl.Result{}, err
 	}
 
 	sourceCM, err = r.prepareSourceConfigMap(sourceCM, infra)
@@ -127,23 +160,45 @@ func (r *CloudConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		if err := r.setDegradedCondition(ctx); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
 		}
-		return ctrl.Result{}, err
+		return ctrl.Result{}, permanentSyncError(err)
 	}
 
+	// The transformer is applied regardless of where the source config came from, so the infra
+	// config used as a fallback for a missing managed config is transformed the same way.
 	if cloudConfigTransformerFn != nil {
 		// We ignore stuff in sourceCM.BinaryData. This isn't allowed to
 		// contain any key that overlaps with those found in sourceCM.Data and
 		// we're not expecting users to put their data in the former.
-		output, err := cloudConfigTransformerFn(sourceCM.Data[defaultConfigKey], infra, network)
+		output, err := r.transformCloudConfig(cloudConfigTransformerFn, cloud.GetCloudConfigValidator(infra.Status.PlatformStatus),
+			sourceCM.Data[defaultConfigKey], infra, network)
 		if err != nil {
+			configTransformFailures.WithLabelValues(string(infra.Status.PlatformStatus.Type)).Inc()
 			if err := r.setDegradedCondition(ctx); err != nil {
 				return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
 			}
-			return ctrl.Result{}, err
+			return ctrl.Result{}, permanentSyncError(err)
 		}
 		sourceCM.Data[defaultConfigKey] = output
 	}
 
+	// Syncs fail while the managed namespace is missing or being deleted; report it and check back later,
+	// so the cloud config is synced again once the namespace is recreated.
+	namespaceReady, err := r.isManagedNamespaceReady(ctx)
+	if err != nil {
+		klog.Errorf("unable to get managed namespace %s: %v", r.ManagedNamespace, err)
+		if err := r.setDegradedCondition(ctx); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
+		}
+		return ctrl.Result{}, err
+	}
+	if !namespaceReady {
+		klog.Errorf("managed namespace %s is missing or terminating, cloud config can not be synced until it is recreated", r.ManagedNamespace)
+		if err := r.setDegradedCondition(ctx); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
+		}
+		return ctrl.Result{RequeueAfter: managedNamespaceRequeuePeriod}, nil
+	}
+
 	targetCM := &corev1.ConfigMap{}
 	targetConfigMapKey := client.ObjectKey{
 		Namespace: r.ManagedNamespace,
@@ -159,10 +214,14 @@ func (r *CloudConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// The key referenced by the infrastructure resource might have changed since the last sync,
+	// drop previously synced keys, so they do not linger in the synced config.
+	r.pruneStaleConfigKeys(sourceCM, targetCM)
+
 	// Note that the source config map is actually a *transformed* source config map
-	if r.isCloudConfigEqual(sourceCM, targetCM) {
+	if r.isCloudConfigEqual(sourceCM, targetCM) && r.isSourceKeyEqual(sourceCM, targetCM) {
 		klog.V(1).Infof("source and target cloud-config content are equal, no sync needed")
-		if err := r.setAvailableCondition(ctx); err != nil {
+		if err := r.setAvailableCondition(ctx, availableMessage); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
 		}
 		return ctrl.Result{}, nil
@@ -176,13 +235,134 @@ func (r *CloudConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	if err := r.setAvailableCondition(ctx); err != nil {
+	if err := r.setAvailableCondition(ctx, availableMessage); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to set conditions for cloud config controller: %v", err)
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// transformCloudConfig transforms the source config and checks the output with the platform validator, if any,
+// so a malformed output is not synced. A validation failure means the transformer is broken for the source
+// config, which is reported with a warning event, since retrying the sync would not fix it.
+func (r *CloudConfigReconciler) transformCloudConfig(transform func(string, *configv1.Infrastructure, *configv1.Network) (string, error),
+	validate func(string) error, source string, infra *configv1.Infrastructure, network *configv1.Network) (string, error) {
+	output, err := transform(source, infra, network)
+	if err != nil {
+		return "", err
+	}
+	if validate == nil {
+		return output, nil
+	}
+	if err := validate(output); err != nil {
+		err = fmt.Errorf("transformed cloud config is invalid, refusing to sync it: %w", err)
+		klog.Error(err)
+		if r.Recorder != nil {
+			target := &corev1.ConfigMap{}
+			target.SetName(syncedCloudConfigMapName)
+			target.SetNamespace(r.ManagedNamespace)
+			r.Recorder.Event(target, corev1.EventTypeWarning, "CloudConfigValidationFailed", err.Error())
+		}
+		return "", err
+	}
+	return output, nil
+}
+
+// getSourceCloudConfigMap returns the ConfigMap to sync the cloud config from.
+// NOTE: We know that there is some transformation logic in place in the
+// Cluster Config Operator (CCO) for AWS and Azure. We have not implemented
+// this logic here yet so we've intentionally chosen to lookup up config
+// from the (CCO-) managed namespace **only for these cloud platforms**.
+// If the managed config is not found, the config referenced in the infrastructure resource is used instead.
+// TODO: Drop this once we implement the AWS and Azure transformers here in
+// CCCMO, allowing us to drop this kinda-sorta reliance on CCO stuff.
+func (r *CloudConfigReconciler) getSourceCloudConfigMap(ctx context.Context, infra *configv1.Infrastructure, needsManagedConfigLookup bool) (*corev1.ConfigMap, error) {
+	sourceCM := &corev1.ConfigMap{}
+
+	if needsManagedConfigLookup {
+		defaultSourceCMObjectKey := client.ObjectKey{
+			Name:      managedCloudConfigMapName,
+			Namespace: OpenshiftManagedConfigNamespace,
+		}
+		err := r.Get(ctx, defaultSourceCMObjectKey, sourceCM)
+		if err == nil {
+			return sourceCM, nil
+		}
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		klog.Warningf("managed cloud-config is not found, falling back to infrastructure config")
+	}
+
+	if r.CloudConfigSecretName != "" {
+		sourceSecret := &corev1.Secret{}
+		sourceSecretKey := client.ObjectKey{
+			Name:      r.CloudConfigSecretName,
+			Namespace: OpenshiftConfigNamespace,
+		}
+		if err := r.Get(ctx, sourceSecretKey, sourceSecret); err != nil {
+			return nil, err
+		}
+		secretCM, err := configMapFromSecret(sourceSecret)
+		if err != nil {
+			return nil, permanentSyncError(err)
+		}
+		return secretCM, nil
+	}
+
+	openshiftUnmanagedCMKey := client.ObjectKey{
+		Name:      infra.Spec.CloudConfig.Name,
+		Namespace: OpenshiftConfigNamespace,
+	}
+	if err := r.Get(ctx, openshiftUnmanagedCMKey, sourceCM); err != nil {
+		return nil, err
+	}
+	return sourceCM, nil
+}
+
+// credentialSettingRegexp matches settings of INI, JSON or YAML cloud configs which hold credentials inline,
+// e.g. the vSphere "password" or the Azure "aadClientSecret" settings.
+var credentialSettingRegexp = regexp.MustCompile(
+	`(?im)(?:^|[\s{,])"?(user|username|password|token|aadClientSecret|aadClientCertPassword|application-credential-secret)"?\s*[:=]`)
+
+// credentialKeyRegexp matches Secret keys which hold credentials, e.g. the "<server>.password" keys of vSphere.
+var credentialKeyRegexp = regexp.MustCompile(`(?i)(?:^|\.)(user|username|password|token)$`)
+
+// configMapFromSecret converts the Secret holding the cloud config into a ConfigMap with decoded content,
+// so it goes through the same preparation and transformation steps as the ConfigMap source,
+// and can be compared with the synced config.
+// The synced cloud-conf is a ConfigMap readable by anyone with access to the managed namespace, so Secrets
+// carrying credentials are refused instead of leaking them, credentials have to be passed through the
+// credentials Secrets of the platform.
+func configMapFromSecret(secret *corev1.Secret) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+		},
+		Data: make(map[string]string, len(secret.Data)+len(secret.StringData)),
+	}
+	for key, value := range secret.Data {
+		cm.Data[key] = string(value)
+	}
+	for key, value := range secret.StringData {
+		cm.Data[key] = value
+	}
+
+	keys := sets.List(sets.KeySet(cm.Data))
+	for _, key := range keys {
+		if credentialKeyRegexp.MatchString(key) {
+			return nil, fmt.Errorf("key %s of secret %s/%s holds credentials, which can not be synced to the cloud-config ConfigMap",
+				key, secret.Namespace, secret.Name)
+		}
+		if match := credentialSettingRegexp.FindStringSubmatch(cm.Data[key]); match != nil {
+			return nil, fmt.Errorf("key %s of secret %s/%s sets credentials in %q, which can not be synced to the cloud-config ConfigMap",
+				key, secret.Namespace, secret.Name, match[1])
+		}
+	}
+	return cm, nil
+}
+
 func (r *CloudConfigReconciler) isCloudConfigSyncNeeded(platformStatus *configv1.PlatformStatus, infraCloudConfigRef configv1.ConfigMapFileReference) (bool, error) {
 	if platformStatus == nil {
 		return false, fmt.Errorf("platformStatus is required")
@@ -207,15 +387,21 @@ func (r *CloudConfigReconciler) isCloudConfigSyncNeeded(platformStatus *configv1
 func (r *CloudConfigReconciler) prepareSourceConfigMap(source *corev1.ConfigMap, infra *configv1.Infrastructure) (*corev1.ConfigMap, error) {
 	// Keys might be different between openshift-config/cloud-config and openshift-config-managed/kube-cloud-config
 	// Always use "cloud.conf" which is default one across openshift
+	// The key from the infra resource takes precedence, so a change of the key is picked up
+	// even if the source config also has the default key.
 	cloudConfCm := source.DeepCopy()
-	if _, ok := cloudConfCm.Data[defaultConfigKey]; ok {
+	infraConfigKey := infra.Spec.CloudConfig.Key
+	if val, ok := cloudConfCm.Data[infraConfigKey]; ok && infraConfigKey != "" {
+		cloudConfCm.Data[defaultConfigKey] = val
+		if infraConfigKey != defaultConfigKey {
+			delete(cloudConfCm.Data, infraConfigKey)
+		}
+		metav1.SetMetaDataAnnotation(&cloudConfCm.ObjectMeta, cloudConfigSourceKeyAnnotation, infraConfigKey)
 		return cloudConfCm, nil
 	}
 
-	infraConfigKey := infra.Spec.CloudConfig.Key
-	if val, ok := cloudConfCm.Data[infraConfigKey]; ok {
-		cloudConfCm.Data[defaultConfigKey] = val
-		delete(cloudConfCm.Data, infraConfigKey)
+	if _, ok := cloudConfCm.Data[defaultConfigKey]; ok {
+		metav1.SetMetaDataAnnotation(&cloudConfCm.ObjectMeta, cloudConfigSourceKeyAnnotation, defaultConfigKey)
 		return cloudConfCm, nil
 	}
 	return nil, fmt.Errorf(
@@ -224,38 +410,179 @@ func (r *CloudConfigReconciler) prepareSourceConfigMap(source *corev1.ConfigMap,
 	)
 }
 
+// isCloudConfigEqual returns true if the target config has the same content as the source config.
+// Immutability of the source is not synced, an immutable target config always needs to be re-synced.
 func (r *CloudConfigReconciler) isCloudConfigEqual(source *corev1.ConfigMap, target *corev1.ConfigMap) bool {
-	return source.Immutable == target.Immutable &&
+	return !ptr.Deref(target.Immutable, false) &&
 		reflect.DeepEqual(source.Data, target.Data) && reflect.DeepEqual(source.BinaryData, target.BinaryData)
 }
 
+// isSourceKeyEqual returns true if the target config was synced from the same source key as the source config,
+// with the same keys pruned.
+func (r *CloudConfigReconciler) isSourceKeyEqual(source *corev1.ConfigMap, target *corev1.ConfigMap) bool {
+	return source.Annotations[cloudConfigSourceKeyAnnotation] == target.Annotations[cloudConfigSourceKeyAnnotation] &&
+		source.Annotations[cloudConfigPrunedKeysAnnotation] == target.Annotations[cloudConfigPrunedKeysAnnotation]
+}
+
+// pruneStaleConfigKeys removes keys the target config was synced from previously from the source config,
+// and records them on the source config, so they are pruned by following syncs as well.
+// A key is not pruned anymore once it is referenced by the infrastructure resource again.
+func (r *CloudConfigReconciler) pruneStaleConfigKeys(source *corev1.ConfigMap, target *corev1.ConfigMap) {
+	currentKey := source.Annotations[cloudConfigSourceKeyAnnotation]
+	previousKey := target.Annotations[cloudConfigSourceKeyAnnotation]
+
+	prunedKeys := sets.New[string]()
+	if keys := target.Annotations[cloudConfigPrunedKeysAnnotation]; keys != "" {
+		prunedKeys.Insert(strings.Split(keys, ",")...)
+	}
+	if previousKey != "" && previousKey != currentKey && previousKey != defaultConfigKey {
+		klog.Infof("cloud-config key changed from %s to %s, pruning the previously synced key", previousKey, currentKey)
+		prunedKeys.Insert(previousKey)
+	}
+	prunedKeys.Delete(currentKey)
+
+	if prunedKeys.Len() == 0 {
+		return
+	}
+	for key := range prunedKeys {
+		delete(source.Data, key)
+	}
+	metav1.SetMetaDataAnnotation(&source.ObjectMeta, cloudConfigPrunedKeysAnnotation, strings.Join(sets.List(prunedKeys), ","))
+}
+
+// isManagedNamespaceReady returns false if the managed namespace does not exist or is being deleted.
+func (r *CloudConfigReconciler) isManagedNamespaceReady(ctx context.Context) (bool, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: r.ManagedNamespace}, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return namespace.DeletionTimestamp == nil, nil
+}
+
 func (r *CloudConfigReconciler) syncCloudConfigData(ctx context.Context, source *corev1.ConfigMap, target *corev1.ConfigMap) error {
 	target.SetName(syncedCloudConfigMapName)
 	target.SetNamespace(r.ManagedNamespace)
 	target.Data = source.Data
 	target.BinaryData = source.BinaryData
-	target.Immutable = source.Immutable
+	// The synced config is never made immutable, even if the source config is, since that would block further syncs.
+	target.Immutable = nil
+	for _, annotation := range []string{cloudConfigSourceKeyAnnotation, cloudConfigPrunedKeysAnnotation} {
+		if value, ok := source.Annotations[annotation]; ok {
+			metav1.SetMetaDataAnnotation(&target.ObjectMeta, annotation, value)
+		} else {
+			delete(target.Annotations, annotation)
+		}
+	}
 
 	// check if target config exists, create if not
-	err := r.Get(ctx, client.ObjectKeyFromObject(target), &corev1.ConfigMap{})
+	existing := &corev1.ConfigMap{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(target), existing)
 
 	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, target)
+		if err := r.Create(ctx, target); err != nil {
+			return err
+		}
+		r.recordCloudConfigChanges(target, &corev1.ConfigMap{})
+		return nil
 	} else if err != nil {
 		return err
 	}
 
-	return r.Update(ctx, target)
+	// Immutable config maps can not be updated, replace the target config synced as immutable before.
+	if ptr.Deref(existing.Immutable, false) {
+		klog.Infof("synced cloud-config %s is immutable, recreating it", client.ObjectKeyFromObject(existing))
+		if err := r.Delete(ctx, existing); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		target.ResourceVersion = ""
+		target.UID = ""
+		if err := r.Create(ctx, target); err != nil {
+			return err
+		}
+		r.recordCloudConfigChanges(target, existing)
+		return nil
+	}
+
+	if err := r.Update(ctx, target); err != nil {
+		return err
+	}
+	r.recordCloudConfigChanges(target, existing)
+	return nil
+}
+
+// recordCloudConfigChanges records an event on the synced config listing keys which were added,
+// modified or removed compared to the previously synced config.
+func (r *CloudConfigReconciler) recordCloudConfigChanges(target, previous *corev1.ConfigMap) {
+	changes := cloudConfigChanges(previous, target)
+	if changes == "" || r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(target, corev1.EventTypeNormal, "CloudConfigSynced", "Synced cloud config keys: %s", changes)
+}
+
+// cloudConfigChanges summarizes keys added, modified and removed in the target config map data
+// compared to the previous one, e.g. "added [a], modified [b]". Only key names are reported, since config
+// values might be sensitive. An empty string is returned if the data did not change.
+func cloudConfigChanges(previous, target *corev1.ConfigMap) string {
+	previousData, targetData := configMapData(previous), configMapData(target)
+
+	var added, modified, removed []string
+	for key, value := range targetData {
+		previousValue, ok := previousData[key]
+		if !ok {
+			added = append(added, key)
+		} else if !bytes.Equal(previousValue, value) {
+			modified = append(modified, key)
+		}
+	}
+	for key := range previousData {
+		if _, ok := targetData[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+
+	var changes []string
+	for _, group := range []struct {
+		name string
+		keys []string
+	}{{"added", added}, {"modified", modified}, {"removed", removed}} {
+		if len(group.keys) == 0 {
+			continue
+		}
+		slices.Sort(group.keys)
+		changes = append(changes, fmt.Sprintf("%s [%s]", group.name, strings.Join(group.keys, ", ")))
+	}
+	return strings.Join(changes, ", ")
+}
+
+// configMapData returns both data and binary data of the config map keyed by key names.
+func configMapData(cm *corev1.ConfigMap) map[string][]byte {
+	data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+	for key, value := range cm.Data {
+		data[key] = []byte(value)
+	}
+	for key, value := range cm.BinaryData {
+		data[key] = value
+	}
+	return data
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *CloudConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	build := ctrl.NewControllerManagedBy(mgr).
 		Named("CloudConfigSyncController").
+		WithOptions(controller.Options{
+			RateLimiter: workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](cloudConfigSyncBaseDelay, cloudConfigSyncMaxDelay),
+		}).
 		For(
 			&corev1.ConfigMap{},
 			builder.WithPredicates(
 				predicate.Or(
+					// Deletion of the synced config map triggers a re-sync right away, so operands
+					// are not left without cloud config until the next resync period.
 					ownCloudConfigPredicate(r.ManagedNamespace),
 					openshiftCloudConfigMapPredicates(),
 				),
@@ -269,22 +596,49 @@ func (r *CloudConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(
 			&configv1.Network{},
 			handler.EnqueueRequestsFromMapFunc(toManagedConfigMap),
+		).
+		// The synced config map goes away along with the managed namespace,
+		// re-sync as soon as the namespace is recreated.
+		Watches(
+			&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(toManagedConfigMap),
+			builder.WithPredicates(managedNamespacePredicate(r.ManagedNamespace)),
 		)
 
-	return build.Complete(r)
+	if r.CloudConfigSecretName != "" {
+		build = build.Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(toManagedConfigMap),
+			builder.WithPredicates(cloudConfigSecretPredicate(r.CloudConfigSecretName)),
+		)
+	}
+
+	return build.Complete(newTimedReconciler("CloudConfigSyncController", r.Clock, newDrainingReconciler(r.ShutdownGracePeriod, r)))
+}
+
+// cloudConfigSyncMessage summarizes for the available condition which platform the cloud config is synced for,
+// and whether it is transformed by a dedicated transformer of the platform, passed through as is or not synced at all.
+func cloudConfigSyncMessage(platformStatus *configv1.PlatformStatus, syncNeeded bool) string {
+	if !syncNeeded {
+		return fmt.Sprintf("%s, cloud config sync is not needed on platform %s", cloudConfigControllerAvailableMessage, platformStatus.Type)
+	}
+	transformer := cloud.GetCloudConfigTransformerName(platformStatus)
+	if transformer == "" {
+		return fmt.Sprintf("%s, last sync on platform %s passed the cloud config through", cloudConfigControllerAvailableMessage, platformStatus.Type)
+	}
+	return fmt.Sprintf("%s, last sync on platform %s used the %s transformer", cloudConfigControllerAvailableMessage, platformStatus.Type, transformer)
 }
 
-func (r *CloudConfigReconciler) setAvailableCondition(ctx context.Context) error {
+func (r *CloudConfigReconciler) setAvailableCondition(ctx context.Context, message string) error {
 	co, err := r.getOrCreateClusterOperator(ctx)
 	if err != nil {
 		return err
 	}
 
 	conds := []configv1.ClusterOperatorStatusCondition{
-		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected,
-			"Cloud Config Controller works as expected"),
+		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, message),
 		newClusterOperatorStatusCondition(cloudConfigControllerDegradedCondition, configv1.ConditionFalse, ReasonAsExpected,
-			"Cloud Config Controller works as expected"),
+			cloudConfigControllerAvailableMessage),
 	}
 
 	co.Status.Versions = []configv1.OperandVersion{{Name: operatorVersionKey, Version: r.ReleaseVersion}}
@@ -2,11 +2,13 @@ package controllers
 
 import (
 	"context"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
+	dto "github.com/prometheus/client_model/go"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,12 +21,36 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/cloud"
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/cloud/vsphere"
 )
 
 const (
 	infraCloudConfName = "test-config"
 	infraCloudConfKey  = "foo"
 
+	cloudConfigSecretName = "test-config-secret"
+
+	vsphereIniConfig = `
+[Global]
+secret-name = "vsphere-creds"
+secret-namespace = "kube-system"
+insecure-flag = "1"
+
+[VirtualCenter "test-server"]
+datacenters = "DC1"`
+
+	vsphereYamlConfig = `global:
+  insecureFlag: true
+  secretName: vsphere-creds
+  secretNamespace: kube-system
+vcenter:
+  test-server:
+    server: test-server
+    datacenters:
+    - DC1`
+
 	defaultAzureConfig = `{"cloud":"AzurePublicCloud","tenantId":"0000000-0000-0000-0000-000000000000","subscriptionId":"0000000-0000-0000-0000-000000000000","vmType":"standard","putVMSSVMBatchSize":0,"enableMigrateToIPBasedBackendPoolAPI":false,"clusterServiceLoadBalancerHealthProbeMode":"shared"}`
 )
 
@@ -88,6 +114,13 @@ func makeInfraCloudConfig() *corev1.ConfigMap {
 	}, Data: map[string]string{infraCloudConfKey: defaultAzureConfig}}
 }
 
+func makeCloudConfigSecret(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      cloudConfigSecretName,
+		Namespace: OpenshiftConfigNamespace,
+	}, Data: data}
+}
+
 func makeManagedCloudConfig() *corev1.ConfigMap {
 	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
 		Name:      managedCloudConfigMapName,
@@ -104,13 +137,63 @@ var _ = Describe("isCloudConfigEqual reconciler method", func() {
 
 	It("should return 'false' if ConfigMaps content are not equal", func() {
 		changedManagedCloudConfig := makeManagedCloudConfig()
-		changedManagedCloudConfig.Immutable = ptr.To[bool](true)
-		Expect(reconciler.isCloudConfigEqual(changedManagedCloudConfig, makeManagedCloudConfig())).Should(BeFalse())
-
-		changedManagedCloudConfig = makeManagedCloudConfig()
 		changedManagedCloudConfig.Data = map[string]string{}
 		Expect(reconciler.isCloudConfigEqual(changedManagedCloudConfig, makeManagedCloudConfig())).Should(BeFalse())
 	})
+
+	It("should return 'false' if target ConfigMap is immutable", func() {
+		immutableCloudConfig := makeManagedCloudConfig()
+		immutableCloudConfig.Immutable = ptr.To[bool](true)
+		Expect(reconciler.isCloudConfigEqual(makeManagedCloudConfig(), immutableCloudConfig)).Should(BeFalse())
+	})
+
+	It("should ignore immutability of source ConfigMap", func() {
+		immutableCloudConfig := makeManagedCloudConfig()
+		immutableCloudConfig.Immutable = ptr.To[bool](true)
+		Expect(reconciler.isCloudConfigEqual(immutableCloudConfig, makeManagedCloudConfig())).Should(BeTrue())
+	})
+})
+
+var _ = Describe("cloudConfigChanges", func() {
+	It("should be empty if data is equal", func() {
+		cm := &corev1.ConfigMap{Data: map[string]string{"cloud.conf": "foo"}}
+		Expect(cloudConfigChanges(cm, cm.DeepCopy())).To(BeEmpty())
+	})
+
+	It("should name added, modified and removed keys", func() {
+		previous := &corev1.ConfigMap{
+			Data:       map[string]string{"cloud.conf": "foo", "stale": "bar"},
+			BinaryData: map[string][]byte{"ca.pem": []byte("old")},
+		}
+		target := &corev1.ConfigMap{
+			Data:       map[string]string{"cloud.conf": "foo", "new-b": "b", "new-a": "a"},
+			BinaryData: map[string][]byte{"ca.pem": []byte("new")},
+		}
+		Expect(cloudConfigChanges(previous, target)).To(Equal("added [new-a, new-b], modified [ca.pem], removed [stale]"))
+	})
+})
+
+var _ = Describe("configMapFromSecret", func() {
+	It("should decode the secret data", func() {
+		secret := makeCloudConfigSecret(map[string][]byte{infraCloudConfKey: []byte(vsphereIniConfig)})
+		secret.StringData = map[string]string{"extra": "bar"}
+		cm, err := configMapFromSecret(secret)
+		Expect(err).Should(Succeed())
+		Expect(cm.Data).Should(Equal(map[string]string{infraCloudConfKey: vsphereIniConfig, "extra": "bar"}))
+	})
+
+	DescribeTable("should refuse secrets carrying credentials",
+		func(data map[string][]byte, expectedError string) {
+			_, err := configMapFromSecret(makeCloudConfigSecret(data))
+			Expect(err).Should(MatchError(expectedError))
+		},
+		Entry("INI password setting", map[string][]byte{infraCloudConfKey: []byte("[Global]\nuser = \"admin\"\npassword = \"pass\"\n")},
+			`key foo of secret openshift-config/test-config-secret sets credentials in "user", which can not be synced to the cloud-config ConfigMap`),
+		Entry("JSON client secret setting", map[string][]byte{infraCloudConfKey: []byte(`{"cloud":"AzurePublicCloud","aadClientSecret":"secret"}`)},
+			`key foo of secret openshift-config/test-config-secret sets credentials in "aadClientSecret", which can not be synced to the cloud-config ConfigMap`),
+		Entry("credentials key", map[string][]byte{infraCloudConfKey: []byte(vsphereIniConfig), "vcenter.example.com.password": []byte("pass")},
+			"key vcenter.example.com.password of secret openshift-config/test-config-secret holds credentials, which can not be synced to the cloud-config ConfigMap"),
+	)
 })
 
 var _ = Describe("prepareSourceConfigMap reconciler method", func() {
@@ -143,6 +226,24 @@ var _ = Describe("prepareSourceConfigMap reconciler method", func() {
 		Expect(err.Error()).Should(BeEquivalentTo("key foo specified in infra resource does not found in source configmap openshift-config/test-config"))
 	})
 
+	It("config preparation should fail if key from infra resource does not found in secret", func() {
+		brokenSecret := makeCloudConfigSecret(map[string][]byte{"hehehehehe": []byte("bar")})
+		sourceCM, err := configMapFromSecret(brokenSecret)
+		Expect(err).Should(Succeed())
+		_, err = reconciler.prepareSourceConfigMap(sourceCM, infra)
+		Expect(err).Should(Not(Succeed()))
+		Expect(err.Error()).Should(BeEquivalentTo("key foo specified in infra resource does not found in source configmap openshift-config/test-config-secret"))
+	})
+
+	It("prepared config from secret should be equal with managed one", func() {
+		secret := makeCloudConfigSecret(map[string][]byte{infraCloudConfKey: []byte(defaultAzureConfig)})
+		sourceCM, err := configMapFromSecret(secret)
+		Expect(err).Should(Succeed())
+		preparedConfig, err := reconciler.prepareSourceConfigMap(sourceCM, infra)
+		Expect(err).Should(Succeed())
+		Expect(reconciler.isCloudConfigEqual(preparedConfig, managedCloudConfig)).Should(BeTrue())
+	})
+
 	It("config preparation should not touch extra fields in infra ConfigMap", func() {
 		extendedInfraConfig := infraCloudConfig.DeepCopy()
 		extendedInfraConfig.Data = map[string]string{infraCloudConfKey: "{}", "{}": "{}"}
@@ -152,6 +253,65 @@ var _ = Describe("prepareSourceConfigMap reconciler method", func() {
 		Expect(ok).Should(BeTrue())
 		Expect(len(preparedConfig.Data)).Should(BeEquivalentTo(2))
 	})
+
+	It("key from infra resource should take precedence over the default key", func() {
+		changedKeyInfra := infra.DeepCopy()
+		changedKeyInfra.Spec.CloudConfig.Key = "new-key"
+		extendedInfraConfig := infraCloudConfig.DeepCopy()
+		extendedInfraConfig.Data = map[string]string{defaultConfigKey: "{}", "new-key": defaultAzureConfig}
+		preparedConfig, err := reconciler.prepareSourceConfigMap(extendedInfraConfig, changedKeyInfra)
+		Expect(err).Should(Succeed())
+		Expect(preparedConfig.Data).Should(Equal(map[string]string{defaultConfigKey: defaultAzureConfig}))
+		Expect(preparedConfig.Annotations).Should(HaveKeyWithValue(cloudConfigSourceKeyAnnotation, "new-key"))
+	})
+})
+
+var _ = Describe("transformCloudConfig reconciler method", func() {
+	var (
+		reconciler *CloudConfigReconciler
+		recorder   *record.FakeRecorder
+	)
+	infra := makeInfrastructureResource(configv1.VSpherePlatformType)
+	infra.Status = makeInfraStatus(infra.Spec.PlatformSpec.Type)
+	network := makeNetworkResource()
+	validator := cloud.GetCloudConfigValidator(infra.Status.PlatformStatus)
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(32)
+		reconciler = &CloudConfigReconciler{
+			ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+				Recorder:         recorder,
+				ManagedNamespace: testManagedNamespace,
+			},
+		}
+	})
+
+	It("should return the transformer output if it can be read by the cloud provider", func() {
+		Expect(validator).NotTo(BeNil())
+		output, err := reconciler.transformCloudConfig(vsphere.CloudConfigTransformer, validator, vsphereIniConfig, infra, network)
+		Expect(err).To(Succeed())
+		Expect(output).To(MatchYAML(vsphereYamlConfig))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should refuse invalid transformer output", func() {
+		garbageTransformer := func(string, *configv1.Infrastructure, *configv1.Network) (string, error) {
+			return "vcenter: [", nil
+		}
+		output, err := reconciler.transformCloudConfig(garbageTransformer, validator, vsphereIniConfig, infra, network)
+		Expect(err).To(MatchError(ContainSubstring("transformed cloud config is invalid, refusing to sync it: cloud config can not be read by the vSphere cloud provider")))
+		Expect(output).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning CloudConfigValidationFailed")))
+	})
+
+	It("should not validate output for platforms without a validator", func() {
+		passthroughTransformer := func(source string, _ *configv1.Infrastructure, _ *configv1.Network) (string, error) {
+			return source, nil
+		}
+		output, err := reconciler.transformCloudConfig(passthroughTransformer, nil, "vcenter: [", infra, network)
+		Expect(err).To(Succeed())
+		Expect(output).To(Equal("vcenter: ["))
+	})
 })
 
 var _ = Describe("Cloud config sync controller", func() {
@@ -317,6 +477,60 @@ var _ = Describe("Cloud config sync controller", func() {
 		}).Should(Equal(defaultAzureConfig))
 	})
 
+	It("config should be recreated promptly if own cloud-config deleted", func() {
+		syncedCloudConfigMap := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return cl.Get(ctx, syncedConfigMapKey, syncedCloudConfigMap)
+		}, timeout).Should(Succeed())
+		deletedUID := syncedCloudConfigMap.UID
+
+		Expect(cl.Delete(ctx, syncedCloudConfigMap)).To(Succeed())
+
+		// Recreation must not wait for the cache resync period.
+		Eventually(func(g Gomega) {
+			recreated := &corev1.ConfigMap{}
+			g.Expect(cl.Get(ctx, syncedConfigMapKey, recreated)).To(Succeed())
+			g.Expect(recreated.UID).NotTo(Equal(deletedUID))
+			g.Expect(recreated.Data).To(HaveKeyWithValue(defaultConfigKey, defaultAzureConfig))
+		}, timeout).Should(Succeed())
+	})
+
+	It("config should be synced up again once the managed namespace is recreated", func() {
+		syncedCloudConfigMap := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return cl.Get(ctx, syncedConfigMapKey, syncedCloudConfigMap)
+		}, timeout).Should(Succeed())
+		deletedUID := syncedCloudConfigMap.UID
+
+		By("Deleting the managed namespace")
+		managedNamespace := &corev1.Namespace{}
+		Expect(cl.Get(ctx, client.ObjectKey{Name: targetNamespaceName}, managedNamespace)).To(Succeed())
+		Expect(cl.Delete(ctx, managedNamespace)).To(Succeed())
+
+		// There is no namespace controller in envtest, do its job: remove namespace content and finalize it.
+		Expect(cl.Delete(ctx, syncedCloudConfigMap)).To(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(cl.Get(ctx, client.ObjectKey{Name: targetNamespaceName}, managedNamespace)).To(Succeed())
+			managedNamespace.Spec.Finalizers = nil
+			g.Expect(cl.SubResource("finalize").Update(ctx, managedNamespace)).To(Succeed())
+		}, timeout).Should(Succeed())
+		Eventually(func() error {
+			return cl.Get(ctx, client.ObjectKey{Name: targetNamespaceName}, &corev1.Namespace{})
+		}, timeout).Should(MatchError(apierrors.IsNotFound, "IsNotFound"))
+
+		By("Recreating the managed namespace")
+		recreatedNamespace := &corev1.Namespace{}
+		recreatedNamespace.SetName(targetNamespaceName)
+		Expect(cl.Create(ctx, recreatedNamespace)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			recreated := &corev1.ConfigMap{}
+			g.Expect(cl.Get(ctx, syncedConfigMapKey, recreated)).To(Succeed())
+			g.Expect(recreated.UID).NotTo(Equal(deletedUID))
+			g.Expect(recreated.Data).To(HaveKeyWithValue(defaultConfigKey, defaultAzureConfig))
+		}, timeout).Should(Succeed())
+	})
+
 	It("config should not be updated if source and target config content are identical", func() {
 		syncedCloudConfigMap := &corev1.ConfigMap{}
 		Eventually(func() error {
@@ -349,6 +563,37 @@ var _ = Describe("Cloud config sync controller", func() {
 		}).Should(Equal(changedInfraConfigString))
 	})
 
+	It("config should be re-synced from the new key and the old key pruned if infra cloud config key changed", func() {
+		changedConfigString := `{"cloud":"AzurePublicCloud","tenantId":"0000000-1234-1234-0000-000000000000","subscriptionId":"0000000-0000-0000-0000-000000000000","vmType":"standard","putVMSSVMBatchSize":0,"enableMigrateToIPBasedBackendPoolAPI":false,"clusterServiceLoadBalancerHealthProbeMode":"shared"}`
+		changedManagedConfig := managedCloudConfig.DeepCopy()
+		changedManagedConfig.Data = map[string]string{infraCloudConfKey: defaultAzureConfig, "new-key": changedConfigString}
+		Expect(cl.Update(ctx, changedManagedConfig)).Should(Succeed())
+
+		Eventually(func(g Gomega) map[string]string {
+			syncedCloudConfigMap := &corev1.ConfigMap{}
+			g.Expect(cl.Get(ctx, syncedConfigMapKey, syncedCloudConfigMap)).To(Succeed())
+			return syncedCloudConfigMap.Data
+		}, timeout).Should(Equal(map[string]string{defaultConfigKey: defaultAzureConfig, "new-key": changedConfigString}))
+
+		infra := &configv1.Infrastructure{}
+		Expect(cl.Get(ctx, client.ObjectKey{Name: infrastructureResourceName}, infra)).To(Succeed())
+		infra.Spec.CloudConfig.Key = "new-key"
+		Expect(cl.Update(ctx, infra)).To(Succeed())
+
+		Eventually(func(g Gomega) map[string]string {
+			syncedCloudConfigMap := &corev1.ConfigMap{}
+			g.Expect(cl.Get(ctx, syncedConfigMapKey, syncedCloudConfigMap)).To(Succeed())
+			return syncedCloudConfigMap.Data
+		}, timeout).Should(Equal(map[string]string{defaultConfigKey: changedConfigString}))
+
+		// The old key should stay pruned on following syncs
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{})
+		Expect(err).Should(Succeed())
+		syncedCloudConfigMap := &corev1.ConfigMap{}
+		Expect(cl.Get(ctx, syncedConfigMapKey, syncedCloudConfigMap)).To(Succeed())
+		Expect(syncedCloudConfigMap.Data).Should(Equal(map[string]string{defaultConfigKey: changedConfigString}))
+	})
+
 	It("all keys from cloud-config should be synced", func() {
 
 		changedInfraConfigString := `{"cloud":"AzurePublicCloud","tenantId":"0000000-1234-1234-0000-000000000000","subscriptionId":"0000000-0000-0000-0000-000000000000","vmType":"standard","putVMSSVMBatchSize":0,"enableMigrateToIPBasedBackendPoolAPI":false,"clusterServiceLoadBalancerHealthProbeMode":"shared"}`
@@ -393,13 +638,46 @@ var _ = Describe("Cloud config sync reconciler", func() {
 	It("reconcile should fail if no infra resource found", func() {
 		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
 		Expect(err.Error()).Should(BeEquivalentTo("infrastructures.config.openshift.io \"cluster\" not found"))
+		// Transient errors are requeued with backoff
+		Expect(err).NotTo(MatchError(reconcile.TerminalError(nil)))
 	})
 
 	It("should fail if no PlatformStatus in infra resource presented ", func() {
 		infraResource := makeInfrastructureResource(configv1.AWSPlatformType)
 		Expect(cl.Create(ctx, infraResource)).To(Succeed())
 		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
-		Expect(err.Error()).Should(BeEquivalentTo("platformStatus is required"))
+		Expect(err).Should(MatchError(ContainSubstring("platformStatus is required")))
+		// Permanent errors are not requeued
+		Expect(err).Should(MatchError(reconcile.TerminalError(nil)))
+	})
+
+	It("should record a warning event after repeated transient failures", func() {
+		recorder := record.NewFakeRecorder(32)
+		reconciler.Recorder = recorder
+
+		for i := 1; i < cloudConfigSyncFailuresEventThreshold; i++ {
+			_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+			Expect(err).To(HaveOccurred())
+		}
+		Expect(recorder.Events).To(BeEmpty())
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning CloudConfigSyncFailing")))
+	})
+
+	It("should reset consecutive failures once the error is permanent", func() {
+		for i := 1; i < cloudConfigSyncFailuresEventThreshold; i++ {
+			_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+			Expect(err).To(HaveOccurred())
+		}
+		Expect(reconciler.consecutiveFailures.Load()).To(BeEquivalentTo(cloudConfigSyncFailuresEventThreshold - 1))
+
+		infraResource := makeInfrastructureResource(configv1.AWSPlatformType)
+		Expect(cl.Create(ctx, infraResource)).To(Succeed())
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).Should(MatchError(reconcile.TerminalError(nil)))
+		Expect(reconciler.consecutiveFailures.Load()).To(BeZero())
 	})
 
 	It("should skip config sync for AWS platform if there is no reference in infra resource", func() {
@@ -446,6 +724,18 @@ var _ = Describe("Cloud config sync reconciler", func() {
 		allCMs := &corev1.ConfigMapList{}
 		Expect(cl.List(ctx, allCMs, &client.ListOptions{Namespace: targetNamespaceName})).To(Succeed())
 		Expect(len(allCMs.Items)).To(BeZero())
+
+		co := &configv1.ClusterOperator{}
+		Expect(cl.Get(ctx, client.ObjectKey{Name: clusterOperatorName}, co)).To(Succeed())
+		var available *configv1.ClusterOperatorStatusCondition
+		for i := range co.Status.Conditions {
+			if co.Status.Conditions[i].Type == cloudConfigControllerAvailableCondition {
+				available = &co.Status.Conditions[i]
+			}
+		}
+		Expect(available).NotTo(BeNil())
+		Expect(available.Message).To(Equal(
+			"Cloud Config Controller works as expected, cloud config sync is not needed on platform BareMetal"))
 	})
 
 	It("should perform config sync for Azure platform", func() {
@@ -461,6 +751,219 @@ var _ = Describe("Cloud config sync reconciler", func() {
 		Expect(len(allCMs.Items)).To(BeEquivalentTo(1))
 	})
 
+	It("should sync immutable source config to a mutable target", func() {
+		infraCloudConfig := makeInfraCloudConfig()
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(infraCloudConfig), infraCloudConfig)).To(Succeed())
+		infraCloudConfig.Immutable = ptr.To(true)
+		Expect(cl.Update(ctx, infraCloudConfig)).To(Succeed())
+
+		infraResource := makeInfrastructureResource(configv1.AzurePlatformType)
+		Expect(cl.Create(ctx, infraResource)).To(Succeed())
+		infraResource.Status = makeInfraStatus(infraResource.Spec.PlatformSpec.Type)
+		Expect(cl.Status().Update(ctx, infraResource.DeepCopy())).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(BeNil())
+
+		syncedCloudConfigMap := &corev1.ConfigMap{}
+		syncedConfigMapKey := client.ObjectKey{Namespace: targetNamespaceName, Name: syncedCloudConfigMapName}
+		Expect(cl.Get(ctx, syncedConfigMapKey, syncedCloudConfigMap)).To(Succeed())
+		Expect(syncedCloudConfigMap.Immutable).To(BeNil())
+		Expect(syncedCloudConfigMap.Data).To(HaveKeyWithValue(defaultConfigKey, defaultAzureConfig))
+	})
+
+	It("should replace a previously synced immutable target config", func() {
+		infraResource := makeInfrastructureResource(configv1.AzurePlatformType)
+		Expect(cl.Create(ctx, infraResource)).To(Succeed())
+		infraResource.Status = makeInfraStatus(infraResource.Spec.PlatformSpec.Type)
+		Expect(cl.Status().Update(ctx, infraResource.DeepCopy())).To(Succeed())
+
+		immutableTarget := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: syncedCloudConfigMapName, Namespace: targetNamespaceName},
+			Data:       map[string]string{defaultConfigKey: "stale"},
+			Immutable:  ptr.To(true),
+		}
+		Expect(cl.Create(ctx, immutableTarget)).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(BeNil())
+
+		syncedCloudConfigMap := &corev1.ConfigMap{}
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(immutableTarget), syncedCloudConfigMap)).To(Succeed())
+		Expect(syncedCloudConfigMap.UID).NotTo(Equal(immutableTarget.UID))
+		Expect(syncedCloudConfigMap.Immutable).To(BeNil())
+		Expect(syncedCloudConfigMap.Data).To(HaveKeyWithValue(defaultConfigKey, defaultAzureConfig))
+	})
+
+	It("should record an event naming changed keys after the managed config is updated", func() {
+		recorder := record.NewFakeRecorder(32)
+		reconciler.Recorder = recorder
+
+		infraResource := makeInfrastructureResource(configv1.AzurePlatformType)
+		Expect(cl.Create(ctx, infraResource)).To(Succeed())
+		infraResource.Status = makeInfraStatus(infraResource.Spec.PlatformSpec.Type)
+		Expect(cl.Status().Update(ctx, infraResource.DeepCopy())).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(Equal("Normal CloudConfigSynced Synced cloud config keys: added [cloud.conf]")))
+
+		infraCloudConfig := makeInfraCloudConfig()
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(infraCloudConfig), infraCloudConfig)).To(Succeed())
+		infraCloudConfig.Data[infraCloudConfKey] = strings.Replace(defaultAzureConfig, `"vmType":"standard"`, `"vmType":"vmss"`, 1)
+		Expect(cl.Update(ctx, infraCloudConfig)).To(Succeed())
+
+		_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(Equal("Normal CloudConfigSynced Synced cloud config keys: modified [cloud.conf]")))
+	})
+
+	It("should transform fallback infra config for vSphere platform when managed config is absent", func() {
+		managedCloudConfig := makeManagedCloudConfig()
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(managedCloudConfig), managedCloudConfig)).To(
+			MatchError(apierrors.IsNotFound, "IsNotFound"))
+
+		infraCloudConfig := makeInfraCloudConfig()
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(infraCloudConfig), infraCloudConfig)).To(Succeed())
+		infraCloudConfig.Data = map[string]string{infraCloudConfKey: vsphereIniConfig}
+		Expect(cl.Update(ctx, infraCloudConfig)).To(Succeed())
+
+		infraResource := makeInfrastructureResource(configv1.VSpherePlatformType)
+		Expect(cl.Create(ctx, infraResource)).To(Succeed())
+		infraResource.Status = makeInfraStatus(infraResource.Spec.PlatformSpec.Type)
+		Expect(cl.Status().Update(ctx, infraResource.DeepCopy())).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(BeNil())
+
+		syncedCloudConfigMap := &corev1.ConfigMap{}
+		syncedConfigMapKey := client.ObjectKey{Namespace: targetNamespaceName, Name: syncedCloudConfigMapName}
+		Expect(cl.Get(ctx, syncedConfigMapKey, syncedCloudConfigMap)).To(Succeed())
+		Expect(syncedCloudConfigMap.Data[defaultConfigKey]).To(MatchYAML(vsphereYamlConfig))
+		// The raw infra config must not be synced as-is
+		Expect(syncedCloudConfigMap.Data[defaultConfigKey]).NotTo(ContainSubstring("[Global]"))
+	})
+
+	It("should report the vSphere transformer in the available condition on vSphere platform", func() {
+		infraCloudConfig := makeInfraCloudConfig()
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(infraCloudConfig), infraCloudConfig)).To(Succeed())
+		infraCloudConfig.Data = map[string]string{infraCloudConfKey: vsphereIniConfig}
+		Expect(cl.Update(ctx, infraCloudConfig)).To(Succeed())
+
+		infraResource := makeInfrastructureResource(configv1.VSpherePlatformType)
+		Expect(cl.Create(ctx, infraResource)).To(Succeed())
+		infraResource.Status = makeInfraStatus(infraResource.Spec.PlatformSpec.Type)
+		Expect(cl.Status().Update(ctx, infraResource.DeepCopy())).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(BeNil())
+
+		co := &configv1.ClusterOperator{}
+		Expect(cl.Get(ctx, client.ObjectKey{Name: clusterOperatorName}, co)).To(Succeed())
+		var available *configv1.ClusterOperatorStatusCondition
+		for i := range co.Status.Conditions {
+			if co.Status.Conditions[i].Type == cloudConfigControllerAvailableCondition {
+				available = &co.Status.Conditions[i]
+			}
+		}
+		Expect(available).NotTo(BeNil())
+		Expect(available.Status).To(Equal(configv1.ConditionTrue))
+		Expect(available.Message).To(Equal(
+			"Cloud Config Controller works as expected, last sync on platform VSphere used the VSphere transformer"))
+	})
+
+	It("should count config transform failures for the platform", func() {
+		getFailures := func() float64 {
+			metric := &dto.Metric{}
+			Expect(configTransformFailures.WithLabelValues(string(configv1.VSpherePlatformType)).Write(metric)).To(Succeed())
+			return metric.GetCounter().GetValue()
+		}
+		initialFailures := getFailures()
+
+		infraCloudConfig := makeInfraCloudConfig()
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(infraCloudConfig), infraCloudConfig)).To(Succeed())
+		infraCloudConfig.Data = map[string]string{infraCloudConfKey: ":"}
+		Expect(cl.Update(ctx, infraCloudConfig)).To(Succeed())
+
+		infraResource := makeInfrastructureResource(configv1.VSpherePlatformType)
+		Expect(cl.Create(ctx, infraResource)).To(Succeed())
+		infraResource.Status = makeInfraStatus(infraResource.Spec.PlatformSpec.Type)
+		Expect(cl.Status().Update(ctx, infraResource.DeepCopy())).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(MatchError(ContainSubstring("failed to read the cloud.conf")))
+		Expect(getFailures()).To(Equal(initialFailures + 1))
+	})
+
+	It("should perform config sync from secret if it is configured", func() {
+		reconciler.CloudConfigSecretName = cloudConfigSecretName
+		secret := makeCloudConfigSecret(map[string][]byte{infraCloudConfKey: []byte(vsphereIniConfig)})
+		Expect(cl.Create(ctx, secret)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(cl.Delete(ctx, secret)).To(Succeed())
+		})
+
+		infraResource := makeInfrastructureResource(configv1.VSpherePlatformType)
+		Expect(cl.Create(ctx, infraResource)).To(Succeed())
+		infraResource.Status = makeInfraStatus(infraResource.Spec.PlatformSpec.Type)
+		Expect(cl.Status().Update(ctx, infraResource.DeepCopy())).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(BeNil())
+
+		syncedCloudConfigMap := &corev1.ConfigMap{}
+		syncedConfigMapKey := client.ObjectKey{Namespace: targetNamespaceName, Name: syncedCloudConfigMapName}
+		Expect(cl.Get(ctx, syncedConfigMapKey, syncedCloudConfigMap)).To(Succeed())
+		Expect(syncedCloudConfigMap.Data[defaultConfigKey]).To(MatchYAML(vsphereYamlConfig))
+		initialCMresourceVersion := syncedCloudConfigMap.ResourceVersion
+
+		By("Reconciling again with the same secret content")
+		_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(BeNil())
+		Expect(cl.Get(ctx, syncedConfigMapKey, syncedCloudConfigMap)).To(Succeed())
+		Expect(syncedCloudConfigMap.ResourceVersion).To(Equal(initialCMresourceVersion))
+	})
+
+	It("should refuse config sync from secret carrying credentials", func() {
+		reconciler.CloudConfigSecretName = cloudConfigSecretName
+		secret := makeCloudConfigSecret(map[string][]byte{infraCloudConfKey: []byte(vsphereIniConfig + "\npassword = \"pass\"\n")})
+		Expect(cl.Create(ctx, secret)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(cl.Delete(ctx, secret)).To(Succeed())
+		})
+
+		infraResource := makeInfrastructureResource(configv1.VSpherePlatformType)
+		Expect(cl.Create(ctx, infraResource)).To(Succeed())
+		infraResource.Status = makeInfraStatus(infraResource.Spec.PlatformSpec.Type)
+		Expect(cl.Status().Update(ctx, infraResource.DeepCopy())).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(MatchError(ContainSubstring(`sets credentials in "password"`)))
+		Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+
+		syncedCloudConfigMap := &corev1.ConfigMap{}
+		syncedConfigMapKey := client.ObjectKey{Namespace: targetNamespaceName, Name: syncedCloudConfigMapName}
+		Expect(cl.Get(ctx, syncedConfigMapKey, syncedCloudConfigMap)).To(MatchError(apierrors.IsNotFound, "IsNotFound"))
+	})
+
+	It("should fail config sync from secret if key from infra resource is not found", func() {
+		reconciler.CloudConfigSecretName = cloudConfigSecretName
+		secret := makeCloudConfigSecret(map[string][]byte{"hehehehehe": []byte("bar")})
+		Expect(cl.Create(ctx, secret)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(cl.Delete(ctx, secret)).To(Succeed())
+		})
+
+		infraResource := makeInfrastructureResource(configv1.VSpherePlatformType)
+		Expect(cl.Create(ctx, infraResource)).To(Succeed())
+		infraResource.Status = makeInfraStatus(infraResource.Spec.PlatformSpec.Type)
+		Expect(cl.Status().Update(ctx, infraResource.DeepCopy())).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(MatchError(ContainSubstring("key foo specified in infra resource does not found in source configmap openshift-config/test-config-secret")))
+		Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+	})
+
 	AfterEach(func() {
 		deleteOptions := &client.DeleteOptions{
 			GracePeriodSeconds: ptr.To[int64](0),
@@ -19,15 +19,30 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/library-go/pkg/cloudprovider"
 	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
+	"golang.org/x/sync/errgroup"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,6 +50,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/cloud"
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/cloud/common"
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
 	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/controllers/resourceapply"
 )
@@ -43,23 +59,131 @@ const (
 	externalFeatureGateName = "cluster"
 	kcmResourceName         = "cluster"
 
+	cloudCredentialResourceName = "cluster"
+	dnsResourceName             = "cluster"
+	schedulerResourceName       = "cluster"
+
 	// Condition type for Cloud Controller ownership
 	cloudControllerOwnershipCondition = "CloudControllerOwner"
+
+	// Maximum number of resources applied concurrently
+	applyResourcesConcurrency = 4
+
+	// managedNamespaceLabel records on the cluster operator the namespace operands were last applied to,
+	// so operands left in the previous namespace can be pruned once the managed namespace changes.
+	managedNamespaceLabel = "cloud-controller-manager.openshift.io/managed-namespace"
+
+	// forceSyncAnnotation set on the cluster operator to a new value makes the operator rewrite all operands
+	// once, even if they are up to date. The handled value is recorded with forceSyncHandledAnnotation.
+	forceSyncAnnotation        = "operator.openshift.io/force-sync"
+	forceSyncHandledAnnotation = "operator.openshift.io/force-sync-handled"
+
+	// operandStatusAnnotation on the cluster operator summarizes readiness of each operand workload,
+	// so it is visible which operand is unhealthy when describing the cluster operator.
+	operandStatusAnnotation = "operator.openshift.io/operand-status"
+
+	// namespaceOwnerLabel on the managed namespace names the operator owning operands in it. The operator
+	// refuses to manage operands in a namespace owned by someone else, instead of fighting over them.
+	namespaceOwnerLabel = "cloud-controller-manager.openshift.io/namespace-owner"
+
+	// deploymentRevisionAnnotation is set by the deployment controller to the revision of the deployment pod
+	// template, starting at 1 for the first one.
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
 )
 
+// OperandOverrides override operand settings of the provider manifests. Settings which are not set
+// keep the provider defaults.
+type OperandOverrides struct {
+	// AllocateNodeCIDRs, if set, overrides whether cloud-controller-manager allocates node CIDRs.
+	AllocateNodeCIDRs *bool
+	// CCMVerbosity, if set, overrides the log verbosity of the cloud-controller-manager container.
+	CCMVerbosity *int
+	// ExtraHostPathMounts are additional host paths mounted into operand containers.
+	ExtraHostPathMounts []config.HostPathMount
+	// PDBPolicy, if set, overrides the disruption policy of operand PodDisruptionBudgets.
+	PDBPolicy *config.PDBPolicy
+	// ExtraNodeManagerArgs are appended to the cloud-node-manager container command.
+	ExtraNodeManagerArgs []string
+	// ServiceAccountAnnotations are set on service accounts used by operands, e.g. to bind them to a cloud identity.
+	ServiceAccountAnnotations map[string]string
+	// InitContainerResources, if set, overrides resource requirements of operand init containers.
+	InitContainerResources *corev1.ResourceRequirements
+	// NodeManagerPriorityClassName, if set, overrides the priority class of node-manager pods.
+	NodeManagerPriorityClassName string
+	// CloudAPIReadinessProbe adds a readiness probe checking the cloud metadata endpoint to the
+	// cloud-controller-manager container, on platforms which have one.
+	CloudAPIReadinessProbe bool
+	// LoggingFormat, if set, is the log format of the cloud-controller-manager container.
+	LoggingFormat config.LoggingFormat
+	// ResourceAutosizing, if set, enables scaling of operand resource requests with the cluster node count.
+	ResourceAutosizing *config.ResourceAutosizing
+	// CloudEndpointOverrides are injected as environment variables into operand containers.
+	CloudEndpointOverrides []config.CloudEndpointOverride
+	// ExtraVolumes are added to every operand pod template.
+	ExtraVolumes []corev1.Volume
+	// ExtraVolumeMounts mount ExtraVolumes into every operand container.
+	ExtraVolumeMounts []corev1.VolumeMount
+	// StartupTimeouts limit, per platform, how long operand container scripts may spend before
+	// the operand binary is executed.
+	StartupTimeouts map[configv1.PlatformType]time.Duration
+	// ConfigureCloudRoutes, if set, enables the cloud route controller of cloud-controller-manager.
+	ConfigureCloudRoutes *bool
+	// AzureCredentials, if set, configures where Azure operands read cloud credentials from.
+	AzureCredentials *config.AzureCredentials
+	// PodSysctls are set, per platform, on operand pods.
+	PodSysctls map[configv1.PlatformType]config.Sysctls
+	// HostAliases are added to /etc/hosts of operand pods.
+	HostAliases []corev1.HostAlias
+	// NodeManagerMinReadySeconds, if set, is how long new node-manager pods have to be ready before they are available.
+	NodeManagerMinReadySeconds *int32
+	// Affinity is merged into the affinity of controller pods.
+	Affinity *corev1.Affinity
+}
+
 // CloudOperatorReconciler reconciles a ClusterOperator object
 type CloudOperatorReconciler struct {
 	ClusterOperatorStatusClient
+	OperandOverrides
 	Scheme            *runtime.Scheme
 	watcher           ObjectWatcher
 	ImagesFile        string
 	FeatureGateAccess featuregates.FeatureGateAccess
+	// DryRun makes the reconciler send operand writes as dry-run requests, reporting changes
+	// it would make with events and logs instead of persisting them.
+	DryRun bool
+	// StrippedAnnotations are removed from operands before they are applied, so they never
+	// make the operator update operands.
+	StrippedAnnotations []string
+	// NodeManagerConfigHashSources are names of config maps in the managed namespace whose changes restart
+	// node-manager pods, although the pods do not reference them.
+	NodeManagerConfigHashSources []string
+	// StartupScriptsFromConfigMap makes operand containers run their command scripts from rendered
+	// config maps instead of inlining them into container commands.
+	StartupScriptsFromConfigMap bool
+	// LogOperandFlags makes cloud-controller-manager containers echo their effective flags at startup.
+	LogOperandFlags bool
+	// TolerateRoleBindingFailures makes the reconciler apply the remaining operands when some role bindings
+	// fail to apply, e.g. bindings in namespaces which do not exist yet, reporting the failed bindings afterwards.
+	TolerateRoleBindingFailures bool
+	// DisablePodDisruptionBudget makes the reconciler omit operand PodDisruptionBudgets,
+	// pruning the ones it created before.
+	DisablePodDisruptionBudget bool
+	// DropOperandPrivilegesToUser is the ID of the non-root user operand binaries drop to after their startup
+	// scripts run. Operands keep running as root when it is zero.
+	DropOperandPrivilegesToUser int64
+	// RestartOnDNSChange makes the reconciler restart operand pods when the cluster DNS config changes.
+	RestartOnDNSChange bool
+	// IgnoreUnsupportedFeatureGates makes the reconciler only log feature gates which are not supported
+	// on the cluster platform, instead of reporting the operator as degraded.
+	IgnoreUnsupportedFeatureGates bool
 }
 
 // +kubebuilder:rbac:groups=config.openshift.io,resources=clusteroperators,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=config.openshift.io,resources=clusteroperators/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=config.openshift.io,resources=clusteroperators/finalizers,verbs=update
 // +kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=dnses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=schedulers,verbs=get;list;watch
 
 // Reconcile will process the cloud-controller-manager clusterOperator
 func (r *CloudOperatorReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
@@ -77,12 +201,7 @@ func (r *CloudOperatorReconciler) Reconcile(ctx context.Context, _ ctrl.Request)
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		klog.Errorf("Unable to retrive Infrastructure object: %v", err)
-
-		if err := r.setStatusDegraded(ctx, err, conditionOverrides); err != nil {
-			klog.Errorf("Error syncing ClusterOperatorStatus: %v", err)
-			return ctrl.Result{}, fmt.Errorf("error syncing ClusterOperatorStatus: %v", err)
-		}
-		return ctrl.Result{}, err
+		return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
 	}
 
 	allowedToProvision, err := r.provisioningAllowed(ctx, infra, conditionOverrides)
@@ -96,35 +215,133 @@ func (r *CloudOperatorReconciler) Reconcile(ctx context.Context, _ ctrl.Request)
 	clusterProxy := &configv1.Proxy{}
 	if err := r.Get(ctx, client.ObjectKey{Name: proxyResourceName}, clusterProxy); err != nil && !errors.IsNotFound(err) {
 		klog.Errorf("Unable to retrive Proxy object: %v", err)
-
-		if err := r.setStatusDegraded(ctx, err, conditionOverrides); err != nil {
-			klog.Errorf("Error syncing ClusterOperatorStatus: %v", err)
-			return ctrl.Result{}, fmt.Errorf("error syncing ClusterOperatorStatus: %v", err)
-		}
-		return ctrl.Result{}, err
+		return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
 	}
 
 	operatorConfig, err := config.ComposeConfig(infra, clusterProxy, r.ImagesFile, r.ManagedNamespace, r.FeatureGateAccess)
 	if err != nil {
 		klog.Errorf("Unable to build operator config %s", err)
-		if err := r.setStatusDegraded(ctx, err, conditionOverrides); err != nil {
-			klog.Errorf("Error syncing ClusterOperatorStatus: %v", err)
-			return ctrl.Result{}, fmt.Errorf("error syncing ClusterOperatorStatus: %v", err)
+		return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
+	}
+	conditionOverrides = append(conditionOverrides, cloud.GetStatusConditions(operatorConfig)...)
+
+	if err := r.checkFeatureGates(infra.Status.PlatformStatus.Type); err != nil {
+		klog.Errorf("Unsupported feature gates are enabled: %v", err)
+		return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
+	}
+
+	credentialsMode, err := r.getCredentialsMode(ctx)
+	if err != nil {
+		klog.Errorf("Unable to determine cloud credentials mode: %v", err)
+		return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
+	}
+	operatorConfig.CredentialsMode = credentialsMode
+	operatorConfig.StartupScriptsFromConfigMap = r.StartupScriptsFromConfigMap
+	operatorConfig.NodeManagerConfigHashSources = r.NodeManagerConfigHashSources
+	operatorConfig.LogEffectiveFlags = r.LogOperandFlags
+	operatorConfig.DropPrivilegesToUser = r.DropOperandPrivilegesToUser
+	operatorConfig.DisablePodDisruptionBudget = r.DisablePodDisruptionBudget
+	operatorConfig.AllocateNodeCIDRs = r.AllocateNodeCIDRs
+	operatorConfig.CCMVerbosity = r.CCMVerbosity
+	operatorConfig.ExtraHostPathMounts = r.ExtraHostPathMounts
+	operatorConfig.PDBPolicy = r.PDBPolicy
+	operatorConfig.ExtraNodeManagerArgs = r.ExtraNodeManagerArgs
+	operatorConfig.ServiceAccountAnnotations = r.ServiceAccountAnnotations
+	operatorConfig.InitContainerResources = r.InitContainerResources
+	operatorConfig.NodeManagerPriorityClassName = r.NodeManagerPriorityClassName
+	operatorConfig.CloudAPIReadinessProbe = r.CloudAPIReadinessProbe
+	operatorConfig.LoggingFormat = r.LoggingFormat
+	operatorConfig.CloudEndpointOverrides = r.CloudEndpointOverrides
+	operatorConfig.ExtraVolumes = r.ExtraVolumes
+	operatorConfig.ExtraVolumeMounts = r.ExtraVolumeMounts
+	operatorConfig.StartupTimeouts = r.StartupTimeouts
+	operatorConfig.ConfigureCloudRoutes = r.ConfigureCloudRoutes
+	operatorConfig.AzureCredentials = r.AzureCredentials
+	operatorConfig.PodSysctls = r.PodSysctls
+	operatorConfig.HostAliases = r.HostAliases
+	operatorConfig.NodeManagerMinReadySeconds = r.NodeManagerMinReadySeconds
+	operatorConfig.Affinity = r.Affinity
+
+	if r.RestartOnDNSChange {
+		dnsConfigHash, err := r.getDNSConfigHash(ctx)
+		if err != nil {
+			klog.Errorf("Unable to determine cluster DNS config hash: %v", err)
+			return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
 		}
-		return ctrl.Result{}, err
+		operatorConfig.DNSConfigHash = dnsConfigHash
+	}
+
+	if r.ResourceAutosizing != nil {
+		nodeCount, err := r.countNodes(ctx)
+		if err != nil {
+			klog.Errorf("Unable to count cluster nodes for resource autosizing: %v", err)
+			return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
+		}
+		operatorConfig.ResourceAutosizing = r.ResourceAutosizing
+		operatorConfig.NodeCount = nodeCount
+	}
+
+	if err := r.checkNamespaceOwnership(ctx); err != nil {
+		klog.Errorf("Unable to manage operands in the managed namespace: %v", err)
+		return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
+	}
+
+	namespace, err := r.ensureNamespaceNodeSelector(ctx)
+	if err != nil {
+		klog.Errorf("Unable to set the node selector of the managed namespace: %v", err)
+		return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
+	}
+
+	defaultNodeSelector, err := r.getDefaultNodeSelector(ctx, namespace)
+	if err != nil {
+		klog.Errorf("Unable to determine default node selector of the managed namespace: %v", err)
+		return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
 	}
+	operatorConfig.DefaultNodeSelector = defaultNodeSelector
 
-	if err := r.sync(ctx, operatorConfig, conditionOverrides); err != nil {
+	resources, err := r.sync(ctx, operatorConfig, conditionOverrides)
+	if err != nil {
 		klog.Errorf("Unable to sync operands: %s", err)
-		if err := r.setStatusDegraded(ctx, err, conditionOverrides); err != nil {
-			klog.Errorf("Error syncing ClusterOperatorStatus: %v", err)
-			return ctrl.Result{}, fmt.Errorf("error syncing ClusterOperatorStatus: %v", err)
+		return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
+	}
+
+	if len(resources) == 0 {
+		klog.Infof("Platform %s requires no cloud-controller-manager. Skipping...", operatorConfig.GetPlatformNameString())
+		if err := r.setStatusNoOperandsRequired(ctx, operatorConfig.PlatformStatus.Type, conditionOverrides); err != nil {
+			klog.Errorf("Unable to sync cluster operator status: %s", err)
+			return ctrl.Result{}, err
+		}
+	} else if r.DryRun {
+		message := fmt.Sprintf("Cluster Cloud Controller Manager Operator is available at %s in dry-run mode, operand changes are not applied", r.ReleaseVersion)
+		if err := r.setStatusAvailableWithMessage(ctx, message, conditionOverrides); err != nil {
+			klog.Errorf("Unable to sync cluster operator status: %s", err)
+			return ctrl.Result{}, err
+		}
+	} else if rollingOut, err := r.rollingOutWorkloads(ctx, resources); err != nil {
+		klog.Errorf("Unable to check operand workloads rollout: %s", err)
+		return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
+	} else if len(rollingOut) > 0 {
+		// Rollouts are checked first, as pods of rolling out deployments are not available until they complete.
+		if err := r.setStatusOperandRollout(ctx, strings.Join(rollingOut, ", "), conditionOverrides); err != nil {
+			klog.Errorf("Unable to sync cluster operator status: %s", err)
+			return ctrl.Result{}, err
 		}
+	} else if notReady, err := r.notReadyWorkloads(ctx, resources); err != nil {
+		klog.Errorf("Unable to check operand workloads readiness: %s", err)
+		return ctrl.Result{}, r.reportDegraded(ctx, err, conditionOverrides)
+	} else if len(notReady) > 0 {
+		// Workload status changes are delivered by operand watches, so there is no need to requeue.
+		if err := r.setStatusWorkloadsNotReady(ctx, strings.Join(notReady, ", "), conditionOverrides); err != nil {
+			klog.Errorf("Unable to sync cluster operator status: %s", err)
+			return ctrl.Result{}, err
+		}
+	} else if err := r.setStatusAvailable(ctx, conditionOverrides); err != nil {
+		klog.Errorf("Unable to sync cluster operator status: %s", err)
 		return ctrl.Result{}, err
 	}
 
-	if err := r.setStatusAvailable(ctx, conditionOverrides); err != nil {
-		klog.Errorf("Unable to sync cluster operator status: %s", err)
+	if err := r.recordOperandStatus(ctx, resources); err != nil {
+		klog.Errorf("Unable to record operand status: %s", err)
 		return ctrl.Result{}, err
 	}
 
@@ -136,46 +353,607 @@ func (r *CloudOperatorReconciler) Reconcile(ctx context.Context, _ ctrl.Request)
 	return ctrl.Result{}, nil
 }
 
-func (r *CloudOperatorReconciler) sync(ctx context.Context, config config.OperatorConfig, conditionOverrides []configv1.ClusterOperatorStatusCondition) error {
+// reportDegraded sets the Degraded condition of the cluster operator for the error which failed the reconciliation,
+// and returns the error, or the one of syncing the cluster operator status if the condition could not be set.
+func (r *CloudOperatorReconciler) reportDegraded(ctx context.Context, err error, conditionOverrides []configv1.ClusterOperatorStatusCondition) error {
+	if err := r.setStatusDegraded(ctx, err, conditionOverrides); err != nil {
+		klog.Errorf("Error syncing ClusterOperatorStatus: %v", err)
+		return fmt.Errorf("error syncing ClusterOperatorStatus: %v", err)
+	}
+	return err
+}
+
+// checkNamespaceOwnership returns an error if the managed namespace is owned by another operator according
+// to its namespace owner label. A namespace without the label, or which does not exist yet, is not claimed.
+func (r *CloudOperatorReconciler) checkNamespaceOwnership(ctx context.Context) error {
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: r.ManagedNamespace}, namespace); errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if owner, ok := namespace.Labels[namespaceOwnerLabel]; ok && owner != clusterOperatorName {
+		return fmt.Errorf("managed namespace %s is owned by %q according to its %s label, refusing to manage operands in it",
+			r.ManagedNamespace, owner, namespaceOwnerLabel)
+	}
+	return nil
+}
+
+// checkFeatureGates returns an error if feature gates which are not supported on the given platform are enabled.
+// Feature gates are not checked until they are observed, operands are rendered with provider defaults meanwhile.
+func (r *CloudOperatorReconciler) checkFeatureGates(platform configv1.PlatformType) error {
+	if r.FeatureGateAccess == nil {
+		return nil
+	}
+	features, err := r.FeatureGateAccess.CurrentFeatureGates()
+	if err != nil {
+		return nil
+	}
+
+	err = config.ValidateFeatureGates(platform, features)
+	if err != nil && r.IgnoreUnsupportedFeatureGates {
+		klog.Warningf("Ignoring unsupported feature gates: %v", err)
+		return nil
+	}
+	return err
+}
+
+// getDNSConfigHash returns a hash of the cluster DNS config. No hash is returned if the DNS object
+// or its API is absent, so operands are not restarted because of it.
+func (r *CloudOperatorReconciler) getDNSConfigHash(ctx context.Context) (string, error) {
+	dns := &configv1.DNS{}
+	err := r.Get(ctx, client.ObjectKey{Name: dnsResourceName}, dns)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return config.GetDNSConfigHash(dns)
+}
+
+// ensureNamespaceNodeSelector sets the node selector annotation of the managed namespace to an empty selector,
+// so the cluster default node selector does not apply to operand pods, which select nodes themselves, e.g.
+// node managers run on every node, including control plane ones, before the cluster network is up.
+// The annotation is reapplied whenever it is removed or changed. The operator may only patch the default
+// managed namespace, if patching a configured one is forbidden, this is reported and operands are validated
+// against the default node selector which applies instead. The namespace is returned, as it would be stored
+// in dry-run mode, or nil if it does not exist yet.
+func (r *CloudOperatorReconciler) ensureNamespaceNodeSelector(ctx context.Context) (*corev1.Namespace, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: r.ManagedNamespace}, namespace); errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if selector, ok := namespace.Annotations[config.NamespaceNodeSelectorAnnotation]; ok && selector == "" {
+		return namespace, nil
+	}
+
+	operandClient, recorder := r.operandClient()
+	patched := namespace.DeepCopy()
+	metav1.SetMetaDataAnnotation(&patched.ObjectMeta, config.NamespaceNodeSelectorAnnotation, "")
+	if err := operandClient.Patch(ctx, patched, client.MergeFrom(namespace)); errors.IsForbidden(err) {
+		klog.Warningf("Not allowed to set empty %s annotation of the managed namespace %s, a default node selector may apply to operands: %v",
+			config.NamespaceNodeSelectorAnnotation, namespace.Name, err)
+		recorder.Eventf(namespace, corev1.EventTypeWarning, "NamespaceNodeSelectorNotEmpty",
+			"Expected an empty %s annotation, so the cluster default node selector does not apply to operands", config.NamespaceNodeSelectorAnnotation)
+		return namespace, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to set %s annotation of namespace %s: %w", config.NamespaceNodeSelectorAnnotation, namespace.Name, err)
+	}
+	klog.Infof("Set empty %s annotation of the managed namespace %s", config.NamespaceNodeSelectorAnnotation, namespace.Name)
+	recorder.Eventf(patched, corev1.EventTypeNormal, "NamespaceNodeSelectorSet",
+		"Set empty %s annotation, so the cluster default node selector does not apply to operands", config.NamespaceNodeSelectorAnnotation)
+	return patched, nil
+}
+
+// getDefaultNodeSelector returns the node selector the cluster adds to operand pods on admission, from the
+// node selector annotation of the given managed namespace or the default node selector of the cluster scheduler
+// config. The scheduler config is not considered if the Scheduler object or its API is absent.
+func (r *CloudOperatorReconciler) getDefaultNodeSelector(ctx context.Context, namespace *corev1.Namespace) (map[string]string, error) {
+	scheduler := &configv1.Scheduler{}
+	err := r.Get(ctx, client.ObjectKey{Name: schedulerResourceName}, scheduler)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		scheduler = nil
+	} else if err != nil {
+		return nil, err
+	}
+	return config.GetDefaultNodeSelector(scheduler, namespace)
+}
+
+// getCredentialsMode returns the mode of the cloud-credential-operator. The default mode is returned
+// if the CloudCredential object or its API is absent, e.g. when the CloudCredential capability is disabled.
+// Switching modes makes the cloud-credential-operator rewrite operand secrets, which triggers reconciliation,
+// so the object is not watched.
+func (r *CloudOperatorReconciler) getCredentialsMode(ctx context.Context) (operatorv1.CloudCredentialsMode, error) {
+	cloudCredential := &operatorv1.CloudCredential{}
+	err := r.Get(ctx, client.ObjectKey{Name: cloudCredentialResourceName}, cloudCredential)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return operatorv1.CloudCredentialsModeDefault, nil
+	} else if err != nil {
+		return "", err
+	}
+	return cloudCredential.Spec.CredentialsMode, nil
+}
+
+// countNodes returns the number of nodes in the cluster. Only node metadata is fetched.
+func (r *CloudOperatorReconciler) countNodes(ctx context.Context) (int, error) {
+	nodes := &metav1.PartialObjectMetadataList{}
+	nodes.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("NodeList"))
+	if err := r.List(ctx, nodes); err != nil {
+		return 0, err
+	}
+	return len(nodes.Items), nil
+}
+
+// notReadyWorkloads returns descriptions of operand deployments and daemonsets which do not have
+// all their pods available yet, e.g. because they are rolling out or crash looping.
+func (r *CloudOperatorReconciler) notReadyWorkloads(ctx context.Context, resources []client.Object) ([]string, error) {
+	var notReady []string
+	for _, resource := range resources {
+		switch resource.(type) {
+		case *appsv1.Deployment:
+			deployment := &appsv1.Deployment{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(resource), deployment); err != nil {
+				return nil, err
+			}
+			desired := ptr.Deref(deployment.Spec.Replicas, 1)
+			if deployment.Status.ObservedGeneration < deployment.Generation || deployment.Status.AvailableReplicas < desired {
+				notReady = append(notReady, fmt.Sprintf("deployment %s/%s has %d/%d available replicas",
+					deployment.Namespace, deployment.Name, deployment.Status.AvailableReplicas, desired))
+			}
+		case *appsv1.DaemonSet:
+			daemonSet := &appsv1.DaemonSet{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(resource), daemonSet); err != nil {
+				return nil, err
+			}
+			if daemonSet.Status.ObservedGeneration < daemonSet.Generation || daemonSet.Status.NumberReady < daemonSet.Status.DesiredNumberScheduled {
+				notReady = append(notReady, fmt.Sprintf("daemonset %s/%s has %d/%d ready pods",
+					daemonSet.Namespace, daemonSet.Name, daemonSet.Status.NumberReady, daemonSet.Status.DesiredNumberScheduled))
+			}
+		}
+	}
+	return notReady, nil
+}
+
+// rollingOutWorkloads returns descriptions of operand deployments which are replacing pods of a previous revision.
+// Operand deployments use the Recreate strategy, so their pods are not available until the rollout completes.
+// The first revision of a deployment is not a rollout, nor is one which exceeded its progress deadline.
+func (r *CloudOperatorReconciler) rollingOutWorkloads(ctx context.Context, resources []client.Object) ([]string, error) {
+	var rollingOut []string
+	for _, resource := range resources {
+		if _, ok := resource.(*appsv1.Deployment); !ok {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(resource), deployment); err != nil {
+			return nil, err
+		}
+		if revision, err := strconv.Atoi(deployment.Annotations[deploymentRevisionAnnotation]); err != nil || revision <= 1 {
+			continue
+		}
+		if progressDeadlineExceeded(deployment) {
+			continue
+		}
+
+		desired := ptr.Deref(deployment.Spec.Replicas, 1)
+		status := deployment.Status
+		if status.ObservedGeneration < deployment.Generation || status.UpdatedReplicas < desired || status.Replicas > status.UpdatedReplicas {
+			rollingOut = append(rollingOut, fmt.Sprintf("deployment %s/%s has %d/%d updated replicas",
+				deployment.Namespace, deployment.Name, status.UpdatedReplicas, desired))
+		} else if status.AvailableReplicas < desired {
+			rollingOut = append(rollingOut, fmt.Sprintf("deployment %s/%s has %d/%d available updated replicas",
+				deployment.Namespace, deployment.Name, status.AvailableReplicas, desired))
+		}
+	}
+	return rollingOut, nil
+}
+
+// progressDeadlineExceeded returns whether the deployment controller gave up waiting for the deployment to progress.
+func progressDeadlineExceeded(deployment *appsv1.Deployment) bool {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing {
+			return condition.Status == corev1.ConditionFalse && condition.Reason == "ProgressDeadlineExceeded"
+		}
+	}
+	return false
+}
+
+// operandStatusSummary returns a summary of readiness of operand deployments and daemonsets, one
+// "<kind>/<name>: <Ready|NotReady> (<ready>/<desired>)" entry per workload.
+func (r *CloudOperatorReconciler) operandStatusSummary(ctx context.Context, resources []client.Object) (string, error) {
+	var summary []string
+	for _, resource := range resources {
+		var kind string
+		var ready, desired int32
+		var observed bool
+		switch resource.(type) {
+		case *appsv1.Deployment:
+			deployment := &appsv1.Deployment{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(resource), deployment); err != nil {
+				return "", err
+			}
+			kind, ready, desired = "Deployment", deployment.Status.AvailableReplicas, ptr.Deref(deployment.Spec.Replicas, 1)
+			observed = deployment.Status.ObservedGeneration >= deployment.Generation
+		case *appsv1.DaemonSet:
+			daemonSet := &appsv1.DaemonSet{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(resource), daemonSet); err != nil {
+				return "", err
+			}
+			kind, ready, desired = "DaemonSet", daemonSet.Status.NumberReady, daemonSet.Status.DesiredNumberScheduled
+			observed = daemonSet.Status.ObservedGeneration >= daemonSet.Generation
+		default:
+			continue
+		}
+		state := "Ready"
+		if !observed || ready < desired {
+			state = "NotReady"
+		}
+		summary = append(summary, fmt.Sprintf("%s/%s: %s (%d/%d)", kind, resource.GetName(), state, ready, desired))
+	}
+	return strings.Join(summary, ", "), nil
+}
+
+// recordOperandStatus sets the operand status summary annotation on the cluster operator, or removes it
+// if there are no operand workloads. Like status conditions, it is recorded in dry-run mode as well.
+func (r *CloudOperatorReconciler) recordOperandStatus(ctx context.Context, resources []client.Object) error {
+	summary, err := r.operandStatusSummary(ctx, resources)
+	if err != nil {
+		return err
+	}
+	co, err := r.getOrCreateClusterOperator(ctx)
+	if err != nil {
+		return err
+	}
+	current, found := co.Annotations[operandStatusAnnotation]
+	if (summary == "" && !found) || (summary != "" && current == summary) {
+		return nil
+	}
+
+	patch := client.MergeFrom(co.DeepCopy())
+	if summary == "" {
+		delete(co.Annotations, operandStatusAnnotation)
+	} else {
+		metav1.SetMetaDataAnnotation(&co.ObjectMeta, operandStatusAnnotation, summary)
+	}
+	return r.Patch(ctx, co, patch)
+}
+
+// operandClient returns the client and event recorder to write operands with, which do not persist
+// any changes in dry-run mode.
+func (r *CloudOperatorReconciler) operandClient() (client.Client, record.EventRecorder) {
+	if !r.DryRun {
+		return r.Client, r.Recorder
+	}
+	return newDryRunClient(r.Client, r.Recorder), dryRunRecorder{EventRecorder: r.Recorder}
+}
+
+// sync applies resources required for the platform and returns them. No resources are returned
+// if the platform does not require any cloud-controller-manager operands.
+func (r *CloudOperatorReconciler) sync(ctx context.Context, config config.OperatorConfig, conditionOverrides []configv1.ClusterOperatorStatusCondition) ([]client.Object, error) {
 	// Deploy resources for platform
 	resources, err := cloud.GetResources(config)
+	if err != nil {
+		return nil, err
+	}
+	recordRenderedResources(r.Scheme, config.GetPlatformNameString(), resources)
+	if len(resources) == 0 {
+		return nil, r.pruneClusterRBAC(ctx, nil)
+	}
+	forceSync, err := r.pendingForceSync(ctx)
+	if err != nil {
+		return resources, err
+	}
+	applyCtx := ctx
+	if forceSync != "" {
+		klog.Infof("Force sync %q requested, rewriting all operands", forceSync)
+		applyCtx = resourceapply.WithForceApply(ctx)
+	}
+	updated, err := r.applyResources(applyCtx, resources)
+	if err != nil {
+		if updated {
+			// Report the resources which were updated before the failure as progressing,
+			// the caller reports the failure itself as degraded.
+			if err := r.setStatusProgressing(ctx, conditionOverrides); err != nil {
+				klog.Errorf("Error syncing ClusterOperatorStatus: %v", err)
+			}
+		}
+		return resources, err
+	}
+	if err := r.prunePreviousNamespaceOperands(ctx, config, resources); err != nil {
+		return resources, err
+	}
+	if err := r.pruneClusterRBAC(ctx, resources); err != nil {
+		return resources, err
+	}
+	if err := r.prunePodDisruptionBudgets(ctx, config.ManagedNamespace, resources); err != nil {
+		return resources, err
+	}
+	if forceSync != "" {
+		if err := r.recordForceSync(ctx, forceSync); err != nil {
+			return resources, err
+		}
+	}
+	if updated {
+		return resources, r.setStatusProgressing(ctx, conditionOverrides)
+	}
+
+	return resources, nil
+}
+
+// pendingForceSync returns the value of the force sync annotation of the cluster operator,
+// if it has not been handled yet.
+func (r *CloudOperatorReconciler) pendingForceSync(ctx context.Context) (string, error) {
+	co, err := r.getOrCreateClusterOperator(ctx)
+	if err != nil {
+		return "", err
+	}
+	value := co.Annotations[forceSyncAnnotation]
+	if value == "" || value == co.Annotations[forceSyncHandledAnnotation] {
+		return "", nil
+	}
+	return value, nil
+}
+
+// recordForceSync records the handled force sync value on the cluster operator, so operands are
+// rewritten once per value set.
+func (r *CloudOperatorReconciler) recordForceSync(ctx context.Context, value string) error {
+	co, err := r.getOrCreateClusterOperator(ctx)
 	if err != nil {
 		return err
 	}
-	updated, err := r.applyResources(ctx, resources)
+	operandClient, _ := r.operandClient()
+	patch := client.MergeFrom(co.DeepCopy())
+	metav1.SetMetaDataAnnotation(&co.ObjectMeta, forceSyncHandledAnnotation, value)
+	return operandClient.Patch(ctx, co, patch)
+}
+
+// prunePreviousNamespaceOperands deletes operands from the namespace they were applied to before the managed namespace
+// changed, and records the current managed namespace on the cluster operator. Operands to delete are found by rendering
+// them for the previous namespace, so the previous namespace does not need to be cached. Resources which are still
+// part of the current operands are kept, as well as resources which do not carry the ownership label, since the
+// previous namespace may hold objects of the same names which were not applied by the operator.
+func (r *CloudOperatorReconciler) prunePreviousNamespaceOperands(ctx context.Context, operatorConfig config.OperatorConfig, currentResources []client.Object) error {
+	co, err := r.getOrCreateClusterOperator(ctx)
 	if err != nil {
 		return err
 	}
-	if updated {
-		return r.setStatusProgressing(ctx, conditionOverrides)
+
+	previousNamespace := co.Labels[managedNamespaceLabel]
+	if previousNamespace == operatorConfig.ManagedNamespace {
+		return nil
 	}
 
-	return nil
+	if previousNamespace != "" {
+		klog.Infof("Managed namespace changed from %s to %s, pruning operands from the previous namespace", previousNamespace, operatorConfig.ManagedNamespace)
+
+		previousConfig := operatorConfig
+		previousConfig.ManagedNamespace = previousNamespace
+		previousResources, err := cloud.GetResources(previousConfig)
+		if err != nil {
+			return err
+		}
+
+		current := sets.New[string]()
+		for _, resource := range currentResources {
+			current.Insert(fmt.Sprintf("%T/%s/%s", resource, resource.GetNamespace(), resource.GetName()))
+		}
+
+		operandClient, recorder := r.operandClient()
+		var errs []error
+		for _, resource := range previousResources {
+			if resource.GetNamespace() != previousNamespace ||
+				current.Has(fmt.Sprintf("%T/%s/%s", resource, resource.GetNamespace(), resource.GetName())) {
+				continue
+			}
+			// A collection delete selecting the operand by name and ownership label leaves the object alone
+			// unless the operator applied it, without reading the uncached previous namespace first.
+			if err := operandClient.DeleteAllOf(ctx, resource,
+				client.InNamespace(previousNamespace),
+				client.MatchingLabels(common.GetOwnershipLabels()),
+				client.MatchingFields{"metadata.name": resource.GetName()},
+			); err != nil {
+				if !errors.IsNotFound(err) {
+					errs = append(errs, fmt.Errorf("failed to prune %T %s/%s: %w", resource, resource.GetNamespace(), resource.GetName(), err))
+				}
+				continue
+			}
+			klog.Infof("Pruned %T %s/%s from the previous managed namespace, if it was applied by the operator", resource, resource.GetNamespace(), resource.GetName())
+			recorder.Eventf(co, corev1.EventTypeNormal, "OperandPruned",
+				"Pruned %T %s/%s from the previous managed namespace, if it was applied by the operator", resource, resource.GetNamespace(), resource.GetName())
+		}
+		if len(errs) > 0 {
+			return utilerrors.NewAggregate(errs)
+		}
+	}
+
+	operandClient, _ := r.operandClient()
+	patch := client.MergeFrom(co.DeepCopy())
+	metav1.SetMetaDataLabel(&co.ObjectMeta, managedNamespaceLabel, operatorConfig.ManagedNamespace)
+	return operandClient.Patch(ctx, co, patch)
+}
+
+// pruneClusterRBAC deletes cluster roles and cluster role bindings which were applied by the operator, but are not
+// part of the current operands, e.g. after the platform changed. Cluster-scoped objects can not be owned by the
+// namespaced operands, so they are identified by the ownership label the operator sets on everything it applies.
+func (r *CloudOperatorReconciler) pruneClusterRBAC(ctx context.Context, currentResources []client.Object) error {
+	current := sets.New[string]()
+	for _, resource := range currentResources {
+		current.Insert(fmt.Sprintf("%T/%s", resource, resource.GetName()))
+	}
+
+	operandClient, recorder := r.operandClient()
+	selector := client.MatchingLabels(common.GetOwnershipLabels())
+
+	clusterRoles := &rbacv1.ClusterRoleList{}
+	if err := r.List(ctx, clusterRoles, selector); err != nil {
+		return fmt.Errorf("failed to list cluster roles: %w", err)
+	}
+	clusterRoleBindings := &rbacv1.ClusterRoleBindingList{}
+	if err := r.List(ctx, clusterRoleBindings, selector); err != nil {
+		return fmt.Errorf("failed to list cluster role bindings: %w", err)
+	}
+
+	var stale []client.Object
+	for i := range clusterRoles.Items {
+		stale = append(stale, &clusterRoles.Items[i])
+	}
+	for i := range clusterRoleBindings.Items {
+		stale = append(stale, &clusterRoleBindings.Items[i])
+	}
+
+	var errs []error
+	for _, resource := range stale {
+		if current.Has(fmt.Sprintf("%T/%s", resource, resource.GetName())) {
+			continue
+		}
+		if err := operandClient.Delete(ctx, resource); err != nil {
+			if !errors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to prune %T %s: %w", resource, resource.GetName(), err))
+			}
+			continue
+		}
+		klog.Infof("Pruned %T %s which is no longer required", resource, resource.GetName())
+		recorder.Eventf(resource, corev1.EventTypeNormal, "OperandPruned", "Pruned %T %s which is no longer required", resource, resource.GetName())
+	}
+	return utilerrors.NewAggregate(errs)
 }
 
-// applyResources will apply all resources as-is to the cluster, allowing adding of custom annotations and lables
+// prunePodDisruptionBudgets deletes PodDisruptionBudgets in the managed namespace which were applied by the operator,
+// but are not part of the current operands, e.g. after PodDisruptionBudgets were disabled or the topology changed.
+// They are identified by the ownership label the operator sets on everything it applies.
+func (r *CloudOperatorReconciler) prunePodDisruptionBudgets(ctx context.Context, namespace string, currentResources []client.Object) error {
+	current := sets.New[string]()
+	for _, resource := range currentResources {
+		if _, ok := resource.(*policyv1.PodDisruptionBudget); ok {
+			current.Insert(resource.GetName())
+		}
+	}
+
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	if err := r.List(ctx, pdbs, client.InNamespace(namespace), client.MatchingLabels(common.GetOwnershipLabels())); err != nil {
+		return fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+
+	operandClient, recorder := r.operandClient()
+	var errs []error
+	for i := range pdbs.Items {
+		pdb := &pdbs.Items[i]
+		if current.Has(pdb.Name) {
+			continue
+		}
+		if err := operandClient.Delete(ctx, pdb); err != nil {
+			if !errors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to prune PodDisruptionBudget %s/%s: %w", pdb.Namespace, pdb.Name, err))
+			}
+			continue
+		}
+		klog.Infof("Pruned PodDisruptionBudget %s/%s which is no longer required", pdb.Namespace, pdb.Name)
+		recorder.Eventf(pdb, corev1.EventTypeNormal, "OperandPruned", "Pruned PodDisruptionBudget %s/%s which is no longer required", pdb.Namespace, pdb.Name)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// applyResources will apply all resources as-is to the cluster, allowing adding of custom annotations and lables.
+// Independent resources are applied concurrently, RBAC resources are applied before the workloads which rely on them.
 func (r *CloudOperatorReconciler) applyResources(ctx context.Context, resources []client.Object) (bool, error) {
-	updated := false
-	var err error
+	var (
+		updated     atomic.Bool
+		bindingErrs []error
+	)
+	operandClient, recorder := r.operandClient()
 
-	for _, resource := range resources {
-		updated, err = resourceapply.ApplyResource(ctx, r.Client, r.Recorder, resource)
-		if err != nil {
-			return false, err
+	for _, stage := range applyStages(resources) {
+		var (
+			errsLock sync.Mutex
+			errs     []error
+		)
+
+		group := &errgroup.Group{}
+		group.SetLimit(applyResourcesConcurrency)
+		for _, resource := range stage {
+			group.Go(func() error {
+				resourceUpdated, err := resourceapply.ApplyResource(ctx, operandClient, recorder, resourceapply.StripAnnotations(resource, r.StrippedAnnotations))
+				if err != nil {
+					errsLock.Lock()
+					defer errsLock.Unlock()
+					if binding, ok := describeRoleBinding(resource); ok {
+						err = fmt.Errorf("failed to apply %s: %w", binding, err)
+						if r.TolerateRoleBindingFailures {
+							bindingErrs = append(bindingErrs, err)
+							return nil
+						}
+					}
+					errs = append(errs, err)
+					return nil
+				}
+				if resourceUpdated {
+					updated.Store(true)
+				}
+				return nil
+			})
 		}
+		// Errors are collected separately, so all resources in the stage are applied even if some of them fail.
+		_ = group.Wait()
 
-		if err := r.watcher.Watch(ctx, resource); err != nil {
-			klog.Errorf("Unable to establish watch on object %s '%s': %+v", resource.GetObjectKind().GroupVersionKind(), resource.GetName(), err)
-			r.Recorder.Event(resource, corev1.EventTypeWarning, "Establish watch failed", err.Error())
-			return false, err
+		// Resources applied in earlier stages, or by the rest of the stage, may have been updated already.
+		if len(errs) > 0 {
+			return updated.Load(), utilerrors.NewAggregate(errs)
+		}
+
+		for _, resource := range stage {
+			if err := r.watcher.Watch(ctx, resource); err != nil {
+				klog.Errorf("Unable to establish watch on object %s '%s': %+v", resource.GetObjectKind().GroupVersionKind(), resource.GetName(), err)
+				r.Recorder.Event(resource, corev1.EventTypeWarning, "Establish watch failed", err.Error())
+				return updated.Load(), err
+			}
 		}
 	}
 
+	if len(bindingErrs) > 0 {
+		klog.Errorf("Resources applied, except for %d role bindings which failed", len(bindingErrs))
+		return updated.Load(), utilerrors.NewAggregate(bindingErrs)
+	}
+
 	if len(resources) > 0 {
 		klog.V(2).Info("Resources applied successfully.")
 	}
 
-	return updated, nil
+	return updated.Load(), nil
+}
+
+// describeRoleBinding returns the kind and key of the resource, if it is a role binding or a cluster role binding.
+func describeRoleBinding(resource client.Object) (string, bool) {
+	switch resource.(type) {
+	case *rbacv1.RoleBinding:
+		return fmt.Sprintf("RoleBinding %s", client.ObjectKeyFromObject(resource)), true
+	case *rbacv1.ClusterRoleBinding:
+		return fmt.Sprintf("ClusterRoleBinding %s", resource.GetName()), true
+	default:
+		return "", false
+	}
+}
+
+// applyStages splits resources into groups which have to be applied one after another.
+// RBAC resources and service accounts go first, so workloads do not start without permissions
+// and identity they rely on.
+// Resources within a single group do not depend on each other and can be applied concurrently.
+func applyStages(resources []client.Object) [][]client.Object {
+	rbac := []client.Object{}
+	rest := []client.Object{}
+
+	for _, resource := range resources {
+		switch resource.(type) {
+		case *rbacv1.Role, *rbacv1.ClusterRole, *rbacv1.RoleBinding, *rbacv1.ClusterRoleBinding, *corev1.ServiceAccount:
+			rbac = append(rbac, resource)
+		default:
+			rest = append(rest, resource)
+		}
+	}
+
+	return [][]client.Object{rbac, rest}
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -200,22 +978,38 @@ func (r *CloudOperatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&operatorv1.KubeControllerManager{},
 			handler.EnqueueRequestsFromMapFunc(toClusterOperator),
 			builder.WithPredicates(kcmPredicates())).
+		Watches(&configv1.Scheduler{},
+			handler.EnqueueRequestsFromMapFunc(toClusterOperator),
+			builder.WithPredicates(schedulerPredicates())).
+		Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(toClusterOperator),
+			builder.WithPredicates(managedNamespacePredicate(r.ManagedNamespace))).
 		WatchesRawSource(source.Channel(watcher.EventStream(), handler.EnqueueRequestsFromMapFunc(toClusterOperator))).
 		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(toClusterOperator)).
 		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(toClusterOperator))
 
-	return build.Complete(r)
+	if r.RestartOnDNSChange {
+		build = build.Watches(&configv1.DNS{},
+			handler.EnqueueRequestsFromMapFunc(toClusterOperator),
+			builder.WithPredicates(dnsPredicates()))
+	}
+
+	if r.ResourceAutosizing != nil {
+		// Operand requests depend on the node count, resize them as nodes come and go.
+		build = build.Watches(&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(toClusterOperator),
+			builder.OnlyMetadata,
+			builder.WithPredicates(nodeCountPredicates()))
+	}
+
+	return build.Complete(newTimedReconciler("ClusterOperatorController", r.Clock, newDrainingReconciler(r.ShutdownGracePeriod, r)))
 }
 
 func (r *CloudOperatorReconciler) provisioningAllowed(ctx context.Context, infra *configv1.Infrastructure, conditionOverrides []configv1.ClusterOperatorStatusCondition) (bool, error) {
 	// Check if dependant controllers are available
 	available, err := r.checkControllerConditions(ctx)
 	if err != nil {
-		if err := r.setStatusDegraded(ctx, err, conditionOverrides); err != nil {
-			klog.Errorf("Error syncing ClusterOperatorStatus: %v", err)
-			return false, fmt.Errorf("error syncing ClusterOperatorStatus: %v", err)
-		}
-		return false, err
+		return false, r.reportDegraded(ctx, err, conditionOverrides)
 	}
 	if !available {
 		return false, nil
@@ -242,16 +1036,11 @@ func (r *CloudOperatorReconciler) provisioningAllowed(ctx context.Context, infra
 	external, err := cloudprovider.IsCloudProviderExternal(infra.Status.PlatformStatus)
 	if err != nil {
 		klog.Errorf("Could not determine external cloud provider state: %v", err)
-
-		if err := r.setStatusDegraded(ctx, err, conditionOverrides); err != nil {
-			klog.Errorf("Error syncing ClusterOperatorStatus: %v", err)
-			return false, fmt.Errorf("error syncing ClusterOperatorStatus: %v", err)
-		}
-		return false, err
+		return false, r.reportDegraded(ctx, err, conditionOverrides)
 	} else if !external {
 		klog.Infof("Platform does not require an external cloud provider. Skipping...")
 
-		if err := r.setStatusAvailable(ctx, conditionOverrides); err != nil {
+		if err := r.setStatusNoOperandsRequired(ctx, infra.Status.PlatformStatus.Type, conditionOverrides); err != nil {
 			klog.Errorf("Unable to sync cluster operator status: %s", err)
 			return false, err
 		}
@@ -266,12 +1055,7 @@ func (r *CloudOperatorReconciler) isCloudControllersOwnedByCCM(ctx context.Conte
 	co, err := r.getOrCreateClusterOperator(ctx)
 	if err != nil {
 		klog.Errorf("Unable to retrive ClusterOperator object: %v", err)
-
-		if err := r.setStatusDegraded(ctx, err, conditionOverrides); err != nil {
-			klog.Errorf("Error syncing ClusterOperatorStatus: %v", err)
-			return false, fmt.Errorf("error syncing ClusterOperatorStatus: %v", err)
-		}
-		return false, err
+		return false, r.reportDegraded(ctx, err, conditionOverrides)
 	}
 
 	// If there is no condition, we assume that CCM doesn't own the Cloud Controllers
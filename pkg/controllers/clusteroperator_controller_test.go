@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -10,8 +11,12 @@ import (
 	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
@@ -274,8 +279,10 @@ var _ = Describe("Apply resources should", func() {
 					CloudControllerManagerAzure:     "quay.io/openshift/origin-azure-cloud-controller-manager",
 					CloudNodeManagerAzure:           "quay.io/openshift/origin-azure-cloud-node-manager",
 					CloudControllerManagerOpenStack: "registry.ci.openshift.org/openshift:openstack-cloud-controller-manager",
+					CloudControllerManagerVSphere:   "registry.ci.openshift.org/openshift:vsphere-cloud-controller-manager",
 				},
-				PlatformStatus: status,
+				PlatformStatus:     status,
+				InfrastructureName: "my-cool-cluster-777",
 			}
 		}
 	})
@@ -334,6 +341,8 @@ var _ = Describe("Apply resources should", func() {
 		Expect(err).To(Succeed())
 
 		objects[0].SetNamespace("non-existent")
+		// Remaining objects are applied regardless of the failure, so they need to be cleaned up
+		resources = append(resources, objects[1:]...)
 
 		updated, err := reconciler.applyResources(context.TODO(), objects)
 		Expect(err).Should(HaveOccurred())
@@ -341,6 +350,93 @@ var _ = Describe("Apply resources should", func() {
 		Eventually(recorder.Events).Should(Receive(ContainSubstring(resourceapply.ResourceCreateFailedEvent)))
 	})
 
+	It("Expect all vSphere resources to be created", func() {
+		operatorConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.VSpherePlatformType})
+		vsphereResources, err := cloud.GetResources(operatorConfig)
+		Expect(err).To(Succeed())
+
+		resources = append(resources, vsphereResources...)
+
+		updated, err := reconciler.applyResources(context.TODO(), resources)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		for range vsphereResources {
+			Eventually(recorder.Events).Should(Receive(ContainSubstring("Resource was successfully created")))
+		}
+
+		updated, err = reconciler.applyResources(context.TODO(), resources)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("Expect aggregated error and no workloads applied when a single RBAC resource fails", func() {
+		operatorConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.VSpherePlatformType})
+		objects, err := cloud.GetResources(operatorConfig)
+		Expect(err).To(Succeed())
+
+		var broken client.Object
+		for _, obj := range objects {
+			switch obj.(type) {
+			case *rbacv1.RoleBinding:
+				if broken == nil {
+					broken = obj
+					obj.SetNamespace("non-existent")
+					continue
+				}
+				resources = append(resources, obj)
+			case *rbacv1.Role, *rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding:
+				resources = append(resources, obj)
+			}
+		}
+		Expect(broken).ToNot(BeNil())
+
+		// RBAC resources other than the broken one are still applied
+		updated, err := reconciler.applyResources(context.TODO(), objects)
+		Expect(updated).To(BeTrue())
+
+		var aggregate utilerrors.Aggregate
+		Expect(errors.As(err, &aggregate)).To(BeTrue())
+		Expect(aggregate.Errors()).To(HaveLen(1))
+
+		for _, obj := range objects {
+			if _, ok := obj.(*appsv1.Deployment); ok {
+				Expect(apierrors.IsNotFound(cl.Get(context.TODO(), client.ObjectKeyFromObject(obj), &appsv1.Deployment{}))).To(BeTrue())
+			}
+		}
+	})
+
+	It("Expect failed role bindings reported and other resources applied when role binding failures are tolerated", func() {
+		reconciler.TolerateRoleBindingFailures = true
+		operatorConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.VSpherePlatformType})
+		objects, err := cloud.GetResources(operatorConfig)
+		Expect(err).To(Succeed())
+
+		var broken client.Object
+		for _, obj := range objects {
+			if _, ok := obj.(*rbacv1.RoleBinding); ok && broken == nil {
+				broken = obj
+				obj.SetNamespace("non-existent")
+				continue
+			}
+			resources = append(resources, obj)
+		}
+		Expect(broken).ToNot(BeNil())
+
+		updated, err := reconciler.applyResources(context.TODO(), objects)
+		Expect(updated).To(BeTrue())
+
+		var aggregate utilerrors.Aggregate
+		Expect(errors.As(err, &aggregate)).To(BeTrue())
+		Expect(aggregate.Errors()).To(HaveLen(1))
+		Expect(aggregate.Errors()[0]).To(MatchError(ContainSubstring("failed to apply RoleBinding non-existent/" + broken.GetName())))
+
+		for _, obj := range objects {
+			if _, ok := obj.(*appsv1.Deployment); ok {
+				Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(obj), &appsv1.Deployment{})).To(Succeed())
+			}
+		}
+	})
+
 	It("Expect no update when resources are applied twice", func() {
 		operatorConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.AWSPlatformType})
 		awsResources, err := cloud.GetResources(operatorConfig)
@@ -511,7 +607,7 @@ var _ = Describe("Apply resources should", func() {
 
 		// Checking that the label has been added and there are two items in the map
 		Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(dep), dep)).To(Succeed())
-		Expect(len(dep.Labels)).To(Equal(3))
+		Expect(len(dep.Labels)).To(Equal(4))
 		Expect(dep.Labels[labelName]).To(Equal(labelValue))
 
 		// Apply resources again
@@ -532,7 +628,7 @@ var _ = Describe("Apply resources should", func() {
 
 		// Checking that the new label is still there
 		Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(dep), dep)).To(Succeed())
-		Expect(len(dep.Labels)).To(Equal(3))
+		Expect(len(dep.Labels)).To(Equal(4))
 		Expect(dep.Labels[labelName]).To(Equal(labelValue))
 	})
 
@@ -557,7 +653,7 @@ var _ = Describe("Apply resources should", func() {
 		Expect(updated).To(BeTrue())
 		Eventually(recorder.Events).Should(Receive(ContainSubstring("Resource was successfully created")))
 
-		// Now the deployment has just one label "k8s-app: aws-cloud-controller-manager"
+		// Now the deployment has system labels only
 		// Manually modifying the value
 		dep.Labels["k8s-app"] = "someValue"
 		dep.Labels[common.CloudControllerManagerProviderLabel] = "FOO"
@@ -566,7 +662,7 @@ var _ = Describe("Apply resources should", func() {
 
 		// Checking that the label has been updated
 		Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(dep), dep)).To(Succeed())
-		Expect(len(dep.Labels)).To(Equal(2))
+		Expect(len(dep.Labels)).To(Equal(3))
 		Expect(dep.Labels["k8s-app"]).To(Equal("someValue"))
 		Expect(dep.Labels[common.CloudControllerManagerProviderLabel]).To(Equal("FOO"))
 
@@ -588,11 +684,265 @@ var _ = Describe("Apply resources should", func() {
 
 		// Checking that the label value has been reverted and there is only one item in the map
 		Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(dep), dep)).To(Succeed())
-		Expect(len(dep.Labels)).To(Equal(2))
+		Expect(len(dep.Labels)).To(Equal(3))
 		Expect(dep.Labels["k8s-app"]).To(Equal("aws-cloud-controller-manager"))
 		Expect(dep.Labels[common.CloudControllerManagerProviderLabel]).To(Equal("AWS"))
 	})
 
+	It("Expect to have removed common labels reverted back", func() {
+		operatorConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.AWSPlatformType})
+		awsResources, err := cloud.GetResources(operatorConfig)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		resources = append(resources, awsResources...)
+
+		updated, err := reconciler.applyResources(context.TODO(), resources)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+
+		for _, res := range resources {
+			obj := res.DeepCopyObject().(client.Object)
+			Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(res), obj)).To(Succeed())
+			Expect(obj.GetLabels()).To(HaveKeyWithValue(common.PartOfLabel, common.PartOfLabelValue))
+
+			// Manually removing the label
+			labels := obj.GetLabels()
+			delete(labels, common.PartOfLabel)
+			obj.SetLabels(labels)
+			Expect(cl.Update(context.Background(), obj)).To(Succeed())
+		}
+
+		freshResources, err := cloud.GetResources(operatorConfig)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		updated, err = reconciler.applyResources(context.TODO(), freshResources)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+
+		// Checking that the label has been reverted on every resource
+		for _, res := range resources {
+			obj := res.DeepCopyObject().(client.Object)
+			Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(res), obj)).To(Succeed())
+			Expect(obj.GetLabels()).To(HaveKeyWithValue(common.PartOfLabel, common.PartOfLabelValue))
+		}
+	})
+
+	It("Expect update to be reported but not persisted in dry-run mode", func() {
+		recorder = record.NewFakeRecorder(128)
+		reconciler.Recorder = recorder
+		reconciler.DryRun = true
+
+		operatorConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.AWSPlatformType})
+		awsResources, err := cloud.GetResources(operatorConfig)
+		Expect(err).To(Succeed())
+
+		var dep *appsv1.Deployment
+		for _, res := range awsResources {
+			if deployment, ok := res.(*appsv1.Deployment); ok {
+				dep = deployment
+				break
+			}
+		}
+		Expect(dep).NotTo(BeNil())
+
+		updated, err := reconciler.applyResources(context.TODO(), []client.Object{dep})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+
+		Expect(apierrors.IsNotFound(cl.Get(context.TODO(), client.ObjectKeyFromObject(dep), &appsv1.Deployment{}))).To(BeTrue())
+		Eventually(recorder.Events).Should(Receive(ContainSubstring(dryRunEvent)))
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("[dry-run] Resource was successfully created")))
+	})
+
+	It("Expect operands to be pruned from the previous managed namespace", func() {
+		const previousNamespace = "previous-cloud-controller-manager"
+
+		ns := &corev1.Namespace{}
+		ns.SetName(previousNamespace)
+		if err := cl.Create(context.TODO(), ns); err != nil {
+			Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+		}
+
+		previousConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.AWSPlatformType})
+		previousConfig.ManagedNamespace = previousNamespace
+		previousResources, err := cloud.GetResources(previousConfig)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = reconciler.applyResources(context.TODO(), previousResources)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		var previousPDB, foreign client.Object
+		for _, res := range previousResources {
+			if res.GetNamespace() != previousNamespace {
+				continue
+			}
+			if foreign == nil {
+				foreign = res.DeepCopyObject().(client.Object)
+			} else {
+				previousPDB = res
+			}
+		}
+		Expect(previousPDB).NotTo(BeNil())
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(previousPDB), previousPDB.DeepCopyObject().(client.Object))).To(Succeed())
+
+		// Objects without the ownership label were not applied by the operator and are not pruned
+		Expect(foreign).NotTo(BeNil())
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(foreign), foreign)).To(Succeed())
+		labels := foreign.GetLabels()
+		delete(labels, common.OwnedByLabel)
+		foreign.SetLabels(labels)
+		Expect(cl.Update(context.TODO(), foreign)).To(Succeed())
+
+		// Simulate operands of the previous operator version being applied to the previous namespace
+		co, err := reconciler.getOrCreateClusterOperator(context.TODO())
+		Expect(err).ShouldNot(HaveOccurred())
+		co.SetLabels(map[string]string{managedNamespaceLabel: previousNamespace})
+		Expect(cl.Update(context.TODO(), co)).To(Succeed())
+
+		operatorConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.AWSPlatformType})
+		awsResources, err := cloud.GetResources(operatorConfig)
+		Expect(err).ShouldNot(HaveOccurred())
+		resources = append(resources, awsResources...)
+
+		_, err = reconciler.sync(context.TODO(), operatorConfig, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Eventually(func() bool {
+			return apierrors.IsNotFound(cl.Get(context.TODO(), client.ObjectKeyFromObject(previousPDB), previousPDB.DeepCopyObject().(client.Object)))
+		}, timeout).Should(BeTrue())
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(foreign), foreign)).To(Succeed())
+		resources = append(resources, foreign)
+		for _, res := range resources {
+			Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(res), res.DeepCopyObject().(client.Object))).To(Succeed())
+		}
+
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(co), co)).To(Succeed())
+		Expect(co.Labels).To(HaveKeyWithValue(managedNamespaceLabel, DefaultManagedNamespace))
+	})
+
+	It("Expect all operands to be rewritten once when force sync is requested", func() {
+		operatorConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.AWSPlatformType})
+		awsResources, err := reconciler.sync(context.TODO(), operatorConfig, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		resources = append(resources, awsResources...)
+		for range awsResources {
+			Eventually(recorder.Events).Should(Receive(ContainSubstring("Resource was successfully created")))
+		}
+
+		// Operands in the required state are not rewritten without the annotation
+		_, err = reconciler.sync(context.TODO(), operatorConfig, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Consistently(recorder.Events).ShouldNot(Receive(ContainSubstring("Resource was successfully updated")))
+
+		co, err := reconciler.getOrCreateClusterOperator(context.TODO())
+		Expect(err).ShouldNot(HaveOccurred())
+		metav1.SetMetaDataAnnotation(&co.ObjectMeta, forceSyncAnnotation, "1")
+		Expect(cl.Update(context.TODO(), co)).To(Succeed())
+
+		_, err = reconciler.sync(context.TODO(), operatorConfig, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		for range awsResources {
+			Eventually(recorder.Events).Should(Receive(ContainSubstring("Resource was successfully updated")))
+		}
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(co), co)).To(Succeed())
+		Expect(co.Annotations).To(HaveKeyWithValue(forceSyncHandledAnnotation, "1"))
+
+		// The handled value does not make the operator rewrite operands again
+		_, err = reconciler.sync(context.TODO(), operatorConfig, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Consistently(recorder.Events).ShouldNot(Receive(ContainSubstring("Resource was successfully updated")))
+	})
+
+	It("Expect an unmanaged operand to be left untouched while other operands are reconciled", func() {
+		operatorConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.AWSPlatformType})
+		awsResources, err := reconciler.sync(context.TODO(), operatorConfig, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		resources = append(resources, awsResources...)
+
+		var deployment *appsv1.Deployment
+		var pdb *policyv1.PodDisruptionBudget
+		for _, res := range awsResources {
+			switch obj := res.(type) {
+			case *appsv1.Deployment:
+				deployment = obj.DeepCopy()
+			case *policyv1.PodDisruptionBudget:
+				pdb = obj.DeepCopy()
+			}
+		}
+		Expect(deployment).NotTo(BeNil())
+		Expect(pdb).NotTo(BeNil())
+
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(deployment), deployment)).To(Succeed())
+		metav1.SetMetaDataAnnotation(&deployment.ObjectMeta, resourceapply.UnmanagedAnnotation, "true")
+		deployment.Spec.Replicas = ptr.To[int32](5)
+		Expect(cl.Update(context.TODO(), deployment)).To(Succeed())
+
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(pdb), pdb)).To(Succeed())
+		requiredMinAvailable := pdb.Spec.MinAvailable
+		pdb.Spec.MinAvailable = ptr.To(intstr.FromInt32(5))
+		Expect(cl.Update(context.TODO(), pdb)).To(Succeed())
+
+		_, err = reconciler.sync(context.TODO(), operatorConfig, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(deployment), deployment)).To(Succeed())
+		Expect(deployment.Spec.Replicas).To(Equal(ptr.To[int32](5)))
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(pdb), pdb)).To(Succeed())
+		Expect(pdb.Spec.MinAvailable).To(Equal(requiredMinAvailable))
+	})
+
+	It("Expect cluster RBAC of the previous platform to be pruned", func() {
+		vsphereConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.VSpherePlatformType})
+		_, err := reconciler.sync(context.TODO(), vsphereConfig, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		vsphereResources, err := cloud.GetResources(vsphereConfig)
+		Expect(err).ShouldNot(HaveOccurred())
+		var vsphereClusterRBAC []client.Object
+		for _, res := range vsphereResources {
+			switch res.(type) {
+			case *rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding:
+				Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(res), res.DeepCopyObject().(client.Object))).To(Succeed())
+				vsphereClusterRBAC = append(vsphereClusterRBAC, res)
+			default:
+				// Namespaced vSphere operands are cleaned up with the test resources
+				resources = append(resources, res)
+			}
+		}
+		Expect(vsphereClusterRBAC).NotTo(BeEmpty())
+
+		awsConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.AWSPlatformType})
+		awsResources, err := reconciler.sync(context.TODO(), awsConfig, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		resources = append(resources, awsResources...)
+
+		for _, res := range vsphereClusterRBAC {
+			Eventually(func() bool {
+				return apierrors.IsNotFound(cl.Get(context.TODO(), client.ObjectKeyFromObject(res), res.DeepCopyObject().(client.Object)))
+			}, timeout).Should(BeTrue())
+		}
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("OperandPruned")))
+	})
+
+	It("Expect cluster RBAC of other components sharing the part-of label to be kept", func() {
+		foreignClusterRole := &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "foreign-cloud-controller-manager",
+				Labels: common.GetCommonLabels(),
+			},
+		}
+		Expect(cl.Create(context.TODO(), foreignClusterRole)).To(Succeed())
+		resources = append(resources, foreignClusterRole)
+
+		awsConfig := getConfigForPlatform(&configv1.PlatformStatus{Type: configv1.AWSPlatformType})
+		awsResources, err := reconciler.sync(context.TODO(), awsConfig, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		resources = append(resources, awsResources...)
+
+		Consistently(func() error {
+			return cl.Get(context.TODO(), client.ObjectKeyFromObject(foreignClusterRole), &rbacv1.ClusterRole{})
+		}).Should(Succeed())
+	})
+
 	AfterEach(func() {
 		co := &configv1.ClusterOperator{}
 		err := cl.Get(context.Background(), client.ObjectKey{Name: clusterOperatorName}, co)
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// dryRunEvent is the reason of events recording changes the operator would make in dry-run mode.
+	dryRunEvent = "DryRun"
+
+	dryRunMessagePrefix = "[dry-run] "
+)
+
+// dryRunClient sends writes to the API server as dry-run requests, so objects are validated
+// and defaulted but not persisted, and reports changes which would have been made.
+type dryRunClient struct {
+	client.Client
+	recorder record.EventRecorder
+}
+
+// newDryRunClient returns a client which does not persist any writes and reports them
+// with events on the written objects and diffs in logs instead.
+func newDryRunClient(c client.Client, recorder record.EventRecorder) client.Client {
+	return &dryRunClient{
+		Client:   client.NewDryRunClient(c),
+		recorder: recorder,
+	}
+}
+
+func (c *dryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.report(ctx, obj, "create")
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *dryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.report(ctx, obj, "update")
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.report(ctx, obj, "patch")
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *dryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.report(ctx, obj, "delete")
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *dryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	c.report(ctx, obj, "delete")
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+// report logs the diff between the object stored in the cluster and the one which would be written,
+// and records an event on the written object.
+func (c *dryRunClient) report(ctx context.Context, obj client.Object, verb string) {
+	description := fmt.Sprintf("%T %s", obj, client.ObjectKeyFromObject(obj))
+
+	var diff string
+	existing, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err == nil {
+		diff, err = objectDiff(existing, obj)
+		if err != nil {
+			klog.Errorf("Unable to compute dry-run diff of %s: %v", description, err)
+		}
+	}
+
+	klog.Infof("Dry-run: would %s %s:\n%s", verb, description, diff)
+	if c.recorder != nil {
+		c.recorder.Eventf(obj, corev1.EventTypeNormal, dryRunEvent, "Would %s %s, changes are not persisted in dry-run mode", verb, description)
+	}
+}
+
+// objectDiff returns a human readable diff of two objects.
+// Objects are compared in the unstructured form, since typed objects have unexported fields.
+func objectDiff(from, to runtime.Object) (string, error) {
+	fromUnstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(from)
+	if err != nil {
+		return "", err
+	}
+	toUnstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(to)
+	if err != nil {
+		return "", err
+	}
+	return cmp.Diff(fromUnstructured, toUnstructured), nil
+}
+
+// dryRunRecorder marks events recorded while applying resources in dry-run mode,
+// so they are not mistaken for changes made to the cluster.
+type dryRunRecorder struct {
+	record.EventRecorder
+}
+
+func (r dryRunRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, dryRunMessagePrefix+message)
+}
+
+func (r dryRunRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Eventf(object, eventtype, reason, dryRunMessagePrefix+messageFmt, args...)
+}
+
+func (r dryRunRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, dryRunMessagePrefix+messageFmt, args...)
+}
@@ -0,0 +1,21 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+// NewImagesFileChecker returns a readiness checker which fails if the images file
+// used by the operator for operand images substitution can not be read or decoded.
+func NewImagesFileChecker(imagesFile string) healthz.Checker {
+	return func(_ *http.Request) error {
+		if err := config.CheckImagesFile(imagesFile); err != nil {
+			return fmt.Errorf("unable to decode images file from location %s: %w", imagesFile, err)
+		}
+		return nil
+	}
+}
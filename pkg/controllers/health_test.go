@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImagesFileChecker(t *testing.T) {
+	tc := []struct {
+		name        string
+		imagesFile  string
+		expectError bool
+	}{{
+		name:        "Missing images file",
+		imagesFile:  "./fixtures/missing.json",
+		expectError: true,
+	}, {
+		name:        "Images file can not be decoded",
+		imagesFile:  "./fixtures/trust_bundle_valid.pem",
+		expectError: true,
+	}, {
+		name:       "Valid images file",
+		imagesFile: testImagesFilePath,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewImagesFileChecker(tc.imagesFile)(nil)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// Values of the result label of the reconcile duration histogram.
+	reconcileResultSuccess = "success"
+	reconcileResultRequeue = "requeue"
+	reconcileResultError   = "error"
+)
+
+var (
+	// configTransformFailures counts errors returned by platform cloud config transformers.
+	configTransformFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cccmo_config_transform_failures_total",
+			Help: "Number of failures to transform the cloud config for the platform.",
+		},
+		[]string{"platform"},
+	)
+
+	// renderedResources reports the number of operand resources rendered for the platform, per kind.
+	renderedResources = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cccmo_rendered_resources",
+			Help: "Number of operand resources rendered for the platform, by kind.",
+		},
+		[]string{"platform", "kind"},
+	)
+
+	// reconcileDuration observes the time taken by each reconcile, per controller and result.
+	reconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cccmo_reconcile_duration_seconds",
+			Help:    "Time taken to reconcile, by controller and result.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"controller", "result"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(configTransformFailures, renderedResources, reconcileDuration)
+}
+
+// timedReconciler wraps a reconciler to observe the duration of its reconciles.
+type timedReconciler struct {
+	controller string
+	clock      clock.PassiveClock
+	reconciler reconcile.Reconciler
+}
+
+// newTimedReconciler returns a reconciler observing durations of the given reconciler's reconciles
+// with the given clock, or the real clock if none is given.
+func newTimedReconciler(controller string, clk clock.PassiveClock, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	if clk == nil {
+		clk = clock.RealClock{}
+	}
+	return &timedReconciler{
+		controller: controller,
+		clock:      clk,
+		reconciler: reconciler,
+	}
+}
+
+func (t *timedReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	start := t.clock.Now()
+	result, err := t.reconciler.Reconcile(ctx, req)
+	reconcileDuration.WithLabelValues(t.controller, reconcileResultLabel(result, err)).Observe(t.clock.Since(start).Seconds())
+	return result, err
+}
+
+// reconcileResultLabel returns the result label value for a reconcile outcome.
+func reconcileResultLabel(result reconcile.Result, err error) string {
+	switch {
+	case err != nil:
+		return reconcileResultError
+	case result.Requeue || result.RequeueAfter > 0:
+		return reconcileResultRequeue
+	default:
+		return reconcileResultSuccess
+	}
+}
+
+// recordRenderedResources sets the rendered resources gauge to the number of the given resources of each kind.
+// Values reported for previously rendered resources are dropped, so kinds which are no longer rendered disappear.
+func recordRenderedResources(scheme *runtime.Scheme, platform string, resources []client.Object) {
+	counts := map[string]int{}
+	for _, resource := range resources {
+		gvk, err := apiutil.GVKForObject(resource, scheme)
+		if err != nil {
+			klog.Warningf("Unable to determine kind of rendered resource %s: %v", resource.GetName(), err)
+			continue
+		}
+		counts[gvk.Kind]++
+	}
+
+	renderedResources.Reset()
+	for kind, count := range counts {
+		renderedResources.WithLabelValues(platform, kind).Set(float64(count))
+	}
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/cloud"
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+)
+
+func TestRecordRenderedResources(t *testing.T) {
+	getRenderedResources := func(platform, kind string) float64 {
+		metric := &dto.Metric{}
+		assert.NoError(t, renderedResources.WithLabelValues(platform, kind).Write(metric))
+		return metric.GetGauge().GetValue()
+	}
+
+	operatorConfig := config.OperatorConfig{
+		ManagedNamespace: DefaultManagedNamespace,
+		ImagesReference: config.ImagesReference{
+			CloudControllerManagerOperator: "registry.ci.openshift.org/openshift:cluster-cloud-controller-manager-operator",
+			CloudControllerManagerAWS:      "registry.ci.openshift.org/openshift:aws-cloud-controller-manager",
+		},
+		PlatformStatus:     &configv1.PlatformStatus{Type: configv1.AWSPlatformType},
+		InfrastructureName: "my-cool-cluster-777",
+	}
+	resources, err := cloud.GetResources(operatorConfig)
+	assert.NoError(t, err)
+
+	recordRenderedResources(scheme.Scheme, string(configv1.AWSPlatformType), resources)
+	assert.Equal(t, float64(1), getRenderedResources(string(configv1.AWSPlatformType), "Deployment"))
+	assert.Equal(t, float64(1), getRenderedResources(string(configv1.AWSPlatformType), "PodDisruptionBudget"))
+
+	// Values of previously rendered resources are dropped
+	recordRenderedResources(scheme.Scheme, string(configv1.AWSPlatformType), nil)
+	assert.Equal(t, float64(0), getRenderedResources(string(configv1.AWSPlatformType), "Deployment"))
+}
+
+func TestTimedReconciler(t *testing.T) {
+	const step = 2 * time.Second
+
+	getSampleCounts := func(controller, result string) (uint64, uint64) {
+		metric := &dto.Metric{}
+		assert.NoError(t, reconcileDuration.WithLabelValues(controller, result).(prometheus.Histogram).Write(metric))
+		var withinStep uint64
+		for _, bucket := range metric.GetHistogram().GetBucket() {
+			if bucket.GetUpperBound() == step.Seconds()+0.5 {
+				withinStep = bucket.GetCumulativeCount()
+			}
+		}
+		return metric.GetHistogram().GetSampleCount(), withinStep
+	}
+
+	fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+	reconcileErr := errors.New("reconcile failed")
+	var returnErr error
+	timed := newTimedReconciler("TestController", fakeClock, reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		fakeClock.SetTime(fakeClock.Now().Add(step))
+		return reconcile.Result{}, returnErr
+	}))
+
+	_, err := timed.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+	count, withinStep := getSampleCounts("TestController", reconcileResultSuccess)
+	assert.Equal(t, uint64(1), count)
+	assert.Equal(t, uint64(1), withinStep, "reconcile duration should be observed in the 2.5s bucket")
+
+	returnErr = reconcileErr
+	_, err = timed.Reconcile(context.TODO(), reconcile.Request{})
+	assert.ErrorIs(t, err, reconcileErr)
+	count, withinStep = getSampleCounts("TestController", reconcileResultError)
+	assert.Equal(t, uint64(1), count)
+	assert.Equal(t, uint64(1), withinStep, "reconcile duration should be observed in the 2.5s bucket")
+
+	count, _ = getSampleCounts("TestController", reconcileResultSuccess)
+	assert.Equal(t, uint64(1), count, "failed reconciles should not be observed with the success result")
+}
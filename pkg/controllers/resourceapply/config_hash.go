@@ -5,12 +5,17 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
 )
 
 const configHashAnnotation = "operator.openshift.io/config-hash"
@@ -18,15 +23,42 @@ const configHashAnnotation = "operator.openshift.io/config-hash"
 type configSources struct {
 	ConfigMaps sets.Set[string]
 	Secrets    sets.Set[string]
+	// OptionalConfigMaps and OptionalSecrets are sources which are only referenced as optional.
+	// Pods start without them, so they are left out of the hash while they do not exist.
+	OptionalConfigMaps sets.Set[string]
+	OptionalSecrets    sets.Set[string]
+}
+
+// addConfigMap adds a config map referenced by a pod template, optional references are tracked
+// unless the config map is referenced as required as well.
+func (s *configSources) addConfigMap(name string, optional *bool) {
+	addSource(s.ConfigMaps, s.OptionalConfigMaps, name, optional)
+}
+
+// addSecret adds a secret referenced by a pod template, optional references are tracked
+// unless the secret is referenced as required as well.
+func (s *configSources) addSecret(name string, optional *bool) {
+	addSource(s.Secrets, s.OptionalSecrets, name, optional)
+}
+
+func addSource(sources, optionalSources sets.Set[string], name string, optional *bool) {
+	if !ptr.Deref(optional, false) {
+		optionalSources.Delete(name)
+	} else if !sources.Has(name) {
+		optionalSources.Insert(name)
+	}
+	sources.Insert(name)
 }
 
 // collectRelatedConfigSources looks into pod template spec for secret or config map references.
-// Currently, checks volumes and env vars for each container,
+// Currently, checks volumes, including projected ones, and env vars for each container,
 // returns configSources structure which contains sets of config maps and secrets names.
 func collectRelatedConfigSources(spec *corev1.PodTemplateSpec) configSources {
 	sources := configSources{
-		ConfigMaps: sets.Set[string]{},
-		Secrets:    sets.Set[string]{},
+		ConfigMaps:         sets.Set[string]{},
+		Secrets:            sets.Set[string]{},
+		OptionalConfigMaps: sets.Set[string]{},
+		OptionalSecrets:    sets.Set[string]{},
 	}
 
 	if spec == nil {
@@ -35,10 +67,21 @@ func collectRelatedConfigSources(spec *corev1.PodTemplateSpec) configSources {
 
 	for _, volume := range spec.Spec.Volumes {
 		if volume.ConfigMap != nil {
-			sources.ConfigMaps.Insert(volume.ConfigMap.Name)
+			sources.addConfigMap(volume.ConfigMap.Name, volume.ConfigMap.Optional)
 		}
 		if volume.Secret != nil {
-			sources.Secrets.Insert(volume.Secret.SecretName)
+			sources.addSecret(volume.Secret.SecretName, volume.Secret.Optional)
+		}
+		if volume.Projected == nil {
+			continue
+		}
+		for _, projection := range volume.Projected.Sources {
+			if projection.ConfigMap != nil {
+				sources.addConfigMap(projection.ConfigMap.Name, projection.ConfigMap.Optional)
+			}
+			if projection.Secret != nil {
+				sources.addSecret(projection.Secret.Name, projection.Secret.Optional)
+			}
 		}
 	}
 
@@ -53,15 +96,25 @@ func collectRelatedConfigSources(spec *corev1.PodTemplateSpec) configSources {
 	return sources
 }
 
+// collectAnnotatedConfigSources collects names of config maps listed in the config hash sources annotation
+// of a workload into passed configSources instance.
+func collectAnnotatedConfigSources(annotations map[string]string, sources *configSources) {
+	for _, name := range strings.Split(annotations[config.ConfigHashSourcesAnnotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			sources.ConfigMaps.Insert(name)
+		}
+	}
+}
+
 // collectRelatedConfigsFromContainer collects related configs names into passed configSources instance.
 // Looks into env and envVar of the passed container spec and populates configSources with configmaps and secrets names.
 func collectRelatedConfigsFromContainer(container *corev1.Container, sources *configSources) {
 	for _, envVar := range container.EnvFrom {
 		if envVar.ConfigMapRef != nil {
-			sources.ConfigMaps.Insert(envVar.ConfigMapRef.Name)
+			sources.addConfigMap(envVar.ConfigMapRef.Name, envVar.ConfigMapRef.Optional)
 		}
 		if envVar.SecretRef != nil {
-			sources.Secrets.Insert(envVar.SecretRef.Name)
+			sources.addSecret(envVar.SecretRef.Name, envVar.SecretRef.Optional)
 		}
 	}
 	for _, envVar := range container.Env {
@@ -69,16 +122,17 @@ func collectRelatedConfigsFromContainer(container *corev1.Container, sources *co
 			continue
 		}
 		if envVar.ValueFrom.ConfigMapKeyRef != nil {
-			sources.ConfigMaps.Insert(envVar.ValueFrom.ConfigMapKeyRef.Name)
+			sources.addConfigMap(envVar.ValueFrom.ConfigMapKeyRef.Name, envVar.ValueFrom.ConfigMapKeyRef.Optional)
 		}
 		if envVar.ValueFrom.SecretKeyRef != nil {
-			sources.Secrets.Insert(envVar.ValueFrom.SecretKeyRef.Name)
+			sources.addSecret(envVar.ValueFrom.SecretKeyRef.Name, envVar.ValueFrom.SecretKeyRef.Optional)
 		}
 	}
 }
 
 // calculateRelatedConfigsHash calculates configmaps and secrets content hash.
 // Returns error in case object was not found or error during object request occured.
+// Optional objects which are not found are left out of the hash.
 func calculateRelatedConfigsHash(ctx context.Context, cl runtimeclient.Client, ns string, source configSources) (string, error) {
 	hashSource := struct {
 		ConfigMaps map[string]map[string]string `json:"configMaps"`
@@ -92,7 +146,9 @@ func calculateRelatedConfigsHash(ctx context.Context, cl runtimeclient.Client, n
 
 	for _, cm := range source.ConfigMaps.UnsortedList() {
 		obj := &corev1.ConfigMap{}
-		if err := cl.Get(ctx, types.NamespacedName{Namespace: ns, Name: cm}, obj); err != nil {
+		if err := cl.Get(ctx, types.NamespacedName{Namespace: ns, Name: cm}, obj); apierrors.IsNotFound(err) && source.OptionalConfigMaps.Has(cm) {
+			continue
+		} else if err != nil {
 			errList = append(errList, err)
 		} else {
 			hashSource.ConfigMaps[cm] = obj.Data
@@ -101,7 +157,9 @@ func calculateRelatedConfigsHash(ctx context.Context, cl runtimeclient.Client, n
 
 	for _, secret := range source.Secrets.UnsortedList() {
 		obj := &corev1.Secret{}
-		if err := cl.Get(ctx, types.NamespacedName{Namespace: ns, Name: secret}, obj); err != nil {
+		if err := cl.Get(ctx, types.NamespacedName{Namespace: ns, Name: secret}, obj); apierrors.IsNotFound(err) && source.OptionalSecrets.Has(secret) {
+			continue
+		} else if err != nil {
 			errList = append(errList, err)
 		} else {
 			hashSource.Secrets[secret] = obj.Data
@@ -121,8 +179,10 @@ func calculateRelatedConfigsHash(ctx context.Context, cl runtimeclient.Client, n
 }
 
 // annotatePodSpecWithRelatedConfigsHash annotates pod template spec with a hash of related config maps and secrets content.
-func annotatePodSpecWithRelatedConfigsHash(ctx context.Context, cl runtimeclient.Client, ns string, spec *corev1.PodTemplateSpec) error {
+// Config maps listed in the config hash sources annotation of the workload are included as well.
+func annotatePodSpecWithRelatedConfigsHash(ctx context.Context, cl runtimeclient.Client, ns string, workloadAnnotations map[string]string, spec *corev1.PodTemplateSpec) error {
 	sources := collectRelatedConfigSources(spec)
+	collectAnnotatedConfigSources(workloadAnnotations, &sources)
 	hash, err := calculateRelatedConfigsHash(ctx, cl, ns, sources)
 	if err != nil {
 		return fmt.Errorf("error calculating configuration hash: %w", err)
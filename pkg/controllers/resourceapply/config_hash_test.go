@@ -6,6 +6,9 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	gmg "github.com/onsi/gomega"
@@ -211,6 +214,44 @@ func TestCollectDependantConfigs(t *testing.T) {
 	})
 }
 
+func TestCollectOptionalConfigSources(t *testing.T) {
+	g := gmg.NewWithT(t)
+
+	podTemplate := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{{
+				Name: "projected", VolumeSource: corev1.VolumeSource{
+					Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{{
+						ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "projected-configmap"}},
+					}, {
+						Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "projected-secret"}, Optional: ptr.To(true)},
+					}}},
+				},
+			}, {
+				Name: "optional-secret", VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: "optional-secret", Optional: ptr.To(true)},
+				},
+			}, {
+				Name: "optional-configmap", VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "cloud-conf"}, Optional: ptr.To(true)},
+				},
+			}},
+			Containers: []corev1.Container{{
+				Name: "foo", EnvFrom: []corev1.EnvFromSource{{
+					// Also referenced as required, so it is not optional
+					ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "cloud-conf"}},
+				}},
+			}},
+		},
+	}
+
+	sources := collectRelatedConfigSources(podTemplate)
+	g.Expect(sets.List(sources.ConfigMaps)).To(gmg.Equal([]string{"cloud-conf", "projected-configmap"}))
+	g.Expect(sets.List(sources.Secrets)).To(gmg.Equal([]string{"optional-secret", "projected-secret"}))
+	g.Expect(sources.OptionalConfigMaps.Len()).To(gmg.BeZero())
+	g.Expect(sets.List(sources.OptionalSecrets)).To(gmg.Equal([]string{"optional-secret", "projected-secret"}))
+}
+
 func TestCalculateConfigsHash(t *testing.T) {
 	sources := configSources{
 		ConfigMaps: sets.New[string]("configmap"),
@@ -269,4 +310,166 @@ func TestCalculateConfigsHash(t *testing.T) {
 		g.Expect(hash).To(gmg.Equal("100444e91862dd77d7ebe29f050c1e9a7f357c771e1a7b7650aae27e6a3a031d"))
 		g.Expect(err).NotTo(gmg.HaveOccurred())
 	})
+	t.Run("optional resources not found", func(t *testing.T) {
+		g := gmg.NewWithT(t)
+
+		fakeClient := fake.NewClientBuilder().WithObjects(configMap).Build()
+		optionalSources := configSources{
+			ConfigMaps:      sets.New[string]("configmap"),
+			Secrets:         sets.New[string]("secret"),
+			OptionalSecrets: sets.New[string]("secret"),
+		}
+
+		hash, err := calculateRelatedConfigsHash(context.TODO(), fakeClient, "test", optionalSources)
+		g.Expect(err).NotTo(gmg.HaveOccurred())
+		g.Expect(hash).NotTo(gmg.BeEmpty())
+
+		// The hash changes once the optional secret is created
+		optionalSecret := secret.DeepCopy()
+		optionalSecret.ResourceVersion = ""
+		g.Expect(fakeClient.Create(context.TODO(), optionalSecret)).To(gmg.Succeed())
+		createdHash, err := calculateRelatedConfigsHash(context.TODO(), fakeClient, "test", optionalSources)
+		g.Expect(err).NotTo(gmg.HaveOccurred())
+		g.Expect(createdHash).NotTo(gmg.Equal(hash))
+	})
+}
+
+func TestCloudConfigChangeRollsDeployment(t *testing.T) {
+	g := gmg.NewWithT(t)
+
+	operatorConfig := getOperatorConfigForAzureStack()
+	resources, err := cloud.GetResources(operatorConfig)
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+
+	var deployment *appsv1.Deployment
+	for _, resource := range resources {
+		if d, ok := resource.(*appsv1.Deployment); ok {
+			deployment = d
+		}
+	}
+	g.Expect(deployment).NotTo(gmg.BeNil())
+
+	namespace := operatorConfig.ManagedNamespace
+	cloudConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloud-conf", Namespace: namespace},
+		Data:       map[string]string{"cloud.conf": `{"cloud": "AzureStackCloud"}`},
+	}
+	trustedCA := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ccm-trusted-ca", Namespace: namespace},
+		Data:       map[string]string{"ca-bundle.crt": "bundle"},
+	}
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "azure-cloud-credentials", Namespace: namespace},
+		Data:       map[string][]byte{"azure_client_id": []byte("id")},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithObjects(cloudConfig, trustedCA, credentials).Build()
+	recorder := record.NewFakeRecorder(100)
+
+	getConfigHash := func() string {
+		applied := &appsv1.Deployment{}
+		g.Expect(fakeClient.Get(context.TODO(), runtimeclient.ObjectKeyFromObject(deployment), applied)).To(gmg.Succeed())
+		return applied.Spec.Template.Annotations[configHashAnnotation]
+	}
+
+	_, err = applyDeployment(context.TODO(), fakeClient, recorder, deployment)
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	initialHash := getConfigHash()
+	g.Expect(initialHash).NotTo(gmg.BeEmpty())
+
+	// The cloud config sync controller writes a new cloud config
+	cloudConfig.Data["cloud.conf"] = `{"cloud": "AzureStackCloud", "vmType": "standard"}`
+	g.Expect(fakeClient.Update(context.TODO(), cloudConfig)).To(gmg.Succeed())
+
+	updated, err := applyDeployment(context.TODO(), fakeClient, recorder, deployment)
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	g.Expect(updated).To(gmg.BeTrue())
+	g.Expect(getConfigHash()).NotTo(gmg.Equal(initialHash))
+
+}
+
+func TestCollectAnnotatedConfigSources(t *testing.T) {
+	tcs := []struct {
+		name               string
+		annotations        map[string]string
+		expectedConfigMaps []string
+	}{
+		{
+			name:               "no annotations",
+			annotations:        nil,
+			expectedConfigMaps: []string{},
+		},
+		{
+			name:               "empty annotation",
+			annotations:        map[string]string{config.ConfigHashSourcesAnnotation: ""},
+			expectedConfigMaps: []string{},
+		},
+		{
+			name:               "config maps listed in annotation",
+			annotations:        map[string]string{config.ConfigHashSourcesAnnotation: "cloud-conf, node-config,,"},
+			expectedConfigMaps: []string{"cloud-conf", "node-config"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gmg.NewWithT(t)
+			sources := collectRelatedConfigSources(nil)
+			collectAnnotatedConfigSources(tc.annotations, &sources)
+			g.Expect(sets.List(sources.ConfigMaps)).To(gmg.Equal(tc.expectedConfigMaps))
+			g.Expect(sources.Secrets.Len()).To(gmg.BeZero())
+		})
+	}
+}
+
+func TestConfigChangeRollsDaemonSet(t *testing.T) {
+	g := gmg.NewWithT(t)
+
+	nodeConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "node-config",
+			Namespace: "test",
+		},
+		Data: map[string]string{
+			"kernel": "5.14",
+		},
+	}
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cloud-node-manager",
+			Namespace: "test",
+			Annotations: map[string]string{
+				config.ConfigHashSourcesAnnotation: "node-config",
+			},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "cloud-node-manager", Image: "bar"}},
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithObjects(nodeConfig).Build()
+	recorder := record.NewFakeRecorder(100)
+
+	getConfigHash := func() string {
+		applied := &appsv1.DaemonSet{}
+		g.Expect(fakeClient.Get(context.TODO(), runtimeclient.ObjectKeyFromObject(daemonSet), applied)).To(gmg.Succeed())
+		return applied.Spec.Template.Annotations[configHashAnnotation]
+	}
+
+	_, err := applyDaemonSet(context.TODO(), fakeClient, recorder, daemonSet)
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	initialHash := getConfigHash()
+	g.Expect(initialHash).NotTo(gmg.BeEmpty())
+
+	nodeConfig.Data["kernel"] = "5.15"
+	g.Expect(fakeClient.Update(context.TODO(), nodeConfig)).To(gmg.Succeed())
+
+	updated, err := applyDaemonSet(context.TODO(), fakeClient, recorder, daemonSet)
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	g.Expect(updated).To(gmg.BeTrue())
+	g.Expect(getConfigHash()).NotTo(gmg.Equal(initialHash))
 }
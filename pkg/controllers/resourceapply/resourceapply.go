@@ -7,6 +7,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -16,7 +19,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 
@@ -47,8 +52,103 @@ const (
 	RecreateSuccessEvent    = "ResourceRecreateSuccess"
 
 	ResourceDeleteFailedEvent = "ResourceDeleteFailed"
+
+	ResourceRestoreSuccessEvent = "ResourceRestoreSuccess"
+	ResourceRestoreFailedEvent  = "ResourceRestoreFailed"
 )
 
+// UnmanagedAnnotation set to "true" on an operand object makes ApplyResource leave the object as it is,
+// e.g. to debug an operand with a modified spec, while other operands are still reconciled.
+const UnmanagedAnnotation = "cloud.openshift.io/unmanaged"
+
+// recreateBackoff is used to retry creation of a resource which was deleted to be recreated,
+// e.g. when the API server is briefly unavailable.
+var recreateBackoff = wait.Backoff{
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Steps:    4,
+}
+
+// createWithRetry creates the object, retrying transient errors with recreateBackoff.
+// Errors which would not go away on retry, e.g. an invalid object, are returned immediately.
+func createWithRetry(ctx context.Context, client coreclientv1.Client, obj coreclientv1.Object) error {
+	return retry.OnError(recreateBackoff, isTransientError, func() error {
+		return client.Create(ctx, obj)
+	})
+}
+
+// isTransientError returns true for API errors which may go away when the request is retried.
+func isTransientError(err error) bool {
+	if _, ok := apierrors.SuggestsClientDelay(err); ok {
+		return true
+	}
+	return apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsUnexpectedServerError(err)
+}
+
+// restorableDeployment returns a copy of the previously existing deployment which can be created again.
+func restorableDeployment(existing *appsv1.Deployment) *appsv1.Deployment {
+	restored := existing.DeepCopy()
+	restored.ObjectMeta = metav1.ObjectMeta{
+		Name:            existing.Name,
+		Namespace:       existing.Namespace,
+		Labels:          existing.Labels,
+		Annotations:     existing.Annotations,
+		OwnerReferences: existing.OwnerReferences,
+	}
+	restored.Status = appsv1.DeploymentStatus{}
+	return restored
+}
+
+// DefaultStrippedAnnotations are annotations which are removed from desired objects before they are applied,
+// since they carry client bookkeeping which should not trigger operand updates.
+var DefaultStrippedAnnotations = []string{corev1.LastAppliedConfigAnnotation}
+
+// StripAnnotations returns a copy of the object without the given annotations. For workloads the annotations
+// are removed from the pod template as well, so they are not part of the spec hash either.
+// The object is returned as-is if there are no annotations to strip.
+func StripAnnotations(obj client.Object, keys []string) client.Object {
+	if len(keys) == 0 {
+		return obj
+	}
+
+	stripped := obj.DeepCopyObject().(client.Object)
+	strip := func(annotations map[string]string) {
+		for _, key := range keys {
+			delete(annotations, key)
+		}
+	}
+	annotations := stripped.GetAnnotations()
+	strip(annotations)
+	stripped.SetAnnotations(annotations)
+
+	switch t := stripped.(type) {
+	case *appsv1.Deployment:
+		strip(t.Spec.Template.Annotations)
+	case *appsv1.DaemonSet:
+		strip(t.Spec.Template.Annotations)
+	}
+	return stripped
+}
+
+type forceApplyKey struct{}
+
+// WithForceApply returns a context which makes ApplyResource write objects even if they are already
+// in the required state, e.g. to recover operands from changes the operator does not detect.
+func WithForceApply(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceApplyKey{}, true)
+}
+
+// isForceApply returns whether objects are to be written even if they are already in the required state.
+func isForceApply(ctx context.Context) bool {
+	force, _ := ctx.Value(forceApplyKey{}).(bool)
+	return force
+}
+
 // setSpecHashAnnotation computes the hash of the provided spec and sets an annotation of the
 // hash on the provided ObjectMeta. This method is used internally by Apply<type> methods, and
 // is exposed to support testing with fake clients that need to know the mutated form of the
@@ -68,6 +168,15 @@ func setSpecHashAnnotation(objMeta *metav1.ObjectMeta, spec interface{}) error {
 
 // ApplyResource applies resources of unspecified type
 func ApplyResource(ctx context.Context, client coreclientv1.Client, recorder record.EventRecorder, resource client.Object) (bool, error) {
+	unmanaged, err := isUnmanaged(ctx, client, resource)
+	if err != nil {
+		return false, err
+	}
+	if unmanaged {
+		klog.V(2).Infof("Skipping %T %s, it is annotated with %s", resource, coreclientv1.ObjectKeyFromObject(resource), UnmanagedAnnotation)
+		return false, nil
+	}
+
 	switch t := resource.(type) {
 	case *appsv1.Deployment:
 		return applyDeployment(ctx, client, recorder, t)
@@ -75,6 +184,10 @@ func ApplyResource(ctx context.Context, client coreclientv1.Client, recorder rec
 		return applyDaemonSet(ctx, client, recorder, t)
 	case *corev1.ConfigMap:
 		return applyConfigMap(ctx, client, recorder, t)
+	case *corev1.ServiceAccount:
+		return applyServiceAccount(ctx, client, recorder, t)
+	case *corev1.Service:
+		return applyService(ctx, client, recorder, t)
 	case *policyv1.PodDisruptionBudget:
 		return applyPodDisruptionBudget(ctx, client, recorder, t)
 	case *rbacv1.Role:
@@ -89,11 +202,25 @@ func ApplyResource(ctx context.Context, client coreclientv1.Client, recorder rec
 		return applyValidatingAdmissionPolicy(ctx, client, recorder, t)
 	case *admissionregistrationv1.ValidatingAdmissionPolicyBinding:
 		return applyValidatingAdmissionPolicyBinding(ctx, client, recorder, t)
+	case *admissionregistrationv1.ValidatingWebhookConfiguration:
+		return applyValidatingWebhookConfiguration(ctx, client, recorder, t)
 	default:
 		return false, fmt.Errorf("unhandled type %T", resource)
 	}
 }
 
+// isUnmanaged returns whether the existing object is opted out of reconciliation with UnmanagedAnnotation.
+func isUnmanaged(ctx context.Context, client coreclientv1.Client, resource coreclientv1.Object) (bool, error) {
+	existing := resource.DeepCopyObject().(coreclientv1.Object)
+	if err := client.Get(ctx, coreclientv1.ObjectKeyFromObject(resource), existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return existing.GetAnnotations()[UnmanagedAnnotation] == "true", nil
+}
+
 func applyConfigMap(ctx context.Context, client coreclientv1.Client, recorder record.EventRecorder, requiredOriginal *corev1.ConfigMap) (bool, error) {
 	required := requiredOriginal.DeepCopy()
 	existing := &corev1.ConfigMap{}
@@ -117,14 +244,23 @@ func applyConfigMap(ctx context.Context, client coreclientv1.Client, recorder re
 
 	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, required.ObjectMeta)
 
-	var modifiedKeys []string
+	// removedKeys are keys which are not required, e.g. added by users, which the update drops.
+	var modifiedKeys, removedKeys []string
 	for existingCopyKey, existingCopyValue := range existingCopy.Data {
-		if requiredValue, ok := required.Data[existingCopyKey]; !ok || (existingCopyValue != requiredValue) {
+		requiredValue, ok := required.Data[existingCopyKey]
+		if !ok {
+			removedKeys = append(removedKeys, "data."+existingCopyKey)
+		}
+		if !ok || (existingCopyValue != requiredValue) {
 			modifiedKeys = append(modifiedKeys, "data."+existingCopyKey)
 		}
 	}
 	for existingCopyKey, existingCopyBinValue := range existingCopy.BinaryData {
-		if requiredBinValue, ok := required.BinaryData[existingCopyKey]; !ok || !bytes.Equal(existingCopyBinValue, requiredBinValue) {
+		requiredBinValue, ok := required.BinaryData[existingCopyKey]
+		if !ok {
+			removedKeys = append(removedKeys, "binaryData."+existingCopyKey)
+		}
+		if !ok || !bytes.Equal(existingCopyBinValue, requiredBinValue) {
 			modifiedKeys = append(modifiedKeys, "binaryData."+existingCopyKey)
 		}
 	}
@@ -140,7 +276,7 @@ func applyConfigMap(ctx context.Context, client coreclientv1.Client, recorder re
 	}
 
 	dataSame := len(modifiedKeys) == 0
-	if dataSame && !*modified {
+	if dataSame && !*modified && !isForceApply(ctx) {
 		return false, nil
 	}
 	existingCopy.Data = required.Data
@@ -154,13 +290,19 @@ func applyConfigMap(ctx context.Context, client coreclientv1.Client, recorder re
 		recorder.Event(required, corev1.EventTypeWarning, ResourceUpdateFailedEvent, err.Error())
 		return false, err
 	}
-	recorder.Event(toWrite, corev1.EventTypeNormal, ResourceUpdateSuccessEvent, "Resource was successfully updated")
+	message := "Resource was successfully updated"
+	if len(removedKeys) > 0 {
+		sort.Strings(removedKeys)
+		klog.Infof("Removed keys %v of configmap %s/%s which are not managed by the operator", removedKeys, toWrite.Namespace, toWrite.Name)
+		message = fmt.Sprintf("%s, removed keys: %s", message, strings.Join(removedKeys, ", "))
+	}
+	recorder.Event(toWrite, corev1.EventTypeNormal, ResourceUpdateSuccessEvent, message)
 	return true, err
 }
 
 func applyDeployment(ctx context.Context, client coreclientv1.Client, recorder record.EventRecorder, requiredOriginal *appsv1.Deployment) (bool, error) {
 	required := requiredOriginal.DeepCopy()
-	if err := annotatePodSpecWithRelatedConfigsHash(ctx, client, required.Namespace, &required.Spec.Template); err != nil {
+	if err := annotatePodSpecWithRelatedConfigsHash(ctx, client, required.Namespace, required.Annotations, &required.Spec.Template); err != nil {
 		klog.V(3).Infof("Can not check related configs for %s/%s: %v", required.GetObjectKind(), required.GetName(), err)
 		recorder.Event(required, corev1.EventTypeWarning, ConfigCheckFailedEvent, err.Error())
 	}
@@ -194,7 +336,8 @@ func applyDeployment(ctx context.Context, client coreclientv1.Client, recorder r
 	}
 
 	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, required.ObjectMeta)
-	if !*modified && expectedGeneration == fmt.Sprintf("%x", existingCopy.GetGeneration()) {
+	inSync := !*modified && expectedGeneration == fmt.Sprintf("%x", existingCopy.GetGeneration())
+	if inSync && !isForceApply(ctx) {
 		return false, nil
 	}
 
@@ -225,31 +368,72 @@ func applyDeployment(ctx context.Context, client coreclientv1.Client, recorder r
 		}
 
 		required.Annotations[generationAnnotation] = "1"
-		if err := client.Create(ctx, required); err != nil {
+		if err := createWithRetry(ctx, client, required); err != nil {
 			recorder.Event(required, corev1.EventTypeWarning, ResourceCreateFailedEvent, err.Error())
-			return false, fmt.Errorf("deployment recreation failed: %v", err)
+			// Never leave the cluster without a deployment, put back the one which was deleted
+			if restoreErr := createWithRetry(ctx, client, restorableDeployment(existing)); restoreErr != nil {
+				recorder.Event(existing, corev1.EventTypeWarning, ResourceRestoreFailedEvent, restoreErr.Error())
+				return false, fmt.Errorf("deployment recreation failed: %v, previous deployment restoration failed: %v", err, restoreErr)
+			}
+			recorder.Event(existing, corev1.EventTypeNormal, ResourceRestoreSuccessEvent, "Previous resource was restored after recreation failure")
+			return false, fmt.Errorf("deployment recreation failed, previous deployment restored: %v", err)
 		}
 		recorder.Event(required, corev1.EventTypeNormal, RecreateSuccessEvent, "Resource was successfully recreated")
 		return true, nil
 	}
 
-	// at this point we know that we're going to perform a write.  We're just trying to get the object correct
-	toWrite := existingCopy // shallow copy so the code reads easier
-	toWrite.Spec = *required.Spec.DeepCopy()
+	// at this point we know that we're going to perform a write. Another reconciler might write the same object
+	// concurrently, e.g. during leader handoff, so on conflict the object is fetched again and
+	// the generation annotation is computed from the fresh state.
+	updated := false
+	firstAttempt := true
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !firstAttempt {
+			fresh := &appsv1.Deployment{}
+			if err := client.Get(ctx, coreclientv1.ObjectKeyFromObject(required), fresh); err != nil {
+				return err
+			}
+			existingCopy = fresh.DeepCopy()
+			*modified = false
+			resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, required.ObjectMeta)
+			inSync = !*modified && existingCopy.Annotations[generationAnnotation] == fmt.Sprintf("%x", existingCopy.GetGeneration())
+			if inSync {
+				// The concurrent write has already brought the object to the required state
+				return nil
+			}
+		}
+		firstAttempt = false
 
-	toWrite.Annotations[generationAnnotation] = fmt.Sprintf("%x", existingCopy.GetGeneration()+1)
+		toWrite := existingCopy // shallow copy so the code reads easier
+		toWrite.Spec = *required.Spec.DeepCopy()
 
-	if err := client.Update(ctx, toWrite); err != nil {
+		// A forced write of an object in sync does not change its spec, so the generation is not bumped
+		nextGeneration := existingCopy.GetGeneration() + 1
+		if inSync {
+			nextGeneration = existingCopy.GetGeneration()
+		}
+		toWrite.Annotations[generationAnnotation] = fmt.Sprintf("%x", nextGeneration)
+
+		if err := client.Update(ctx, toWrite); err != nil {
+			return err
+		}
+		updated = true
+		return nil
+	})
+	if err != nil {
 		recorder.Event(required, corev1.EventTypeWarning, ResourceUpdateFailedEvent, err.Error())
 		return false, err
 	}
+	if !updated {
+		return false, nil
+	}
 	recorder.Event(required, corev1.EventTypeNormal, ResourceUpdateSuccessEvent, "Resource was successfully updated")
 	return true, nil
 }
 
 func applyDaemonSet(ctx context.Context, client coreclientv1.Client, recorder record.EventRecorder, requiredOriginal *appsv1.DaemonSet) (bool, error) {
 	required := requiredOriginal.DeepCopy()
-	if err := annotatePodSpecWithRelatedConfigsHash(ctx, client, required.Namespace, &required.Spec.Template); err != nil {
+	if err := annotatePodSpecWithRelatedConfigsHash(ctx, client, required.Namespace, required.Annotations, &required.Spec.Template); err != nil {
 		klog.V(3).Infof("Can not check related configs for %s/%s: %v", required.GetObjectKind(), required.GetName(), err)
 		recorder.Event(required, corev1.EventTypeWarning, ConfigCheckFailedEvent, err.Error())
 	}
@@ -283,7 +467,8 @@ func applyDaemonSet(ctx context.Context, client coreclientv1.Client, recorder re
 	}
 
 	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, required.ObjectMeta)
-	if !*modified && expectedGeneration == fmt.Sprintf("%x", existingCopy.GetGeneration()) {
+	inSync := !*modified && expectedGeneration == fmt.Sprintf("%x", existingCopy.GetGeneration())
+	if inSync && !isForceApply(ctx) {
 		return false, nil
 	}
 
@@ -322,13 +507,145 @@ func applyDaemonSet(ctx context.Context, client coreclientv1.Client, recorder re
 		return true, nil
 	}
 
-	// at this point we know that we're going to perform a write.  We're just trying to get the object correct
-	toWrite := existingCopy // shallow copy so the code reads easier
-	toWrite.Spec = *required.Spec.DeepCopy()
+	// at this point we know that we're going to perform a write. Another reconciler might write the same object
+	// concurrently, e.g. during leader handoff, so on conflict the object is fetched again and
+	// the generation annotation is computed from the fresh state.
+	updated := false
+	firstAttempt := true
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !firstAttempt {
+			fresh := &appsv1.DaemonSet{}
+			if err := client.Get(ctx, coreclientv1.ObjectKeyFromObject(required), fresh); err != nil {
+				return err
+			}
+			existingCopy = fresh.DeepCopy()
+			*modified = false
+			resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, required.ObjectMeta)
+			inSync = !*modified && existingCopy.Annotations[generationAnnotation] == fmt.Sprintf("%x", existingCopy.GetGeneration())
+			if inSync {
+				// The concurrent write has already brought the object to the required state
+				return nil
+			}
+		}
+		firstAttempt = false
 
-	toWrite.Annotations[generationAnnotation] = fmt.Sprintf("%x", existingCopy.GetGeneration()+1)
+		toWrite := existingCopy // shallow copy so the code reads easier
+		toWrite.Spec = *required.Spec.DeepCopy()
 
-	if err := client.Update(ctx, toWrite); err != nil {
+		// A forced write of an object in sync does not change its spec, so the generation is not bumped
+		nextGeneration := existingCopy.GetGeneration() + 1
+		if inSync {
+			nextGeneration = existingCopy.GetGeneration()
+		}
+		toWrite.Annotations[generationAnnotation] = fmt.Sprintf("%x", nextGeneration)
+
+		if err := client.Update(ctx, toWrite); err != nil {
+			return err
+		}
+		updated = true
+		return nil
+	})
+	if err != nil {
+		recorder.Event(required, corev1.EventTypeWarning, ResourceUpdateFailedEvent, err.Error())
+		return false, err
+	}
+	if !updated {
+		return false, nil
+	}
+	recorder.Event(required, corev1.EventTypeNormal, ResourceUpdateSuccessEvent, "Resource was successfully updated")
+	return true, nil
+}
+
+// applyServiceAccount ensures labels and annotations of the required service account are set.
+// Secrets and image pull secrets are populated by other parties, e.g. the token and image pull secret controllers,
+// so they are preserved on an existing service account unless explicitly set on the required one.
+func applyServiceAccount(ctx context.Context, client coreclientv1.Client, recorder record.EventRecorder, requiredOriginal *corev1.ServiceAccount) (bool, error) {
+	required := requiredOriginal.DeepCopy()
+
+	existing := &corev1.ServiceAccount{}
+	err := client.Get(ctx, coreclientv1.ObjectKeyFromObject(required), existing)
+	if apierrors.IsNotFound(err) {
+		if err := client.Create(ctx, required); err != nil {
+			recorder.Event(required, corev1.EventTypeWarning, ResourceCreateFailedEvent, err.Error())
+			return false, fmt.Errorf("service account creation failed: %v", err)
+		}
+		recorder.Event(required, corev1.EventTypeNormal, ResourceCreateSuccessEvent, "Resource was successfully created")
+		return true, nil
+	}
+	if err != nil {
+		recorder.Event(required, corev1.EventTypeWarning, ResourceUpdateFailedEvent, err.Error())
+		return false, fmt.Errorf("failed to get service account for update: %v", err)
+	}
+
+	modified := ptr.To[bool](false)
+	existingCopy := existing.DeepCopy()
+
+	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, required.ObjectMeta)
+	if required.Secrets != nil && !equality.Semantic.DeepEqual(existingCopy.Secrets, required.Secrets) {
+		existingCopy.Secrets = required.Secrets
+		*modified = true
+	}
+	if required.ImagePullSecrets != nil && !equality.Semantic.DeepEqual(existingCopy.ImagePullSecrets, required.ImagePullSecrets) {
+		existingCopy.ImagePullSecrets = required.ImagePullSecrets
+		*modified = true
+	}
+	if required.AutomountServiceAccountToken != nil && !equality.Semantic.DeepEqual(existingCopy.AutomountServiceAccountToken, required.AutomountServiceAccountToken) {
+		existingCopy.AutomountServiceAccountToken = required.AutomountServiceAccountToken
+		*modified = true
+	}
+	if !*modified && !isForceApply(ctx) {
+		return false, nil
+	}
+
+	if err := client.Update(ctx, existingCopy); err != nil {
+		recorder.Event(required, corev1.EventTypeWarning, ResourceUpdateFailedEvent, err.Error())
+		return false, err
+	}
+	recorder.Event(required, corev1.EventTypeNormal, ResourceUpdateSuccessEvent, "Resource was successfully updated")
+	return true, nil
+}
+
+// applyService ensures the selector, type and ports of the required service are set. Fields allocated
+// by the API server, e.g. the cluster IP, are preserved.
+func applyService(ctx context.Context, client coreclientv1.Client, recorder record.EventRecorder, requiredOriginal *corev1.Service) (bool, error) {
+	required := requiredOriginal.DeepCopy()
+
+	existing := &corev1.Service{}
+	err := client.Get(ctx, coreclientv1.ObjectKeyFromObject(required), existing)
+	if apierrors.IsNotFound(err) {
+		if err := client.Create(ctx, required); err != nil {
+			recorder.Event(required, corev1.EventTypeWarning, ResourceCreateFailedEvent, err.Error())
+			return false, fmt.Errorf("service creation failed: %v", err)
+		}
+		recorder.Event(required, corev1.EventTypeNormal, ResourceCreateSuccessEvent, "Resource was successfully created")
+		return true, nil
+	}
+	if err != nil {
+		recorder.Event(required, corev1.EventTypeWarning, ResourceUpdateFailedEvent, err.Error())
+		return false, fmt.Errorf("failed to get service for update: %v", err)
+	}
+
+	modified := ptr.To[bool](false)
+	existingCopy := existing.DeepCopy()
+
+	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, required.ObjectMeta)
+	if !equality.Semantic.DeepEqual(existingCopy.Spec.Selector, required.Spec.Selector) {
+		existingCopy.Spec.Selector = required.Spec.Selector
+		*modified = true
+	}
+	if required.Spec.Type != "" && existingCopy.Spec.Type != required.Spec.Type {
+		existingCopy.Spec.Type = required.Spec.Type
+		*modified = true
+	}
+	if !equality.Semantic.DeepDerivative(required.Spec.Ports, existingCopy.Spec.Ports) {
+		existingCopy.Spec.Ports = required.Spec.Ports
+		*modified = true
+	}
+	if !*modified && !isForceApply(ctx) {
+		return false, nil
+	}
+
+	if err := client.Update(ctx, existingCopy); err != nil {
 		recorder.Event(required, corev1.EventTypeWarning, ResourceUpdateFailedEvent, err.Error())
 		return false, err
 	}
@@ -360,7 +677,7 @@ func applyPodDisruptionBudget(ctx context.Context, client coreclientv1.Client, r
 	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, required.ObjectMeta)
 	contentSame := equality.Semantic.DeepEqual(existingCopy.Spec, required.Spec)
 
-	if !*modified && contentSame {
+	if !*modified && contentSame && !isForceApply(ctx) {
 		return false, nil
 	}
 
@@ -400,7 +717,7 @@ func applyRole(ctx context.Context, client coreclientv1.Client, recorder record.
 	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, required.ObjectMeta)
 	contentSame := equality.Semantic.DeepEqual(existingCopy.Rules, required.Rules)
 
-	if !*modified && contentSame {
+	if !*modified && contentSame && !isForceApply(ctx) {
 		return false, nil
 	}
 
@@ -438,7 +755,7 @@ func applyClusterRole(ctx context.Context, client coreclientv1.Client, recorder
 	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, required.ObjectMeta)
 	contentSame := equality.Semantic.DeepEqual(existingCopy.Rules, required.Rules)
 
-	if !*modified && contentSame {
+	if !*modified && contentSame && !isForceApply(ctx) {
 		return false, nil
 	}
 
@@ -495,7 +812,7 @@ func applyRoleBinding(ctx context.Context, client coreclientv1.Client, recorder
 	subjectsAreSame := equality.Semantic.DeepEqual(existingCopy.Subjects, requiredCopy.Subjects)
 	roleRefIsSame := equality.Semantic.DeepEqual(existingCopy.RoleRef, requiredCopy.RoleRef)
 
-	if subjectsAreSame && roleRefIsSame && !*modified {
+	if subjectsAreSame && roleRefIsSame && !*modified && !isForceApply(ctx) {
 		return false, nil
 	}
 
@@ -552,7 +869,7 @@ func applyClusterRoleBinding(ctx context.Context, client coreclientv1.Client, re
 	subjectsAreSame := equality.Semantic.DeepEqual(existingCopy.Subjects, requiredCopy.Subjects)
 	roleRefIsSame := equality.Semantic.DeepEqual(existingCopy.RoleRef, requiredCopy.RoleRef)
 
-	if subjectsAreSame && roleRefIsSame && !*modified {
+	if subjectsAreSame && roleRefIsSame && !*modified && !isForceApply(ctx) {
 		return false, nil
 	}
 
@@ -591,7 +908,7 @@ func applyValidatingAdmissionPolicy(ctx context.Context, client coreclientv1.Cli
 
 	resourcemerge.EnsureObjectMeta(&modified, &existingCopy.ObjectMeta, required.ObjectMeta)
 	specEquivalent := equality.Semantic.DeepDerivative(required.Spec, existingCopy.Spec)
-	if specEquivalent && !modified {
+	if specEquivalent && !modified && !isForceApply(ctx) {
 		return false, nil
 	}
 	// at this point we know that we're going to perform a write.  We're just trying to get the object correct
@@ -633,7 +950,7 @@ func applyValidatingAdmissionPolicyBinding(ctx context.Context, client coreclien
 
 	resourcemerge.EnsureObjectMeta(&modified, &existingCopy.ObjectMeta, required.ObjectMeta)
 	specEquivalent := equality.Semantic.DeepDerivative(required.Spec, existingCopy.Spec)
-	if specEquivalent && !modified {
+	if specEquivalent && !modified && !isForceApply(ctx) {
 		return false, nil
 	}
 	// at this point we know that we're going to perform a write.  We're just trying to get the object correct
@@ -650,3 +967,55 @@ func applyValidatingAdmissionPolicyBinding(ctx context.Context, client coreclien
 
 	return true, nil
 }
+
+// applyValidatingWebhookConfiguration ensures the webhooks of the required configuration are set. CA bundles
+// injected into existing webhooks, e.g. by the service CA operator, are preserved unless explicitly required.
+func applyValidatingWebhookConfiguration(ctx context.Context, client coreclientv1.Client, recorder record.EventRecorder,
+	requiredOriginal *admissionregistrationv1.ValidatingWebhookConfiguration) (bool, error) {
+	required := requiredOriginal.DeepCopy()
+
+	existing := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	err := client.Get(ctx, coreclientv1.ObjectKeyFromObject(requiredOriginal), existing)
+	if apierrors.IsNotFound(err) {
+		if err := client.Create(ctx, required); err != nil {
+			recorder.Event(required, corev1.EventTypeWarning, ResourceCreateFailedEvent, err.Error())
+			return false, fmt.Errorf("validatingwebhookconfiguration creation failed: %v", err)
+		}
+		recorder.Event(required, corev1.EventTypeNormal, ResourceCreateSuccessEvent, "Resource was successfully created")
+		return true, nil
+	} else if err != nil {
+		recorder.Event(required, corev1.EventTypeWarning, ResourceUpdateFailedEvent, err.Error())
+		return false, fmt.Errorf("failed to get validatingwebhookconfiguration for update: %v", err)
+	}
+
+	existingCABundles := map[string][]byte{}
+	for _, webhook := range existing.Webhooks {
+		existingCABundles[webhook.Name] = webhook.ClientConfig.CABundle
+	}
+	for i, webhook := range required.Webhooks {
+		if len(webhook.ClientConfig.CABundle) == 0 {
+			required.Webhooks[i].ClientConfig.CABundle = existingCABundles[webhook.Name]
+		}
+	}
+
+	modified := false
+	existingCopy := existing.DeepCopy()
+
+	resourcemerge.EnsureObjectMeta(&modified, &existingCopy.ObjectMeta, required.ObjectMeta)
+	webhooksEquivalent := equality.Semantic.DeepDerivative(required.Webhooks, existingCopy.Webhooks)
+	if webhooksEquivalent && !modified && !isForceApply(ctx) {
+		return false, nil
+	}
+	// at this point we know that we're going to perform a write.  We're just trying to get the object correct
+	toWrite := existingCopy // shallow copy so the code reads easier
+	toWrite.Webhooks = required.Webhooks
+
+	klog.V(2).Infof("ValidatingWebhookConfiguration %q changes: %v", required.GetName(), resourceapply.JSONPatchNoError(existing, toWrite))
+
+	if err := client.Update(ctx, toWrite); err != nil {
+		recorder.Event(required, corev1.EventTypeWarning, ResourceUpdateFailedEvent, err.Error())
+		return false, err
+	}
+	recorder.Event(required, corev1.EventTypeNormal, ResourceUpdateSuccessEvent, "Resource was successfully updated")
+	return true, nil
+}
@@ -1,21 +1,30 @@
 package resourceapply
 
 import (
+	"context"
 	"fmt"
+	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/openshift/cluster-api-actuator-pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	appsclientv1 "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 const (
@@ -146,6 +155,46 @@ var _ = Describe("applyConfigMap", func() {
 			},
 		),
 	)
+
+	It("Removes keys which are not required and names them in the update event", func() {
+		existing := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: namespaceName,
+			},
+			Data: map[string]string{
+				"configmap": "value",
+				"user-key":  "user-value",
+			},
+			BinaryData: map[string][]byte{
+				"user-bin-key": []byte("value"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, existing)).To(Succeed())
+
+		recorder := record.NewFakeRecorder(1000)
+		input := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: namespaceName,
+			},
+			Data: map[string]string{
+				"configmap": "value",
+			},
+		}
+		modified, err := applyConfigMap(ctx, k8sClient, recorder, input)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(modified).To(BeTrue())
+
+		updated := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, appsclientv1.ObjectKeyFromObject(input), updated)).To(Succeed())
+		Expect(updated.Data).To(Equal(map[string]string{"configmap": "value"}))
+		Expect(updated.BinaryData).To(BeEmpty())
+
+		Expect(recorder.Events).To(Receive(Equal(
+			"Normal ResourceUpdateSuccess Resource was successfully updated, removed keys: binaryData.user-bin-key, data.user-key",
+		)))
+	})
 })
 
 type deploymentSupplier func(string) *appsv1.Deployment
@@ -456,6 +505,146 @@ var _ = Describe("applyDeployment", func() {
 		),
 	)
 
+	It("Restores previous deployment when recreation keeps failing", func() {
+		eventRecorder := record.NewFakeRecorder(1000)
+
+		actualDeployment := workloadDeploymentWithDefaultSpecHash(namespaceName)
+		Expect(k8sClient.Create(ctx, actualDeployment)).To(Succeed())
+
+		desiredDeployment := workloadDeployment(namespaceName)
+		desiredDeployment.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"bar": "baz",
+			},
+		}
+		desiredDeployment.Spec.Template.Labels = map[string]string{"bar": "baz"}
+
+		watchClient, err := appsclientv1.NewWithWatch(cfg, appsclientv1.Options{Scheme: k8sClient.Scheme()})
+		Expect(err).NotTo(HaveOccurred())
+		failingClient := interceptor.NewClient(watchClient, interceptor.Funcs{
+			Create: func(ctx context.Context, c appsclientv1.WithWatch, obj appsclientv1.Object, opts ...appsclientv1.CreateOption) error {
+				createOptions := &appsclientv1.CreateOptions{}
+				createOptions.ApplyOptions(opts)
+				if deployment, ok := obj.(*appsv1.Deployment); ok && len(createOptions.DryRun) == 0 && deployment.Spec.Selector.MatchLabels["bar"] == "baz" {
+					return fmt.Errorf("admission webhook denied the request")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		})
+
+		_, err = applyDeployment(ctx, failingClient, eventRecorder, desiredDeployment)
+		Expect(err).To(MatchError(ContainSubstring("deployment recreation failed, previous deployment restored")))
+
+		restoredDeployment := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, appsclientv1.ObjectKeyFromObject(desiredDeployment), restoredDeployment)).To(Succeed())
+		Expect(restoredDeployment.UID).ShouldNot(BeEquivalentTo(actualDeployment.UID))
+		Expect(restoredDeployment.Spec.Selector).To(BeEquivalentTo(actualDeployment.Spec.Selector))
+		Expect(restoredDeployment.Annotations[specHashAnnotation]).To(BeEquivalentTo(actualDeployment.Annotations[specHashAnnotation]))
+	})
+
+	It("Retries update with generation computed from the fresh object on conflict", func() {
+		eventRecorder := record.NewFakeRecorder(1000)
+
+		actualDeployment := workloadDeploymentWithDefaultSpecHash(namespaceName)
+		Expect(k8sClient.Create(ctx, actualDeployment)).To(Succeed())
+
+		desiredDeployment := workloadDeployment(namespaceName)
+		desiredDeployment.Spec.Template.Spec.Containers[0].Image = "docker-registry/img:new"
+
+		watchClient, err := appsclientv1.NewWithWatch(cfg, appsclientv1.Options{Scheme: k8sClient.Scheme()})
+		Expect(err).NotTo(HaveOccurred())
+		updateAttempts := 0
+		racingClient := interceptor.NewClient(watchClient, interceptor.Funcs{
+			Update: func(ctx context.Context, c appsclientv1.WithWatch, obj appsclientv1.Object, opts ...appsclientv1.UpdateOption) error {
+				updateAttempts++
+				if updateAttempts == 1 {
+					// Simulate another reconciler bumping the generation right before our write
+					concurrent := &appsv1.Deployment{}
+					if err := k8sClient.Get(ctx, appsclientv1.ObjectKeyFromObject(obj), concurrent); err != nil {
+						return err
+					}
+					concurrent.Spec.Replicas = ptr.To[int32](2)
+					if err := k8sClient.Update(ctx, concurrent); err != nil {
+						return err
+					}
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		})
+
+		updated, err := applyDeployment(ctx, racingClient, eventRecorder, desiredDeployment)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updateAttempts).To(Equal(2))
+
+		resultDeployment := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, appsclientv1.ObjectKeyFromObject(desiredDeployment), resultDeployment)).To(Succeed())
+		// Initial generation, the concurrent update and the retried one
+		Expect(resultDeployment.GetGeneration()).To(BeEquivalentTo(3))
+		Expect(resultDeployment.Annotations[generationAnnotation]).To(Equal(fmt.Sprintf("%x", resultDeployment.GetGeneration())))
+		Expect(resultDeployment.Spec.Replicas).To(Equal(desiredDeployment.Spec.Replicas))
+		Expect(resultDeployment.Spec.Template.Spec.Containers[0].Image).To(Equal("docker-registry/img:new"))
+	})
+
+	It("Does not update deployment when only stripped annotations change", func() {
+		eventRecorder := record.NewFakeRecorder(1000)
+
+		withLastApplied := func(value string) *appsv1.Deployment {
+			d := workloadDeployment(namespaceName)
+			d.Annotations[corev1.LastAppliedConfigAnnotation] = value
+			d.Spec.Template.Annotations[corev1.LastAppliedConfigAnnotation] = value
+			return d
+		}
+
+		desired := StripAnnotations(withLastApplied("first"), DefaultStrippedAnnotations).(*appsv1.Deployment)
+		updated, err := applyDeployment(ctx, k8sClient, eventRecorder, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+
+		desired = StripAnnotations(withLastApplied("second"), DefaultStrippedAnnotations).(*appsv1.Deployment)
+		updated, err = applyDeployment(ctx, k8sClient, eventRecorder, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+
+		resultDeployment := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, appsclientv1.ObjectKeyFromObject(desired), resultDeployment)).To(Succeed())
+		Expect(resultDeployment.Annotations).NotTo(HaveKey(corev1.LastAppliedConfigAnnotation))
+		Expect(resultDeployment.Spec.Template.Annotations).NotTo(HaveKey(corev1.LastAppliedConfigAnnotation))
+
+		// Without stripping the annotation change makes it to the deployment
+		updated, err = applyDeployment(ctx, k8sClient, eventRecorder, withLastApplied("third"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+})
+
+var _ = Describe("StripAnnotations", func() {
+	It("Returns the object as-is when there is nothing to strip", func() {
+		deployment := workloadDeployment("test")
+		Expect(StripAnnotations(deployment, nil)).To(BeIdenticalTo(deployment))
+	})
+
+	It("Strips annotations from object and pod template metadata without mutating the object", func() {
+		daemonSet := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{"foo": "bar", "strip": "me"},
+			},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{"strip": "me", "baz": "qux"},
+					},
+				},
+			},
+		}
+		original := daemonSet.DeepCopy()
+
+		stripped := StripAnnotations(daemonSet, []string{"strip"}).(*appsv1.DaemonSet)
+		Expect(stripped.Annotations).To(Equal(map[string]string{"foo": "bar"}))
+		Expect(stripped.Spec.Template.Annotations).To(Equal(map[string]string{"baz": "qux"}))
+		Expect(daemonSet).To(Equal(original))
+	})
 })
 
 type daemonSetSupplier func(string) *appsv1.DaemonSet
@@ -854,6 +1043,300 @@ var _ = Describe("applyPodDisruptionBudget", func() {
 	)
 })
 
+type serviceSupplier func(string) *corev1.Service
+
+type applyServiceArguments struct {
+	inputFn        serviceSupplier
+	existingFn     serviceSupplier
+	expectModified bool
+}
+
+var _ = Describe("applyService", func() {
+	var namespaceName string
+
+	BeforeEach(func() {
+		By("Setting up a namespace for the test")
+		ns := &corev1.Namespace{}
+		ns.SetGenerateName(namespaceNamePrefix)
+		Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+		namespaceName = ns.GetName()
+	})
+
+	AfterEach(func() {
+		testutils.CleanupResources(Default, ctx, cfg, k8sClient, namespaceName,
+			&corev1.Service{},
+		)
+	})
+
+	DescribeTable("Updates service when expected",
+		func(args applyServiceArguments) {
+			recorder := record.NewFakeRecorder(1000)
+
+			if args.existingFn != nil {
+				existing := args.existingFn(namespaceName)
+				Expect(k8sClient.Create(ctx, existing)).To(Succeed())
+			}
+
+			input := args.inputFn(namespaceName)
+			actualModified, err := applyService(ctx, k8sClient, recorder, input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args.expectModified).To(BeEquivalentTo(actualModified), "Resource was modified")
+		},
+		Entry("When it does not exist it is created",
+			applyServiceArguments{
+				inputFn:        service,
+				existingFn:     nil,
+				expectModified: true,
+			},
+		),
+		Entry("When it is equal it does not update, keeping the allocated cluster IP",
+			applyServiceArguments{
+				inputFn:        service,
+				existingFn:     service,
+				expectModified: false,
+			},
+		),
+		Entry("When the target port changes it is updated",
+			applyServiceArguments{
+				inputFn: func(namespace string) *corev1.Service {
+					svc := service(namespace)
+					svc.Spec.Ports[0].TargetPort = intstr.FromInt32(9443)
+					return svc
+				},
+				existingFn:     service,
+				expectModified: true,
+			},
+		),
+	)
+})
+
+var _ = Describe("applyValidatingWebhookConfiguration", func() {
+	AfterEach(func() {
+		Expect(k8sClient.DeleteAllOf(ctx, &admissionregistrationv1.ValidatingWebhookConfiguration{},
+			appsclientv1.MatchingLabels{"test": "apply-webhook-configuration"})).To(Succeed())
+	})
+
+	It("Preserves the injected CA bundle", func() {
+		recorder := record.NewFakeRecorder(1000)
+
+		modified, err := applyValidatingWebhookConfiguration(ctx, k8sClient, recorder, validatingWebhookConfiguration())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(modified).To(BeTrue())
+
+		By("Injecting the CA bundle")
+		existing := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		Expect(k8sClient.Get(ctx, appsclientv1.ObjectKeyFromObject(validatingWebhookConfiguration()), existing)).To(Succeed())
+		existing.Webhooks[0].ClientConfig.CABundle = []byte("injected-ca-bundle")
+		Expect(k8sClient.Update(ctx, existing)).To(Succeed())
+
+		modified, err = applyValidatingWebhookConfiguration(ctx, k8sClient, recorder, validatingWebhookConfiguration())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(modified).To(BeFalse())
+
+		By("Changing the webhook rules")
+		required := validatingWebhookConfiguration()
+		required.Webhooks[0].Rules[0].Operations = []admissionregistrationv1.OperationType{admissionregistrationv1.Create}
+		modified, err = applyValidatingWebhookConfiguration(ctx, k8sClient, recorder, required)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(modified).To(BeTrue())
+
+		Expect(k8sClient.Get(ctx, appsclientv1.ObjectKeyFromObject(required), existing)).To(Succeed())
+		Expect(existing.Webhooks[0].Rules).To(Equal(required.Webhooks[0].Rules))
+		Expect(existing.Webhooks[0].ClientConfig.CABundle).To(Equal([]byte("injected-ca-bundle")))
+	})
+})
+
+type serviceAccountSupplier func(string) *corev1.ServiceAccount
+
+type applyServiceAccountArguments struct {
+	inputFn        serviceAccountSupplier
+	existingFn     serviceAccountSupplier
+	expectModified bool
+	expectedFn     serviceAccountSupplier
+}
+
+var _ = Describe("applyServiceAccount", func() {
+	var namespaceName string
+
+	BeforeEach(func() {
+		By("Setting up a namespace for the test")
+		ns := &corev1.Namespace{}
+		ns.SetGenerateName(namespaceNamePrefix)
+		Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+		namespaceName = ns.GetName()
+	})
+
+	AfterEach(func() {
+		testutils.CleanupResources(Default, ctx, cfg, k8sClient, namespaceName,
+			&corev1.ServiceAccount{},
+		)
+	})
+
+	DescribeTable("Updates service account when expected",
+		func(args applyServiceAccountArguments) {
+			recorder := record.NewFakeRecorder(1000)
+
+			if args.existingFn != nil {
+				existing := args.existingFn(namespaceName)
+				Expect(k8sClient.Create(ctx, existing)).To(Succeed())
+			}
+
+			input := args.inputFn(namespaceName)
+			actualModified, err := applyServiceAccount(ctx, k8sClient, recorder, input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args.expectModified).To(BeEquivalentTo(actualModified), "Resource was modified")
+
+			if args.expectedFn != nil {
+				expected := args.expectedFn(namespaceName)
+				actual := &corev1.ServiceAccount{}
+				Expect(k8sClient.Get(ctx, appsclientv1.ObjectKeyFromObject(expected), actual)).To(Succeed())
+				Expect(actual.Annotations).To(Equal(expected.Annotations))
+				Expect(actual.ImagePullSecrets).To(Equal(expected.ImagePullSecrets))
+			}
+		},
+		Entry("When it does not exist it is created",
+			applyServiceAccountArguments{
+				inputFn:        serviceAccount,
+				existingFn:     nil,
+				expectModified: true,
+			},
+		),
+		Entry("When it is equal it does not update",
+			applyServiceAccountArguments{
+				inputFn:        serviceAccount,
+				existingFn:     serviceAccount,
+				expectModified: false,
+			},
+		),
+		Entry("When there is a new annotation it is updated",
+			applyServiceAccountArguments{
+				inputFn: func(namespace string) *corev1.ServiceAccount {
+					sa := serviceAccount(namespace)
+					sa.Annotations = map[string]string{"new": "merge"}
+					return sa
+				},
+				existingFn: func(namespace string) *corev1.ServiceAccount {
+					sa := serviceAccount(namespace)
+					sa.Annotations = map[string]string{"bar": "baz"}
+					return sa
+				},
+				expectModified: true,
+				expectedFn: func(namespace string) *corev1.ServiceAccount {
+					sa := serviceAccount(namespace)
+					sa.Annotations = map[string]string{"bar": "baz", "new": "merge"}
+					return sa
+				},
+			},
+		),
+		Entry("When the existing service account has image pull secrets they are preserved",
+			applyServiceAccountArguments{
+				inputFn: serviceAccount,
+				existingFn: func(namespace string) *corev1.ServiceAccount {
+					sa := serviceAccount(namespace)
+					sa.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "cloud-controller-manager-dockercfg-abcde"}}
+					return sa
+				},
+				expectModified: false,
+				expectedFn: func(namespace string) *corev1.ServiceAccount {
+					sa := serviceAccount(namespace)
+					sa.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "cloud-controller-manager-dockercfg-abcde"}}
+					return sa
+				},
+			},
+		),
+		Entry("When image pull secrets are explicitly required they are overridden",
+			applyServiceAccountArguments{
+				inputFn: func(namespace string) *corev1.ServiceAccount {
+					sa := serviceAccount(namespace)
+					sa.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "required-pull-secret"}}
+					return sa
+				},
+				existingFn: func(namespace string) *corev1.ServiceAccount {
+					sa := serviceAccount(namespace)
+					sa.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "cloud-controller-manager-dockercfg-abcde"}}
+					return sa
+				},
+				expectModified: true,
+				expectedFn: func(namespace string) *corev1.ServiceAccount {
+					sa := serviceAccount(namespace)
+					sa.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "required-pull-secret"}}
+					return sa
+				},
+			},
+		),
+	)
+})
+
+func service(namespace string) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cloud-provider-webhook",
+			Namespace: namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"k8s-app": "cloud-provider-webhook"},
+			Ports: []corev1.ServicePort{{
+				Name:       "webhook",
+				Port:       443,
+				TargetPort: intstr.FromInt32(8443),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
+
+func validatingWebhookConfiguration() *admissionregistrationv1.ValidatingWebhookConfiguration {
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ValidatingWebhookConfiguration",
+			APIVersion: "admissionregistration.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cloud-provider-webhook",
+			Labels: map[string]string{"test": "apply-webhook-configuration"},
+		},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name: "cloud-provider-webhook.cloud-controller-manager.openshift.io",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{
+					Namespace: "openshift-cloud-controller-manager",
+					Name:      "cloud-provider-webhook",
+					Path:      ptr.To("/validate"),
+					Port:      ptr.To[int32](443),
+				},
+			},
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Update},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"nodes"},
+				},
+			}},
+			FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+			AdmissionReviewVersions: []string{"v1"},
+		}},
+	}
+}
+
+func serviceAccount(namespace string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cloud-controller-manager",
+			Namespace: namespace,
+		},
+	}
+}
+
 func workloadDeployment(namespace string) *appsv1.Deployment {
 	return &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -1010,3 +1493,77 @@ func podDisruptionBudget(namespace string) *policyv1.PodDisruptionBudget {
 		},
 	}
 }
+
+func TestCreateWithRetry(t *testing.T) {
+	defaultBackoff := recreateBackoff
+	recreateBackoff.Duration = time.Millisecond
+	t.Cleanup(func() { recreateBackoff = defaultBackoff })
+
+	deploymentResource := schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+	tc := []struct {
+		name             string
+		errs             []error
+		expectedAttempts int
+		expectedError    bool
+	}{{
+		name:             "Created at the first attempt",
+		expectedAttempts: 1,
+	}, {
+		name:             "Transient errors are retried",
+		errs:             []error{apierrors.NewInternalError(fmt.Errorf("etcd leader changed")), apierrors.NewServiceUnavailable("unavailable")},
+		expectedAttempts: 3,
+	}, {
+		name:             "Throttling is retried",
+		errs:             []error{apierrors.NewTooManyRequests("slow down", 1)},
+		expectedAttempts: 2,
+	}, {
+		name:             "Invalid object is not retried",
+		errs:             []error{apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "foo", nil)},
+		expectedAttempts: 1,
+		expectedError:    true,
+	}, {
+		name:             "Forbidden request is not retried",
+		errs:             []error{apierrors.NewForbidden(deploymentResource, "foo", fmt.Errorf("denied"))},
+		expectedAttempts: 1,
+		expectedError:    true,
+	}, {
+		name:             "Admission webhook denial is not retried",
+		errs:             []error{fmt.Errorf("admission webhook denied the request")},
+		expectedAttempts: 1,
+		expectedError:    true,
+	}, {
+		name: "Transient errors are retried until the backoff is exhausted",
+		errs: []error{
+			apierrors.NewServerTimeout(deploymentResource, "create", 1),
+			apierrors.NewServerTimeout(deploymentResource, "create", 1),
+			apierrors.NewServerTimeout(deploymentResource, "create", 1),
+			apierrors.NewServerTimeout(deploymentResource, "create", 1),
+		},
+		expectedAttempts: 4,
+		expectedError:    true,
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			c := interceptor.NewClient(fake.NewClientBuilder().Build(), interceptor.Funcs{
+				Create: func(ctx context.Context, c appsclientv1.WithWatch, obj appsclientv1.Object, opts ...appsclientv1.CreateOption) error {
+					attempts++
+					if attempts <= len(tc.errs) {
+						return tc.errs[attempts-1]
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			})
+
+			err := createWithRetry(context.Background(), c, workloadDeployment("test"))
+			assert.Equal(t, tc.expectedAttempts, attempts)
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// drainingReconciler wraps a reconciler to let its in-flight reconciles complete once the manager is stopped,
+// for up to the grace period, so operands which are applied together, such as RBAC roles and their bindings,
+// are not left half applied.
+type drainingReconciler struct {
+	gracePeriod time.Duration
+	reconciler  reconcile.Reconciler
+}
+
+// newDrainingReconciler returns a reconciler letting reconciles of the given reconciler complete within
+// the grace period after shutdown is requested. The reconciler is returned as is if there is no grace period.
+func newDrainingReconciler(gracePeriod time.Duration, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	if gracePeriod <= 0 {
+		return reconciler
+	}
+	return &drainingReconciler{
+		gracePeriod: gracePeriod,
+		reconciler:  reconciler,
+	}
+}
+
+func (d *drainingReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	reconcileCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	stop := context.AfterFunc(ctx, func() {
+		klog.Infof("Shutdown requested, waiting up to %s for the in-flight reconcile of %s to complete", d.gracePeriod, req)
+		timer := time.AfterFunc(d.gracePeriod, cancel)
+		context.AfterFunc(reconcileCtx, func() { timer.Stop() })
+	})
+	defer stop()
+
+	return d.reconciler.Reconcile(reconcileCtx, req)
+}
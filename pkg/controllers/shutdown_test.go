@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestDrainingReconciler(t *testing.T) {
+	role := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "cloud-controller-manager", Namespace: "openshift-cloud-controller-manager"}}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloud-controller-manager", Namespace: "openshift-cloud-controller-manager"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "cloud-controller-manager"},
+	}
+
+	// applyRBAC creates the role binding and, once the test cancels the manager context, the role it refers to.
+	applyRBAC := func(cl client.Client, applying chan<- struct{}, cancelled <-chan struct{}) reconcile.Func {
+		return func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+			if err := cl.Create(ctx, binding.DeepCopy()); err != nil {
+				return reconcile.Result{}, err
+			}
+			close(applying)
+			<-cancelled
+			if err := ctx.Err(); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{}, cl.Create(ctx, role.DeepCopy())
+		}
+	}
+
+	t.Run("In-flight reconcile completes after cancellation", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		applying, cancelled := make(chan struct{}), make(chan struct{})
+		r := newDrainingReconciler(time.Minute, applyRBAC(cl, applying, cancelled))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error)
+		go func() {
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			errs <- err
+		}()
+
+		<-applying
+		cancel()
+		close(cancelled)
+		assert.NoError(t, <-errs)
+
+		// Both the binding and its role are applied
+		assert.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(binding), &rbacv1.RoleBinding{}))
+		assert.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(role), &rbacv1.Role{}))
+	})
+
+	t.Run("Reconcile is cancelled once the grace period expires", func(t *testing.T) {
+		var reconcileCtx context.Context
+		r := newDrainingReconciler(10*time.Millisecond, reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+			reconcileCtx = ctx
+			<-ctx.Done()
+			return reconcile.Result{}, ctx.Err()
+		}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Error(t, reconcileCtx.Err())
+	})
+
+	t.Run("Reconcile is cancelled right away without a grace period", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		applying, cancelled := make(chan struct{}), make(chan struct{})
+		r := newDrainingReconciler(0, applyRBAC(cl, applying, cancelled))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error)
+		go func() {
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			errs <- err
+		}()
+
+		<-applying
+		cancel()
+		close(cancelled)
+		assert.ErrorIs(t, <-errs, context.Canceled)
+	})
+}
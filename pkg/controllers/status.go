@@ -2,10 +2,12 @@ package controllers
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"os"
 	"reflect"
 	"strings"
+	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
@@ -15,7 +17,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
 )
 
 // The default set of status change reasons.
@@ -25,6 +30,9 @@ const (
 	ReasonSyncing             = "SyncingResources"
 	ReasonSyncFailed          = "SyncingFailed"
 	ReasonPlatformTechPreview = "PlatformTechPreview"
+	ReasonWorkloadsNotReady   = "WorkloadsNotReady"
+	ReasonOperandRollout      = "OperandRollout"
+	ReasonUnsupportedFeatures = "UnsupportedFeatureGates"
 )
 
 const (
@@ -43,6 +51,11 @@ type ClusterOperatorStatusClient struct {
 	Recorder         record.EventRecorder
 	ManagedNamespace string
 	ReleaseVersion   string
+	// Clock times reconciles for the reconcile duration metric. The real clock is used if unset.
+	Clock clock.PassiveClock
+	// ShutdownGracePeriod is how long in-flight reconciles may take to complete once shutdown is requested.
+	// Reconciles are cancelled right away if unset.
+	ShutdownGracePeriod time.Duration
 }
 
 // setStatusDegraded sets the Degraded condition to True, with the given reason and
@@ -67,7 +80,7 @@ func (r *ClusterOperatorStatusClient) setStatusDegraded(ctx context.Context, rec
 
 	conds := []configv1.ClusterOperatorStatusCondition{
 		newClusterOperatorStatusCondition(configv1.OperatorDegraded, configv1.ConditionTrue,
-			ReasonSyncFailed, message),
+			degradedReason(reconcileErr), message),
 		newClusterOperatorStatusCondition(configv1.OperatorUpgradeable, configv1.ConditionFalse, ReasonAsExpected, ""),
 	}
 
@@ -76,6 +89,15 @@ func (r *ClusterOperatorStatusClient) setStatusDegraded(ctx context.Context, rec
 	return r.syncStatus(ctx, co, conds, overrides)
 }
 
+// degradedReason returns the Degraded condition reason for the given reconcile error.
+func degradedReason(reconcileErr error) string {
+	var unsupportedFeatureGatesErr *config.UnsupportedFeatureGatesError
+	if goerrors.As(reconcileErr, &unsupportedFeatureGatesErr) {
+		return ReasonUnsupportedFeatures
+	}
+	return ReasonSyncFailed
+}
+
 // setStatusProgressing sets the Progressing condition to True, with the given
 // reason and message, and sets the upgradeable condition to True.  It does not
 // modify any existing Available or Degraded conditions.
@@ -111,14 +133,79 @@ func (r *ClusterOperatorStatusClient) setStatusProgressing(ctx context.Context,
 // setStatusAvailable sets the Available condition to True, with the given reason
 // and message, and sets both the Progressing and Degraded conditions to False.
 func (r *ClusterOperatorStatusClient) setStatusAvailable(ctx context.Context, overrides []configv1.ClusterOperatorStatusCondition) error {
+	return r.setStatusAvailableWithMessage(ctx, fmt.Sprintf("Cluster Cloud Controller Manager Operator is available at %s", r.ReleaseVersion), overrides)
+}
+
+// setStatusWorkloadsNotReady sets the Progressing condition to True and the Available condition to False,
+// with a message listing operand workloads which are applied but not ready. It does not modify
+// the Degraded condition, since workloads are expected to be not ready while rolling out.
+func (r *ClusterOperatorStatusClient) setStatusWorkloadsNotReady(ctx context.Context, message string, overrides []configv1.ClusterOperatorStatusCondition) error {
+	co, err := r.getOrCreateClusterOperator(ctx)
+	if err != nil {
+		klog.Errorf("Failed to get or create Cluster Operator: %v", err)
+		return err
+	}
+
+	message = fmt.Sprintf("Waiting for operands to become ready: %s", message)
+	conds := []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(configv1.OperatorAvailable, configv1.ConditionFalse, ReasonWorkloadsNotReady, message),
+		newClusterOperatorStatusCondition(configv1.OperatorProgressing, configv1.ConditionTrue, ReasonWorkloadsNotReady, message),
+		newClusterOperatorStatusCondition(configv1.OperatorUpgradeable, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+
+	klog.V(2).Infof("Syncing status: %s", message)
+	return r.syncStatus(ctx, co, conds, overrides)
+}
+
+// setStatusOperandRollout sets the Progressing condition to True while operand deployments are replacing
+// pods of a previous revision. The operator stays Available, since a rollout is expected to finish shortly,
+// although with the Recreate strategy no operand pods may be serving meanwhile, but operator versions are
+// only reported once the rollout completes.
+func (r *ClusterOperatorStatusClient) setStatusOperandRollout(ctx context.Context, message string, overrides []configv1.ClusterOperatorStatusCondition) error {
 	co, err := r.getOrCreateClusterOperator(ctx)
 	if err != nil {
+		klog.Errorf("Failed to get or create Cluster Operator: %v", err)
 		return err
 	}
 
+	message = fmt.Sprintf("Rolling out operands: %s", message)
 	conds := []configv1.ClusterOperatorStatusCondition{
 		newClusterOperatorStatusCondition(configv1.OperatorAvailable, configv1.ConditionTrue, ReasonAsExpected,
 			fmt.Sprintf("Cluster Cloud Controller Manager Operator is available at %s", r.ReleaseVersion)),
+		newClusterOperatorStatusCondition(configv1.OperatorProgressing, configv1.ConditionTrue, ReasonOperandRollout, message),
+		newClusterOperatorStatusCondition(configv1.OperatorDegraded, configv1.ConditionFalse, ReasonAsExpected, ""),
+		newClusterOperatorStatusCondition(configv1.OperatorUpgradeable, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+
+	klog.V(2).Infof("Syncing status: %s", message)
+	return r.syncStatus(ctx, co, conds, overrides)
+}
+
+// setStatusNoOperandsRequired sets the Available condition to True with a message explaining
+// that the platform does not need any cloud-controller-manager, and records a normal event once
+// the condition transitions to it, so it is visible that the operator skipped the platform intentionally.
+func (r *ClusterOperatorStatusClient) setStatusNoOperandsRequired(ctx context.Context, platform configv1.PlatformType, overrides []configv1.ClusterOperatorStatusCondition) error {
+	co, err := r.getOrCreateClusterOperator(ctx)
+	if err != nil {
+		return err
+	}
+
+	message := noOperandsRequiredMessage(platform)
+	if available := v1helpers.FindStatusCondition(co.Status.Conditions, configv1.OperatorAvailable); available == nil ||
+		available.Status != configv1.ConditionTrue || available.Message != message {
+		r.Recorder.Event(co, corev1.EventTypeNormal, "No operands required", message)
+	}
+	return r.setStatusAvailableWithMessage(ctx, message, overrides)
+}
+
+func (r *ClusterOperatorStatusClient) setStatusAvailableWithMessage(ctx context.Context, message string, overrides []configv1.ClusterOperatorStatusCondition) error {
+	co, err := r.getOrCreateClusterOperator(ctx)
+	if err != nil {
+		return err
+	}
+
+	conds := []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(configv1.OperatorAvailable, configv1.ConditionTrue, ReasonAsExpected, message),
 		newClusterOperatorStatusCondition(configv1.OperatorProgressing, configv1.ConditionFalse, ReasonAsExpected, ""),
 		newClusterOperatorStatusCondition(configv1.OperatorDegraded, configv1.ConditionFalse, ReasonAsExpected, ""),
 		newClusterOperatorStatusCondition(configv1.OperatorUpgradeable, configv1.ConditionTrue, ReasonAsExpected, ""),
@@ -155,6 +242,10 @@ func (r *CloudOperatorReconciler) clearCloudControllerOwnerCondition(ctx context
 	return r.syncStatus(ctx, co, nil, nil)
 }
 
+func noOperandsRequiredMessage(platform configv1.PlatformType) string {
+	return fmt.Sprintf("platform %s requires no cloud-controller-manager", platform)
+}
+
 func printOperandVersions(versions []configv1.OperandVersion) string {
 	versionsOutput := []string{}
 	for _, operand := range versions {
@@ -2,20 +2,36 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
+
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/cloud"
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/cloud/common"
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/config"
+	"github.com/openshift/cluster-cloud-controller-manager-operator/pkg/util"
 )
 
 func TestPrintOperandVersions(t *testing.T) {
@@ -435,3 +451,714 @@ func TestOperatorSetStatusAvailable(t *testing.T) {
 			"test-case %v expected equal version for ClusterOperator to %v, got %v", i, desiredVersion, gotCO.Status.Versions)
 	}
 }
+
+func TestOperatorNoOperandsRequiredMessage(t *testing.T) {
+	tCases := []struct {
+		name            string
+		platform        configv1.PlatformType
+		expectedMessage bool
+	}{{
+		name:            "None platform",
+		platform:        configv1.NonePlatformType,
+		expectedMessage: true,
+	}, {
+		name:            "AWS platform",
+		platform:        configv1.AWSPlatformType,
+		expectedMessage: false,
+	}}
+
+	for _, tc := range tCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(32)
+			optr := CloudOperatorReconciler{
+				ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+					Recorder:       recorder,
+					ReleaseVersion: "1.0",
+				},
+				Scheme: scheme.Scheme,
+			}
+
+			operator := &configv1.ClusterOperator{}
+			operator.SetName(clusterOperatorName)
+			operator.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+				newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+				newClusterOperatorStatusCondition(trustedCABundleControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+			}
+			optr.Client = fake.NewClientBuilder().WithStatusSubresource(&configv1.ClusterOperator{}).WithObjects(operator).Build()
+
+			infra := &configv1.Infrastructure{}
+			infra.Status.PlatformStatus = &configv1.PlatformStatus{Type: tc.platform}
+
+			allowed, err := optr.provisioningAllowed(context.TODO(), infra, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, !tc.expectedMessage, allowed)
+
+			gotCO, err := optr.getOrCreateClusterOperator(context.TODO())
+			assert.NoErrorf(t, err, "Failed to fetch ClusterOperator")
+
+			available := v1helpers.FindStatusCondition(gotCO.Status.Conditions, configv1.OperatorAvailable)
+			if tc.expectedMessage {
+				assert.NotNil(t, available)
+				assert.Equal(t, noOperandsRequiredMessage(tc.platform), available.Message)
+				assert.Len(t, recorder.Events, 1)
+				assert.Contains(t, <-recorder.Events, noOperandsRequiredMessage(tc.platform))
+
+				// The event is only recorded when the condition transitions
+				_, err = optr.provisioningAllowed(context.TODO(), infra, nil)
+				assert.NoError(t, err)
+				assert.Empty(t, recorder.Events)
+			} else {
+				assert.Nil(t, available)
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
+
+func TestReconcileMergesPlatformStatusConditions(t *testing.T) {
+	const zonesMisconfiguredCondition = "AlibabaCloudZonesMisconfigured"
+
+	cloud.RegisterStatusConditionsProvider(configv1.AlibabaCloudPlatformType, func(_ config.OperatorConfig) []configv1.ClusterOperatorStatusCondition {
+		return []configv1.ClusterOperatorStatusCondition{
+			newClusterOperatorStatusCondition(zonesMisconfiguredCondition, configv1.ConditionTrue, "ZonesMisconfigured", "zones are misconfigured"),
+		}
+	})
+	defer cloud.RegisterStatusConditionsProvider(configv1.AlibabaCloudPlatformType, nil)
+
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         record.NewFakeRecorder(32),
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: defaultManagementNamespace,
+		},
+		Scheme:     scheme.Scheme,
+		ImagesFile: testImagesFilePath,
+	}
+
+	operator := &configv1.ClusterOperator{}
+	operator.SetName(clusterOperatorName)
+	operator.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+		newClusterOperatorStatusCondition(trustedCABundleControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+	infra := &configv1.Infrastructure{}
+	infra.SetName(infrastructureResourceName)
+	infra.Status.PlatformStatus = &configv1.PlatformStatus{Type: configv1.AlibabaCloudPlatformType}
+	optr.Client = fake.NewClientBuilder().WithStatusSubresource(&configv1.ClusterOperator{}).WithObjects(operator, infra).Build()
+
+	_, err := optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+
+	gotCO, err := optr.getOrCreateClusterOperator(context.TODO())
+	assert.NoErrorf(t, err, "Failed to fetch ClusterOperator")
+
+	contributed := v1helpers.FindStatusCondition(gotCO.Status.Conditions, zonesMisconfiguredCondition)
+	if assert.NotNil(t, contributed) {
+		assert.Equal(t, configv1.ConditionTrue, contributed.Status)
+		assert.Equal(t, "ZonesMisconfigured", contributed.Reason)
+		assert.Equal(t, "zones are misconfigured", contributed.Message)
+	}
+	assert.True(t, v1helpers.IsStatusConditionTrue(gotCO.Status.Conditions, configv1.OperatorAvailable))
+}
+
+// noopWatcher is an ObjectWatcher which does not watch anything, for tests running the reconciler without a cache.
+type noopWatcher struct{}
+
+func (noopWatcher) Watch(context.Context, client.Object) error { return nil }
+
+func (noopWatcher) EventStream() <-chan event.GenericEvent { return nil }
+
+func TestReconcileReportsNotReadyWorkloads(t *testing.T) {
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         record.NewFakeRecorder(32),
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: defaultManagementNamespace,
+		},
+		Scheme:     scheme.Scheme,
+		ImagesFile: testImagesFilePath,
+		watcher:    noopWatcher{},
+	}
+
+	operator := &configv1.ClusterOperator{}
+	operator.SetName(clusterOperatorName)
+	operator.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+		newClusterOperatorStatusCondition(trustedCABundleControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+	infra := &configv1.Infrastructure{}
+	infra.SetName(infrastructureResourceName)
+	infra.Status.PlatformStatus = &configv1.PlatformStatus{Type: configv1.AWSPlatformType}
+	optr.Client = fake.NewClientBuilder().WithStatusSubresource(&configv1.ClusterOperator{}, &appsv1.Deployment{}).WithObjects(operator, infra).Build()
+
+	_, err := optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+
+	gotCO, err := optr.getOrCreateClusterOperator(context.TODO())
+	assert.NoErrorf(t, err, "Failed to fetch ClusterOperator")
+	assert.True(t, v1helpers.IsStatusConditionTrue(gotCO.Status.Conditions, configv1.OperatorProgressing))
+	assert.True(t, v1helpers.IsStatusConditionFalse(gotCO.Status.Conditions, configv1.OperatorAvailable))
+	available := v1helpers.FindStatusCondition(gotCO.Status.Conditions, configv1.OperatorAvailable)
+	if assert.NotNil(t, available) {
+		assert.Equal(t, ReasonWorkloadsNotReady, available.Reason)
+		assert.Contains(t, available.Message, "deployment openshift-cloud-controller-manager/aws-cloud-controller-manager has 0/2 available replicas")
+	}
+
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, optr.Get(context.TODO(), client.ObjectKey{Namespace: "openshift-cloud-controller-manager", Name: "aws-cloud-controller-manager"}, deployment))
+	deployment.Status.ObservedGeneration = deployment.Generation
+	deployment.Status.Replicas = 2
+	deployment.Status.AvailableReplicas = 2
+	deployment.Status.UpdatedReplicas = 2
+	assert.NoError(t, optr.Status().Update(context.TODO(), deployment))
+
+	_, err = optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+
+	gotCO, err = optr.getOrCreateClusterOperator(context.TODO())
+	assert.NoErrorf(t, err, "Failed to fetch ClusterOperator")
+	assert.True(t, v1helpers.IsStatusConditionFalse(gotCO.Status.Conditions, configv1.OperatorProgressing))
+	assert.True(t, v1helpers.IsStatusConditionTrue(gotCO.Status.Conditions, configv1.OperatorAvailable))
+}
+
+func TestReconcileReportsOperandRollout(t *testing.T) {
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         record.NewFakeRecorder(32),
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: defaultManagementNamespace,
+		},
+		Scheme:     scheme.Scheme,
+		ImagesFile: testImagesFilePath,
+		watcher:    noopWatcher{},
+	}
+
+	operator := &configv1.ClusterOperator{}
+	operator.SetName(clusterOperatorName)
+	operator.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+		newClusterOperatorStatusCondition(trustedCABundleControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+	infra := &configv1.Infrastructure{}
+	infra.SetName(infrastructureResourceName)
+	infra.Status.PlatformStatus = &configv1.PlatformStatus{Type: configv1.AWSPlatformType}
+	optr.Client = fake.NewClientBuilder().WithStatusSubresource(&configv1.ClusterOperator{}, &appsv1.Deployment{}).WithObjects(operator, infra).Build()
+
+	_, err := optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+
+	// Mid-rollout of a second revision with the Recreate strategy: pods of the previous revision are gone,
+	// the updated ones are not available yet.
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, optr.Get(context.TODO(), client.ObjectKey{Namespace: "openshift-cloud-controller-manager", Name: "aws-cloud-controller-manager"}, deployment))
+	metav1.SetMetaDataAnnotation(&deployment.ObjectMeta, deploymentRevisionAnnotation, "2")
+	assert.NoError(t, optr.Update(context.TODO(), deployment))
+	deployment.Status.ObservedGeneration = deployment.Generation
+	deployment.Status.Replicas = 2
+	deployment.Status.UpdatedReplicas = 2
+	deployment.Status.AvailableReplicas = 0
+	deployment.Status.UnavailableReplicas = 2
+	assert.NoError(t, optr.Status().Update(context.TODO(), deployment))
+
+	_, err = optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+
+	gotCO, err := optr.getOrCreateClusterOperator(context.TODO())
+	assert.NoErrorf(t, err, "Failed to fetch ClusterOperator")
+	assert.True(t, v1helpers.IsStatusConditionTrue(gotCO.Status.Conditions, configv1.OperatorAvailable))
+	progressing := v1helpers.FindStatusCondition(gotCO.Status.Conditions, configv1.OperatorProgressing)
+	if assert.NotNil(t, progressing) {
+		assert.Equal(t, configv1.ConditionTrue, progressing.Status)
+		assert.Equal(t, ReasonOperandRollout, progressing.Reason)
+		assert.Contains(t, progressing.Message, "deployment openshift-cloud-controller-manager/aws-cloud-controller-manager has 0/2 available updated replicas")
+	}
+
+	assert.NoError(t, optr.Get(context.TODO(), client.ObjectKeyFromObject(deployment), deployment))
+	deployment.Status.AvailableReplicas = 2
+	deployment.Status.UnavailableReplicas = 0
+	assert.NoError(t, optr.Status().Update(context.TODO(), deployment))
+
+	_, err = optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+
+	gotCO, err = optr.getOrCreateClusterOperator(context.TODO())
+	assert.NoErrorf(t, err, "Failed to fetch ClusterOperator")
+	assert.True(t, v1helpers.IsStatusConditionFalse(gotCO.Status.Conditions, configv1.OperatorProgressing))
+	assert.True(t, v1helpers.IsStatusConditionTrue(gotCO.Status.Conditions, configv1.OperatorAvailable))
+}
+
+func TestReconcileReportsNamespaceOwnershipConflict(t *testing.T) {
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         record.NewFakeRecorder(32),
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: "openshift-cloud-controller-manager",
+		},
+		Scheme:     scheme.Scheme,
+		ImagesFile: testImagesFilePath,
+		watcher:    noopWatcher{},
+	}
+
+	operator := &configv1.ClusterOperator{}
+	operator.SetName(clusterOperatorName)
+	operator.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+		newClusterOperatorStatusCondition(trustedCABundleControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+	infra := &configv1.Infrastructure{}
+	infra.SetName(infrastructureResourceName)
+	infra.Status.PlatformStatus = &configv1.PlatformStatus{Type: configv1.AWSPlatformType}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "openshift-cloud-controller-manager",
+		Labels: map[string]string{namespaceOwnerLabel: "another-operator"},
+	}}
+	optr.Client = fake.NewClientBuilder().WithStatusSubresource(&configv1.ClusterOperator{}).WithObjects(operator, infra, namespace).Build()
+
+	_, err := optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.EqualError(t, err, `managed namespace openshift-cloud-controller-manager is owned by "another-operator" according to its `+
+		`cloud-controller-manager.openshift.io/namespace-owner label, refusing to manage operands in it`)
+
+	gotCO, err := optr.getOrCreateClusterOperator(context.TODO())
+	assert.NoErrorf(t, err, "Failed to fetch ClusterOperator")
+	degraded := v1helpers.FindStatusCondition(gotCO.Status.Conditions, configv1.OperatorDegraded)
+	if assert.NotNil(t, degraded) {
+		assert.Equal(t, configv1.ConditionTrue, degraded.Status)
+		assert.Contains(t, degraded.Message, `is owned by "another-operator"`)
+	}
+
+	// Operands are left to the namespace owner
+	deployments := &appsv1.DeploymentList{}
+	assert.NoError(t, optr.List(context.TODO(), deployments))
+	assert.Empty(t, deployments.Items)
+
+	// Operands are managed once the namespace is owned by the operator
+	namespace.Labels[namespaceOwnerLabel] = clusterOperatorName
+	assert.NoError(t, optr.Update(context.TODO(), namespace))
+
+	_, err = optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+	assert.NoError(t, optr.List(context.TODO(), deployments))
+	assert.Len(t, deployments.Items, 1)
+}
+
+func TestReconcileReportsUnsupportedFeatureGates(t *testing.T) {
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         record.NewFakeRecorder(32),
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: "openshift-cloud-controller-manager",
+		},
+		Scheme:            scheme.Scheme,
+		ImagesFile:        testImagesFilePath,
+		FeatureGateAccess: featuregates.NewHardcodedFeatureGateAccess([]configv1.FeatureGateName{"VSphereMultiVCenters"}, nil),
+		watcher:           noopWatcher{},
+	}
+
+	operator := &configv1.ClusterOperator{}
+	operator.SetName(clusterOperatorName)
+	operator.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+		newClusterOperatorStatusCondition(trustedCABundleControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+	infra := &configv1.Infrastructure{}
+	infra.SetName(infrastructureResourceName)
+	infra.Status.PlatformStatus = &configv1.PlatformStatus{Type: configv1.AWSPlatformType}
+	optr.Client = fake.NewClientBuilder().WithStatusSubresource(&configv1.ClusterOperator{}).WithObjects(operator, infra).Build()
+
+	_, err := optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.EqualError(t, err, "feature gates VSphereMultiVCenters are enabled but not supported on platform AWS")
+
+	gotCO, err := optr.getOrCreateClusterOperator(context.TODO())
+	assert.NoErrorf(t, err, "Failed to fetch ClusterOperator")
+	degraded := v1helpers.FindStatusCondition(gotCO.Status.Conditions, configv1.OperatorDegraded)
+	if assert.NotNil(t, degraded) {
+		assert.Equal(t, configv1.ConditionTrue, degraded.Status)
+		assert.Equal(t, ReasonUnsupportedFeatures, degraded.Reason)
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	assert.NoError(t, optr.List(context.TODO(), deployments))
+	assert.Empty(t, deployments.Items)
+
+	// Operands are rendered when unsupported feature gates are ignored
+	optr.IgnoreUnsupportedFeatureGates = true
+	_, err = optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+	assert.NoError(t, optr.List(context.TODO(), deployments))
+	assert.Len(t, deployments.Items, 1)
+}
+
+func TestReconcileWithFeatureGatesNotObserved(t *testing.T) {
+	// The FeatureGate object is missing, so the operator gives up waiting for feature gates at startup
+	notObserved := featuregates.NewHardcodedFeatureGateAccessForTesting(nil, nil, make(chan struct{}), errors.New("featuregates not observed"))
+	featureGateAccess, observed := util.WaitForFeatureGates(context.TODO(), notObserved, time.Millisecond)
+	assert.False(t, observed)
+
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         record.NewFakeRecorder(32),
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: "openshift-cloud-controller-manager",
+		},
+		Scheme:            scheme.Scheme,
+		ImagesFile:        testImagesFilePath,
+		FeatureGateAccess: featureGateAccess,
+		watcher:           noopWatcher{},
+	}
+
+	operator := &configv1.ClusterOperator{}
+	operator.SetName(clusterOperatorName)
+	operator.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+		newClusterOperatorStatusCondition(trustedCABundleControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+	infra := &configv1.Infrastructure{}
+	infra.SetName(infrastructureResourceName)
+	infra.Status.PlatformStatus = &configv1.PlatformStatus{Type: configv1.AWSPlatformType}
+	optr.Client = fake.NewClientBuilder().WithStatusSubresource(&configv1.ClusterOperator{}).WithObjects(operator, infra).Build()
+
+	features, err := optr.FeatureGateAccess.CurrentFeatureGates()
+	assert.NoError(t, err)
+	if assert.NotNil(t, features) {
+		assert.Empty(t, features.KnownFeatures())
+	}
+
+	_, err = optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+
+	deployments := &appsv1.DeploymentList{}
+	assert.NoError(t, optr.List(context.TODO(), deployments))
+	assert.Len(t, deployments.Items, 1)
+}
+
+func TestReconcilePrunesDisabledPodDisruptionBudgets(t *testing.T) {
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         record.NewFakeRecorder(32),
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: "openshift-cloud-controller-manager",
+		},
+		Scheme:     scheme.Scheme,
+		ImagesFile: testImagesFilePath,
+		watcher:    noopWatcher{},
+	}
+
+	operator := &configv1.ClusterOperator{}
+	operator.SetName(clusterOperatorName)
+	operator.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+		newClusterOperatorStatusCondition(trustedCABundleControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+	infra := &configv1.Infrastructure{}
+	infra.SetName(infrastructureResourceName)
+	infra.Status.PlatformStatus = &configv1.PlatformStatus{Type: configv1.AWSPlatformType}
+	// PodDisruptionBudgets which are not applied by the operator must be kept, even with its common labels
+	foreignPDB := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "openshift-cloud-controller-manager"},
+	}
+	partOfPDB := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "part-of", Namespace: "openshift-cloud-controller-manager", Labels: common.GetCommonLabels()},
+	}
+	optr.Client = fake.NewClientBuilder().WithStatusSubresource(&configv1.ClusterOperator{}).WithObjects(operator, infra, foreignPDB, partOfPDB).Build()
+
+	listPDBs := func() []string {
+		pdbs := &policyv1.PodDisruptionBudgetList{}
+		assert.NoError(t, optr.List(context.TODO(), pdbs, client.InNamespace("openshift-cloud-controller-manager")))
+		var names []string
+		for _, pdb := range pdbs.Items {
+			names = append(names, pdb.Name)
+		}
+		return names
+	}
+
+	_, err := optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"aws-cloud-controller-manager", "foreign", "part-of"}, listPDBs())
+
+	optr.DisablePodDisruptionBudget = true
+	_, err = optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"foreign", "part-of"}, listPDBs())
+}
+
+func TestReconcileAutosizesOperandResources(t *testing.T) {
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         record.NewFakeRecorder(32),
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: "openshift-cloud-controller-manager",
+		},
+		OperandOverrides: OperandOverrides{
+			ResourceAutosizing: &config.ResourceAutosizing{
+				Min:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+				PerNode: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2m")},
+				Max:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		},
+		Scheme:     scheme.Scheme,
+		ImagesFile: testImagesFilePath,
+		watcher:    noopWatcher{},
+	}
+
+	operator := &configv1.ClusterOperator{}
+	operator.SetName(clusterOperatorName)
+	operator.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+		newClusterOperatorStatusCondition(trustedCABundleControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+	infra := &configv1.Infrastructure{}
+	infra.SetName(infrastructureResourceName)
+	infra.Status.PlatformStatus = &configv1.PlatformStatus{Type: configv1.AWSPlatformType}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "openshift-cloud-controller-manager",
+			Annotations: map[string]string{config.NamespaceNodeSelectorAnnotation: ""},
+		},
+	}
+	objects := []client.Object{operator, infra, namespace}
+	for i := 0; i < 12; i++ {
+		node := &corev1.Node{}
+		node.SetName(fmt.Sprintf("node-%d", i))
+		objects = append(objects, node)
+	}
+	optr.Client = fake.NewClientBuilder().WithStatusSubresource(&configv1.ClusterOperator{}).WithObjects(objects...).Build()
+
+	_, err := optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+
+	deployment := &appsv1.Deployment{}
+	deploymentKey := client.ObjectKey{Namespace: "openshift-cloud-controller-manager", Name: "aws-cloud-controller-manager"}
+	assert.NoError(t, optr.Get(context.TODO(), deploymentKey, deployment))
+	var requests corev1.ResourceList
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == "cloud-controller-manager" {
+			requests = container.Resources.Requests
+		}
+	}
+	// 12 nodes are rounded up to the default bucket of 10 nodes, so 200m + 20 * 2m are requested
+	cpu := requests[corev1.ResourceCPU]
+	assert.Equal(t, "240m", cpu.String())
+}
+
+func TestReconcileKeepsOperandNodeSelection(t *testing.T) {
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         record.NewFakeRecorder(32),
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: "openshift-cloud-controller-manager",
+		},
+		Scheme:     scheme.Scheme,
+		ImagesFile: testImagesFilePath,
+		watcher:    noopWatcher{},
+	}
+
+	operator := &configv1.ClusterOperator{}
+	operator.SetName(clusterOperatorName)
+	operator.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+		newClusterOperatorStatusCondition(trustedCABundleControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+	infra := &configv1.Infrastructure{}
+	infra.SetName(infrastructureResourceName)
+	infra.Status.PlatformStatus = &configv1.PlatformStatus{Type: configv1.AWSPlatformType}
+	// The cluster default node selector keeps pods of namespaces without a node selector off control plane nodes
+	scheduler := &configv1.Scheduler{
+		ObjectMeta: metav1.ObjectMeta{Name: schedulerResourceName},
+		Spec:       configv1.SchedulerSpec{DefaultNodeSelector: "node-role.kubernetes.io/worker="},
+	}
+	optr.Client = fake.NewClientBuilder().WithStatusSubresource(&configv1.ClusterOperator{}).WithObjects(operator, infra, scheduler).Build()
+
+	deployment := &appsv1.Deployment{}
+	deploymentKey := client.ObjectKey{Namespace: "openshift-cloud-controller-manager", Name: "aws-cloud-controller-manager"}
+
+	_, err := optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.ErrorContains(t, err, `default node selector node-role.kubernetes.io/worker= of namespace openshift-cloud-controller-manager would change the nodes deployment "aws-cloud-controller-manager" runs on`)
+	co, err := optr.getOrCreateClusterOperator(context.TODO())
+	assert.NoError(t, err)
+	assert.True(t, v1helpers.IsStatusConditionTrue(co.Status.Conditions, configv1.OperatorDegraded), "operator should be degraded")
+	assert.True(t, apierrors.IsNotFound(optr.Get(context.TODO(), deploymentKey, deployment)), "operands should not be applied")
+
+	// The operator replaces the cluster default with an empty node selector once the namespace exists
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-cloud-controller-manager"}}
+	assert.NoError(t, optr.Create(context.TODO(), namespace))
+
+	_, err = optr.Reconcile(context.TODO(), reconcile.Request{})
+	assert.NoError(t, err)
+	assert.NoError(t, optr.Get(context.TODO(), deploymentKey, deployment))
+	assert.Equal(t, map[string]string{"node-role.kubernetes.io/master": ""}, deployment.Spec.Template.Spec.NodeSelector)
+}
+
+func TestReconcileRestoresNamespaceNodeSelector(t *testing.T) {
+	recorder := record.NewFakeRecorder(32)
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         recorder,
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: "openshift-cloud-controller-manager",
+		},
+		Scheme:     scheme.Scheme,
+		ImagesFile: testImagesFilePath,
+		watcher:    noopWatcher{},
+	}
+
+	operator := &configv1.ClusterOperator{}
+	operator.SetName(clusterOperatorName)
+	operator.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+		newClusterOperatorStatusCondition(cloudConfigControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+		newClusterOperatorStatusCondition(trustedCABundleControllerAvailableCondition, configv1.ConditionTrue, ReasonAsExpected, ""),
+	}
+	infra := &configv1.Infrastructure{}
+	infra.SetName(infrastructureResourceName)
+	infra.Status.PlatformStatus = &configv1.PlatformStatus{Type: configv1.AWSPlatformType}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "openshift-cloud-controller-manager",
+			Annotations: map[string]string{config.NamespaceNodeSelectorAnnotation: "", "openshift.io/sa.scc.uid-range": "1000/10000"},
+		},
+	}
+	forbidPatch := false
+	optr.Client = fake.NewClientBuilder().WithStatusSubresource(&configv1.ClusterOperator{}).WithObjects(operator, infra, namespace).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if _, ok := obj.(*corev1.Namespace); ok && forbidPatch {
+				return apierrors.NewForbidden(corev1.Resource("namespaces"), obj.GetName(), errors.New("namespace is not listed in resourceNames"))
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}).Build()
+
+	getAnnotations := func() map[string]string {
+		ns := &corev1.Namespace{}
+		assert.NoError(t, optr.Get(context.TODO(), client.ObjectKeyFromObject(namespace), ns))
+		return ns.Annotations
+	}
+	drainEvents := func() []string {
+		var events []string
+		for {
+			select {
+			case event := <-recorder.Events:
+				events = append(events, event)
+			default:
+				return events
+			}
+		}
+	}
+
+	tc := []struct {
+		name                string
+		annotations         map[string]string
+		forbidPatch         bool
+		expectedError       string
+		expectedAnnotations map[string]string
+		expectedEvent       string
+	}{{
+		name:                "Annotation is removed",
+		annotations:         map[string]string{"openshift.io/sa.scc.uid-range": "1000/10000"},
+		expectedAnnotations: map[string]string{config.NamespaceNodeSelectorAnnotation: "", "openshift.io/sa.scc.uid-range": "1000/10000"},
+		expectedEvent:       "Normal NamespaceNodeSelectorSet Set empty openshift.io/node-selector annotation, so the cluster default node selector does not apply to operands",
+	}, {
+		name:                "Annotation selects nodes",
+		annotations:         map[string]string{config.NamespaceNodeSelectorAnnotation: "node-role.kubernetes.io/worker=", "openshift.io/sa.scc.uid-range": "1000/10000"},
+		expectedAnnotations: map[string]string{config.NamespaceNodeSelectorAnnotation: "", "openshift.io/sa.scc.uid-range": "1000/10000"},
+		expectedEvent:       "Normal NamespaceNodeSelectorSet Set empty openshift.io/node-selector annotation, so the cluster default node selector does not apply to operands",
+	}, {
+		name:                "Patching a configured namespace is forbidden",
+		annotations:         map[string]string{config.NamespaceNodeSelectorAnnotation: "node-role.kubernetes.io/worker=", "openshift.io/sa.scc.uid-range": "1000/10000"},
+		forbidPatch:         true,
+		expectedError:       `default node selector node-role.kubernetes.io/worker= of namespace openshift-cloud-controller-manager would change the nodes`,
+		expectedAnnotations: map[string]string{config.NamespaceNodeSelectorAnnotation: "node-role.kubernetes.io/worker=", "openshift.io/sa.scc.uid-range": "1000/10000"},
+		expectedEvent:       "Warning NamespaceNodeSelectorNotEmpty Expected an empty openshift.io/node-selector annotation, so the cluster default node selector does not apply to operands",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			ns := &corev1.Namespace{}
+			assert.NoError(t, optr.Get(context.TODO(), client.ObjectKeyFromObject(namespace), ns))
+			ns.Annotations = tc.annotations
+			assert.NoError(t, optr.Update(context.TODO(), ns))
+			forbidPatch = tc.forbidPatch
+
+			_, err := optr.Reconcile(context.TODO(), reconcile.Request{})
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedAnnotations, getAnnotations())
+			assert.Contains(t, drainEvents(), tc.expectedEvent)
+		})
+	}
+}
+
+func TestApplyResourcesReportsUpdatesBeforeFailure(t *testing.T) {
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         record.NewFakeRecorder(32),
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: "openshift-cloud-controller-manager",
+		},
+		Scheme:  scheme.Scheme,
+		watcher: noopWatcher{},
+	}
+	optr.Client = fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if _, ok := obj.(*appsv1.Deployment); ok {
+				return errors.New("deployment create failed")
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	resources := []client.Object{
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "cloud-controller-manager", Namespace: "openshift-cloud-controller-manager"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "cloud-controller-manager", Namespace: "openshift-cloud-controller-manager"}},
+	}
+	updated, err := optr.applyResources(context.TODO(), resources)
+	assert.ErrorContains(t, err, "deployment create failed")
+	assert.True(t, updated, "the service account applied before the failure should be reported as updated")
+}
+
+func TestRecordOperandStatus(t *testing.T) {
+	optr := CloudOperatorReconciler{
+		ClusterOperatorStatusClient: ClusterOperatorStatusClient{
+			Recorder:         record.NewFakeRecorder(32),
+			ReleaseVersion:   "1.0",
+			ManagedNamespace: defaultManagementNamespace,
+		},
+		Scheme: scheme.Scheme,
+	}
+
+	operator := &configv1.ClusterOperator{}
+	operator.SetName(clusterOperatorName)
+	ready := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready-cloud-controller-manager", Namespace: "openshift-cloud-controller-manager", Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, AvailableReplicas: 2},
+	}
+	notReady := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "unready-cloud-controller-manager", Namespace: "openshift-cloud-controller-manager", Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, AvailableReplicas: 1},
+	}
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloud-node-manager", Namespace: "openshift-cloud-controller-manager", Generation: 2},
+		Status:     appsv1.DaemonSetStatus{ObservedGeneration: 1, NumberReady: 3, DesiredNumberScheduled: 3},
+	}
+	optr.Client = fake.NewClientBuilder().WithObjects(operator, ready, notReady, daemonSet).Build()
+
+	resources := []client.Object{ready, notReady, daemonSet, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cloud-conf"}}}
+	summary, err := optr.operandStatusSummary(context.TODO(), resources)
+	assert.NoError(t, err)
+	assert.Equal(t, "Deployment/ready-cloud-controller-manager: Ready (2/2), "+
+		"Deployment/unready-cloud-controller-manager: NotReady (1/2), "+
+		"DaemonSet/cloud-node-manager: NotReady (3/3)", summary)
+
+	assert.NoError(t, optr.recordOperandStatus(context.TODO(), resources))
+	gotCO, err := optr.getOrCreateClusterOperator(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, summary, gotCO.Annotations[operandStatusAnnotation])
+
+	assert.NoError(t, optr.recordOperandStatus(context.TODO(), nil))
+	gotCO, err = optr.getOrCreateClusterOperator(context.TODO())
+	assert.NoError(t, err)
+	assert.NotContains(t, gotCO.Annotations, operandStatusAnnotation)
+}
@@ -34,6 +34,11 @@ const (
 	cloudProviderConfigCABundleConfigMapKey = "ca-bundle.pem"
 	systemTrustBundlePath                   = "/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem"
 
+	// trustedCAInjectLabel instructs the network operator to inject the cluster trust bundle into a config map.
+	// The merged bundle is built by this controller, so the label must not be set on it, otherwise
+	// the network operator would overwrite the merged bundle with the cluster one.
+	trustedCAInjectLabel = "config.openshift.io/inject-trusted-cabundle"
+
 	// Controller conditions for the Cluster Operator resource
 	trustedCABundleControllerAvailableCondition = "TrustedCABundleControllerControllerAvailable"
 	trustedCABundleControllerDegradedCondition  = "TrustedCABundleControllerControllerDegraded"
@@ -230,14 +235,25 @@ func (r *TrustedCABundleReconciler) makeCABundleConfigMap(trustBundle []byte) *c
 
 func (r *TrustedCABundleReconciler) createOrUpdateConfigMap(ctx context.Context, cm *corev1.ConfigMap) error {
 	// check if target config exists, create if not
-	err := r.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+	existing := &corev1.ConfigMap{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(cm), existing)
 	if err != nil && apierrors.IsNotFound(err) {
 		return r.Create(ctx, cm)
 	} else if err != nil {
 		return err
 	}
 
-	return r.Update(ctx, cm)
+	if err := r.Update(ctx, cm); err != nil {
+		return err
+	}
+
+	// The inject label might be added to the config map by a user, report when it had to be removed.
+	if _, ok := existing.Labels[trustedCAInjectLabel]; ok {
+		klog.Infof("removed %s label from %s config map", trustedCAInjectLabel, trustedCAConfigMapName)
+		r.Recorder.Eventf(cm, corev1.EventTypeNormal, "Trusted CA label removed",
+			"Removed %s label from %s config map, the bundle is merged by the operator", trustedCAInjectLabel, trustedCAConfigMapName)
+	}
+	return nil
 }
 
 // for test purposes only, normally it returns value from 'trustBundlePath' constant in this module
@@ -300,7 +316,7 @@ func (r *TrustedCABundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&handler.EnqueueRequestForObject{},
 		)
 
-	return build.Complete(r)
+	return build.Complete(newTimedReconciler("TrustedCABundleController", r.Clock, newDrainingReconciler(r.ShutdownGracePeriod, r)))
 }
 
 func (r *TrustedCABundleReconciler) setAvailableCondition(ctx context.Context) error {
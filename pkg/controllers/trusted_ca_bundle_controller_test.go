@@ -115,6 +115,7 @@ var _ = Describe("Trusted CA bundle sync controller", func() {
 		})
 		Expect(err).NotTo(HaveOccurred())
 
+		rec = record.NewFakeRecorder(1000)
 		reconciler = &TrustedCABundleReconciler{
 			ClusterOperatorStatusClient: ClusterOperatorStatusClient{
 				Client:           cl,
@@ -211,6 +212,26 @@ var _ = Describe("Trusted CA bundle sync controller", func() {
 		Eventually(checkMergedTrustedCAConfig(3, "Amazon")).Should(Succeed())
 	})
 
+	It("inject label should be removed if it was added to own ca bundle", func() {
+		mergedTrustedCA := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return cl.Get(ctx, mergedCAObjectKey, mergedTrustedCA)
+		}, timeout).Should(Succeed())
+		Expect(mergedTrustedCA.Labels).NotTo(HaveKey(trustedCAInjectLabel))
+
+		mergedTrustedCA.Labels = map[string]string{trustedCAInjectLabel: "true"}
+		Expect(cl.Update(ctx, mergedTrustedCA)).To(Succeed())
+
+		Eventually(func() (map[string]string, error) {
+			cm := &corev1.ConfigMap{}
+			if err := cl.Get(ctx, mergedCAObjectKey, cm); err != nil {
+				return nil, err
+			}
+			return cm.Labels, nil
+		}, timeout).ShouldNot(HaveKey(trustedCAInjectLabel))
+		Eventually(rec.Events).Should(Receive(ContainSubstring("Trusted CA label removed")))
+	})
+
 	It("ca bundle should be synced up if user one in openshift-config was changed", func() {
 		mergedTrustedCA := &corev1.ConfigMap{}
 		Eventually(func() error {
@@ -27,6 +27,16 @@ func clusterOperatorPredicates() predicate.Funcs {
 	}
 }
 
+// nodeCountPredicates passes node creation and deletion events only, which change the cluster node count.
+func nodeCountPredicates() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
 func toClusterOperator(context.Context, client.Object) []reconcile.Request {
 	return []reconcile.Request{{
 		NamespacedName: client.ObjectKey{Name: clusterOperatorName},
@@ -81,6 +91,38 @@ func kcmPredicates() predicate.Funcs {
 	}
 }
 
+func dnsPredicates() predicate.Funcs {
+	isDNSCluster := func(obj runtime.Object) bool {
+		dns, ok := obj.(*configv1.DNS)
+		return ok && dns.GetName() == dnsResourceName
+	}
+
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return isDNSCluster(e.Object) },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isDNSCluster(e.ObjectNew) && e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
+		},
+		GenericFunc: func(e event.GenericEvent) bool { return isDNSCluster(e.Object) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isDNSCluster(e.Object) },
+	}
+}
+
+func schedulerPredicates() predicate.Funcs {
+	isSchedulerCluster := func(obj runtime.Object) bool {
+		scheduler, ok := obj.(*configv1.Scheduler)
+		return ok && scheduler.GetName() == schedulerResourceName
+	}
+
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return isSchedulerCluster(e.Object) },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isSchedulerCluster(e.ObjectNew) && e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
+		},
+		GenericFunc: func(e event.GenericEvent) bool { return isSchedulerCluster(e.Object) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isSchedulerCluster(e.Object) },
+	}
+}
+
 func ownCloudConfigPredicate(targetNamespace string) predicate.Funcs {
 	isOwnCloudConfigMap := func(obj runtime.Object) bool {
 		configMap, ok := obj.(*corev1.ConfigMap)
@@ -95,6 +137,20 @@ func ownCloudConfigPredicate(targetNamespace string) predicate.Funcs {
 	}
 }
 
+func managedNamespacePredicate(targetNamespace string) predicate.Funcs {
+	isManagedNamespace := func(obj runtime.Object) bool {
+		namespace, ok := obj.(*corev1.Namespace)
+		return ok && namespace.GetName() == targetNamespace
+	}
+
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isManagedNamespace(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isManagedNamespace(e.ObjectNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isManagedNamespace(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return isManagedNamespace(e.Object) },
+	}
+}
+
 func openshiftCloudConfigMapPredicates() predicate.Funcs {
 	isCloudConfigMap := func(obj runtime.Object) bool {
 		configMap, ok := obj.(*corev1.ConfigMap)
@@ -117,6 +173,20 @@ func openshiftCloudConfigMapPredicates() predicate.Funcs {
 	}
 }
 
+func cloudConfigSecretPredicate(secretName string) predicate.Funcs {
+	isCloudConfigSecret := func(obj runtime.Object) bool {
+		secret, ok := obj.(*corev1.Secret)
+		return ok && secret.GetNamespace() == OpenshiftConfigNamespace && secret.GetName() == secretName
+	}
+
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isCloudConfigSecret(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isCloudConfigSecret(e.ObjectNew) },
+		GenericFunc: func(e event.GenericEvent) bool { return isCloudConfigSecret(e.Object) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isCloudConfigSecret(e.Object) },
+	}
+}
+
 func ccmTrustedCABundleConfigMapPredicates(targetNamespace string) predicate.Funcs {
 	isTrustedCaConfigMap := func(obj runtime.Object) bool {
 		configMap, ok := obj.(*corev1.ConfigMap)
@@ -0,0 +1,170 @@
+package restmapper
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+)
+
+// DefaultNegativeCacheTTL is how long lookups which could not be matched are remembered by default.
+const DefaultNegativeCacheTTL = 10 * time.Second
+
+// NewNegativeCachingRestMapperProvider returns a rest mapper provider wrapping mappers of the given provider
+// with NewNegativeCachingRESTMapper.
+func NewNegativeCachingRestMapperProvider(provider func(c *rest.Config, httpClient *http.Client) (meta.RESTMapper, error), ttl time.Duration) func(c *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
+	return func(c *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
+		mapper, err := provider(c, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return NewNegativeCachingRESTMapper(mapper, ttl, clock.RealClock{}), nil
+	}
+}
+
+// NegativeCachingRESTMapper remembers lookups its delegate could not match for a short time. Lazy mappers
+// query discovery on every miss, so repeated lookups of a kind which does not exist yet, e.g. of a CRD
+// which is not installed, would otherwise query the API server on every reconcile.
+// Remembered lookups of a group are forgotten once the delegate matches any lookup of the group, since
+// the delegate has refreshed its view of the group then, e.g. after a CRD of the group was installed.
+type NegativeCachingRESTMapper struct {
+	delegate meta.RESTMapper
+	ttl      time.Duration
+	clock    clock.PassiveClock
+
+	mu        sync.Mutex
+	noMatches map[string]noMatch
+}
+
+type noMatch struct {
+	err     error
+	group   string
+	expires time.Time
+}
+
+var _ meta.ResettableRESTMapper = &NegativeCachingRESTMapper{}
+
+// NewNegativeCachingRESTMapper returns a mapper which remembers lookups the delegate could not match for the given TTL.
+// Remembered lookups are forgotten once the mapper is reset, e.g. after discovery is refreshed.
+func NewNegativeCachingRESTMapper(delegate meta.RESTMapper, ttl time.Duration, clock clock.PassiveClock) *NegativeCachingRESTMapper {
+	return &NegativeCachingRESTMapper{
+		delegate:  delegate,
+		ttl:       ttl,
+		clock:     clock,
+		noMatches: map[string]noMatch{},
+	}
+}
+
+// Reset forgets remembered lookups and resets the delegate, if it supports it.
+func (m *NegativeCachingRESTMapper) Reset() {
+	m.mu.Lock()
+	m.noMatches = map[string]noMatch{}
+	m.mu.Unlock()
+
+	if resettable, ok := m.delegate.(meta.ResettableRESTMapper); ok {
+		resettable.Reset()
+	}
+}
+
+// cachedNoMatch returns the error of a remembered lookup with the given key, if it has not expired.
+func (m *NegativeCachingRESTMapper) cachedNoMatch(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.noMatches[key]
+	if !ok {
+		return nil
+	}
+	if !m.clock.Now().Before(entry.expires) {
+		delete(m.noMatches, key)
+		return nil
+	}
+	return entry.err
+}
+
+// recordLookup remembers the lookup with the given key if the delegate could not match it. Once the delegate
+// matches a lookup, remembered lookups of the same group are forgotten.
+func (m *NegativeCachingRESTMapper) recordLookup(key, group string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err == nil {
+		for key, entry := range m.noMatches {
+			if entry.group == group {
+				delete(m.noMatches, key)
+			}
+		}
+		return
+	}
+	if meta.IsNoMatchError(err) {
+		m.noMatches[key] = noMatch{err: err, group: group, expires: m.clock.Now().Add(m.ttl)}
+	}
+}
+
+// lookup returns the remembered error of the lookup with the given key, or runs the lookup on the delegate.
+func lookup[T any](m *NegativeCachingRESTMapper, key, group string, delegateLookup func() (T, error)) (T, error) {
+	if err := m.cachedNoMatch(key); err != nil {
+		var empty T
+		return empty, err
+	}
+	result, err := delegateLookup()
+	m.recordLookup(key, group, err)
+	return result, err
+}
+
+func groupKindKey(method string, gk schema.GroupKind, versions []string) string {
+	return fmt.Sprintf("%s/%s/%s", method, gk.String(), strings.Join(versions, ","))
+}
+
+// KindFor implements meta.RESTMapper.
+func (m *NegativeCachingRESTMapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	return lookup(m, "KindFor/"+resource.String(), resource.Group, func() (schema.GroupVersionKind, error) {
+		return m.delegate.KindFor(resource)
+	})
+}
+
+// KindsFor implements meta.RESTMapper.
+func (m *NegativeCachingRESTMapper) KindsFor(resource schema.GroupVersionResource) ([]schema.GroupVersionKind, error) {
+	return lookup(m, "KindsFor/"+resource.String(), resource.Group, func() ([]schema.GroupVersionKind, error) {
+		return m.delegate.KindsFor(resource)
+	})
+}
+
+// ResourceFor implements meta.RESTMapper.
+func (m *NegativeCachingRESTMapper) ResourceFor(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	return lookup(m, "ResourceFor/"+input.String(), input.Group, func() (schema.GroupVersionResource, error) {
+		return m.delegate.ResourceFor(input)
+	})
+}
+
+// ResourcesFor implements meta.RESTMapper.
+func (m *NegativeCachingRESTMapper) ResourcesFor(input schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	return lookup(m, "ResourcesFor/"+input.String(), input.Group, func() ([]schema.GroupVersionResource, error) {
+		return m.delegate.ResourcesFor(input)
+	})
+}
+
+// RESTMapping implements meta.RESTMapper.
+func (m *NegativeCachingRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	return lookup(m, groupKindKey("RESTMapping", gk, versions), gk.Group, func() (*meta.RESTMapping, error) {
+		return m.delegate.RESTMapping(gk, versions...)
+	})
+}
+
+// RESTMappings implements meta.RESTMapper.
+func (m *NegativeCachingRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) ([]*meta.RESTMapping, error) {
+	return lookup(m, groupKindKey("RESTMappings", gk, versions), gk.Group, func() ([]*meta.RESTMapping, error) {
+		return m.delegate.RESTMappings(gk, versions...)
+	})
+}
+
+// ResourceSingularizer implements meta.RESTMapper.
+func (m *NegativeCachingRESTMapper) ResourceSingularizer(resource string) (string, error) {
+	return m.delegate.ResourceSingularizer(resource)
+}
@@ -0,0 +1,104 @@
+package restmapper
+
+import (
+	"testing"
+	"time"
+
+	gmg "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+// countingRESTMapper counts RESTMapping lookups, standing in for a lazy mapper which queries discovery on every miss.
+type countingRESTMapper struct {
+	meta.RESTMapper
+	requestCount int
+	resetCount   int
+}
+
+func (m *countingRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	m.requestCount++
+	return m.RESTMapper.RESTMapping(gk, versions...)
+}
+
+func (m *countingRESTMapper) Reset() {
+	m.resetCount++
+}
+
+func TestNegativeCachingRESTMapper(t *testing.T) {
+	g := gmg.NewWithT(t)
+
+	deploymentGVK := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	defaultMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{deploymentGVK.GroupVersion()})
+	defaultMapper.Add(deploymentGVK, meta.RESTScopeNamespace)
+	delegate := &countingRESTMapper{RESTMapper: defaultMapper}
+
+	fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+	mapper := NewNegativeCachingRESTMapper(delegate, 10*time.Second, fakeClock)
+
+	invalidGK := schema.GroupKind{Group: "example.openshift.io", Kind: "NotInstalled"}
+	_, err := mapper.RESTMapping(invalidGK)
+	g.Expect(meta.IsNoMatchError(err)).To(gmg.BeTrue())
+	g.Expect(delegate.requestCount).To(gmg.Equal(1))
+
+	// A second identical invalid lookup within the TTL is answered from the cache
+	_, err = mapper.RESTMapping(invalidGK)
+	g.Expect(meta.IsNoMatchError(err)).To(gmg.BeTrue())
+	g.Expect(delegate.requestCount).To(gmg.Equal(1))
+
+	// Valid lookups are never cached
+	_, err = mapper.RESTMapping(deploymentGVK.GroupKind())
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	_, err = mapper.RESTMapping(deploymentGVK.GroupKind())
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	g.Expect(delegate.requestCount).To(gmg.Equal(3))
+
+	// The lookup is retried once the TTL expires
+	fakeClock.SetTime(fakeClock.Now().Add(10 * time.Second))
+	_, err = mapper.RESTMapping(invalidGK)
+	g.Expect(meta.IsNoMatchError(err)).To(gmg.BeTrue())
+	g.Expect(delegate.requestCount).To(gmg.Equal(4))
+
+	// Resetting the mapper forgets cached lookups and resets the delegate
+	mapper.Reset()
+	g.Expect(delegate.resetCount).To(gmg.Equal(1))
+	_, err = mapper.RESTMapping(invalidGK)
+	g.Expect(meta.IsNoMatchError(err)).To(gmg.BeTrue())
+	g.Expect(delegate.requestCount).To(gmg.Equal(5))
+}
+
+func TestNegativeCachingRESTMapperForgetsGroupOnMatch(t *testing.T) {
+	g := gmg.NewWithT(t)
+
+	defaultMapper := meta.NewDefaultRESTMapper(nil)
+	delegate := &countingRESTMapper{RESTMapper: defaultMapper}
+	mapper := NewNegativeCachingRESTMapper(delegate, 10*time.Second, clocktesting.NewFakePassiveClock(time.Now()))
+
+	notInstalledGVK := schema.GroupVersionKind{Group: "example.openshift.io", Version: "v1", Kind: "NotInstalled"}
+	otherGroupGVK := schema.GroupVersionKind{Group: "other.openshift.io", Version: "v1", Kind: "NotInstalled"}
+	for _, gvk := range []schema.GroupVersionKind{notInstalledGVK, otherGroupGVK} {
+		_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		g.Expect(meta.IsNoMatchError(err)).To(gmg.BeTrue())
+	}
+	g.Expect(delegate.requestCount).To(gmg.Equal(2))
+
+	// CRDs of the group are installed, the delegate matches a kind of the group the mapper did not look up before
+	defaultMapper.Add(notInstalledGVK, meta.RESTScopeNamespace)
+	installedGVK := schema.GroupVersionKind{Group: "example.openshift.io", Version: "v1", Kind: "Installed"}
+	defaultMapper.Add(installedGVK, meta.RESTScopeNamespace)
+	_, err := mapper.RESTMapping(installedGVK.GroupKind(), installedGVK.Version)
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	g.Expect(delegate.requestCount).To(gmg.Equal(3))
+
+	// Remembered lookups of the group are forgotten, so the kind is matched without waiting for the TTL
+	_, err = mapper.RESTMapping(notInstalledGVK.GroupKind(), notInstalledGVK.Version)
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	g.Expect(delegate.requestCount).To(gmg.Equal(4))
+
+	// Remembered lookups of other groups are kept
+	_, err = mapper.RESTMapping(otherGroupGVK.GroupKind(), otherGroupGVK.Version)
+	g.Expect(meta.IsNoMatchError(err)).To(gmg.BeTrue())
+	g.Expect(delegate.requestCount).To(gmg.Equal(4))
+}
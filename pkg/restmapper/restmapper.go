@@ -17,6 +17,7 @@ type RESTMapperProvider func(c *rest.Config) (meta.RESTMapper, error)
 
 // NewPartialRestMapperProvider returns configured 'partial' rest mapper provider intended to be used with controller-runtime manager.
 // Takes GroupFilterPredicate as an argument for filtering out APIGroups during discovery procedure.
+// Provided mappers are *PartialRESTMapper, so more groups can be permitted after construction.
 func NewPartialRestMapperProvider(groupFilterPredicate GroupFilterPredicate) func(c *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
 	partialRESTMapperProvider := func(c *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
 		dc, err := discovery.NewDiscoveryClientForConfig(c)
@@ -24,16 +25,137 @@ func NewPartialRestMapperProvider(groupFilterPredicate GroupFilterPredicate) fun
 			return nil, err
 		}
 
-		groupResources, err := getFilteredAPIGroupResources(dc, groupFilterPredicate)
-		if err != nil {
-			return nil, err
-		}
-
-		return restmapper.NewDiscoveryRESTMapper(groupResources), nil
+		return newPartialRESTMapper(dc, groupFilterPredicate)
 	}
 	return partialRESTMapperProvider
 }
 
+// PartialRESTMapper is a REST mapper for API groups permitted by its group filter predicates.
+// It allows to permit more groups at runtime, e.g. when a platform starts managing a resource
+// of a group which was not known when the mapper was constructed.
+type PartialRESTMapper struct {
+	discoveryClient discovery.DiscoveryInterface
+
+	mu                   sync.RWMutex
+	groupFilterPredicate GroupFilterPredicate
+	mapper               meta.RESTMapper
+	// stale is set once a group filter is added, groups are discovered again on the next lookup.
+	stale bool
+}
+
+var _ meta.RESTMapper = &PartialRESTMapper{}
+
+func newPartialRESTMapper(dc discovery.DiscoveryInterface, groupFilterPredicate GroupFilterPredicate) (*PartialRESTMapper, error) {
+	groupResources, err := getFilteredAPIGroupResources(dc, groupFilterPredicate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PartialRESTMapper{
+		discoveryClient:      dc,
+		groupFilterPredicate: groupFilterPredicate,
+		mapper:               restmapper.NewDiscoveryRESTMapper(groupResources),
+	}, nil
+}
+
+// AddGroupFilter permits groups matching the given predicate in addition to the already permitted ones.
+// Resources of the added groups are discovered on the next lookup.
+func (m *PartialRESTMapper) AddGroupFilter(groupFilterPredicate GroupFilterPredicate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.groupFilterPredicate = Or(m.groupFilterPredicate, groupFilterPredicate)
+	m.stale = true
+}
+
+// delegate returns the REST mapper for the currently permitted groups, discovering them again
+// if a group filter was added since the last lookup.
+func (m *PartialRESTMapper) delegate() (meta.RESTMapper, error) {
+	m.mu.RLock()
+	if !m.stale {
+		defer m.mu.RUnlock()
+		return m.mapper, nil
+	}
+	m.mu.RUnlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.stale {
+		return m.mapper, nil
+	}
+
+	groupResources, err := getFilteredAPIGroupResources(m.discoveryClient, m.groupFilterPredicate)
+	if err != nil {
+		return nil, err
+	}
+	m.mapper = restmapper.NewDiscoveryRESTMapper(groupResources)
+	m.stale = false
+	return m.mapper, nil
+}
+
+// KindFor implements meta.RESTMapper.
+func (m *PartialRESTMapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	mapper, err := m.delegate()
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return mapper.KindFor(resource)
+}
+
+// KindsFor implements meta.RESTMapper.
+func (m *PartialRESTMapper) KindsFor(resource schema.GroupVersionResource) ([]schema.GroupVersionKind, error) {
+	mapper, err := m.delegate()
+	if err != nil {
+		return nil, err
+	}
+	return mapper.KindsFor(resource)
+}
+
+// ResourceFor implements meta.RESTMapper.
+func (m *PartialRESTMapper) ResourceFor(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	mapper, err := m.delegate()
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapper.ResourceFor(input)
+}
+
+// ResourcesFor implements meta.RESTMapper.
+func (m *PartialRESTMapper) ResourcesFor(input schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	mapper, err := m.delegate()
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ResourcesFor(input)
+}
+
+// RESTMapping implements meta.RESTMapper.
+func (m *PartialRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	mapper, err := m.delegate()
+	if err != nil {
+		return nil, err
+	}
+	return mapper.RESTMapping(gk, versions...)
+}
+
+// RESTMappings implements meta.RESTMapper.
+func (m *PartialRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) ([]*meta.RESTMapping, error) {
+	mapper, err := m.delegate()
+	if err != nil {
+		return nil, err
+	}
+	return mapper.RESTMappings(gk, versions...)
+}
+
+// ResourceSingularizer implements meta.RESTMapper.
+func (m *PartialRESTMapper) ResourceSingularizer(resource string) (string, error) {
+	mapper, err := m.delegate()
+	if err != nil {
+		return "", err
+	}
+	return mapper.ResourceSingularizer(resource)
+}
+
 // fetchGroupVersionResources uses the discovery client to fetch the resources for the specified groups in parallel.
 // Mainly replicates the same named function from the client-go internals aside from the changed `apiGroups` argument type (uses slice instead of APIGroupList).
 // ref: https://github.com/kubernetes/kubernetes/blob/a84d877310ba5cf9237c8e8e3218229c202d3a1e/staging/src/k8s.io/client-go/discovery/discovery_client.go#L506
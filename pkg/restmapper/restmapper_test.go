@@ -5,6 +5,7 @@ import (
 
 	gmg "github.com/onsi/gomega"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
@@ -82,4 +83,27 @@ func TestPartialRestMapperProvider(t *testing.T) {
 		_, err = filteredGroupsMapper.RESTMapping(schema.GroupKind{Group: "apps", Kind: "deployment"})
 		g.Expect(err).To(gmg.Succeed())
 	})
+	t.Run("PartialRESTMapper should resolve groups added after construction", func(t *testing.T) {
+		g := gmg.NewWithT(t)
+
+		httpClient, err := rest.HTTPClientFor(restCfg)
+		g.Expect(err).ToNot(gmg.HaveOccurred())
+		restMapper, err := NewPartialRestMapperProvider(KubernetesAppsGroup)(restCfg, httpClient)
+		g.Expect(err).To(gmg.Succeed())
+		partialRestMapper, ok := restMapper.(*PartialRESTMapper)
+		g.Expect(ok).To(gmg.BeTrue())
+
+		_, err = partialRestMapper.RESTMapping(schema.GroupKind{Group: "events.k8s.io", Kind: "event"})
+		g.Expect(err).To(gmg.HaveOccurred())
+
+		partialRestMapper.AddGroupFilter(func(group *metav1.APIGroup) bool {
+			return group.Name == "events.k8s.io"
+		})
+
+		_, err = partialRestMapper.RESTMapping(schema.GroupKind{Group: "events.k8s.io", Kind: "event"})
+		g.Expect(err).To(gmg.Succeed())
+		// Previously permitted groups are still resolved
+		_, err = partialRestMapper.RESTMapping(schema.GroupKind{Group: "apps", Kind: "deployment"})
+		g.Expect(err).To(gmg.Succeed())
+	})
 }
@@ -1,9 +1,13 @@
 package util
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
 	upstreamfeature "k8s.io/component-base/featuregate"
 	cloudfeatures "k8s.io/controller-manager/pkg/features"
@@ -48,6 +52,69 @@ func BuildFeatureGateString(enabled, disabled []string) string {
 	return strings.Join(append(enabled, disabled...), ",")
 }
 
+// NewStaticFeatureGateAccess returns a feature gate accessor with a fixed set of feature gates parsed
+// from a comma-separated list in the "--feature-gates" format, e.g. "ChocobombStrawberry=true,ChocobombBanana=false".
+// It is useful when the FeatureGate object can not be observed, e.g. in disconnected or bootstrap scenarios.
+func NewStaticFeatureGateAccess(featureGates string) (featuregates.FeatureGateAccess, error) {
+	var enabled []configv1.FeatureGateName
+	var disabled []configv1.FeatureGateName
+
+	for _, featureGate := range strings.Split(featureGates, ",") {
+		featureGate = strings.TrimSpace(featureGate)
+		if featureGate == "" {
+			continue
+		}
+		name, value, found := strings.Cut(featureGate, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid feature gate %q, expected format is Name=true|false", featureGate)
+		}
+		isEnabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of feature gate %q: %w", name, err)
+		}
+		if isEnabled {
+			enabled = append(enabled, configv1.FeatureGateName(name))
+		} else {
+			disabled = append(disabled, configv1.FeatureGateName(name))
+		}
+	}
+
+	return featuregates.NewHardcodedFeatureGateAccess(enabled, disabled), nil
+}
+
+// WaitForFeatureGates waits up to the timeout for the accessor to observe the initial feature gates, and reports
+// whether they were observed in time. If they were not, e.g. while the FeatureGate object is missing during
+// bootstrap, the returned accessor serves an explicit empty set of feature gates until the accessor observes them,
+// so consumers never get nil feature gates. Feature gates observed later are served once they are available.
+func WaitForFeatureGates(ctx context.Context, accessor featuregates.FeatureGateAccess, timeout time.Duration) (featuregates.FeatureGateAccess, bool) {
+	select {
+	case <-accessor.InitialFeatureGatesObserved():
+		return accessor, true
+	case <-ctx.Done():
+	case <-time.After(timeout):
+	}
+	return &emptyFallbackFeatureGateAccess{
+		FeatureGateAccess: accessor,
+		empty:             featuregates.NewFeatureGate(nil, nil),
+	}, false
+}
+
+// emptyFallbackFeatureGateAccess serves an empty set of feature gates while the wrapped accessor
+// has not observed feature gates.
+type emptyFallbackFeatureGateAccess struct {
+	featuregates.FeatureGateAccess
+	empty featuregates.FeatureGate
+}
+
+// CurrentFeatureGates returns the feature gates observed by the wrapped accessor, or an empty set of feature gates.
+func (a *emptyFallbackFeatureGateAccess) CurrentFeatureGates() (featuregates.FeatureGate, error) {
+	features, err := a.FeatureGateAccess.CurrentFeatureGates()
+	if err != nil || features == nil {
+		return a.empty, nil
+	}
+	return features, nil
+}
+
 // GetUpstreamCloudFeatureGates returns a list of feature gates that are allowed to be used in the
 // context of cloud provider.
 func GetUpstreamCloudFeatureGates() ([]string, error) {
@@ -0,0 +1,92 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStaticFeatureGateAccess(t *testing.T) {
+	tc := []struct {
+		name             string
+		featureGates     string
+		expectedEnabled  []string
+		expectedDisabled []string
+		expectedErr      string
+	}{{
+		name: "Empty list",
+	}, {
+		name:             "Enabled and disabled feature gates",
+		featureGates:     "CloudDualStackNodeIPs=true, ChocobombBanana=false,ChocobombVanilla=true",
+		expectedEnabled:  []string{"CloudDualStackNodeIPs", "ChocobombVanilla"},
+		expectedDisabled: []string{"ChocobombBanana"},
+	}, {
+		name:            "Trailing comma is ignored",
+		featureGates:    "CloudDualStackNodeIPs=true,",
+		expectedEnabled: []string{"CloudDualStackNodeIPs"},
+	}, {
+		name:         "Missing value",
+		featureGates: "CloudDualStackNodeIPs",
+		expectedErr:  "invalid feature gate \"CloudDualStackNodeIPs\", expected format is Name=true|false",
+	}, {
+		name:         "Missing name",
+		featureGates: "=true",
+		expectedErr:  "invalid feature gate \"=true\", expected format is Name=true|false",
+	}, {
+		name:         "Invalid value",
+		featureGates: "CloudDualStackNodeIPs=yes",
+		expectedErr:  "invalid value of feature gate \"CloudDualStackNodeIPs\": strconv.ParseBool: parsing \"yes\": invalid syntax",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			accessor, err := NewStaticFeatureGateAccess(tc.featureGates)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			select {
+			case <-accessor.InitialFeatureGatesObserved():
+			default:
+				t.Fatal("static feature gates should be observed immediately")
+			}
+
+			features, err := accessor.CurrentFeatureGates()
+			assert.NoError(t, err)
+
+			enabled, disabled := GetEnabledDisabledFeatures(features, nil)
+			assert.Equal(t, tc.expectedEnabled, enabled)
+			assert.Equal(t, tc.expectedDisabled, disabled)
+		})
+	}
+}
+
+func TestWaitForFeatureGates(t *testing.T) {
+	t.Run("Observed feature gates", func(t *testing.T) {
+		accessor := featuregates.NewHardcodedFeatureGateAccess([]configv1.FeatureGateName{"CloudDualStackNodeIPs"}, nil)
+
+		waited, observed := WaitForFeatureGates(context.TODO(), accessor, time.Second)
+		assert.True(t, observed)
+		assert.Equal(t, accessor, waited)
+	})
+
+	t.Run("Timed out waiting for feature gates", func(t *testing.T) {
+		accessor := featuregates.NewHardcodedFeatureGateAccessForTesting(nil, nil, make(chan struct{}), errors.New("featuregates not observed"))
+
+		waited, observed := WaitForFeatureGates(context.TODO(), accessor, time.Millisecond)
+		assert.False(t, observed)
+
+		features, err := waited.CurrentFeatureGates()
+		assert.NoError(t, err)
+		if assert.NotNil(t, features) {
+			assert.Empty(t, features.KnownFeatures())
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package util
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// LogFormatText is the default klog text log format
+	LogFormatText = "text"
+	// LogFormatJSON makes the logger emit one JSON object per log entry
+	LogFormatJSON = "json"
+)
+
+// NewLogger returns the logger for the given log format.
+// Text format uses the logger returned by textLogger, so klog flags keep configuring it as before.
+// JSON format writes structured entries to stderr respecting the klog verbosity flag,
+// klog output is routed through the same logger in this case.
+func NewLogger(format string, textLogger func() logr.Logger) (logr.Logger, error) {
+	switch format {
+	case LogFormatText:
+		return textLogger(), nil
+	case LogFormatJSON:
+		logger := funcr.NewJSON(func(obj string) {
+			fmt.Fprintln(os.Stderr, obj)
+		}, funcr.Options{
+			LogTimestamp: true,
+			Verbosity:    verbosityFromFlags(),
+		})
+		klog.SetLogger(logger)
+		return logger, nil
+	default:
+		return logr.Logger{}, fmt.Errorf("unsupported log format %q, expected one of: %s, %s", format, LogFormatText, LogFormatJSON)
+	}
+}
+
+// verbosityFromFlags returns the value of the klog verbosity flag, if registered.
+func verbosityFromFlags() int {
+	verbosityFlag := flag.CommandLine.Lookup("v")
+	if verbosityFlag == nil {
+		return 0
+	}
+	verbosity, err := strconv.Atoi(verbosityFlag.Value.String())
+	if err != nil {
+		return 0
+	}
+	return verbosity
+}
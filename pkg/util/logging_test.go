@@ -0,0 +1,58 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/klog/v2"
+)
+
+func TestNewLogger(t *testing.T) {
+	textLogger := funcr.New(func(prefix, args string) {}, funcr.Options{})
+	textLoggerFn := func() logr.Logger { return textLogger }
+
+	tc := []struct {
+		name        string
+		format      string
+		expectText  bool
+		expectedErr string
+	}{{
+		name:       "Text format",
+		format:     LogFormatText,
+		expectText: true,
+	}, {
+		name:   "JSON format",
+		format: LogFormatJSON,
+	}, {
+		name:        "Invalid format",
+		format:      "yaml",
+		expectedErr: "unsupported log format \"yaml\", expected one of: text, json",
+	}, {
+		name:        "Empty format",
+		format:      "",
+		expectedErr: "unsupported log format \"\", expected one of: text, json",
+	}}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			defer klog.ClearLogger()
+
+			logger, err := NewLogger(tc.format, textLoggerFn)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			if tc.expectText {
+				assert.Equal(t, textLogger, logger)
+			} else {
+				assert.NotEqual(t, textLogger, logger)
+				_, isUnderlier := logger.GetSink().(funcr.Underlier)
+				assert.True(t, isUnderlier, "JSON logger should be backed by funcr")
+			}
+		})
+	}
+}